@@ -0,0 +1,80 @@
+// Command storagegc reconciles the photo and attachment folders of the storage with the rows of the database. It lists
+// the objects no row references and the rows whose object is missing, and deletes the orphans with -delete.
+//
+//	go run ./cmd/storagegc [-delete] [-grace 168h] [-folder event-photos,accident-photos,attachments,uploads] [-out report.json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"safety-riding/infrastructure/database"
+	"safety-riding/infrastructure/media"
+	"safety-riding/internal/dto"
+	repositorystoragegc "safety-riding/internal/repositories/storagegc"
+	servicestoragegc "safety-riding/internal/services/storagegc"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		remove  bool
+		grace   time.Duration
+		folders string
+		out     string
+	)
+	flag.BoolVar(&remove, "delete", false, "delete the orphaned objects, they are only reported without it")
+	flag.DurationVar(&grace, "grace", 7*24*time.Hour, "keep objects younger than this and the files of rows deleted within it")
+	flag.StringVar(&folders, "folder", "", "comma separated folders to reconcile, all of "+strings.Join(servicestoragegc.Folders(), ", ")+" by default")
+	flag.StringVar(&out, "out", "", "write the JSON report to this file instead of stdout, where it follows the log lines")
+	flag.Parse()
+
+	if timeZone, err := time.LoadLocation("Asia/Jakarta"); err == nil {
+		time.Local = timeZone
+	}
+	if err := godotenv.Load(".env"); err != nil && os.Getenv("APP_ENV") == "" {
+		log.Fatalf("Error app environment")
+	}
+
+	db, sqlDb, err := database.ConnDb()
+	if err != nil {
+		log.Fatalf("Failed to open db: %s", err)
+	}
+	defer sqlDb.Close()
+
+	provider, err := media.InitStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize storage provider: %s", err)
+	}
+
+	opts := dto.StorageGCOptions{GracePeriod: grace, Delete: remove}
+	if folders != "" {
+		opts.Folders = strings.Split(folders, ",")
+	}
+
+	service := servicestoragegc.NewStorageGCService(repositorystoragegc.NewStorageGCRepo(db), provider)
+	report, err := service.Reconcile(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Failed to reconcile storage: %s", err)
+	}
+
+	output := os.Stdout
+	if out != "" {
+		if output, err = os.Create(out); err != nil {
+			log.Fatalf("Failed to create report file: %s", err)
+		}
+		defer output.Close()
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to write report: %s", err)
+	}
+}
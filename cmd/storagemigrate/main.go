@@ -0,0 +1,101 @@
+// Command storagemigrate copies the stored objects from the storage configured by the STORAGE_* variables to
+// the one configured by the TARGET_STORAGE_* variables, e.g. from MinIO to R2, and moves the file URLs kept in
+// the database to the target. Every copy is read back and compared by SHA-256, and the copied objects are
+// recorded in the state file so an interrupted run resumes where it stopped.
+//
+//	go run ./cmd/storagemigrate [-dry-run] [-rewrite=false] [-prefix event-photos/] [-state storage-migration.state]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"safety-riding/infrastructure/database"
+	"safety-riding/infrastructure/media"
+	"safety-riding/internal/dto"
+	repositorystoragemigration "safety-riding/internal/repositories/storagemigration"
+	servicestoragemigration "safety-riding/internal/services/storagemigration"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		opts dto.StorageMigrationOptions
+		out  string
+	)
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "report what would be copied and rewritten without changing anything")
+	flag.BoolVar(&opts.Rewrite, "rewrite", true, "move the file URLs of the copied objects to the target in the database")
+	flag.StringVar(&opts.Prefix, "prefix", "", "only copy the objects whose name starts with this prefix")
+	flag.StringVar(&opts.StateFile, "state", "storage-migration.state", "file recording the copied objects and their SHA-256")
+	flag.IntVar(&opts.BatchSize, "batch", 500, "rows rewritten per transaction")
+	flag.IntVar(&opts.Workers, "workers", 4, "objects copied in parallel")
+	flag.StringVar(&out, "out", "", "write the JSON report to this file instead of stdout, where it follows the log lines")
+	flag.Parse()
+
+	if timeZone, err := time.LoadLocation("Asia/Jakarta"); err == nil {
+		time.Local = timeZone
+	}
+	if err := godotenv.Load(".env"); err != nil && os.Getenv("APP_ENV") == "" {
+		log.Fatalf("Error app environment")
+	}
+	if os.Getenv("TARGET_STORAGE_PROVIDER") == "" {
+		log.Fatalf("TARGET_STORAGE_PROVIDER is not set, configure the target storage with the TARGET_STORAGE_* variables")
+	}
+
+	db, sqlDb, err := database.ConnDb()
+	if err != nil {
+		log.Fatalf("Failed to open db: %s", err)
+	}
+	defer sqlDb.Close()
+
+	source, err := media.InitStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize source storage: %s", err)
+	}
+	target, err := media.InitStorageFrom("TARGET_")
+	if err != nil {
+		log.Fatalf("Failed to initialize target storage: %s", err)
+	}
+	if source.GetFileURL("") == target.GetFileURL("") {
+		log.Fatalf("The source and target storage have the same URL %s", source.GetFileURL(""))
+	}
+
+	// Ctrl+C stops the migration, the objects copied so far stay recorded in the state file
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	service := servicestoragemigration.NewStorageMigrationService(repositorystoragemigration.NewStorageMigrationRepo(db), source, target)
+	report, migrateErr := service.Migrate(ctx, opts)
+	if err := writeReport(out, report); err != nil {
+		log.Fatalf("Failed to write report: %s", err)
+	}
+
+	if migrateErr != nil {
+		log.Fatalf("Failed to migrate storage: %s", migrateErr)
+	}
+	if len(report.Failed) > 0 {
+		log.Fatalf("%d objects failed to copy, run the migration again to retry them", len(report.Failed))
+	}
+}
+
+func writeReport(out string, report dto.StorageMigrationReport) error {
+	output := os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"fmt"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/mailer"
+	"safety-riding/utils"
+	"strings"
+)
+
+// InitMailer initializes and returns a mailer (SMTP or log)
+func InitMailer() (mailer.Mailer, error) {
+	driver := strings.ToLower(utils.GetEnv("MAIL_DRIVER", "log").(string))
+
+	config := mailer.Config{
+		Driver:   driver,
+		Host:     utils.GetEnv("SMTP_HOST", "").(string),
+		Port:     utils.GetEnv("SMTP_PORT", 587).(int),
+		Username: utils.GetEnv("SMTP_USERNAME", "").(string),
+		Password: utils.GetEnv("SMTP_PASSWORD", "").(string),
+		From:     utils.GetEnv("MAIL_FROM", "").(string),
+		LogFile:  utils.GetEnv("MAIL_LOG_FILE", "").(string),
+	}
+
+	m, err := mailer.NewMailer(config)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("InitMailer; Failed to initialize mailer: %s", err.Error()))
+		return nil, fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("InitMailer; Mailer initialized successfully. Driver: %s", driver))
+	return m, nil
+}
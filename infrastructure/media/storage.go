@@ -9,28 +9,18 @@ import (
 	"strings"
 )
 
-// InitStorage initializes and returns a storage provider (MinIO or R2)
+// InitStorage initializes and returns a storage provider (MinIO, R2 or the local filesystem)
 func InitStorage() (storage.StorageProvider, error) {
-	logger.WriteLog(logger.LogLevelDebug, "InitStorage; Initializing storage provider...")
-
-	// Get storage provider from environment (default: minio)
-	provider := strings.ToLower(utils.GetEnv("STORAGE_PROVIDER", "minio").(string))
+	return InitStorageFrom("")
+}
 
-	// Parse SSL configuration
-	useSSL, _ := strconv.ParseBool(utils.GetEnv("STORAGE_USE_SSL", "false").(string))
+// InitStorageFrom initializes the storage provider configured by the STORAGE_* variables behind prefix,
+// e.g. TARGET_STORAGE_PROVIDER for the target of a storage migration
+func InitStorageFrom(prefix string) (storage.StorageProvider, error) {
+	logger.WriteLog(logger.LogLevelDebug, "InitStorage; Initializing storage provider...")
 
-	// Build storage configuration
-	config := storage.Config{
-		Provider:        provider,
-		Endpoint:        utils.GetEnv("STORAGE_ENDPOINT", "localhost:9000").(string),
-		AccessKeyID:     utils.GetEnv("STORAGE_ACCESS_KEY", "minioadmin").(string),
-		SecretAccessKey: utils.GetEnv("STORAGE_SECRET_KEY", "minioadmin").(string),
-		BucketName:      utils.GetEnv("STORAGE_BUCKET_NAME", "safety-riding").(string),
-		UseSSL:          useSSL,
-		BaseURL:         utils.GetEnv("STORAGE_BASE_URL", "http://localhost:9000").(string),
-		Region:          utils.GetEnv("STORAGE_REGION", "auto").(string),
-		AccountID:       utils.GetEnv("R2_ACCOUNT_ID", "").(string),
-	}
+	config := StorageConfig(prefix)
+	provider := config.Provider
 
 	// Create storage provider using factory
 	storageProvider, err := storage.NewStorageProvider(config)
@@ -45,6 +35,31 @@ func InitStorage() (storage.StorageProvider, error) {
 	return storageProvider, nil
 }
 
+// StorageConfig reads the storage configuration from the STORAGE_* variables behind prefix
+func StorageConfig(prefix string) storage.Config {
+	// Get storage provider from environment (default: minio)
+	provider := strings.ToLower(utils.GetEnv(prefix+"STORAGE_PROVIDER", "minio").(string))
+
+	// Parse SSL configuration
+	useSSL, _ := strconv.ParseBool(utils.GetEnv(prefix+"STORAGE_USE_SSL", "false").(string))
+
+	config := storage.Config{
+		Provider:        provider,
+		Endpoint:        utils.GetEnv(prefix+"STORAGE_ENDPOINT", "localhost:9000").(string),
+		AccessKeyID:     utils.GetEnv(prefix+"STORAGE_ACCESS_KEY", "minioadmin").(string),
+		SecretAccessKey: utils.GetEnv(prefix+"STORAGE_SECRET_KEY", "minioadmin").(string),
+		BucketName:      utils.GetEnv(prefix+"STORAGE_BUCKET_NAME", "safety-riding").(string),
+		UseSSL:          useSSL,
+		BaseURL:         utils.GetEnv(prefix+"STORAGE_BASE_URL", "http://localhost:9000").(string),
+		Region:          utils.GetEnv(prefix+"STORAGE_REGION", "auto").(string),
+		AccountID:       utils.GetEnv(prefix+"R2_ACCOUNT_ID", "").(string),
+		LocalPath:       utils.GetEnv(prefix+"STORAGE_LOCAL_PATH", "./storage").(string),
+	}
+	// Local file URLs are signed with their own key, or with the storage secret key when it is not set
+	config.SigningKey = utils.GetEnv(prefix+"STORAGE_SIGNING_KEY", config.SecretAccessKey).(string)
+	return config
+}
+
 // Deprecated: Use InitStorage instead
 // InitMinio is kept for backward compatibility but now returns the generic StorageProvider
 func InitMinio() (storage.StorageProvider, error) {
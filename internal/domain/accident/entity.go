@@ -58,6 +58,15 @@ type AccidentPhoto struct {
 	PhotoUrl   string `json:"photo_url" gorm:"column:photo_url"`
 	Caption    string `json:"caption" gorm:"column:caption"`
 	PhotoOrder int    `json:"photo_order" gorm:"column:photo_order"`
+	// IsCover marks the photo shown for the accident on the dashboard, an accident has at most one
+	IsCover bool `json:"is_cover" gorm:"column:is_cover"`
+
+	// MediumUrl and ThumbnailUrl are smaller renditions of the photo for the map and lists
+	MediumUrl    string `json:"medium_url" gorm:"column:medium_url"`
+	ThumbnailUrl string `json:"thumbnail_url" gorm:"column:thumbnail_url"`
+	// Latitude and Longitude come from the EXIF data of the upload when PHOTO_KEEP_LOCATION is enabled, the stored files never carry them
+	Latitude  *float64 `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude *float64 `json:"longitude,omitempty" gorm:"column:longitude"`
 
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
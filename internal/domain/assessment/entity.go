@@ -0,0 +1,97 @@
+package domainassessment
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TypePre  = "pre"
+	TypePost = "post"
+)
+
+// Groupings of the assessment analytics
+const (
+	GroupByEvent      = "event"
+	GroupByInstructor = "instructor"
+	GroupBySchool     = "school"
+	GroupByRegion     = "region"
+)
+
+func (QuestionBank) TableName() string {
+	return "question_banks"
+}
+
+// QuestionBank is a reusable set of multiple choice safety knowledge questions
+type QuestionBank struct {
+	ID          string     `json:"id" gorm:"column:id;primaryKey"`
+	Name        string     `json:"name" gorm:"column:name"`
+	Description string     `json:"description" gorm:"column:description"`
+	Questions   []Question `json:"questions,omitempty" gorm:"foreignKey:BankId"`
+
+	// QuestionCount is only filled when listing question banks
+	QuestionCount int `json:"question_count" gorm:"column:question_count;->;-:migration"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	UpdatedAt *time.Time     `json:"updated_at" gorm:"column:updated_at"`
+	UpdatedBy string         `json:"updated_by" gorm:"column:updated_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
+
+func (Question) TableName() string {
+	return "assessment_questions"
+}
+
+type Question struct {
+	ID            string   `json:"id" gorm:"column:id;primaryKey"`
+	BankId        string   `json:"bank_id" gorm:"column:bank_id"`
+	Question      string   `json:"question" gorm:"column:question"`
+	Options       []string `json:"options" gorm:"column:options;serializer:json"`
+	CorrectOption int      `json:"correct_option" gorm:"column:correct_option"`
+	SortOrder     int      `json:"sort_order" gorm:"column:sort_order"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
+
+func (EventAssessment) TableName() string {
+	return "event_assessments"
+}
+
+// EventAssessment attaches a question bank to an event as its pre-test or post-test
+type EventAssessment struct {
+	ID      string        `json:"id" gorm:"column:id;primaryKey"`
+	EventId string        `json:"event_id" gorm:"column:event_id"`
+	BankId  string        `json:"bank_id" gorm:"column:bank_id"`
+	Type    string        `json:"type" gorm:"column:type"`
+	Bank    *QuestionBank `json:"bank,omitempty" gorm:"foreignKey:BankId;references:ID"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
+
+func (Submission) TableName() string {
+	return "assessment_submissions"
+}
+
+// Submission is the scored answer sheet of one participant
+type Submission struct {
+	ID             string         `json:"id" gorm:"column:id;primaryKey"`
+	AssessmentId   string         `json:"assessment_id" gorm:"column:assessment_id"`
+	EventId        string         `json:"event_id" gorm:"column:event_id"`
+	ParticipantId  string         `json:"participant_id" gorm:"column:participant_id"`
+	Answers        map[string]int `json:"answers" gorm:"column:answers;serializer:json"`
+	CorrectCount   int            `json:"correct_count" gorm:"column:correct_count"`
+	TotalQuestions int            `json:"total_questions" gorm:"column:total_questions"`
+	Score          float64        `json:"score" gorm:"column:score"`
+	SubmittedAt    time.Time      `json:"submitted_at" gorm:"column:submitted_at"`
+	SubmittedBy    string         `json:"submitted_by" gorm:"column:submitted_by"`
+
+	// ParticipantName is only filled when listing submissions
+	ParticipantName string `json:"participant_name" gorm:"column:participant_name;->;-:migration"`
+}
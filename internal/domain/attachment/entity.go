@@ -0,0 +1,46 @@
+package domainattachment
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The resources documents can be attached to, an attachment follows the permissions of its parent
+const (
+	EntityEvent    = "event"
+	EntityAccident = "accident"
+	EntityBudget   = "budget"
+)
+
+var (
+	// ErrFileType is returned for files that are not a PDF, JPEG or PNG, the type is sniffed from the content
+	ErrFileType = errors.New("attachments must be PDF, JPEG or PNG files")
+	// ErrFileTooLarge is returned for files above ATTACHMENT_MAX_SIZE_MB
+	ErrFileTooLarge = errors.New("attachment is too large")
+)
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// Attachment is a document such as a police report, an attendance sheet or an invoice kept with an event,
+// an accident or a budget
+type Attachment struct {
+	ID         string `json:"id" gorm:"column:id;primaryKey"`
+	EntityType string `json:"entity_type" gorm:"column:entity_type"`
+	EntityId   string `json:"entity_id" gorm:"column:entity_id"`
+	FileName   string `json:"file_name" gorm:"column:file_name"`
+	ObjectName string `json:"-" gorm:"column:object_name"`
+	// FileUrl is a presigned download URL that expires after ATTACHMENT_URL_EXPIRY_SECONDS, it is not stored
+	FileUrl     string `json:"file_url" gorm:"-"`
+	ContentType string `json:"content_type" gorm:"column:content_type"`
+	Size        int64  `json:"size" gorm:"column:size"`
+	Description string `json:"description" gorm:"column:description"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
@@ -0,0 +1,51 @@
+package domainauditlog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreate            = "create"
+	ActionUpdate            = "update"
+	ActionDelete            = "delete"
+	ActionOverrideFinalized = "override_finalized"
+	ActionAssignPermissions = "assign_permissions"
+)
+
+const (
+	EntityEvent                = "event"
+	EntityEventPhoto           = "event_photo"
+	EntityEventParticipant     = "event_participant"
+	EntityQuestionBank         = "question_bank"
+	EntityEventAssessment      = "event_assessment"
+	EntityAssessmentSubmission = "assessment_submission"
+	EntityCertificateTemplate  = "certificate_template"
+	EntityCertificate          = "certificate"
+	EntityInstructor           = "instructor"
+	EntityBudget               = "budget"
+	EntitySchool               = "school"
+	EntityPublic               = "public"
+	EntityAccident             = "accident"
+	EntityAccidentPhoto        = "accident_photo"
+	EntityMarketShare          = "market_share"
+	EntityRole                 = "role"
+	EntityPermission           = "permission"
+	EntityAttachment           = "attachment"
+)
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+type AuditLog struct {
+	ID         string          `json:"id" gorm:"column:id;primaryKey"`
+	Actor      string          `json:"actor" gorm:"column:actor"`
+	Action     string          `json:"action" gorm:"column:action"`
+	EntityType string          `json:"entity_type" gorm:"column:entity_type"`
+	EntityId   string          `json:"entity_id" gorm:"column:entity_id"`
+	BeforeData json.RawMessage `json:"before_data,omitempty" gorm:"column:before_data;type:jsonb"`
+	AfterData  json.RawMessage `json:"after_data,omitempty" gorm:"column:after_data;type:jsonb"`
+	Diff       json.RawMessage `json:"diff,omitempty" gorm:"column:diff;type:jsonb"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"column:created_at"`
+}
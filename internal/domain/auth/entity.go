@@ -2,6 +2,14 @@ package domainauth
 
 import "time"
 
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeTwoFactorLogin    = "two_factor_login"
+	TokenPurposeRecoveryCode      = "two_factor_recovery"
+	TokenPurposeCalendarFeed      = "calendar_feed"
+)
+
 func (Blacklist) TableName() string {
 	return "blacklist"
 }
@@ -11,3 +19,28 @@ type Blacklist struct {
 	Token     string    `gorm:"not null; unique" json:"token"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+func (TokenRevocation) TableName() string {
+	return "token_revocations"
+}
+
+// TokenRevocation rejects every access token of the user that was issued before RevokedAt
+type TokenRevocation struct {
+	UserId    string    `gorm:"primaryKey;column:user_id" json:"user_id"`
+	RevokedAt time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// UserToken is a single-use token sent to a user, only the sha256 hash of the token is stored
+type UserToken struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	UserId    string     `gorm:"column:user_id" json:"user_id"`
+	Purpose   string     `gorm:"column:purpose" json:"purpose"`
+	TokenHash string     `gorm:"column:token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+}
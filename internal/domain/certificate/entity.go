@@ -0,0 +1,72 @@
+package domaincertificate
+
+import (
+	"time"
+
+	"safety-riding/pkg/certificate"
+
+	"gorm.io/gorm"
+)
+
+func (Template) TableName() string {
+	return "certificate_templates"
+}
+
+// Template is the layout of a certificate: a background image with text fields holding placeholders
+type Template struct {
+	ID            string              `json:"id" gorm:"column:id;primaryKey"`
+	Name          string              `json:"name" gorm:"column:name"`
+	PageSize      string              `json:"page_size" gorm:"column:page_size"`
+	Orientation   string              `json:"orientation" gorm:"column:orientation"`
+	BackgroundUrl string              `json:"background_url" gorm:"column:background_url"`
+	Fields        []certificate.Field `json:"fields" gorm:"column:fields;serializer:json"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	UpdatedAt *time.Time     `json:"updated_at" gorm:"column:updated_at"`
+	UpdatedBy string         `json:"updated_by" gorm:"column:updated_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
+
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+// Certificate is issued to one participant of a completed event. The printed names are kept as they were issued
+// so a certificate can still be verified after the roster or the event changes.
+type Certificate struct {
+	ID               string    `json:"id" gorm:"column:id;primaryKey"`
+	EventId          string    `json:"event_id" gorm:"column:event_id"`
+	ParticipantId    string    `json:"participant_id" gorm:"column:participant_id"`
+	TemplateId       string    `json:"template_id" gorm:"column:template_id"`
+	VerificationCode string    `json:"verification_code" gorm:"column:verification_code"`
+	ParticipantName  string    `json:"participant_name" gorm:"column:participant_name"`
+	Organization     string    `json:"organization" gorm:"column:organization"`
+	EventTitle       string    `json:"event_title" gorm:"column:event_title"`
+	EventDate        string    `json:"event_date" gorm:"column:event_date"`
+	FileUrl          string    `json:"file_url" gorm:"column:file_url"`
+	IssuedAt         time.Time `json:"issued_at" gorm:"column:issued_at"`
+	IssuedBy         string    `json:"issued_by" gorm:"column:issued_by"`
+
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
+
+func (Batch) TableName() string {
+	return "certificate_batches"
+}
+
+// Batch is the merged PDF of the latest merged generation of an event, generating again replaces it
+type Batch struct {
+	ID               string    `json:"id" gorm:"column:id;primaryKey"`
+	EventId          string    `json:"event_id" gorm:"column:event_id"`
+	TemplateId       string    `json:"template_id" gorm:"column:template_id"`
+	FileUrl          string    `json:"file_url" gorm:"column:file_url"`
+	CertificateCount int       `json:"certificate_count" gorm:"column:certificate_count"`
+	GeneratedAt      time.Time `json:"generated_at" gorm:"column:generated_at"`
+	GeneratedBy      string    `json:"generated_by" gorm:"column:generated_by"`
+
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
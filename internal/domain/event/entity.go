@@ -1,6 +1,7 @@
 package domainevent
 
 import (
+	domaininstructor "safety-riding/internal/domain/instructor"
 	domainpublic "safety-riding/internal/domain/publics"
 	domainschool "safety-riding/internal/domain/school"
 	"time"
@@ -38,11 +39,19 @@ type Event struct {
 	AppsDownloaded           int     `json:"apps_downloaded" gorm:"column:apps_downloaded"`
 	AppsName                 string  `json:"apps_name" gorm:"column:apps_name"`
 
+	// RequestNumber links the event to the approved training request it was created from
+	RequestNumber *int `json:"request_number,omitempty" gorm:"column:request_number"`
+
 	Photos         []EventPhoto         `json:"photos,omitempty" gorm:"foreignKey:EventId;constraint:OnDelete:CASCADE"`
 	OnTheSpotSales []EventOnTheSpotSale `json:"on_the_spot_sales,omitempty" gorm:"foreignKey:EventId;constraint:OnDelete:CASCADE"`
 	School         *domainschool.School `json:"school,omitempty" gorm:"foreignKey:SchoolId;references:ID"`
 	Public         *domainpublic.Public `json:"public,omitempty" gorm:"foreignKey:PublicId;references:ID"`
 
+	// Instructors are the directory instructors of the event, InstructorName and InstructorPhone follow them once assigned
+	Instructors []domaininstructor.EventInstructor `json:"instructors,omitempty" gorm:"foreignKey:EventId"`
+	// ScheduleConflicts lists the overlapping bookings that were accepted when saving the event, it is not stored
+	ScheduleConflicts []domaininstructor.Booking `json:"schedule_conflicts,omitempty" gorm:"-"`
+
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
@@ -61,6 +70,15 @@ type EventPhoto struct {
 	PhotoUrl   string `json:"photo_url" gorm:"column:photo_url"`
 	Caption    string `json:"caption" gorm:"column:caption"`
 	PhotoOrder int    `json:"photo_order" gorm:"column:photo_order"`
+	// IsCover marks the photo shown for the event on the map and the dashboard, an event has at most one
+	IsCover bool `json:"is_cover" gorm:"column:is_cover"`
+
+	// MediumUrl and ThumbnailUrl are smaller renditions of the photo for the map and lists
+	MediumUrl    string `json:"medium_url" gorm:"column:medium_url"`
+	ThumbnailUrl string `json:"thumbnail_url" gorm:"column:thumbnail_url"`
+	// Latitude and Longitude come from the EXIF data of the upload when PHOTO_KEEP_LOCATION is enabled, the stored files never carry them
+	Latitude  *float64 `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude *float64 `json:"longitude,omitempty" gorm:"column:longitude"`
 
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
@@ -83,3 +101,18 @@ type EventOnTheSpotSale struct {
 	UpdatedAt     time.Time `json:"updated_at" gorm:"column:updated_at"`
 	UpdatedBy     string    `json:"updated_by" gorm:"column:updated_by"`
 }
+
+func (EventStatusHistory) TableName() string {
+	return "event_status_history"
+}
+
+// EventStatusHistory records a status transition of an event, FromStatus is empty for the status the event was created with
+type EventStatusHistory struct {
+	ID         string    `json:"id" gorm:"column:id;primaryKey"`
+	EventId    string    `json:"event_id" gorm:"column:event_id"`
+	FromStatus string    `json:"from_status" gorm:"column:from_status"`
+	ToStatus   string    `json:"to_status" gorm:"column:to_status"`
+	Reason     string    `json:"reason" gorm:"column:reason"`
+	ChangedAt  time.Time `json:"changed_at" gorm:"column:changed_at"`
+	ChangedBy  string    `json:"changed_by" gorm:"column:changed_by"`
+}
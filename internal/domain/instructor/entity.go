@@ -0,0 +1,73 @@
+package domaininstructor
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleLead      = "lead"
+	RoleAssistant = "assistant"
+)
+
+func (Instructor) TableName() string {
+	return "instructors"
+}
+
+type Instructor struct {
+	ID             string          `json:"id" gorm:"column:id;primaryKey"`
+	Name           string          `json:"name" gorm:"column:name"`
+	Phone          string          `json:"phone" gorm:"column:phone"`
+	Email          string          `json:"email" gorm:"column:email"`
+	HomeProvinceId string          `json:"home_province_id" gorm:"column:home_province_id"`
+	HomeCityId     string          `json:"home_city_id" gorm:"column:home_city_id"`
+	Certifications []Certification `json:"certifications" gorm:"column:certifications;serializer:json"`
+	IsActive       bool            `json:"is_active" gorm:"column:is_active"`
+	Notes          string          `json:"notes" gorm:"column:notes"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	UpdatedAt *time.Time     `json:"updated_at" gorm:"column:updated_at"`
+	UpdatedBy string         `json:"updated_by" gorm:"column:updated_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
+
+// Certification is a safety riding qualification of an instructor, dates are YYYY-MM-DD
+type Certification struct {
+	Name      string `json:"name" binding:"required,max=150"`
+	Issuer    string `json:"issuer" binding:"omitempty,max=150"`
+	Number    string `json:"number" binding:"omitempty,max=100"`
+	IssuedAt  string `json:"issued_at" binding:"omitempty,datetime=2006-01-02"`
+	ExpiresAt string `json:"expires_at" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (EventInstructor) TableName() string {
+	return "event_instructors"
+}
+
+// EventInstructor assigns an instructor to an event, the first instructor of an event is its lead
+type EventInstructor struct {
+	EventId      string      `json:"event_id" gorm:"column:event_id;primaryKey"`
+	InstructorId string      `json:"instructor_id" gorm:"column:instructor_id;primaryKey"`
+	Role         string      `json:"role" gorm:"column:role"`
+	Instructor   *Instructor `json:"instructor,omitempty" gorm:"foreignKey:InstructorId;references:ID"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string    `json:"created_by" gorm:"column:created_by"`
+}
+
+// Booking is an event an instructor is assigned to
+type Booking struct {
+	InstructorId   string `json:"instructor_id" gorm:"column:instructor_id"`
+	InstructorName string `json:"instructor_name" gorm:"column:instructor_name"`
+	Role           string `json:"role" gorm:"column:role"`
+	EventId        string `json:"event_id" gorm:"column:event_id"`
+	Title          string `json:"title" gorm:"column:title"`
+	EventDate      string `json:"event_date" gorm:"column:event_date"`
+	StartTime      string `json:"start_time" gorm:"column:start_time"`
+	EndTime        string `json:"end_time" gorm:"column:end_time"`
+	Location       string `json:"location" gorm:"column:location"`
+	Status         string `json:"status" gorm:"column:status"`
+}
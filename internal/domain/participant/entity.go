@@ -0,0 +1,45 @@
+package domainparticipant
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+
+	LicenseNone      = "none"
+	LicenseInProcess = "in_process"
+	LicenseLicensed  = "licensed"
+)
+
+func (EventParticipant) TableName() string {
+	return "event_participants"
+}
+
+// EventParticipant is one person on the attendee roster of an event
+type EventParticipant struct {
+	ID            string `json:"id" gorm:"column:id;primaryKey"`
+	EventId       string `json:"event_id" gorm:"column:event_id"`
+	Name          string `json:"name" gorm:"column:name"`
+	Gender        string `json:"gender" gorm:"column:gender"`
+	Age           int    `json:"age" gorm:"column:age"`
+	Grade         string `json:"grade" gorm:"column:grade"`
+	Organization  string `json:"organization" gorm:"column:organization"`
+	Phone         string `json:"phone" gorm:"column:phone"`
+	LicenseStatus string `json:"license_status" gorm:"column:license_status"`
+	IdentityKey   string `json:"-" gorm:"column:identity_key"`
+
+	// CheckedInAt is set when the participant checks in with the event QR code
+	CheckedInAt *time.Time `json:"checked_in_at" gorm:"column:checked_in_at"`
+
+	// EventCount is the number of events the same person joined, including this one. It is only filled when listing a roster.
+	EventCount int `json:"event_count" gorm:"column:event_count;->;-:migration"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	CreatedBy string         `json:"created_by" gorm:"column:created_by"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy string         `json:"-"`
+}
@@ -1,20 +1,29 @@
 package domainsession
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
 
 // Session represents a user login session
 type Session struct {
-	SessionID    string    `json:"session_id"`
-	UserID       string    `json:"user_id"`
-	Email        string    `json:"email"`
-	Role         string    `json:"role"`
-	Token        string    `json:"token"`
-	DeviceInfo   string    `json:"device_info,omitempty"`
-	IP           string    `json:"ip,omitempty"`
-	UserAgent    string    `json:"user_agent,omitempty"`
-	LoginAt      time.Time `json:"login_at"`
-	LastActivity time.Time `json:"last_activity"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	SessionID        string    `json:"session_id"`
+	UserID           string    `json:"user_id"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	Token            string    `json:"token"`
+	RefreshTokenHash string    `json:"refresh_token_hash"`
+	DeviceInfo       string    `json:"device_info,omitempty"`
+	IP               string    `json:"ip,omitempty"`
+	UserAgent        string    `json:"user_agent,omitempty"`
+	LoginAt          time.Time `json:"login_at"`
+	LastActivity     time.Time `json:"last_activity"`
+	ExpiresAt        time.Time `json:"expires_at"`
 }
 
 // SessionInfo represents session information for listing
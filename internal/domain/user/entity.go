@@ -11,14 +11,32 @@ func (Users) TableName() string {
 }
 
 type Users struct {
-	Id        string         `json:"id" gorm:"column:id;primaryKey"`
-	Name      string         `json:"name" gorm:"column:name"`
-	Email     string         `json:"email,omitempty" gorm:"column:email"`
-	Phone     string         `json:"phone,omitempty" gorm:"column:phone"`
-	Password  string         `json:"-" gorm:"column:password"`
-	Role      string         `json:"role,omitempty" gorm:"column:role"`
-	RoleId    *string        `json:"role_id,omitempty" gorm:"column:role_id"`
-	CreatedAt time.Time      `json:"created_at,omitempty" gorm:"column:created_at"`
-	UpdatedAt *time.Time     `json:"updated_at,omitempty" gorm:"column:updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	Id              string         `json:"id" gorm:"column:id;primaryKey"`
+	Name            string         `json:"name" gorm:"column:name"`
+	Email           string         `json:"email,omitempty" gorm:"column:email"`
+	Phone           string         `json:"phone,omitempty" gorm:"column:phone"`
+	Password        string         `json:"-" gorm:"column:password"`
+	Role            string         `json:"role,omitempty" gorm:"column:role"`
+	RoleId          *string        `json:"role_id,omitempty" gorm:"column:role_id"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty" gorm:"column:email_verified_at"`
+	TOTPSecret      string         `json:"-" gorm:"column:totp_secret"`
+	TOTPEnabledAt   *time.Time     `json:"two_factor_enabled_at,omitempty" gorm:"column:totp_enabled_at"`
+	TOTPLastStep    int64          `json:"-" gorm:"column:totp_last_step"`
+	CreatedAt       time.Time      `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt       *time.Time     `json:"updated_at,omitempty" gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (UserRegion) TableName() string {
+	return "user_regions"
+}
+
+// UserRegion limits a user to a province, or to one city of it when CityId is set
+type UserRegion struct {
+	Id         string    `json:"id" gorm:"column:id;primaryKey"`
+	UserId     string    `json:"user_id" gorm:"column:user_id"`
+	ProvinceId string    `json:"province_id" gorm:"column:province_id"`
+	CityId     string    `json:"city_id" gorm:"column:city_id"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	CreatedBy  string    `json:"created_by" gorm:"column:created_by"`
 }
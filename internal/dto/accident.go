@@ -61,3 +61,14 @@ type AddAccidentPhoto struct {
 	Caption    string `json:"caption,omitempty"`
 	PhotoOrder int    `json:"photo_order,omitempty"`
 }
+
+// UpdateAccidentPhoto changes the given fields only, is_cover false removes the cover of the accident
+type UpdateAccidentPhoto struct {
+	Caption *string `json:"caption" binding:"omitempty,max=255"`
+	IsCover *bool   `json:"is_cover"`
+}
+
+// ReorderAccidentPhotos lists every photo of the accident in its new order
+type ReorderAccidentPhotos struct {
+	PhotoIds []string `json:"photo_ids" binding:"required,min=1,dive,uuid"`
+}
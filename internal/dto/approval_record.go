@@ -31,6 +31,7 @@ type ApprovalRecordListItem struct {
 	ActivityName     string     `json:"activity_name"`
 	ParticipantCount int        `json:"participant_count"`
 	LatestStatus     string     `json:"latest_status"`
+	EventId          string     `json:"event_id,omitempty"`
 	SyncedAt         time.Time  `json:"synced_at"`
 }
 
@@ -54,6 +55,7 @@ type ApprovalRecordDetail struct {
 	TrainingType         string                                `json:"training_type"`
 	SheetStatus          string                                `json:"sheet_status"`
 	LatestStatus         string                                `json:"latest_status"`
+	EventId              string                                `json:"event_id,omitempty"`
 	SyncedAt             time.Time                             `json:"synced_at"`
 	Approvals            []domainapprovalrecord.ApprovalRecord `json:"approvals"`
 }
@@ -0,0 +1,86 @@
+package dto
+
+type AssessmentQuestion struct {
+	Question      string   `json:"question" binding:"required,max=1000"`
+	Options       []string `json:"options" binding:"required,min=2,max=6,dive,required,max=500"`
+	CorrectOption int      `json:"correct_option" binding:"min=0"`
+}
+
+type AddQuestionBank struct {
+	Name        string               `json:"name" binding:"required,max=200"`
+	Description string               `json:"description" binding:"omitempty,max=2000"`
+	Questions   []AssessmentQuestion `json:"questions" binding:"required,min=1,max=100,dive"`
+}
+
+// UpdateQuestionBank replaces every question of the bank when Questions is given
+type UpdateQuestionBank struct {
+	Name        string               `json:"name" binding:"omitempty,max=200"`
+	Description string               `json:"description" binding:"omitempty,max=2000"`
+	Questions   []AssessmentQuestion `json:"questions" binding:"omitempty,max=100,dive"`
+}
+
+type AddEventAssessment struct {
+	Type   string `json:"type" binding:"required,oneof=pre post"`
+	BankId string `json:"bank_id" binding:"required,uuid"`
+}
+
+// SubmitAssessment holds the chosen option index per question id, unanswered questions count as wrong
+type SubmitAssessment struct {
+	ParticipantId string         `json:"participant_id" binding:"required,uuid"`
+	Answers       map[string]int `json:"answers" binding:"required"`
+}
+
+// EventAssessmentSummary describes a pre-test or post-test of an event
+type EventAssessmentSummary struct {
+	ID              string  `json:"id"`
+	Type            string  `json:"type"`
+	BankId          string  `json:"bank_id"`
+	BankName        string  `json:"bank_name"`
+	QuestionCount   int64   `json:"question_count"`
+	SubmissionCount int64   `json:"submission_count"`
+	AvgScore        float64 `json:"avg_score"`
+}
+
+// ParticipantAssessmentResult compares the pre-test and post-test score of one participant
+type ParticipantAssessmentResult struct {
+	ParticipantId string   `json:"participant_id"`
+	Name          string   `json:"name"`
+	PreScore      *float64 `json:"pre_score"`
+	PostScore     *float64 `json:"post_score"`
+	Improvement   *float64 `json:"improvement"`
+}
+
+// EventAssessmentResults averages are computed over the participants who took both tests
+type EventAssessmentResults struct {
+	EventId        string                        `json:"event_id"`
+	Assessments    []EventAssessmentSummary      `json:"assessments"`
+	Participants   []ParticipantAssessmentResult `json:"participants"`
+	PairedCount    int                           `json:"paired_count"`
+	AvgPreScore    float64                       `json:"avg_pre_score"`
+	AvgPostScore   float64                       `json:"avg_post_score"`
+	AvgImprovement float64                       `json:"avg_improvement"`
+}
+
+// AssessmentStatsItem is the knowledge improvement of one event, instructor, school or region
+type AssessmentStatsItem struct {
+	GroupId          string  `json:"group_id"`
+	GroupName        string  `json:"group_name"`
+	ProvinceId       string  `json:"province_id,omitempty"`
+	CityId           string  `json:"city_id,omitempty"`
+	EventCount       int64   `json:"event_count"`
+	ParticipantCount int64   `json:"participant_count"`
+	AvgPreScore      float64 `json:"avg_pre_score"`
+	AvgPostScore     float64 `json:"avg_post_score"`
+	AvgImprovement   float64 `json:"avg_improvement"`
+}
+
+// AssessmentStatsResponse only counts participants who took both the pre-test and the post-test of an event
+type AssessmentStatsResponse struct {
+	GroupBy          string                `json:"group_by"`
+	Groups           []AssessmentStatsItem `json:"groups"`
+	EventCount       int64                 `json:"event_count"`
+	ParticipantCount int64                 `json:"participant_count"`
+	AvgPreScore      float64               `json:"avg_pre_score"`
+	AvgPostScore     float64               `json:"avg_post_score"`
+	AvgImprovement   float64               `json:"avg_improvement"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	domaincertificate "safety-riding/internal/domain/certificate"
+	"safety-riding/pkg/certificate"
+)
+
+type AddCertificateTemplate struct {
+	Name        string              `json:"name" binding:"required,max=200"`
+	PageSize    string              `json:"page_size" binding:"omitempty,oneof=A4 Letter"`
+	Orientation string              `json:"orientation" binding:"omitempty,oneof=landscape portrait"`
+	Fields      []certificate.Field `json:"fields" binding:"required,min=1,max=30,dive"`
+}
+
+// UpdateCertificateTemplate replaces every field of the template when Fields is given
+type UpdateCertificateTemplate struct {
+	Name        string              `json:"name" binding:"omitempty,max=200"`
+	PageSize    string              `json:"page_size" binding:"omitempty,oneof=A4 Letter"`
+	Orientation string              `json:"orientation" binding:"omitempty,oneof=landscape portrait"`
+	Fields      []certificate.Field `json:"fields" binding:"omitempty,max=30,dive"`
+}
+
+// GenerateCertificates issues certificates to the roster of a completed event.
+// ParticipantIds limits the run to some participants, Merged renders all of them into a single PDF instead of one file each.
+type GenerateCertificates struct {
+	TemplateId     string   `json:"template_id" binding:"required,uuid"`
+	ParticipantIds []string `json:"participant_ids" binding:"omitempty,max=1000,dive,uuid"`
+	CheckedInOnly  bool     `json:"checked_in_only"`
+	Merged         bool     `json:"merged"`
+}
+
+type GenerateCertificatesResponse struct {
+	EventId      string                          `json:"event_id"`
+	Generated    int                             `json:"generated"`
+	MergedUrl    string                          `json:"merged_url,omitempty"`
+	Certificates []domaincertificate.Certificate `json:"certificates"`
+}
+
+// CertificateVerification is the public answer of the verify endpoint
+type CertificateVerification struct {
+	VerificationCode string    `json:"verification_code"`
+	ParticipantName  string    `json:"participant_name"`
+	Organization     string    `json:"organization"`
+	EventTitle       string    `json:"event_title"`
+	EventDate        string    `json:"event_date"`
+	IssuedAt         time.Time `json:"issued_at"`
+}
@@ -70,6 +70,8 @@ type RecentEvent struct {
 	EventType string `json:"event_type"`
 	EventDate string `json:"event_date"`
 	Location  string `json:"location"`
+	// CoverPhotoUrl is the thumbnail of the cover photo, or of the first photo when none was chosen
+	CoverPhotoUrl string `json:"cover_photo_url,omitempty"`
 }
 
 // RecentAccident represents recent accident summary
@@ -80,6 +82,8 @@ type RecentAccident struct {
 	Location       string `json:"location"`
 	DeathCount     int    `json:"death_count"`
 	InjuredCount   int    `json:"injured_count"`
+	// CoverPhotoUrl is the thumbnail of the cover photo, or of the first photo when none was chosen
+	CoverPhotoUrl string `json:"cover_photo_url,omitempty"`
 }
 
 // AccidentRecommendation represents district accident recommendation
@@ -1,5 +1,10 @@
 package dto
 
+import "time"
+
+// AddEvent links the directory instructors in InstructorIds, the first one leads the event.
+// An instructor booked for an overlapping event is rejected unless AllowScheduleConflict is set.
+// RequestNumber is only set when the event is created from an approved training request.
 type AddEvent struct {
 	SchoolId                 string              `json:"school_id,omitempty"`
 	PublicId                 string              `json:"public_id,omitempty"`
@@ -21,13 +26,18 @@ type AddEvent struct {
 	VisitingServiceProfit    float64             `json:"visiting_service_profit,omitempty"`
 	InstructorName           string              `json:"instructor_name,omitempty"`
 	InstructorPhone          string              `json:"instructor_phone,omitempty"`
+	InstructorIds            []string            `json:"instructor_ids,omitempty" binding:"omitempty,max=10,dive,uuid"`
+	AllowScheduleConflict    bool                `json:"allow_schedule_conflict,omitempty"`
 	Status                   string              `json:"status,omitempty"`
 	Notes                    string              `json:"notes,omitempty"`
 	AppsDownloaded           int                 `json:"apps_downloaded,omitempty"`
 	AppsName                 string              `json:"apps_name,omitempty"`
 	Photos                   []AddEventPhoto     `json:"photos,omitempty"`
+	RequestNumber            int                 `json:"-"`
 }
 
+// UpdateEvent replaces the instructors of the event when InstructorIds is given, an empty list removes them all.
+// A new Status must be an allowed transition, StatusReason is required to cancel the event.
 type UpdateEvent struct {
 	SchoolId                 string              `json:"school_id,omitempty"`
 	PublicId                 string              `json:"public_id,omitempty"`
@@ -49,12 +59,21 @@ type UpdateEvent struct {
 	VisitingServiceProfit    float64             `json:"visiting_service_profit,omitempty"`
 	InstructorName           string              `json:"instructor_name,omitempty"`
 	InstructorPhone          string              `json:"instructor_phone,omitempty"`
+	InstructorIds            []string            `json:"instructor_ids,omitempty" binding:"omitempty,max=10,dive,uuid"`
+	AllowScheduleConflict    bool                `json:"allow_schedule_conflict,omitempty"`
 	Status                   string              `json:"status,omitempty"`
+	StatusReason             string              `json:"status_reason,omitempty" binding:"omitempty,max=500"`
 	Notes                    string              `json:"notes,omitempty"`
 	AppsDownloaded           int                 `json:"apps_downloaded,omitempty"`
 	AppsName                 string              `json:"apps_name,omitempty"`
 }
 
+// TransitionEventStatus moves an event along planned -> ongoing -> completed, or cancels it with a reason
+type TransitionEventStatus struct {
+	Status string `json:"status" binding:"required,oneof=planned ongoing completed cancelled"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
 type OnTheSpotSaleItem struct {
 	VehicleType   string `json:"vehicle_type"`
 	PaymentMethod string `json:"payment_method"`
@@ -67,11 +86,15 @@ type AddEventPhoto struct {
 	PhotoOrder int    `json:"photo_order,omitempty"`
 }
 
+// UpdateEventPhoto changes the given fields only, is_cover false removes the cover of the event
 type UpdateEventPhoto struct {
-	ID         string `json:"id" binding:"required"`
-	PhotoUrl   string `json:"photo_url,omitempty"`
-	Caption    string `json:"caption,omitempty"`
-	PhotoOrder int    `json:"photo_order,omitempty"`
+	Caption *string `json:"caption" binding:"omitempty,max=255"`
+	IsCover *bool   `json:"is_cover"`
+}
+
+// ReorderEventPhotos lists every photo of the event in its new order
+type ReorderEventPhotos struct {
+	PhotoIds []string `json:"photo_ids" binding:"required,min=1,dive,uuid"`
 }
 
 type EventMapData struct {
@@ -86,4 +109,62 @@ type EventMapData struct {
 	Longitude      float64 `json:"longitude"`
 	VenueName      string  `json:"venue_name"`
 	VenueType      string  `json:"venue_type"`
+	// CoverPhotoUrl is the medium rendition of the cover photo, or of the first photo when none was chosen
+	CoverPhotoUrl string `json:"cover_photo_url,omitempty"`
+}
+
+type EventCalendarItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	EventType string `json:"event_type"`
+	Status    string `json:"status"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Location  string `json:"location"`
+	VenueName string `json:"venue_name"`
+}
+
+type EventCalendarDay struct {
+	Date   string              `json:"date"`
+	Events []EventCalendarItem `json:"events"`
+}
+
+type CalendarFeed struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Visit is a completed event held at a school or public entity
+type Visit struct {
+	EventId        string `json:"event_id"`
+	Title          string `json:"title"`
+	EventType      string `json:"event_type"`
+	EventDate      string `json:"event_date"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	AttendeesCount int    `json:"attendees_count"`
+}
+
+// VisitRecomputeResult is the number of schools and publics whose visit statistics were rebuilt
+type VisitRecomputeResult struct {
+	Schools int64 `json:"schools"`
+	Publics int64 `json:"publics"`
+}
+
+// EventRequestDraft is an event pre-filled from an approved training request, it is reviewed and completed before it is created.
+// MissingFields lists the required event fields the request could not fill, the region comes from the matched school or public entity.
+type EventRequestDraft struct {
+	RequestNumber int         `json:"request_number"`
+	Event         AddEvent    `json:"event"`
+	School        *VenueMatch `json:"school,omitempty"`
+	Public        *VenueMatch `json:"public,omitempty"`
+	MissingFields []string    `json:"missing_fields,omitempty"`
+}
+
+// VenueMatch is a school or public entity recognized in a training request, MatchedBy is "name", "address" or "name_and_address"
+type VenueMatch struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	MatchedBy string `json:"matched_by"`
 }
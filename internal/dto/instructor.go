@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"fmt"
+
+	domaininstructor "safety-riding/internal/domain/instructor"
+)
+
+type AddInstructor struct {
+	Name           string                           `json:"name" binding:"required,min=3,max=100"`
+	Phone          string                           `json:"phone" binding:"required,max=20"`
+	Email          string                           `json:"email" binding:"omitempty,email,max=100"`
+	HomeProvinceId string                           `json:"home_province_id" binding:"required,max=20"`
+	HomeCityId     string                           `json:"home_city_id" binding:"omitempty,max=20"`
+	Certifications []domaininstructor.Certification `json:"certifications" binding:"omitempty,max=20,dive"`
+	Notes          string                           `json:"notes,omitempty"`
+}
+
+// UpdateInstructor replaces the certifications of the instructor when Certifications is given, an empty list removes them all
+type UpdateInstructor struct {
+	Name           string                           `json:"name" binding:"omitempty,min=3,max=100"`
+	Phone          string                           `json:"phone" binding:"omitempty,max=20"`
+	Email          string                           `json:"email" binding:"omitempty,email,max=100"`
+	HomeProvinceId string                           `json:"home_province_id" binding:"omitempty,max=20"`
+	HomeCityId     string                           `json:"home_city_id" binding:"omitempty,max=20"`
+	Certifications []domaininstructor.Certification `json:"certifications" binding:"omitempty,max=20,dive"`
+	IsActive       *bool                            `json:"is_active,omitempty"`
+	Notes          string                           `json:"notes,omitempty"`
+}
+
+type InstructorSchedule struct {
+	Instructor domaininstructor.Instructor `json:"instructor"`
+	From       string                      `json:"from"`
+	To         string                      `json:"to"`
+	EventCount int                         `json:"event_count"`
+	TotalHours float64                     `json:"total_hours"`
+	Bookings   []domaininstructor.Booking  `json:"bookings"`
+}
+
+// ScheduleConflictError is returned when an instructor is already booked for an overlapping event
+type ScheduleConflictError struct {
+	Conflicts []domaininstructor.Booking
+}
+
+func (e *ScheduleConflictError) Error() string {
+	if len(e.Conflicts) == 1 {
+		c := e.Conflicts[0]
+		return fmt.Sprintf("instructor %s is already booked for %s on %s %s-%s", c.InstructorName, c.Title, c.EventDate, c.StartTime, c.EndTime)
+	}
+	return fmt.Sprintf("the instructors have %d overlapping bookings", len(e.Conflicts))
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+type AddParticipant struct {
+	Name          string `json:"name" binding:"required,max=150"`
+	Gender        string `json:"gender" binding:"omitempty,oneof=male female"`
+	Age           int    `json:"age" binding:"omitempty,min=1,max=120"`
+	Grade         string `json:"grade" binding:"omitempty,max=50"`
+	Organization  string `json:"organization" binding:"omitempty,max=200"`
+	Phone         string `json:"phone" binding:"omitempty,max=20"`
+	LicenseStatus string `json:"license_status" binding:"omitempty,oneof=none in_process licensed"`
+}
+
+type AddParticipants struct {
+	Participants []AddParticipant `json:"participants" binding:"required,min=1,max=500,dive"`
+}
+
+// ImportParticipantRowResult represents the outcome of a single imported roster row
+type ImportParticipantRowResult struct {
+	Row           int      `json:"row"`
+	Name          string   `json:"name"`
+	Status        string   `json:"status"` // "created" or "rejected"
+	ParticipantId string   `json:"participant_id,omitempty"`
+	Reasons       []string `json:"reasons,omitempty"`
+}
+
+// ImportParticipantResponse represents the validation report of a roster import
+type ImportParticipantResponse struct {
+	DryRun    bool                         `json:"dry_run"`
+	TotalRows int                          `json:"total_rows"`
+	Created   int                          `json:"created"`
+	Rejected  int                          `json:"rejected"`
+	Rows      []ImportParticipantRowResult `json:"rows"`
+}
+
+// CheckIn identifies a participant checking in with the event QR code
+type CheckIn struct {
+	Name         string `json:"name" binding:"required,max=150"`
+	Phone        string `json:"phone" binding:"omitempty,max=20"`
+	Organization string `json:"organization" binding:"omitempty,max=200"`
+}
+
+// CheckInCode is the signed check-in link of an event, shown to participants as a QR code
+type CheckInCode struct {
+	EventId  string    `json:"event_id"`
+	Token    string    `json:"token"`
+	URL      string    `json:"url"`
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+// CheckInEvent is the public event summary shown on the check-in page
+type CheckInEvent struct {
+	Title     string    `json:"title"`
+	EventDate string    `json:"event_date"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+	Location  string    `json:"location"`
+	Open      bool      `json:"open"`
+	OpensAt   time.Time `json:"opens_at"`
+	ClosesAt  time.Time `json:"closes_at"`
+}
+
+type CheckInResult struct {
+	ParticipantId    string    `json:"participant_id"`
+	Name             string    `json:"name"`
+	CheckedInAt      time.Time `json:"checked_in_at"`
+	AlreadyCheckedIn bool      `json:"already_checked_in"`
+}
+
+// EventAttendance is the live check-in count of an event
+type EventAttendance struct {
+	EventId       string     `json:"event_id"`
+	Registered    int64      `json:"registered"`
+	CheckedIn     int64      `json:"checked_in"`
+	NotCheckedIn  int64      `json:"not_checked_in"`
+	LastCheckInAt *time.Time `json:"last_check_in_at"`
+	CheckInOpen   bool       `json:"check_in_open"`
+	ClosesAt      time.Time  `json:"closes_at"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type PhotoUploadFile struct {
+	Filename    string `json:"filename" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/webp"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+// RequestPhotoUploads asks for presigned URLs to upload photos directly to the bucket
+type RequestPhotoUploads struct {
+	Files []PhotoUploadFile `json:"files" binding:"required,min=1,max=20,dive"`
+}
+
+// PhotoUploadSlot is where the client uploads one file with PUT, sending the given headers, before the URL expires
+type PhotoUploadSlot struct {
+	UploadId  string            `json:"upload_id"`
+	Filename  string            `json:"filename"`
+	UploadUrl string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type ConfirmPhotoUpload struct {
+	UploadId   string `json:"upload_id" binding:"required,max=255"`
+	Caption    string `json:"caption,omitempty"`
+	PhotoOrder int    `json:"photo_order,omitempty"`
+}
+
+// ConfirmPhotoUploads turns finished direct uploads into photos of the record
+type ConfirmPhotoUploads struct {
+	Photos []ConfirmPhotoUpload `json:"photos" binding:"required,min=1,max=20,dive"`
+}
@@ -1,7 +1,5 @@
 package dto
 
-import "time"
-
 type AddPublic struct {
 	Name          string  `json:"name" binding:"required,min=3,max=200"`
 	Category      string  `json:"category" binding:"required"`
@@ -18,10 +16,6 @@ type AddPublic struct {
 	Latitude      float64 `json:"latitude,omitempty"`
 	Longitude     float64 `json:"longitude,omitempty"`
 	EmployeeCount int     `json:"employee_count,omitempty"`
-	VisitCount    int     `json:"visit_count,omitempty"`
-	IsEducated    bool    `json:"is_educated,omitempty"`
-
-	LastVisitAt *time.Time `json:"last_visit_at,omitempty"`
 }
 
 type UpdatePublic struct {
@@ -40,10 +34,6 @@ type UpdatePublic struct {
 	Latitude      float64 `json:"latitude,omitempty"`
 	Longitude     float64 `json:"longitude,omitempty"`
 	EmployeeCount int     `json:"employee_count,omitempty"`
-	VisitCount    int     `json:"visit_count,omitempty"`
-	IsEducated    bool    `json:"is_educated,omitempty"`
-
-	LastVisitAt *time.Time `json:"last_visit_at,omitempty"`
 }
 
 // PublicEducationStats represents education statistics for a public entity
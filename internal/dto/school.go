@@ -1,7 +1,5 @@
 package dto
 
-import "time"
-
 type AddSchool struct {
 	Name         string  `json:"name" binding:"required,min=3,max=100"`
 	NPSN         string  `json:"npsn" binding:"required,min=3,max=100"`
@@ -20,10 +18,6 @@ type AddSchool struct {
 	StudentCount int     `json:"student_count,omitempty"`
 	TeacherCount int     `json:"teacher_count,omitempty"`
 	MajorCount   int     `json:"major_count,omitempty"`
-	VisitCount   int     `json:"visit_count,omitempty"`
-	IsEducated   bool    `json:"is_educated,omitempty"`
-
-	LastVisitAt *time.Time `json:"last_visit_at,omitempty"`
 }
 
 type UpdateSchool struct {
@@ -44,10 +38,6 @@ type UpdateSchool struct {
 	StudentCount int     `json:"student_count,omitempty"`
 	TeacherCount int     `json:"teacher_count,omitempty"`
 	MajorCount   int     `json:"major_count,omitempty"`
-	VisitCount   int     `json:"visit_count,omitempty"`
-	IsEducated   bool    `json:"is_educated,omitempty"`
-
-	LastVisitAt *time.Time `json:"last_visit_at,omitempty"`
 }
 
 // SchoolEducationStats represents education statistics for a school
@@ -136,3 +126,23 @@ type SchoolMapItem struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 }
+
+// ImportSchoolRowResult represents the outcome of a single imported row
+type ImportSchoolRowResult struct {
+	Row      int      `json:"row"`
+	NPSN     string   `json:"npsn"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"` // "created", "updated" or "rejected"
+	SchoolId string   `json:"school_id,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// ImportSchoolResponse represents the validation report of a school import
+type ImportSchoolResponse struct {
+	DryRun    bool                    `json:"dry_run"`
+	TotalRows int                     `json:"total_rows"`
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Rejected  int                     `json:"rejected"`
+	Rows      []ImportSchoolRowResult `json:"rows"`
+}
@@ -0,0 +1,95 @@
+package dto
+
+import "time"
+
+// StoredFileRef is a file URL kept by a row, Live is false for rows that were soft-deleted
+type StoredFileRef struct {
+	RowId string
+	Url   string
+	Live  bool
+}
+
+// StorageGCOptions selects what the storage garbage collector reconciles. Objects younger than GracePeriod and the
+// files of rows deleted within it are kept, so uploads in progress and recently deleted photos are never touched
+type StorageGCOptions struct {
+	Folders     []string
+	GracePeriod time.Duration
+	Delete      bool
+}
+
+type OrphanFile struct {
+	ObjectName   string    `json:"object_name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Deleted      bool      `json:"deleted"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// MissingFile is a live row whose stored file is not in the bucket
+type MissingFile struct {
+	Table string `json:"table"`
+	RowId string `json:"row_id"`
+	Url   string `json:"url"`
+}
+
+type StorageGCFolderReport struct {
+	Folder     string `json:"folder"`
+	Objects    int    `json:"objects"`
+	Referenced int    `json:"referenced"`
+	// Skipped explains why the orphans of the folder were not deleted
+	Skipped     string        `json:"skipped,omitempty"`
+	OrphanBytes int64         `json:"orphan_bytes"`
+	Orphans     []OrphanFile  `json:"orphans"`
+	Missing     []MissingFile `json:"missing"`
+}
+
+type StorageGCReport struct {
+	StartedAt  time.Time               `json:"started_at"`
+	FinishedAt time.Time               `json:"finished_at"`
+	Delete     bool                    `json:"delete"`
+	Cutoff     time.Time               `json:"cutoff"`
+	Folders    []StorageGCFolderReport `json:"folders"`
+}
+
+// StoredFileURLs holds the values of the URL columns of a row, keyed by column
+type StoredFileURLs struct {
+	Id   string
+	Urls map[string]string
+}
+
+// StorageMigrationOptions controls a copy of the objects between two storage providers. Objects listed in the
+// state file were copied and verified by an earlier run and are skipped
+type StorageMigrationOptions struct {
+	Prefix    string
+	StateFile string
+	DryRun    bool
+	Rewrite   bool
+	BatchSize int
+	Workers   int
+}
+
+type MigrationFailure struct {
+	ObjectName string `json:"object_name"`
+	Error      string `json:"error"`
+}
+
+// MigrationRewrite counts the rows of a table whose URLs were moved to the target. Pending rows still point to
+// objects that are not copied yet, they are rewritten by the next run
+type MigrationRewrite struct {
+	Table     string `json:"table"`
+	Rows      int    `json:"rows"`
+	Rewritten int    `json:"rewritten"`
+	Pending   int    `json:"pending"`
+}
+
+type StorageMigrationReport struct {
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt time.Time          `json:"finished_at"`
+	DryRun     bool               `json:"dry_run"`
+	Objects    int                `json:"objects"`
+	Copied     int                `json:"copied"`
+	Skipped    int                `json:"skipped"`
+	Bytes      int64              `json:"bytes"`
+	Failed     []MigrationFailure `json:"failed"`
+	Rewrites   []MigrationRewrite `json:"rewrites"`
+}
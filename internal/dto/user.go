@@ -21,6 +21,47 @@ type Login struct {
 	Password string `json:"password" binding:"required,min=8,max=64"`
 }
 
+// LoginPolicy carries the app_configs driven rules that apply to a login attempt
+type LoginPolicy struct {
+	RequireVerifiedEmail   bool
+	TwoFactorRequiredRoles []string
+}
+
+type LoginResult struct {
+	Token                  string   `json:"token,omitempty"`
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"`
+	ChallengeToken         string   `json:"challenge_token,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type UserUpdate struct {
 	Name  string `json:"name" binding:"omitempty,min=3,max=100"`
 	Email string `json:"email" binding:"omitempty,email"`
@@ -37,7 +78,25 @@ type ForgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
 type ResetPasswordRequest struct {
 	Token       string `json:"token" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=8,max=64"`
 }
+
+type UserRegionItem struct {
+	ProvinceId string `json:"province_id" binding:"required,max=20"`
+	CityId     string `json:"city_id" binding:"omitempty,max=20"`
+}
+
+// AssignUserRegions replaces the regions of a user, an empty list lifts the restriction
+type AssignUserRegions struct {
+	Regions []UserRegionItem `json:"regions" binding:"dive"`
+}
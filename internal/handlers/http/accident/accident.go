@@ -7,10 +7,13 @@ import (
 	"reflect"
 	"safety-riding/internal/dto"
 	interfaceaccident "safety-riding/internal/interfaces/accident"
+	"safety-riding/pkg/export"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
 	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
 	"safety-riding/utils"
 
 	"github.com/gin-gonic/gin"
@@ -55,9 +58,16 @@ func (h *AccidentHandler) AddAccident(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.AddAccident(username, req)
+	data, err := h.Service.AddAccident(username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddAccident; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -90,7 +100,7 @@ func (h *AccidentHandler) GetAccidentById(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetAccidentById(accidentId)
+	data, err := h.Service.GetAccidentById(accidentId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetAccidentById; Error: %+v", logPrefix, err))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -145,9 +155,16 @@ func (h *AccidentHandler) UpdateAccident(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.UpdateAccident(accidentId, username, req)
+	data, err := h.Service.UpdateAccident(accidentId, username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateAccident; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -185,6 +202,7 @@ func (h *AccidentHandler) FetchAccident(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][AccidentHandler][FetchAccident]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "accident_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id", "accident_type", "vehicle_type", "police_station"})
 
 	accidents, totalData, err := h.Service.FetchAccident(params)
@@ -208,6 +226,58 @@ func (h *AccidentHandler) FetchAccident(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// ExportAccident godoc
+// @Summary Export accidents
+// @Description Download every accident matching the search and filters as CSV or XLSX
+// @Tags Accidents
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format (csv/xlsx)"
+// @Param columns query []string false "Columns to export, defaults to all"
+// @Param search query string false "Search keyword"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param district_id query string false "Filter by district ID"
+// @Param city_id query string false "Filter by city ID"
+// @Param province_id query string false "Filter by province ID"
+// @Param accident_type query string false "Filter by accident type"
+// @Param vehicle_type query string false "Filter by vehicle type"
+// @Param police_station query string false "Filter by police station"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /accidents/export [get]
+func (h *AccidentHandler) ExportAccident(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AccidentHandler][ExportAccident]", logId)
+
+	format, err := export.Format(ctx)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Format; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "accident_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id", "accident_type", "vehicle_type", "police_station"})
+
+	total, err := export.Stream(ctx, "accidents", format, params, h.Service.FetchAccident)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; export.Stream; Error: %+v", logPrefix, err))
+		if !ctx.Writer.Written() {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+		}
+		return
+	}
+
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: exported %d rows as %s;", logPrefix, total, format))
+}
+
 // DeleteAccident godoc
 // @Summary Delete an accident
 // @Description Delete accident by ID
@@ -234,7 +304,7 @@ func (h *AccidentHandler) DeleteAccident(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteAccident(id, username); err != nil {
+	if err := h.Service.DeleteAccident(id, username, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteAccident; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -300,9 +370,22 @@ func (h *AccidentHandler) AddAccidentPhotos(ctx *gin.Context) {
 	photoOrders := form.Value["photo_orders"]
 
 	// Call service to upload photos to MinIO and save to database
-	data, err := h.Service.AddAccidentPhotosFromFiles(ctx, accidentId, username, files, captions, photoOrders)
+	data, err := h.Service.AddAccidentPhotosFromFiles(ctx, accidentId, username, filter.GetRegionScope(ctx), files, captions, photoOrders)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddAccidentPhotosFromFiles; Error: %+v", logPrefix, err))
+		if errors.Is(err, storage.ErrUnavailable) {
+			res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		if errors.Is(err, imaging.ErrNotImage) {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -340,7 +423,7 @@ func (h *AccidentHandler) DeleteAccidentPhoto(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteAccidentPhoto(photoId, username); err != nil {
+	if err := h.Service.DeleteAccidentPhoto(photoId, username, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteAccidentPhoto; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -0,0 +1,223 @@
+package handleraccident
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UpdateAccidentPhoto godoc
+// @Summary Update accident photo
+// @Description Change the caption of an accident photo or make it the cover shown on the dashboard, is_cover false removes the cover
+// @Tags Accidents
+// @Accept json
+// @Produce json
+// @Param photoId path string true "Photo ID"
+// @Param photo body dto.UpdateAccidentPhoto true "Photo changes"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /accident/photo/{photoId} [put]
+func (h *AccidentHandler) UpdateAccidentPhoto(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AccidentHandler][UpdateAccidentPhoto]", logId)
+
+	photoId := ctx.Param("photoId")
+	if photoId == "" {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Missing photo ID", logPrefix))
+		res := response.Response(http.StatusBadRequest, "Photo ID is required", logId, nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	var req dto.UpdateAccidentPhoto
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.UpdateAccidentPhoto(photoId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateAccidentPhoto; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "accident photo not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Update accident photo successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, photoId))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ReorderAccidentPhotos godoc
+// @Summary Reorder accident photos
+// @Description Set the order of the accident photos, photo_ids lists every photo of the accident and they are numbered 1..n in that order
+// @Tags Accidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Accident ID"
+// @Param order body dto.ReorderAccidentPhotos true "Photo IDs in their new order"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /accident/{id}/photos/order [put]
+func (h *AccidentHandler) ReorderAccidentPhotos(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AccidentHandler][ReorderAccidentPhotos]", logId)
+
+	accidentId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.ReorderAccidentPhotos
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.ReorderAccidentPhotos(accidentId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ReorderAccidentPhotos; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "accident data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Reorder accident photos successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d photos;", logPrefix, len(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RequestAccidentPhotoUploads godoc
+// @Summary Request direct photo uploads
+// @Description Reserve presigned URLs to upload accident photos directly to the bucket. Upload each file with the given method and headers before expires_at, then confirm the uploads
+// @Tags Accidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Accident ID"
+// @Param files body dto.RequestPhotoUploads true "Files to upload"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 503 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /accident/{id}/photos/uploads [post]
+func (h *AccidentHandler) RequestAccidentPhotoUploads(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AccidentHandler][RequestAccidentPhotoUploads]", logId)
+
+	accidentId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.RequestPhotoUploads
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.RequestAccidentPhotoUploads(ctx, accidentId, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RequestAccidentPhotoUploads; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "accident data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d upload slots;", logPrefix, len(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ConfirmAccidentPhotoUploads godoc
+// @Summary Confirm direct photo uploads
+// @Description Create the accident photos of finished direct uploads. Each upload is checked for its size and content, stored without EXIF data with its renditions and removed from the upload area
+// @Tags Accidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Accident ID"
+// @Param photos body dto.ConfirmPhotoUploads true "Finished uploads"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 503 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /accident/{id}/photos/uploads/confirm [post]
+func (h *AccidentHandler) ConfirmAccidentPhotoUploads(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AccidentHandler][ConfirmAccidentPhotoUploads]", logId)
+
+	accidentId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.ConfirmPhotoUploads
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.ConfirmAccidentPhotoUploads(ctx, accidentId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ConfirmAccidentPhotoUploads; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "accident data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add accident photos successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: confirmed %d photos;", logPrefix, len(data)))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// photoError answers 404 when the photo or its record does not exist, 503 without file storage and 400 otherwise
+func photoError(ctx *gin.Context, logId uuid.UUID, notFound string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = notFound
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
@@ -0,0 +1,336 @@
+package handlerassessment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	domainassessment "safety-riding/internal/domain/assessment"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AddEventAssessment godoc
+// @Summary Attach an assessment to an event
+// @Description Use a question bank as the pre-test or post-test of the event
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param assessment body dto.AddEventAssessment true "Assessment payload"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments [post]
+func (h *AssessmentHandler) AddEventAssessment(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][AddEventAssessment]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.AddEventAssessment
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.AddEventAssessment(eventId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddEventAssessment; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add event assessment successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// GetEventResults godoc
+// @Summary Get the assessment results of an event
+// @Description Retrieve the pre-test and post-test of the event with the score and improvement of every participant
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments [get]
+func (h *AssessmentHandler) GetEventResults(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][GetEventResults]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetEventResults(eventId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetEventResults; Error: %+v", logPrefix, err))
+		h.readError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetEventAssessment godoc
+// @Summary Get an event assessment
+// @Description Retrieve the pre-test or post-test of the event with its questions and answer key
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param type path string true "pre or post"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments/{type} [get]
+func (h *AssessmentHandler) GetEventAssessment(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][GetEventAssessment]", logId)
+
+	eventId, assessmentType, ok := h.assessmentParams(ctx, logId)
+	if !ok {
+		return
+	}
+
+	data, err := h.Service.GetEventAssessment(eventId, assessmentType, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetEventAssessment; Error: %+v", logPrefix, err))
+		h.readError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteEventAssessment godoc
+// @Summary Remove an event assessment
+// @Description Remove the pre-test or post-test of the event, only while it has no submissions
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param type path string true "pre or post"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments/{type} [delete]
+func (h *AssessmentHandler) DeleteEventAssessment(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][DeleteEventAssessment]", logId)
+
+	eventId, assessmentType, ok := h.assessmentParams(ctx, logId)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteEventAssessment(eventId, assessmentType, username, filter.GetRegionScope(ctx)); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteEventAssessment; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Delete event assessment successfully", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// SubmitAssessment godoc
+// @Summary Submit an answer sheet
+// @Description Score the answers of a participant on the event roster. Each participant answers the pre-test and the post-test once
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param type path string true "pre or post"
+// @Param submission body dto.SubmitAssessment true "Answers per question id"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments/{type}/submissions [post]
+func (h *AssessmentHandler) SubmitAssessment(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][SubmitAssessment]", logId)
+
+	eventId, assessmentType, ok := h.assessmentParams(ctx, logId)
+	if !ok {
+		return
+	}
+
+	var req dto.SubmitAssessment
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.SubmitAssessment(eventId, assessmentType, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.SubmitAssessment; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Submit assessment successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s scored %.2f;", logPrefix, data.ParticipantId, data.Score))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// FetchSubmissions godoc
+// @Summary List the submissions of an event assessment
+// @Description Retrieve the scored answer sheets of the pre-test or post-test
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param type path string true "pre or post"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by participant name"
+// @Param sort query string false "Sort field (participant_name, score, submitted_at)"
+// @Param order query string false "Sort order (asc/desc)"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/assessments/{type}/submissions [get]
+func (h *AssessmentHandler) FetchSubmissions(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][FetchSubmissions]", logId)
+
+	eventId, assessmentType, ok := h.assessmentParams(ctx, logId)
+	if !ok {
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "participant_name", "asc", 50)
+	params.Scope = filter.GetRegionScope(ctx)
+
+	submissions, totalData, err := h.Service.FetchSubmissions(eventId, assessmentType, params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchSubmissions; Error: %+v", logPrefix, err))
+		h.readError(ctx, logId, err)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, submissions)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetAssessmentStats godoc
+// @Summary Get safety knowledge improvement statistics
+// @Description Average pre-test and post-test scores and their improvement, counting participants who took both tests of an event
+// @Tags Schools
+// @Accept json
+// @Produce json
+// @Param group_by query string false "event (default), instructor, school or region"
+// @Param district_id query string false "Filter by district ID"
+// @Param city_id query string false "Filter by city ID"
+// @Param province_id query string false "Filter by province ID"
+// @Param school_id query string false "Filter by school ID"
+// @Param event_type query string false "Filter by event type"
+// @Param month query string false "Filter by event month"
+// @Param year query string false "Filter by event year"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /education/assessment-stats [get]
+func (h *AssessmentHandler) GetAssessmentStats(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][GetAssessmentStats]", logId)
+
+	groupBy := ctx.DefaultQuery("group_by", domainassessment.GroupByEvent)
+	switch groupBy {
+	case domainassessment.GroupByEvent, domainassessment.GroupByInstructor, domainassessment.GroupBySchool, domainassessment.GroupByRegion:
+	default:
+		res := response.Response(http.StatusBadRequest, "group_by must be one of event, instructor, school or region", logId, nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "avg_improvement", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id", "school_id", "event_type", "month", "year"})
+
+	data, err := h.Service.GetAssessmentStats(groupBy, params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetAssessmentStats; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Get assessment statistics successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: groups=%d, participants=%d, avg improvement=%.2f", logPrefix, len(data.Groups), data.ParticipantCount, data.AvgImprovement))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// assessmentParams validates the event id and the assessment type of the path
+func (h *AssessmentHandler) assessmentParams(ctx *gin.Context, logId uuid.UUID) (string, string, bool) {
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return "", "", false
+	}
+
+	assessmentType := ctx.Param("type")
+	if assessmentType != domainassessment.TypePre && assessmentType != domainassessment.TypePost {
+		res := response.Response(http.StatusBadRequest, http.StatusText(http.StatusBadRequest), logId, nil)
+		res.Error = response.Errors{Code: http.StatusBadRequest, Message: "type must be pre or post"}
+		ctx.JSON(http.StatusBadRequest, res)
+		return "", "", false
+	}
+
+	return eventId, assessmentType, true
+}
+
+func (h *AssessmentHandler) readError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = "Event or assessment not found"
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusInternalServerError, res)
+}
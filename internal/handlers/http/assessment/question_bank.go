@@ -0,0 +1,237 @@
+package handlerassessment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	interfaceassessment "safety-riding/internal/interfaces/assessment"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AssessmentHandler struct {
+	Service interfaceassessment.ServiceAssessmentInterface
+}
+
+func NewAssessmentHandler(s interfaceassessment.ServiceAssessmentInterface) *AssessmentHandler {
+	return &AssessmentHandler{
+		Service: s,
+	}
+}
+
+// AddQuestionBank godoc
+// @Summary Create a question bank
+// @Description Create a set of multiple choice safety knowledge questions. correct_option is the zero based index of the correct option
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param bank body dto.AddQuestionBank true "Question bank payload"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /question-bank [post]
+func (h *AssessmentHandler) AddQuestionBank(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][AddQuestionBank]", logId)
+
+	var req dto.AddQuestionBank
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.AddQuestionBank(username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddQuestionBank; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add question bank successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// GetQuestionBankById godoc
+// @Summary Get question bank detail
+// @Description Retrieve a question bank with its questions
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Question bank ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /question-bank/{id} [get]
+func (h *AssessmentHandler) GetQuestionBankById(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][GetQuestionBankById]", logId)
+
+	bankId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetQuestionBankById(bankId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetQuestionBankById; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Question bank not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// UpdateQuestionBank godoc
+// @Summary Update a question bank
+// @Description Update a question bank. Sending questions replaces all of them, which is refused once the bank has submissions
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Question bank ID"
+// @Param bank body dto.UpdateQuestionBank true "Question bank payload"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /question-bank/{id} [put]
+func (h *AssessmentHandler) UpdateQuestionBank(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][UpdateQuestionBank]", logId)
+
+	bankId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.UpdateQuestionBank
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.UpdateQuestionBank(bankId, username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateQuestionBank; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Update question bank successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchQuestionBank godoc
+// @Summary List question banks
+// @Description Retrieve paginated question banks with their number of questions
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by name"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Success 200 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /question-banks [get]
+func (h *AssessmentHandler) FetchQuestionBank(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][FetchQuestionBank]", logId)
+
+	params, _ := filter.GetBaseParams(ctx, "name", "asc", 10)
+
+	banks, totalData, err := h.Service.FetchQuestionBank(params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchQuestionBank; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, banks)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteQuestionBank godoc
+// @Summary Delete a question bank
+// @Description Delete a question bank that is not used by any event assessment
+// @Tags Assessments
+// @Accept json
+// @Produce json
+// @Param id path string true "Question bank ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /question-bank/{id} [delete]
+func (h *AssessmentHandler) DeleteQuestionBank(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AssessmentHandler][DeleteQuestionBank]", logId)
+
+	bankId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	if err := h.Service.DeleteQuestionBank(bankId, username); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteQuestionBank; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Delete question bank successfully", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// serviceError answers 404 when a record does not exist and 400 for rejected changes
+func (h *AssessmentHandler) serviceError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
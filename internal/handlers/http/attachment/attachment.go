@@ -0,0 +1,193 @@
+package handlerattachment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	domainattachment "safety-riding/internal/domain/attachment"
+	interfaceattachment "safety-riding/internal/interfaces/attachment"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentHandler serves the attachments of events, accidents and budgets. The routes are registered below
+// their parent resource, so each handler is built for one entity type and the parent permission applies
+type AttachmentHandler struct {
+	Service interfaceattachment.ServiceAttachmentInterface
+}
+
+func NewAttachmentHandler(s interfaceattachment.ServiceAttachmentInterface) *AttachmentHandler {
+	return &AttachmentHandler{
+		Service: s,
+	}
+}
+
+// FetchAttachments godoc
+// @Summary List the attachments of an event, accident or budget
+// @Description Retrieve the documents attached to the resource, newest first, each file_url is a download link that expires after ATTACHMENT_URL_EXPIRY_SECONDS
+// @Tags Attachments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event, accident or budget ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/attachments [get]
+// @Router /accident/{id}/attachments [get]
+// @Router /budget/{id}/attachments [get]
+func (h *AttachmentHandler) FetchAttachments(entityType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		logId := utils.GenerateLogId(ctx)
+		logPrefix := fmt.Sprintf("[%s][AttachmentHandler][FetchAttachments]", logId)
+
+		entityId, err := utils.ValidateUUID(ctx, logId)
+		if err != nil {
+			return
+		}
+
+		data, err := h.Service.FetchAttachments(ctx, entityType, entityId, filter.GetRegionScope(ctx))
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchAttachments; Error: %+v", logPrefix, err))
+			serviceError(ctx, logId, entityType, err)
+			return
+		}
+
+		res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+// AddAttachments godoc
+// @Summary Attach documents to an event, accident or budget
+// @Description Upload PDF, JPEG or PNG documents such as police reports, signed attendance sheets, invoices or permission letters. The type is checked from the content and each file is limited to ATTACHMENT_MAX_SIZE_MB
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Event, accident or budget ID"
+// @Param files formData file true "Documents"
+// @Param descriptions formData string false "Description (repeat per file)"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 413 {object} response.Error
+// @Failure 503 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/attachments [post]
+// @Router /accident/{id}/attachments [post]
+// @Router /budget/{id}/attachments [post]
+func (h *AttachmentHandler) AddAttachments(entityType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authData := utils.GetAuthData(ctx)
+		username := utils.InterfaceString(authData["username"])
+		logId := utils.GenerateLogId(ctx)
+		logPrefix := fmt.Sprintf("[%s][AttachmentHandler][AddAttachments]", logId)
+
+		entityId, err := utils.ValidateUUID(ctx, logId)
+		if err != nil {
+			return
+		}
+
+		form, err := ctx.MultipartForm()
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; MultipartForm ERROR: %s;", logPrefix, err.Error()))
+			res := response.Response(http.StatusBadRequest, "Failed to parse form data", logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+
+		data, err := h.Service.AddAttachments(ctx, entityType, entityId, username, filter.GetRegionScope(ctx), form.File["files"], form.Value["descriptions"])
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddAttachments; Error: %+v", logPrefix, err))
+			serviceError(ctx, logId, entityType, err)
+			return
+		}
+
+		res := response.Response(http.StatusCreated, "Add attachments successfully", logId, data)
+		logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d attachments added to %s %s;", logPrefix, len(data), entityType, entityId))
+		ctx.JSON(http.StatusCreated, res)
+	}
+}
+
+// DeleteAttachment godoc
+// @Summary Delete an attachment of an event, accident or budget
+// @Description Remove a document from the resource and delete its file
+// @Tags Attachments
+// @Accept json
+// @Produce json
+// @Param id path string true "Event, accident or budget ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/attachments/{attachmentId} [delete]
+// @Router /accident/{id}/attachments/{attachmentId} [delete]
+// @Router /budget/{id}/attachments/{attachmentId} [delete]
+func (h *AttachmentHandler) DeleteAttachment(entityType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authData := utils.GetAuthData(ctx)
+		username := utils.InterfaceString(authData["username"])
+		logId := utils.GenerateLogId(ctx)
+		logPrefix := fmt.Sprintf("[%s][AttachmentHandler][DeleteAttachment]", logId)
+
+		entityId, err := utils.ValidateUUID(ctx, logId)
+		if err != nil {
+			return
+		}
+
+		attachmentId := ctx.Param("attachmentId")
+		if _, err := uuid.Parse(attachmentId); err != nil {
+			res := response.Response(http.StatusBadRequest, http.StatusText(http.StatusBadRequest), logId, nil)
+			res.Error = response.Errors{Code: http.StatusBadRequest, Message: "attachmentId must be a valid UUID"}
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+
+		if err := h.Service.DeleteAttachment(ctx, entityType, entityId, attachmentId, username, filter.GetRegionScope(ctx)); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteAttachment; Error: %+v", logPrefix, err))
+			serviceError(ctx, logId, entityType, err)
+			return
+		}
+
+		res := response.Response(http.StatusOK, "Delete attachment successfully", logId, nil)
+		logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: attachment %s removed from %s %s;", logPrefix, attachmentId, entityType, entityId))
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+// serviceError answers 404 when the resource or attachment does not exist, 413 for files above the size limit,
+// 503 without storage and 400 for rejected uploads
+func serviceError(ctx *gin.Context, logId uuid.UUID, entityType string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = fmt.Sprintf("%s or attachment not found", entityType)
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	if errors.Is(err, domainattachment.ErrFileTooLarge) {
+		res := response.Response(http.StatusRequestEntityTooLarge, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusRequestEntityTooLarge, res)
+		return
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
@@ -0,0 +1,118 @@
+package handlerauditlog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AuditLogHandler struct {
+	Service interfaceauditlog.ServiceAuditLogInterface
+}
+
+func NewAuditLogHandler(s interfaceauditlog.ServiceAuditLogInterface) *AuditLogHandler {
+	return &AuditLogHandler{
+		Service: s,
+	}
+}
+
+// GetAuditLogById godoc
+// @Summary Get audit log detail
+// @Description Retrieve an audit log entry with its before/after snapshots and diff
+// @Tags AuditLog
+// @Accept json
+// @Produce json
+// @Param id path string true "Audit log ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /audit-log/{id} [get]
+func (h *AuditLogHandler) GetAuditLogById(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AuditLogHandler][GetAuditLogById]", logId)
+
+	auditLogId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetAuditLogById(auditLogId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetAuditLogById; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "audit log not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Get audit log successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v", logPrefix, utils.JsonEncode(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchAuditLog godoc
+// @Summary List audit logs
+// @Description Retrieve audit log entries with pagination and filters
+// @Tags AuditLog
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param search query string false "Search keyword"
+// @Param entity_type query string false "Filter by entity type"
+// @Param entity_id query string false "Filter by entity ID"
+// @Param actor query string false "Filter by actor username"
+// @Param action query string false "Filter by action"
+// @Param date_from query string false "Filter from date (YYYY-MM-DD)"
+// @Param date_to query string false "Filter until date (YYYY-MM-DD)"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /audit-logs [get]
+func (h *AuditLogHandler) FetchAuditLog(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][AuditLogHandler][FetchAuditLog]", logId)
+
+	params, _ := filter.GetBaseParams(ctx, "created_at", "desc", 10)
+	params.Filters = filter.WhitelistFilter(params.Filters, []string{"entity_type", "entity_id", "actor", "action", "date_from", "date_to"})
+
+	auditLogs, totalData, err := h.Service.FetchAuditLog(params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Fetch; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "List audit log not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, auditLogs)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v;", logPrefix, utils.JsonEncode(auditLogs)))
+	ctx.JSON(http.StatusOK, res)
+}
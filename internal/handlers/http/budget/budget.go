@@ -8,6 +8,7 @@ import (
 	"safety-riding/internal/dto"
 	interfacebudget "safety-riding/internal/interfaces/budget"
 	interfacepermission "safety-riding/internal/interfaces/permission"
+	"safety-riding/pkg/export"
 	"safety-riding/pkg/filter"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
@@ -59,9 +60,16 @@ func (h *BudgetHandler) AddBudget(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.AddBudget(username, req)
+	data, err := h.Service.AddBudget(username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddBudget; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -94,7 +102,7 @@ func (h *BudgetHandler) GetBudgetById(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetBudgetById(budgetId)
+	data, err := h.Service.GetBudgetById(budgetId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetBudgetById; Error: %+v", logPrefix, err))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -159,9 +167,16 @@ func (h *BudgetHandler) UpdateBudget(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.UpdateBudget(budgetId, username, canOverrideFinalized, req)
+	data, err := h.Service.UpdateBudget(budgetId, username, canOverrideFinalized, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateBudget; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -198,6 +213,7 @@ func (h *BudgetHandler) FetchBudget(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][BudgetHandler][FetchBudget]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "budget_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistFilter(params.Filters, []string{"event_id", "budget_month", "budget_year", "category", "status"})
 
 	budgets, totalData, err := h.Service.FetchBudget(params)
@@ -221,6 +237,57 @@ func (h *BudgetHandler) FetchBudget(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// ExportBudget godoc
+// @Summary Export budgets
+// @Description Download every budget matching the search and filters as CSV or XLSX
+// @Tags Budgets
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format (csv/xlsx)"
+// @Param columns query []string false "Columns to export, defaults to all"
+// @Param search query string false "Search keyword"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param event_id query string false "Filter by event ID"
+// @Param budget_month query int false "Filter by budget month"
+// @Param budget_year query int false "Filter by budget year"
+// @Param category query string false "Filter by category"
+// @Param status query string false "Filter by status"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /budgets/export [get]
+func (h *BudgetHandler) ExportBudget(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][BudgetHandler][ExportBudget]", logId)
+
+	format, err := export.Format(ctx)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Format; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "budget_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistFilter(params.Filters, []string{"event_id", "budget_month", "budget_year", "category", "status"})
+
+	total, err := export.Stream(ctx, "budgets", format, params, h.Service.FetchBudget)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; export.Stream; Error: %+v", logPrefix, err))
+		if !ctx.Writer.Written() {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+		}
+		return
+	}
+
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: exported %d rows as %s;", logPrefix, total, format))
+}
+
 // DeleteBudget godoc
 // @Summary Delete a budget entry
 // @Description Delete budget by ID
@@ -257,7 +324,7 @@ func (h *BudgetHandler) DeleteBudget(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteBudget(id, username, canOverrideFinalized); err != nil {
+	if err := h.Service.DeleteBudget(id, username, canOverrideFinalized, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteBudget; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -309,7 +376,7 @@ func (h *BudgetHandler) GetBudgetsByEvent(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetBudgetsByEvent(eventId)
+	data, err := h.Service.GetBudgetsByEvent(eventId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetBudgetsByEvent; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -363,7 +430,7 @@ func (h *BudgetHandler) GetBudgetsByMonthYear(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetBudgetsByMonthYear(month, year)
+	data, err := h.Service.GetBudgetsByMonthYear(month, year, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetBudgetsByMonthYear; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -417,7 +484,7 @@ func (h *BudgetHandler) GetMonthlySummary(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetMonthlySummary(month, year)
+	data, err := h.Service.GetMonthlySummary(month, year, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetMonthlySummary; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -462,7 +529,7 @@ func (h *BudgetHandler) GetYearlySummary(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetYearlySummary(year)
+	data, err := h.Service.GetYearlySummary(year, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetYearlySummary; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -499,7 +566,7 @@ func (h *BudgetHandler) GetEventSummary(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetEventSummary(eventId)
+	data, err := h.Service.GetEventSummary(eventId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetEventSummary; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
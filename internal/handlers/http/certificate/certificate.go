@@ -0,0 +1,187 @@
+package handlercertificate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GenerateCertificates godoc
+// @Summary Generate participation certificates
+// @Description Render the certificates of a completed event as one PDF per participant, or as a single merged PDF when merged is true. Participants keep their verification code when certificates are generated again
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param certificates body dto.GenerateCertificates true "Generation options"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/certificates [post]
+func (h *CertificateHandler) GenerateCertificates(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][GenerateCertificates]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.GenerateCertificates
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.GenerateCertificates(ctx, eventId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GenerateCertificates; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Generate certificates successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d certificates; merged=%t;", logPrefix, data.Generated, req.Merged))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchCertificates godoc
+// @Summary List event certificates
+// @Description Retrieve the certificates issued for an event
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by participant name or verification code"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/certificates [get]
+func (h *CertificateHandler) FetchCertificates(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][FetchCertificates]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "participant_name", "asc", 50)
+	params.Scope = filter.GetRegionScope(ctx)
+
+	certificates, totalData, err := h.Service.FetchCertificates(eventId, params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchCertificates; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Event not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, certificates)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetMergedCertificates godoc
+// @Summary Get the merged certificates of an event
+// @Description Retrieve the single PDF of the latest merged generation, it is replaced when certificates are merged again
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/certificates/merged [get]
+func (h *CertificateHandler) GetMergedCertificates(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][GetMergedCertificates]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetMergedCertificates(eventId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetMergedCertificates; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "No merged certificates for this event"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// VerifyCertificate godoc
+// @Summary Verify a certificate
+// @Description Public endpoint that checks the verification code printed on a certificate
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 429 {object} response.Error
+// @Router /certificates/verify/{code} [get]
+func (h *CertificateHandler) VerifyCertificate(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][VerifyCertificate]", logId)
+
+	data, err := h.Service.VerifyCertificate(ctx.Param("code"))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.VerifyCertificate; Error: %+v", logPrefix, err))
+		status, message := http.StatusInternalServerError, messages.MsgFail
+		if err.Error() == messages.ErrInvalidCertificate {
+			status, message = http.StatusNotFound, messages.NotFound
+		}
+
+		res := response.Response(status, message, logId, nil)
+		res.Error = response.Errors{Code: status, Message: err.Error()}
+		ctx.JSON(status, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Certificate is valid", logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
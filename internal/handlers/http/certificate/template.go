@@ -0,0 +1,290 @@
+package handlercertificate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	interfacecertificate "safety-riding/internal/interfaces/certificate"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CertificateHandler struct {
+	Service interfacecertificate.ServiceCertificateInterface
+}
+
+func NewCertificateHandler(s interfacecertificate.ServiceCertificateInterface) *CertificateHandler {
+	return &CertificateHandler{
+		Service: s,
+	}
+}
+
+// AddTemplate godoc
+// @Summary Create a certificate template
+// @Description Create a certificate layout. Field texts may contain placeholders such as {{participant_name}}, {{event_title}}, {{event_date}} and {{verification_code}}, positions are in millimetres
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param template body dto.AddCertificateTemplate true "Certificate template payload"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-template [post]
+func (h *CertificateHandler) AddTemplate(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][AddTemplate]", logId)
+
+	var req dto.AddCertificateTemplate
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.AddTemplate(username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddTemplate; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add certificate template successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// GetTemplateById godoc
+// @Summary Get certificate template detail
+// @Description Retrieve a certificate template with its fields
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Certificate template ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-template/{id} [get]
+func (h *CertificateHandler) GetTemplateById(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][GetTemplateById]", logId)
+
+	templateId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetTemplateById(templateId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetTemplateById; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Certificate template not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// UpdateTemplate godoc
+// @Summary Update a certificate template
+// @Description Update a certificate template. Sending fields replaces all of them, certificates already issued keep their PDF
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Certificate template ID"
+// @Param template body dto.UpdateCertificateTemplate true "Certificate template payload"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-template/{id} [put]
+func (h *CertificateHandler) UpdateTemplate(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][UpdateTemplate]", logId)
+
+	templateId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.UpdateCertificateTemplate
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.UpdateTemplate(templateId, username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateTemplate; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Update certificate template successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// UploadTemplateBackground godoc
+// @Summary Upload a certificate background
+// @Description Upload the JPEG or PNG image printed behind the fields, it is stretched to the whole page and replaces the previous background
+// @Tags Certificates
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Certificate template ID"
+// @Param file formData file true "JPEG or PNG image"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-template/{id}/background [post]
+func (h *CertificateHandler) UploadTemplateBackground(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][UploadTemplateBackground]", logId)
+
+	templateId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; FormFile ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, "File is required", logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: template=%s; file=%s; size=%d;", logPrefix, templateId, fileHeader.Filename, fileHeader.Size))
+
+	data, err := h.Service.UploadTemplateBackground(ctx, templateId, username, fileHeader)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UploadTemplateBackground; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Upload certificate background successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.BackgroundUrl))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchTemplate godoc
+// @Summary List certificate templates
+// @Description Retrieve paginated certificate templates
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by name"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Success 200 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-templates [get]
+func (h *CertificateHandler) FetchTemplate(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][FetchTemplate]", logId)
+
+	params, _ := filter.GetBaseParams(ctx, "name", "asc", 10)
+
+	templates, totalData, err := h.Service.FetchTemplate(params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchTemplate; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, templates)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a certificate template
+// @Description Delete a certificate template, certificates already issued stay valid
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Certificate template ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /certificate-template/{id} [delete]
+func (h *CertificateHandler) DeleteTemplate(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][CertificateHandler][DeleteTemplate]", logId)
+
+	templateId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	if err := h.Service.DeleteTemplate(templateId, username); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteTemplate; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Delete certificate template successfully", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// serviceError answers 404 when a record does not exist, 503 without file storage and 400 for rejected changes
+func (h *CertificateHandler) serviceError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	interfacedashboard "safety-riding/internal/interfaces/dashboard"
+	"safety-riding/pkg/filter"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
 	"safety-riding/pkg/response"
@@ -23,7 +24,7 @@ func NewDashboardHandler(dashboardService interfacedashboard.ServiceDashboardInt
 
 // GetStats godoc
 // @Summary Get dashboard statistics
-// @Description Get aggregated statistics for dashboard (current + previous month)
+// @Description Get aggregated statistics for dashboard (current + previous month), limited to the regions of the user
 // @Tags Dashboard
 // @Accept json
 // @Produce json
@@ -35,7 +36,7 @@ func (h *DashboardHandler) GetStats(ctx *gin.Context) {
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][DashboardHandler][GetStats]", logId)
 
-	stats, err := h.DashboardService.GetStats()
+	stats, err := h.DashboardService.GetStats(filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Error: %v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgErr, logId, nil)
@@ -0,0 +1,150 @@
+package handlerevent
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const calendarMaxRangeDays = 366
+
+// GetCalendar godoc
+// @Summary Get the event calendar
+// @Description Retrieve the events between two dates grouped by day, including cancelled ones
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day (YYYY-MM-DD), at most 366 days after from"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /events/calendar [get]
+func (h *EventHandler) GetCalendar(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][GetCalendar]", logId)
+
+	from, errFrom := time.Parse("2006-01-02", ctx.Query("from"))
+	to, errTo := time.Parse("2006-01-02", ctx.Query("to"))
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Invalid range from=%s to=%s", logPrefix, ctx.Query("from"), ctx.Query("to")))
+		res := response.Response(http.StatusBadRequest, "from and to are required as YYYY-MM-DD and from cannot be after to", logId, nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	if to.Sub(from) > calendarMaxRangeDays*24*time.Hour {
+		res := response.Response(http.StatusBadRequest, fmt.Sprintf("Date range cannot be longer than %d days", calendarMaxRangeDays), logId, nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.GetCalendar(from, to, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetCalendar; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateCalendarFeed godoc
+// @Summary Create a calendar feed URL
+// @Description Issue a personal iCalendar (.ics) feed URL for Google Calendar or Outlook. A previously issued URL stops working
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Success 201 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /events/calendar/feed [post]
+func (h *EventHandler) CreateCalendarFeed(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][CreateCalendarFeed]", logId)
+
+	data, err := h.Service.CreateCalendarFeed(userId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.CreateCalendarFeed; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// RevokeCalendarFeed godoc
+// @Summary Revoke the calendar feed URL
+// @Description Disable the personal iCalendar feed URL of the current user
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /events/calendar/feed [delete]
+func (h *EventHandler) RevokeCalendarFeed(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][RevokeCalendarFeed]", logId)
+
+	if err := h.Service.RevokeCalendarFeed(userId); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RevokeCalendarFeed; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetCalendarFeed godoc
+// @Summary Get the iCalendar feed
+// @Description Calendar subscription feed authenticated by the token in the URL. Cancelled and deleted events are published with STATUS:CANCELLED
+// @Tags Events
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally with the .ics extension"
+// @Success 200 {string} string
+// @Failure 401 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /events/calendar/feed/{token} [get]
+func (h *EventHandler) GetCalendarFeed(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][GetCalendarFeed]", logId)
+
+	calendar, err := h.Service.GetCalendarFeed(filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetCalendarFeed; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+	ctx.Header("Content-Disposition", `inline; filename="events.ics"`)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+	if err = calendar.Write(ctx.Writer); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; calendar.Write; Error: %+v", logPrefix, err))
+	}
+}
@@ -10,10 +10,13 @@ import (
 	"safety-riding/internal/dto"
 	interfaceevent "safety-riding/internal/interfaces/event"
 	interfacepermission "safety-riding/internal/interfaces/permission"
+	"safety-riding/pkg/export"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
 	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
 	"safety-riding/utils"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +44,7 @@ func NewEventHandler(s interfaceevent.ServiceEventInterface, permissionRepo inte
 // @Param event body dto.AddEvent true "Event payload"
 // @Success 201 {object} response.Success
 // @Failure 400 {object} response.Error
+// @Failure 409 {object} response.Error "An instructor is booked for an overlapping event"
 // @Failure 500 {object} response.Error
 // @Security ApiKeyAuth
 // @Router /event [post]
@@ -60,9 +64,23 @@ func (h *EventHandler) AddEvent(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.AddEvent(username, req)
+	data, err := h.Service.AddEvent(username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddEvent; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+		var conflict *dto.ScheduleConflictError
+		if errors.As(err, &conflict) {
+			res := response.Response(http.StatusConflict, messages.InvalidRequest, logId, conflict.Conflicts)
+			res.Error = response.Errors{Code: http.StatusConflict, Message: err.Error()}
+			ctx.JSON(http.StatusConflict, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -95,7 +113,7 @@ func (h *EventHandler) GetEventById(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetEventById(eventId)
+	data, err := h.Service.GetEventById(eventId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetEventById; Error: %+v", logPrefix, err))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -126,6 +144,7 @@ func (h *EventHandler) GetEventById(ctx *gin.Context) {
 // @Param event body dto.UpdateEvent true "Event payload"
 // @Success 200 {object} response.Success
 // @Failure 400 {object} response.Error
+// @Failure 409 {object} response.Error "An instructor is booked for an overlapping event"
 // @Failure 500 {object} response.Error
 // @Security ApiKeyAuth
 // @Router /event/{id} [put]
@@ -160,9 +179,23 @@ func (h *EventHandler) UpdateEvent(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.UpdateEvent(eventId, username, canOverrideFinalized, req)
+	data, err := h.Service.UpdateEvent(eventId, username, canOverrideFinalized, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateEvent; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+		var conflict *dto.ScheduleConflictError
+		if errors.As(err, &conflict) {
+			res := response.Response(http.StatusConflict, messages.InvalidRequest, logId, conflict.Conflicts)
+			res.Error = response.Errors{Code: http.StatusConflict, Message: err.Error()}
+			ctx.JSON(http.StatusConflict, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -200,6 +233,7 @@ func (h *EventHandler) FetchEvent(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][EventHandler][FetchEvent]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "event_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistFilter(params.Filters, []string{"school_id", "district_id", "city_id", "province_id", "event_type", "status"})
 
 	events, totalData, err := h.Service.FetchEvent(params)
@@ -223,6 +257,58 @@ func (h *EventHandler) FetchEvent(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// ExportEvent godoc
+// @Summary Export events
+// @Description Download every event matching the search and filters as CSV or XLSX
+// @Tags Events
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format (csv/xlsx)"
+// @Param columns query []string false "Columns to export, defaults to all"
+// @Param search query string false "Search keyword"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param school_id query string false "Filter by school ID"
+// @Param district_id query string false "Filter by district ID"
+// @Param city_id query string false "Filter by city ID"
+// @Param province_id query string false "Filter by province ID"
+// @Param event_type query string false "Filter by event type"
+// @Param status query string false "Filter by status"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /events/export [get]
+func (h *EventHandler) ExportEvent(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][ExportEvent]", logId)
+
+	format, err := export.Format(ctx)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Format; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "event_date", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistFilter(params.Filters, []string{"school_id", "district_id", "city_id", "province_id", "event_type", "status"})
+
+	total, err := export.Stream(ctx, "events", format, params, h.Service.FetchEvent)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; export.Stream; Error: %+v", logPrefix, err))
+		if !ctx.Writer.Written() {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+		}
+		return
+	}
+
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: exported %d rows as %s;", logPrefix, total, format))
+}
+
 // DeleteEvent godoc
 // @Summary Delete an event
 // @Description Delete event by ID
@@ -259,7 +345,7 @@ func (h *EventHandler) DeleteEvent(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteEvent(id, username, canOverrideFinalized); err != nil {
+	if err := h.Service.DeleteEvent(id, username, canOverrideFinalized, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteEvent; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -302,7 +388,7 @@ func (h *EventHandler) GetEventsForMap(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][EventHandler][GetEventsForMap]", logId)
 
 	since := time.Now().AddDate(0, -6, 0)
-	data, err := h.Service.GetCompletedEventsForMap(since)
+	data, err := h.Service.GetCompletedEventsForMap(since, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -368,9 +454,22 @@ func (h *EventHandler) AddEventPhotos(ctx *gin.Context) {
 	photoOrders := form.Value["photo_orders"]
 
 	// Call service to upload photos to MinIO and save to database
-	data, err := h.Service.AddEventPhotosFromFiles(ctx, eventId, username, files, captions, photoOrders)
+	data, err := h.Service.AddEventPhotosFromFiles(ctx, eventId, username, filter.GetRegionScope(ctx), files, captions, photoOrders)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddEventPhotosFromFiles; Error: %+v", logPrefix, err))
+		if errors.Is(err, storage.ErrUnavailable) {
+			res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		if errors.Is(err, imaging.ErrNotImage) {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -408,7 +507,7 @@ func (h *EventHandler) DeleteEventPhoto(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteEventPhoto(photoId, username); err != nil {
+	if err := h.Service.DeleteEventPhoto(photoId, username, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteEventPhoto; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
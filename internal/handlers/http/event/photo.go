@@ -0,0 +1,223 @@
+package handlerevent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UpdateEventPhoto godoc
+// @Summary Update event photo
+// @Description Change the caption of an event photo or make it the cover shown on the event map and the dashboard, is_cover false removes the cover
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param photoId path string true "Photo ID"
+// @Param photo body dto.UpdateEventPhoto true "Photo changes"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/photo/{photoId} [put]
+func (h *EventHandler) UpdateEventPhoto(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][UpdateEventPhoto]", logId)
+
+	photoId := ctx.Param("photoId")
+	if photoId == "" {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Missing photo ID", logPrefix))
+		res := response.Response(http.StatusBadRequest, "Photo ID is required", logId, nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	var req dto.UpdateEventPhoto
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.UpdateEventPhoto(photoId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateEventPhoto; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "event photo not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Update event photo successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, photoId))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ReorderEventPhotos godoc
+// @Summary Reorder event photos
+// @Description Set the order of the event photos, photo_ids lists every photo of the event and they are numbered 1..n in that order
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param order body dto.ReorderEventPhotos true "Photo IDs in their new order"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/photos/order [put]
+func (h *EventHandler) ReorderEventPhotos(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][ReorderEventPhotos]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.ReorderEventPhotos
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.ReorderEventPhotos(eventId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ReorderEventPhotos; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "event data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Reorder event photos successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d photos;", logPrefix, len(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RequestEventPhotoUploads godoc
+// @Summary Request direct photo uploads
+// @Description Reserve presigned URLs to upload event photos directly to the bucket. Upload each file with the given method and headers before expires_at, then confirm the uploads
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param files body dto.RequestPhotoUploads true "Files to upload"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 503 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/photos/uploads [post]
+func (h *EventHandler) RequestEventPhotoUploads(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][RequestEventPhotoUploads]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.RequestPhotoUploads
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.RequestEventPhotoUploads(ctx, eventId, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RequestEventPhotoUploads; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "event data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d upload slots;", logPrefix, len(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ConfirmEventPhotoUploads godoc
+// @Summary Confirm direct photo uploads
+// @Description Create the event photos of finished direct uploads. Each upload is checked for its size and content, stored without EXIF data with its renditions and removed from the upload area
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param photos body dto.ConfirmPhotoUploads true "Finished uploads"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 503 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/photos/uploads/confirm [post]
+func (h *EventHandler) ConfirmEventPhotoUploads(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][ConfirmEventPhotoUploads]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.ConfirmPhotoUploads
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.ConfirmEventPhotoUploads(ctx, eventId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ConfirmEventPhotoUploads; Error: %+v", logPrefix, err))
+		photoError(ctx, logId, "event data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add event photos successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: confirmed %d photos;", logPrefix, len(data)))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// photoError answers 404 when the photo or its record does not exist, 503 without file storage and 400 otherwise
+func photoError(ctx *gin.Context, logId uuid.UUID, notFound string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = notFound
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		res := response.Response(http.StatusServiceUnavailable, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
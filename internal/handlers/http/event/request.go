@@ -0,0 +1,125 @@
+package handlerevent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DraftFromRequest godoc
+// @Summary Draft an event from a training request
+// @Description Pre-fill an event from an approved training request and match its school or public entity by name and address. Fields the request cannot fill are listed in missing_fields
+// @Tags Approval Records
+// @Accept json
+// @Produce json
+// @Param id path string true "Submitted form ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /approval-records/{id}/event-draft [get]
+func (h *EventHandler) DraftFromRequest(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][DraftFromRequest]", logId)
+
+	formId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.DraftFromRequest(formId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DraftFromRequest; Error: %+v", logPrefix, err))
+		requestError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateFromRequest godoc
+// @Summary Create an event from a training request
+// @Description Create the reviewed draft of an approved training request as an event linked to its request number. A request becomes at most one event
+// @Tags Approval Records
+// @Accept json
+// @Produce json
+// @Param id path string true "Submitted form ID"
+// @Param event body dto.AddEvent true "Reviewed event draft"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 403 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 409 {object} response.Error "An instructor is booked for an overlapping event"
+// @Security ApiKeyAuth
+// @Router /approval-records/{id}/event [post]
+func (h *EventHandler) CreateFromRequest(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][CreateFromRequest]", logId)
+
+	formId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.AddEvent
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.CreateFromRequest(formId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.CreateFromRequest; Error: %+v", logPrefix, err))
+		requestError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Create event from training request successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %+v;", logPrefix, utils.JsonEncode(data)))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func requestError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = "training request not found"
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	if errors.Is(err, filter.ErrOutOfRegion) {
+		res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+		res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+		ctx.JSON(http.StatusForbidden, res)
+		return
+	}
+	var conflict *dto.ScheduleConflictError
+	if errors.As(err, &conflict) {
+		res := response.Response(http.StatusConflict, messages.InvalidRequest, logId, conflict.Conflicts)
+		res.Error = response.Errors{Code: http.StatusConflict, Message: err.Error()}
+		ctx.JSON(http.StatusConflict, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
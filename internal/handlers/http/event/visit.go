@@ -0,0 +1,124 @@
+package handlerevent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecomputeVisits godoc
+// @Summary Recompute visit statistics
+// @Description Rebuild the visit count, educated flag and last visit of every school and public entity from their completed events
+// @Tags Education
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /education/visits/recompute [post]
+func (h *EventHandler) RecomputeVisits(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][RecomputeVisits]", logId)
+
+	data, err := h.Service.RecomputeVisits()
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RecomputeVisits; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Recompute visit statistics successfully", logId, data)
+	logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("%s; Success by %s: %+v;", logPrefix, username, data))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchSchoolVisits godoc
+// @Summary Get school visits
+// @Description Retrieve the completed events held at a school, latest first. They make up its visit count and last visit
+// @Tags Schools
+// @Accept json
+// @Produce json
+// @Param id path string true "School ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /school/{id}/visits [get]
+func (h *EventHandler) FetchSchoolVisits(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][FetchSchoolVisits]", logId)
+
+	schoolId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.FetchSchoolVisits(schoolId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchSchoolVisits; Error: %+v", logPrefix, err))
+		visitError(ctx, logId, "school data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchPublicVisits godoc
+// @Summary Get public entity visits
+// @Description Retrieve the completed events held at a public entity, latest first. They make up its visit count and last visit
+// @Tags Publics
+// @Accept json
+// @Produce json
+// @Param id path string true "Public ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /public/{id}/visits [get]
+func (h *EventHandler) FetchPublicVisits(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][EventHandler][FetchPublicVisits]", logId)
+
+	publicId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.FetchPublicVisits(publicId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchPublicVisits; Error: %+v", logPrefix, err))
+		visitError(ctx, logId, "public data not found", err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func visitError(ctx *gin.Context, logId uuid.UUID, notFound string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = notFound
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusInternalServerError, res)
+}
@@ -0,0 +1,47 @@
+package handlerfile
+
+import (
+	"fmt"
+	"net/http"
+
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves the files of the local storage provider, the other providers serve their files themselves
+type FileHandler struct {
+	Storage *storage.LocalAdapter
+}
+
+func NewFileHandler(s *storage.LocalAdapter) *FileHandler {
+	return &FileHandler{
+		Storage: s,
+	}
+}
+
+// ServeFile godoc
+// @Summary Get a stored file
+// @Description Serve a file of the local storage. Only the signed URLs returned by the API are accepted, any other path answers 404
+// @Tags Files
+// @Produce octet-stream
+// @Param path path string true "Object name"
+// @Param sig query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 404 {string} string
+// @Router /files/{path} [get]
+func (h *FileHandler) ServeFile(ctx *gin.Context) {
+	file, info, err := h.Storage.Open(ctx.Param("path"), ctx.Query("sig"))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("[FileHandler][ServeFile]; %s; Error: %+v", ctx.Param("path"), err))
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	// Stored names are unique, a file never changes once written
+	ctx.Header("Cache-Control", "private, max-age=86400")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), file)
+}
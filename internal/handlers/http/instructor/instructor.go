@@ -0,0 +1,275 @@
+package handlerinstructor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	interfaceinstructor "safety-riding/internal/interfaces/instructor"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InstructorHandler struct {
+	Service interfaceinstructor.ServiceInstructorInterface
+}
+
+func NewInstructorHandler(s interfaceinstructor.ServiceInstructorInterface) *InstructorHandler {
+	return &InstructorHandler{
+		Service: s,
+	}
+}
+
+// AddInstructor godoc
+// @Summary Create an instructor
+// @Description Add an instructor to the directory with their certifications and home region
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param instructor body dto.AddInstructor true "Instructor payload"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructor [post]
+func (h *InstructorHandler) AddInstructor(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][AddInstructor]", logId)
+
+	var req dto.AddInstructor
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.AddInstructor(username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddInstructor; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add instructor successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// GetInstructorById godoc
+// @Summary Get instructor detail
+// @Description Retrieve an instructor with their certifications
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructor/{id} [get]
+func (h *InstructorHandler) GetInstructorById(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][GetInstructorById]", logId)
+
+	instructorId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetInstructorById(instructorId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetInstructorById; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Instructor not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// UpdateInstructor godoc
+// @Summary Update an instructor
+// @Description Update an instructor. Sending certifications replaces all of them, set is_active to false to stop assigning the instructor
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Param instructor body dto.UpdateInstructor true "Instructor payload"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructor/{id} [put]
+func (h *InstructorHandler) UpdateInstructor(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][UpdateInstructor]", logId)
+
+	instructorId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.UpdateInstructor
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.UpdateInstructor(instructorId, username, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateInstructor; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Update instructor successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %s;", logPrefix, data.ID))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchInstructor godoc
+// @Summary List instructors
+// @Description Retrieve paginated instructors
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by name or phone"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param filters[is_active] query string false "Filter by active status (true/false)"
+// @Param filters[home_province_id] query string false "Filter by home province"
+// @Param filters[home_city_id] query string false "Filter by home city"
+// @Success 200 {object} response.Success
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructors [get]
+func (h *InstructorHandler) FetchInstructor(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][FetchInstructor]", logId)
+
+	params, _ := filter.GetBaseParams(ctx, "name", "asc", 10)
+	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"is_active", "home_province_id", "home_city_id"})
+
+	instructors, totalData, err := h.Service.FetchInstructor(params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchInstructor; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, instructors)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteInstructor godoc
+// @Summary Delete an instructor
+// @Description Delete an instructor without upcoming events, past events keep the instructor name
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructor/{id} [delete]
+func (h *InstructorHandler) DeleteInstructor(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][DeleteInstructor]", logId)
+
+	instructorId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	if err := h.Service.DeleteInstructor(instructorId, username); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteInstructor; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Delete instructor successfully", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetSchedule godoc
+// @Summary Get instructor schedule
+// @Description Retrieve the events an instructor is booked for, cancelled events are left out. The period defaults to the next 30 days
+// @Tags Instructors
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /instructor/{id}/schedule [get]
+func (h *InstructorHandler) GetSchedule(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][InstructorHandler][GetSchedule]", logId)
+
+	instructorId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetSchedule(instructorId, ctx.Query("from"), ctx.Query("to"), filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetSchedule; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// serviceError answers 404 when a record does not exist and 400 for rejected changes
+func (h *InstructorHandler) serviceError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"safety-riding/internal/dto"
 	interfacemarketshare "safety-riding/internal/interfaces/marketshare"
+	"safety-riding/pkg/export"
 	"safety-riding/pkg/filter"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
@@ -57,9 +58,16 @@ func (h *MarketShareHandler) AddMarketShare(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.AddMarketShare(username, req)
+	data, err := h.Service.AddMarketShare(username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddMarketShare; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -92,7 +100,7 @@ func (h *MarketShareHandler) GetMarketShareById(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetMarketShareById(marketShareId)
+	data, err := h.Service.GetMarketShareById(marketShareId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetMarketShareById; Error: %+v", logPrefix, err))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -147,9 +155,16 @@ func (h *MarketShareHandler) UpdateMarketShare(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.UpdateMarketShare(marketShareId, username, req)
+	data, err := h.Service.UpdateMarketShare(marketShareId, username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateMarketShare; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -186,6 +201,7 @@ func (h *MarketShareHandler) FetchMarketShare(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][MarketShareHandler][FetchMarketShare]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "created_at", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistFilter(params.Filters, []string{"province_id", "city_id", "district_id", "month", "year"})
 
 	marketShares, totalData, err := h.Service.FetchMarketShare(params)
@@ -209,6 +225,57 @@ func (h *MarketShareHandler) FetchMarketShare(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// ExportMarketShare godoc
+// @Summary Export market shares
+// @Description Download every market share matching the search and filters as CSV or XLSX
+// @Tags MarketShare
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format (csv/xlsx)"
+// @Param columns query []string false "Columns to export, defaults to all"
+// @Param search query string false "Search keyword"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param province_id query string false "Filter by province ID"
+// @Param city_id query string false "Filter by city ID"
+// @Param district_id query string false "Filter by district ID"
+// @Param month query int false "Filter by month"
+// @Param year query int false "Filter by year"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /marketshares/export [get]
+func (h *MarketShareHandler) ExportMarketShare(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][MarketShareHandler][ExportMarketShare]", logId)
+
+	format, err := export.Format(ctx)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Format; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "created_at", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistFilter(params.Filters, []string{"province_id", "city_id", "district_id", "month", "year"})
+
+	total, err := export.Stream(ctx, "market_shares", format, params, h.Service.FetchMarketShare)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; export.Stream; Error: %+v", logPrefix, err))
+		if !ctx.Writer.Written() {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+		}
+		return
+	}
+
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: exported %d rows as %s;", logPrefix, total, format))
+}
+
 // DeleteMarketShare godoc
 // @Summary Delete a market share entry
 // @Description Delete market share record by ID
@@ -235,7 +302,7 @@ func (h *MarketShareHandler) DeleteMarketShare(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteMarketShare(id, username); err != nil {
+	if err := h.Service.DeleteMarketShare(id, username, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteMarketShare; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -296,7 +363,7 @@ func (h *MarketShareHandler) GetTopDistricts(ctx *gin.Context) {
 		limit = 5
 	}
 
-	data, err := h.Service.GetTopDistricts(year, month, limit)
+	data, err := h.Service.GetTopDistricts(year, month, limit, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetTopDistricts; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -361,7 +428,7 @@ func (h *MarketShareHandler) GetSummary(ctx *gin.Context) {
 		}
 	}
 
-	data, err := h.Service.GetSummary(level, year, month, provinceID, cityID, districtID)
+	data, err := h.Service.GetSummary(level, year, month, provinceID, cityID, districtID, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetSummary; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -425,7 +492,7 @@ func (h *MarketShareHandler) GetDashboardSuggestions(ctx *gin.Context) {
 
 	sortOrder := ctx.Query("sort_order")
 
-	topCities, err := h.Service.GetTopCities(year, month, cityLimit, sortOrder)
+	topCities, err := h.Service.GetTopCities(year, month, cityLimit, sortOrder, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetTopCities; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -434,7 +501,7 @@ func (h *MarketShareHandler) GetDashboardSuggestions(ctx *gin.Context) {
 		return
 	}
 
-	topDistricts, err := h.Service.GetTopDistricts(year, month, districtLimit)
+	topDistricts, err := h.Service.GetTopDistricts(year, month, districtLimit, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetTopDistricts; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -0,0 +1,179 @@
+package handlerparticipant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCheckInCode godoc
+// @Summary Get the event check-in code
+// @Description Retrieve the signed check-in link of an event, to be shown to participants as a QR code. It stays the same for the whole event
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/checkin-code [get]
+func (h *ParticipantHandler) GetCheckInCode(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][GetCheckInCode]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetCheckInCode(eventId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetCheckInCode; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetAttendance godoc
+// @Summary Get the live attendance count
+// @Description Retrieve how many people on the event roster have checked in
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/attendance [get]
+func (h *ParticipantHandler) GetAttendance(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][GetAttendance]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	data, err := h.Service.GetAttendance(eventId, filter.GetRegionScope(ctx))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetAttendance; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Event not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetCheckInEvent godoc
+// @Summary Get the event of a check-in link
+// @Description Public endpoint used by the check-in page to show the event and whether check-in is open
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Param token path string true "Check-in token"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 429 {object} response.Error
+// @Router /checkin/{token} [get]
+func (h *ParticipantHandler) GetCheckInEvent(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][GetCheckInEvent]", logId)
+
+	data, err := h.Service.GetCheckInEvent(ctx.Param("token"))
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetCheckInEvent; Error: %+v", logPrefix, err))
+		h.checkInError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, messages.MsgSuccess, logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CheckIn godoc
+// @Summary Check in to an event
+// @Description Public endpoint to check in a participant on the event roster, found by name and phone, or name and organization. Check-in closes at the event end time
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Param token path string true "Check-in token"
+// @Param checkin body dto.CheckIn true "Participant identity"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 403 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 429 {object} response.Error
+// @Router /checkin/{token} [post]
+func (h *ParticipantHandler) CheckIn(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][CheckIn]", logId)
+
+	var req dto.CheckIn
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.CheckIn(ctx.Param("token"), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.CheckIn; Error: %+v", logPrefix, err))
+		h.checkInError(ctx, logId, err)
+		return
+	}
+
+	message := "Check-in successfully"
+	if data.AlreadyCheckedIn {
+		message = "Already checked in"
+	}
+
+	res := response.Response(http.StatusOK, message, logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %+v;", logPrefix, utils.JsonEncode(data)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// checkInError answers 404 for an invalid link or someone not on the roster and 403 when check-in is not open
+func (h *ParticipantHandler) checkInError(ctx *gin.Context, logId uuid.UUID, err error) {
+	status := http.StatusInternalServerError
+	message := messages.MsgFail
+	switch err.Error() {
+	case messages.ErrInvalidCheckIn, messages.ErrNotOnRoster:
+		status, message = http.StatusNotFound, messages.NotFound
+	case messages.ErrCheckInNotOpen, messages.ErrCheckInClosed:
+		status, message = http.StatusForbidden, messages.MsgForbidden
+	}
+
+	res := response.Response(status, message, logId, nil)
+	res.Error = response.Errors{Code: status, Message: err.Error()}
+	ctx.JSON(status, res)
+}
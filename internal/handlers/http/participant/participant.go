@@ -0,0 +1,241 @@
+package handlerparticipant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"safety-riding/internal/dto"
+	interfaceparticipant "safety-riding/internal/interfaces/participant"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ParticipantHandler struct {
+	Service interfaceparticipant.ServiceParticipantInterface
+}
+
+func NewParticipantHandler(s interfaceparticipant.ServiceParticipantInterface) *ParticipantHandler {
+	return &ParticipantHandler{
+		Service: s,
+	}
+}
+
+// AddParticipants godoc
+// @Summary Add participants to an event
+// @Description Add one or more people to the event roster. The request is rejected when someone is already on the roster
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param participants body dto.AddParticipants true "Participants payload"
+// @Success 201 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/participants [post]
+func (h *ParticipantHandler) AddParticipants(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][AddParticipants]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	var req dto.AddParticipants
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
+
+	data, err := h.Service.AddParticipants(eventId, username, filter.GetRegionScope(ctx), req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddParticipants; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusCreated, "Add participants successfully", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: %d participants;", logPrefix, len(data)))
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// ImportParticipants godoc
+// @Summary Import participants from CSV or XLSX
+// @Description Add the people of a spreadsheet to the event roster. The first row must contain the column headers (name, gender, age, grade, organization, phone, license_status)
+// @Tags Events
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param file formData file true "CSV or XLSX file"
+// @Param dry_run query bool false "Validate only without saving"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/participants/import [post]
+func (h *ParticipantHandler) ImportParticipants(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][ImportParticipants]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; FormFile ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, "File is required", logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: event=%s; file=%s; size=%d; dry_run=%t;", logPrefix, eventId, fileHeader.Filename, fileHeader.Size, dryRun))
+
+	data, err := h.Service.ImportParticipants(eventId, username, filter.GetRegionScope(ctx), fileHeader, dryRun)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ImportParticipants; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	message := "Import participants successfully"
+	if dryRun {
+		message = "Validate participant import successfully"
+	}
+
+	res := response.Response(http.StatusOK, message, logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: total=%d, created=%d, rejected=%d;", logPrefix, data.TotalRows, data.Created, data.Rejected))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// FetchParticipants godoc
+// @Summary List event participants
+// @Description Retrieve the roster of an event. event_count tells how many events the person has attended
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param search query string false "Search by name or organization"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param gender query string false "Filter by gender"
+// @Param license_status query string false "Filter by license status"
+// @Success 200 {object} response.Success
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/participants [get]
+func (h *ParticipantHandler) FetchParticipants(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][FetchParticipants]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "name", "asc", 50)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistFilter(params.Filters, []string{"gender", "license_status"})
+
+	participants, totalData, err := h.Service.FetchParticipants(eventId, params)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.FetchParticipants; Error: %+v", logPrefix, err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+			res.Error = "Event not found"
+			ctx.JSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.PaginationResponse(http.StatusOK, int(totalData), params.Page, params.Limit, logId, participants)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %d participants;", logPrefix, len(participants)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteParticipant godoc
+// @Summary Remove a participant from an event
+// @Description Remove a person from the event roster
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param participantId path string true "Participant ID"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /event/{id}/participants/{participantId} [delete]
+func (h *ParticipantHandler) DeleteParticipant(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][ParticipantHandler][DeleteParticipant]", logId)
+
+	eventId, err := utils.ValidateUUID(ctx, logId)
+	if err != nil {
+		return
+	}
+
+	participantId := ctx.Param("participantId")
+	if _, err := uuid.Parse(participantId); err != nil {
+		res := response.Response(http.StatusBadRequest, http.StatusText(http.StatusBadRequest), logId, nil)
+		res.Error = response.Errors{Code: http.StatusBadRequest, Message: "participantId must be a valid UUID"}
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := h.Service.DeleteParticipant(eventId, participantId, username, filter.GetRegionScope(ctx)); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteParticipant; Error: %+v", logPrefix, err))
+		h.serviceError(ctx, logId, err)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Delete participant successfully", logId, nil)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: participant %s removed from event %s;", logPrefix, participantId, eventId))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// serviceError answers 404 when the event or participant does not exist and 400 for rejected roster changes
+func (h *ParticipantHandler) serviceError(ctx *gin.Context, logId uuid.UUID, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res := response.Response(http.StatusNotFound, messages.NotFound, logId, nil)
+		res.Error = "Event or participant not found"
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+	res.Error = err.Error()
+	ctx.JSON(http.StatusBadRequest, res)
+}
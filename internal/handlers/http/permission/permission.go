@@ -37,6 +37,8 @@ func NewPermissionHandler(s interfacepermission.ServicePermissionInterface) *Per
 // @Router /permission [post]
 func (h *PermissionHandler) Create(ctx *gin.Context) {
 	var req dto.PermissionCreate
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][PermissionHandler][Create]", logId)
 
@@ -50,7 +52,7 @@ func (h *PermissionHandler) Create(ctx *gin.Context) {
 
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.Create(req)
+	data, err := h.Service.Create(username, req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Create; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
@@ -152,6 +154,8 @@ func (h *PermissionHandler) GetAll(ctx *gin.Context) {
 func (h *PermissionHandler) Update(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var req dto.PermissionUpdate
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][PermissionHandler][Update]", logId)
 
@@ -165,7 +169,7 @@ func (h *PermissionHandler) Update(ctx *gin.Context) {
 
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.Update(id, req)
+	data, err := h.Service.Update(id, username, req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Update; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
@@ -192,10 +196,12 @@ func (h *PermissionHandler) Update(ctx *gin.Context) {
 // @Router /permission/{id} [delete]
 func (h *PermissionHandler) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][PermissionHandler][Delete]", logId)
 
-	if err := h.Service.Delete(id); err != nil {
+	if err := h.Service.Delete(id, username); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Delete; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
 		res.Error = err.Error()
@@ -37,6 +37,8 @@ func NewRoleHandler(s interfacerole.ServiceRoleInterface) *RoleHandler {
 // @Router /role [post]
 func (h *RoleHandler) Create(ctx *gin.Context) {
 	var req dto.RoleCreate
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][RoleHandler][Create]", logId)
 
@@ -50,7 +52,7 @@ func (h *RoleHandler) Create(ctx *gin.Context) {
 
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.Create(req)
+	data, err := h.Service.Create(username, req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Create; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
@@ -156,6 +158,8 @@ func (h *RoleHandler) GetAll(ctx *gin.Context) {
 func (h *RoleHandler) Update(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var req dto.RoleUpdate
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][RoleHandler][Update]", logId)
 
@@ -169,7 +173,7 @@ func (h *RoleHandler) Update(ctx *gin.Context) {
 
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.Update(id, req)
+	data, err := h.Service.Update(id, username, req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Update; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
@@ -196,10 +200,12 @@ func (h *RoleHandler) Update(ctx *gin.Context) {
 // @Router /role/{id} [delete]
 func (h *RoleHandler) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][RoleHandler][Delete]", logId)
 
-	if err := h.Service.Delete(id); err != nil {
+	if err := h.Service.Delete(id, username); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.Delete; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, err.Error(), logId, nil)
 		res.Error = err.Error()
@@ -235,6 +241,7 @@ func (h *RoleHandler) AssignPermissions(ctx *gin.Context) {
 	// Get current user's role
 	authData := utils.GetAuthData(ctx)
 	currentUserRole := utils.InterfaceString(authData["role"])
+	username := utils.InterfaceString(authData["username"])
 
 	if err := ctx.BindJSON(&req); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
@@ -246,7 +253,7 @@ func (h *RoleHandler) AssignPermissions(ctx *gin.Context) {
 
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	if err := h.Service.AssignPermissions(id, req, currentUserRole); err != nil {
+	if err := h.Service.AssignPermissions(id, username, req, currentUserRole); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AssignPermissions; Error: %+v", logPrefix, err))
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "access denied: cannot modify superadmin role" || err.Error() == "access denied: only superadmin and admin can modify system roles" {
@@ -7,11 +7,13 @@ import (
 	"reflect"
 	"safety-riding/internal/dto"
 	interfaceschool "safety-riding/internal/interfaces/school"
+	"safety-riding/pkg/export"
 	"safety-riding/pkg/filter"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
 	"safety-riding/pkg/response"
 	"safety-riding/utils"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -55,9 +57,16 @@ func (h *SchoolHandler) AddSchool(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.AddSchool(username, req)
+	data, err := h.Service.AddSchool(username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.AddSchool; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -90,7 +99,7 @@ func (h *SchoolHandler) GetSchoolById(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.Service.GetSchoolById(schoolId)
+	data, err := h.Service.GetSchoolById(schoolId, filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetSchoolById; Error: %+v", logPrefix, err))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -145,9 +154,16 @@ func (h *SchoolHandler) UpdateSchool(ctx *gin.Context) {
 	}
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: %+v;", logPrefix, utils.JsonEncode(req)))
 
-	data, err := h.Service.UpdateSchool(schoolId, username, req)
+	data, err := h.Service.UpdateSchool(schoolId, username, filter.GetRegionScope(ctx), req)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.UpdateSchool; Error: %+v", logPrefix, err))
+		if errors.Is(err, filter.ErrOutOfRegion) {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: err.Error()}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
+
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -182,6 +198,7 @@ func (h *SchoolHandler) FetchSchool(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][SchoolHandler][FetchSchool]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "updated_at", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id"})
 
 	schools, totalData, err := h.Service.FetchSchool(params)
@@ -206,6 +223,55 @@ func (h *SchoolHandler) FetchSchool(ctx *gin.Context) {
 
 }
 
+// ExportSchool godoc
+// @Summary Export schools
+// @Description Download every school matching the search and filters as CSV or XLSX
+// @Tags Schools
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format (csv/xlsx)"
+// @Param columns query []string false "Columns to export, defaults to all"
+// @Param search query string false "Search keyword"
+// @Param sort query string false "Sort field"
+// @Param order query string false "Sort order (asc/desc)"
+// @Param district_id query string false "Filter by district ID"
+// @Param city_id query string false "Filter by city ID"
+// @Param province_id query string false "Filter by province ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /schools/export [get]
+func (h *SchoolHandler) ExportSchool(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][SchoolHandler][ExportSchool]", logId)
+
+	format, err := export.Format(ctx)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Format; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	params, _ := filter.GetBaseParams(ctx, "updated_at", "desc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
+	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id"})
+
+	total, err := export.Stream(ctx, "schools", format, params, h.Service.FetchSchool)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; export.Stream; Error: %+v", logPrefix, err))
+		if !ctx.Writer.Written() {
+			res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+			res.Error = err.Error()
+			ctx.JSON(http.StatusBadRequest, res)
+		}
+		return
+	}
+
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: exported %d rows as %s;", logPrefix, total, format))
+}
+
 // DeleteSchool godoc
 // @Summary Delete a school
 // @Description Delete school by ID
@@ -232,7 +298,7 @@ func (h *SchoolHandler) DeleteSchool(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteSchool(id, username); err != nil {
+	if err := h.Service.DeleteSchool(id, username, filter.GetRegionScope(ctx)); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DeleteSchool; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -245,6 +311,56 @@ func (h *SchoolHandler) DeleteSchool(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// ImportSchool godoc
+// @Summary Import schools from CSV or XLSX
+// @Description Bulk create or update schools matched by NPSN. The first row must contain the column headers (name, npsn, address, phone, email, district_id, district_name, city_id, city_name, province_id, province_name, postal_code, latitude, longitude, student_count, teacher_count, major_count)
+// @Tags Schools
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param dry_run query bool false "Validate only without saving"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /schools/import [post]
+func (h *SchoolHandler) ImportSchool(ctx *gin.Context) {
+	authData := utils.GetAuthData(ctx)
+	username := utils.InterfaceString(authData["username"])
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][SchoolHandler][ImportSchool]", logId)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; FormFile ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, "File is required", logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Request: file=%s; size=%d; dry_run=%t;", logPrefix, fileHeader.Filename, fileHeader.Size, dryRun))
+
+	data, err := h.Service.ImportSchools(username, filter.GetRegionScope(ctx), fileHeader, dryRun)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ImportSchools; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	message := "Import school successfully"
+	if dryRun {
+		message = "Validate school import successfully"
+	}
+
+	res := response.Response(http.StatusOK, message, logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Success: total=%d, created=%d, updated=%d, rejected=%d;", logPrefix, data.TotalRows, data.Created, data.Updated, data.Rejected))
+	ctx.JSON(http.StatusOK, res)
+}
+
 // GetEducationStats godoc
 // @Summary Get school education statistics
 // @Description Retrieve aggregated school education statistics with optional filters
@@ -270,6 +386,7 @@ func (h *SchoolHandler) GetEducationStats(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][SchoolHandler][GetEducationStats]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "name", "asc", 10)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id", "is_educated", "month", "year"})
 
 	stats, err := h.Service.GetEducationStats(params)
@@ -310,6 +427,7 @@ func (h *SchoolHandler) GetEducationPriority(ctx *gin.Context) {
 	logPrefix := fmt.Sprintf("[%s][SchoolHandler][GetEducationPriority]", logId)
 
 	params, _ := filter.GetBaseParams(ctx, "market_share", "asc", 100)
+	params.Scope = filter.GetRegionScope(ctx)
 	params.Filters = filter.WhitelistStringFilter(params.Filters, []string{"district_id", "city_id", "province_id", "month", "year"})
 
 	priority, err := h.Service.GetEducationPriority(params)
@@ -339,7 +457,7 @@ func (h *SchoolHandler) GetSummary(ctx *gin.Context) {
 	logId := utils.GenerateLogId(ctx)
 	logPrefix := fmt.Sprintf("[%s][SchoolHandler][GetSummary]", logId)
 
-	summary, err := h.Service.GetSummary()
+	summary, err := h.Service.GetSummary(filter.GetRegionScope(ctx))
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Error: %+v", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
@@ -365,7 +483,7 @@ func (h *SchoolHandler) GetSummary(ctx *gin.Context) {
 func (h *SchoolHandler) GetForMap(ctx *gin.Context) {
 	logId := utils.GenerateLogId(ctx)
 
-	schools, err := h.Service.GetForMap()
+	schools, err := h.Service.GetForMap(filter.GetRegionScope(ctx))
 	if err != nil {
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
 		res.Error = err.Error()
@@ -1,5 +1,19 @@
 package handleruser
 
+import (
+	"context"
+	"fmt"
+
+	"safety-riding/infrastructure/database"
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	sessionRepo "safety-riding/internal/repositories/session"
+	sessionSvc "safety-riding/internal/services/session"
+	"safety-riding/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
 func (h *HandlerUser) isPublicRegistrationEnabled() (bool, error) {
 	if h.AppConfigService == nil {
 		return true, nil
@@ -7,3 +21,71 @@ func (h *HandlerUser) isPublicRegistrationEnabled() (bool, error) {
 
 	return h.AppConfigService.IsEnabled(publicRegistrationConfigKey, true)
 }
+
+func (h *HandlerUser) isEmailVerificationRequired() (bool, error) {
+	if h.AppConfigService == nil {
+		return false, nil
+	}
+
+	return h.AppConfigService.IsEnabled(emailVerificationConfigKey, false)
+}
+
+func (h *HandlerUser) isTwoFactorRequired(role string) (bool, error) {
+	if h.AppConfigService == nil {
+		return false, nil
+	}
+
+	return h.AppConfigService.IsEnabled(twoFactorConfigKeyPrefix+role, false)
+}
+
+// loginPolicy reads the login rules from app_configs. A rule that cannot be read or parsed is enforced,
+// so a broken config never lets an unverified user in or an admin skip the two-factor enrollment
+func (h *HandlerUser) loginPolicy() (dto.LoginPolicy, error) {
+	var (
+		policy   dto.LoginPolicy
+		firstErr error
+	)
+
+	requireVerifiedEmail, err := h.isEmailVerificationRequired()
+	if err != nil {
+		firstErr = err
+	}
+	policy.RequireVerifiedEmail = requireVerifiedEmail || err != nil
+
+	for _, role := range twoFactorConfigurableRoles {
+		required, err := h.isTwoFactorRequired(role)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if required || err != nil {
+			policy.TwoFactorRequiredRoles = append(policy.TwoFactorRequiredRoles, role)
+		}
+	}
+
+	return policy, firstErr
+}
+
+// startSession creates the Redis session for a completed login and builds the login response data
+func (h *HandlerUser) startSession(ctx *gin.Context, logPrefix string, user domainuser.Users, result dto.LoginResult) map[string]interface{} {
+	data := map[string]interface{}{"token": result.Token}
+	if len(result.RecoveryCodes) > 0 {
+		data["recovery_codes"] = result.RecoveryCodes
+	}
+
+	// Create session if Redis is available
+	if redisClient := database.GetRedisClient(); redisClient != nil && user.Id != "" {
+		sRepo := sessionRepo.NewSessionRepository(redisClient)
+		sSvc := sessionSvc.NewSessionService(sRepo)
+
+		session, refreshToken, errSession := sSvc.CreateSession(context.Background(), &user, result.Token, ctx)
+		if errSession != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Failed to create session: %v", logPrefix, errSession))
+			// Continue anyway - session is optional
+		} else {
+			logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("%s; Session created: %s", logPrefix, session.SessionID))
+			data["refresh_token"] = refreshToken
+		}
+	}
+
+	return data
+}
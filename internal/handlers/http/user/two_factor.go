@@ -0,0 +1,304 @@
+package handleruser
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginTwoFactor godoc
+// @Summary Complete login with a second factor
+// @Description Verify a TOTP or recovery code for the challenge token returned by login. Completing a required enrollment also returns the recovery codes
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 401 {object} response.Error
+// @Failure 429 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /user/login/2fa [post]
+func (h *HandlerUser) LoginTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][LoginTwoFactor]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	user, result, err := h.Service.LoginTwoFactor(req, logId.String())
+
+	// Wrong codes count against the same limiter as wrong passwords for this account
+	loginIdentifier := fmt.Sprintf("%s:%s", ctx.ClientIP(), strings.ToLower(user.Email))
+	if h.LoginLimiter != nil && user.Id != "" {
+		blocked, ttl, limiterErr := h.LoginLimiter.IsBlocked(ctx.Request.Context(), loginIdentifier)
+		if limiterErr != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; LoginLimiter.IsBlocked error: %v", logPrefix, limiterErr))
+		} else if blocked {
+			logger.WriteLog(logger.LogLevelWarn, fmt.Sprintf("%s; Too many attempts", logPrefix))
+			h.respondTooManyLoginAttempts(ctx, logId, ttl)
+			return
+		}
+	}
+
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.LoginTwoFactor; ERROR: %s;", logPrefix, err))
+		if err.Error() == messages.ErrInvalidTwoFactor {
+			if h.LoginLimiter != nil {
+				blocked, ttl, limiterErr := h.LoginLimiter.RegisterFailure(ctx.Request.Context(), loginIdentifier)
+				if limiterErr != nil {
+					logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; LoginLimiter.RegisterFailure error: %v", logPrefix, limiterErr))
+				}
+				if blocked {
+					logger.WriteLog(logger.LogLevelWarn, fmt.Sprintf("%s; Account temporarily locked after repeated failures", logPrefix))
+					h.respondTooManyLoginAttempts(ctx, logId, ttl)
+					return
+				}
+			}
+
+			res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+			res.Error = response.Errors{Code: http.StatusBadRequest, Message: messages.ErrInvalidTwoFactor}
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+		if user.Id == "" {
+			res := response.Response(http.StatusUnauthorized, messages.MsgFail, logId, nil)
+			res.Error = "Please login and try again"
+			ctx.JSON(http.StatusUnauthorized, res)
+			return
+		}
+
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if h.LoginLimiter != nil {
+		if err := h.LoginLimiter.Reset(ctx.Request.Context(), loginIdentifier); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; LoginLimiter.Reset error: %v", logPrefix, err))
+		}
+	}
+
+	data := h.startSession(ctx, logPrefix, user, result)
+
+	res := response.Response(http.StatusOK, "success", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v;", logPrefix, utils.JsonEncode(result.Token)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// LoginTwoFactorSetup godoc
+// @Summary Start required 2FA enrollment during login
+// @Description Return a TOTP secret and provisioning URI for a user whose role requires 2FA, using the challenge token returned by login
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.TwoFactorChallengeRequest true "Challenge token"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Router /user/login/2fa/setup [post]
+func (h *HandlerUser) LoginTwoFactorSetup(ctx *gin.Context) {
+	var req dto.TwoFactorChallengeRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][LoginTwoFactorSetup]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	data, err := h.Service.SetupTwoFactorWithChallenge(req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.SetupTwoFactorWithChallenge; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Scan the QR code with your authenticator app", logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start 2FA enrollment
+// @Description Generate a new TOTP secret and provisioning URI for the QR code. 2FA is enabled once a code is confirmed
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /user/2fa/setup [post]
+func (h *HandlerUser) SetupTwoFactor(ctx *gin.Context) {
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][SetupTwoFactor]", logId)
+
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+
+	data, err := h.Service.SetupTwoFactor(userId)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.SetupTwoFactor; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Scan the QR code with your authenticator app", logId, data)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// EnableTwoFactor godoc
+// @Summary Confirm 2FA enrollment
+// @Description Verify a code from the authenticator app to enable 2FA. The recovery codes are only shown once
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /user/2fa/enable [post]
+func (h *HandlerUser) EnableTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][EnableTwoFactor]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+
+	codes, err := h.Service.EnableTwoFactor(userId, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.EnableTwoFactor; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Two-factor authentication enabled", logId, map[string]interface{}{"recovery_codes": codes})
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable 2FA
+// @Description Disable 2FA with the current password and a TOTP or recovery code. Not allowed when 2FA is required for the user's role
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.TwoFactorDisableRequest true "Password and code"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 403 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /user/2fa/disable [post]
+func (h *HandlerUser) DisableTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorDisableRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][DisableTwoFactor]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+	role := utils.InterfaceString(authData["role"])
+	required, err := h.isTwoFactorRequired(role)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; AppConfigService.IsEnabled; Error: %+v", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+	if required {
+		res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+		res.Error = response.Errors{Code: http.StatusForbidden, Message: "two-factor authentication is required for your role"}
+		ctx.JSON(http.StatusForbidden, res)
+		return
+	}
+
+	if err := h.Service.DisableTwoFactor(userId, req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.DisableTwoFactor; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Two-factor authentication disabled", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate 2FA recovery codes
+// @Description Replace all recovery codes after verifying a TOTP code, previous codes stop working
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Security ApiKeyAuth
+// @Router /user/2fa/recovery-codes [post]
+func (h *HandlerUser) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][RegenerateRecoveryCodes]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	authData := utils.GetAuthData(ctx)
+	userId := utils.InterfaceString(authData["user_id"])
+
+	codes, err := h.Service.RegenerateRecoveryCodes(userId, req)
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RegenerateRecoveryCodes; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Recovery codes regenerated", logId, map[string]interface{}{"recovery_codes": codes})
+	ctx.JSON(http.StatusOK, res)
+}
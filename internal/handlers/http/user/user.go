@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"safety-riding/infrastructure/database"
+	domainsession "safety-riding/internal/domain/session"
 	"safety-riding/internal/dto"
 	interfaceappconfig "safety-riding/internal/interfaces/appconfig"
 	interfaceuser "safety-riding/internal/interfaces/user"
@@ -28,7 +29,14 @@ import (
 	"gorm.io/gorm"
 )
 
-const publicRegistrationConfigKey = "auth.public_registration_enabled"
+const (
+	publicRegistrationConfigKey = "auth.public_registration_enabled"
+	emailVerificationConfigKey  = "auth.email_verification_required"
+	twoFactorConfigKeyPrefix    = "auth.two_factor_required."
+)
+
+// twoFactorConfigurableRoles are the roles whose 2FA enforcement can be switched on in app_configs
+var twoFactorConfigurableRoles = []string{utils.RoleSuperAdmin, utils.RoleAdmin}
 
 type HandlerUser struct {
 	Service          interfaceuser.ServiceUserInterface
@@ -105,7 +113,7 @@ func (h *HandlerUser) Register(ctx *gin.Context) {
 		return
 	}
 
-	res := response.Response(http.StatusCreated, "User registered successfully", logId, data)
+	res := response.Response(http.StatusCreated, "User registered successfully. Please check your email to verify your account", logId, data)
 	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v;", logPrefix, utils.JsonEncode(data)))
 	ctx.JSON(http.StatusCreated, res)
 }
@@ -207,9 +215,20 @@ func (h *HandlerUser) Login(ctx *gin.Context) {
 		}
 	}
 
-	token, err := h.Service.LoginUser(req, logId.String())
+	policy, err := h.loginPolicy()
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; AppConfigService.IsEnabled; Error: %+v", logPrefix, err))
+	}
+
+	result, err := h.Service.LoginUser(req, logId.String(), policy)
 	if err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.LoginUser; ERROR: %s;", logPrefix, err))
+		if err.Error() == messages.ErrEmailNotVerified {
+			res := response.Response(http.StatusForbidden, messages.MsgDenied, logId, nil)
+			res.Error = response.Errors{Code: http.StatusForbidden, Message: "please verify your email address before logging in"}
+			ctx.JSON(http.StatusForbidden, res)
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) || err.Error() == messages.ErrHashPassword {
 			if h.LoginLimiter != nil {
 				blocked, ttl, limiterErr := h.LoginLimiter.RegisterFailure(ctx.Request.Context(), loginIdentifier)
@@ -235,31 +254,74 @@ func (h *HandlerUser) Login(ctx *gin.Context) {
 		return
 	}
 
+	// Failed attempts are only cleared once the second factor is verified as well
+	if result.TwoFactorRequired {
+		res := response.Response(http.StatusOK, "Two-factor authentication required", logId, result)
+		logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Two-factor challenge issued;", logPrefix))
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
 	if h.LoginLimiter != nil {
 		if err := h.LoginLimiter.Reset(ctx.Request.Context(), loginIdentifier); err != nil {
 			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; LoginLimiter.Reset error: %v", logPrefix, err))
 		}
 	}
 
-	// Create session if Redis is available
-	if redisClient := database.GetRedisClient(); redisClient != nil {
-		user, errUser := h.Service.GetUserByEmail(req.Email)
-		if errUser == nil {
-			sRepo := sessionRepo.NewSessionRepository(redisClient)
-			sSvc := sessionSvc.NewSessionService(sRepo)
-
-			session, errSession := sSvc.CreateSession(context.Background(), &user, token, ctx)
-			if errSession != nil {
-				logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Failed to create session: %v", logPrefix, errSession))
-				// Continue anyway - session is optional
-			} else {
-				logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("%s; Session created: %s", logPrefix, session.SessionID))
-			}
+	user, errUser := h.Service.GetUserByEmail(req.Email)
+	if errUser != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.GetUserByEmail; Error: %+v", logPrefix, errUser))
+	}
+
+	data := h.startSession(ctx, logPrefix, user, result)
+
+	res := response.Response(http.StatusOK, "success", logId, data)
+	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v;", logPrefix, utils.JsonEncode(result.Token)))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and refresh token. A refresh token can only be used once, reusing it revokes the session
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 401 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /user/token/refresh [post]
+func (h *HandlerUser) RefreshToken(ctx *gin.Context) {
+	var req dto.RefreshTokenRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserController][RefreshToken]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	token, refreshToken, err := h.Service.RefreshToken(req, logId.String())
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.RefreshToken; ERROR: %s;", logPrefix, err))
+		if errors.Is(err, domainsession.ErrRefreshTokenInvalid) || errors.Is(err, domainsession.ErrRefreshTokenReused) {
+			res := response.Response(http.StatusUnauthorized, messages.MsgFail, logId, nil)
+			res.Error = "Please login and try again"
+			ctx.JSON(http.StatusUnauthorized, res)
+			return
 		}
+
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
 	}
 
-	res := response.Response(http.StatusOK, "success", logId, map[string]interface{}{"token": token})
-	logger.WriteLog(logger.LogLevelDebug, fmt.Sprintf("%s; Response: %+v;", logPrefix, utils.JsonEncode(token)))
+	res := response.Response(http.StatusOK, "success", logId, map[string]interface{}{"token": token, "refresh_token": refreshToken})
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -638,19 +700,14 @@ func (h *HandlerUser) ForgotPassword(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.Service.ForgotPassword(req)
-	if err != nil {
+	if err := h.Service.ForgotPassword(req); err != nil {
 		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ForgotPassword; ERROR: %s;", logPrefix, err))
 		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
-		res.Error = err.Error()
 		ctx.JSON(http.StatusInternalServerError, res)
 		return
 	}
 
-	// MOCK: Log the token for testing purposes since we don't have an email service
-	logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("MOCK EMAIL SENT: Reset Token for %s: %s", req.Email, token))
-
-	res := response.Response(http.StatusOK, "Password reset instructions sent to your email", logId, token)
+	res := response.Response(http.StatusOK, "Password reset instructions sent to your email", logId, nil)
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -690,6 +747,77 @@ func (h *HandlerUser) ResetPassword(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Verify the email address of a self-registered user using the emailed token
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Router /user/verify-email [post]
+func (h *HandlerUser) VerifyEmail(ctx *gin.Context) {
+	var req dto.VerifyEmailRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserHandler][VerifyEmail]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := h.Service.VerifyEmail(req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.VerifyEmail; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusBadRequest, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "Email verified successfully", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ResendVerificationEmail godoc
+// @Summary Resend verification email
+// @Description Send a new email verification link, previous links stop working
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body dto.ResendVerificationRequest true "Email address"
+// @Success 200 {object} response.Success
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /user/verify-email/resend [post]
+func (h *HandlerUser) ResendVerificationEmail(ctx *gin.Context) {
+	var req dto.ResendVerificationRequest
+	logId := utils.GenerateLogId(ctx)
+	logPrefix := fmt.Sprintf("[%s][UserHandler][ResendVerificationEmail]", logId)
+
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; BindJSON ERROR: %s;", logPrefix, err.Error()))
+		res := response.Response(http.StatusBadRequest, messages.InvalidRequest, logId, nil)
+		res.Error = utils.ValidateError(err, reflect.TypeOf(req), "json")
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := h.Service.ResendVerificationEmail(req); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Service.ResendVerificationEmail; ERROR: %s;", logPrefix, err))
+		res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+		res.Error = err.Error()
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := response.Response(http.StatusOK, "If the account exists and is not verified yet, a verification email has been sent", logId, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
 // Delete godoc
 // @Summary Delete a user
 // @Description Delete a user
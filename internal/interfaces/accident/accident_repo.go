@@ -7,7 +7,7 @@ import (
 
 type RepoAccidentInterface interface {
 	Create(accident domainaccident.Accident) error
-	GetByID(id string) (domainaccident.Accident, error)
+	GetByID(id string, scope filter.RegionScope) (domainaccident.Accident, error)
 	Update(accident domainaccident.Accident) error
 	Fetch(params filter.BaseParams) ([]domainaccident.Accident, int64, error)
 	Delete(id string) error
@@ -17,5 +17,8 @@ type RepoAccidentInterface interface {
 	GetPhotosByAccidentID(accidentId string) ([]domainaccident.AccidentPhoto, error)
 	GetPhotoByID(photoId string) (domainaccident.AccidentPhoto, error)
 	DeletePhoto(photoId string) error
+	UpdatePhoto(photo domainaccident.AccidentPhoto) error
+	ReorderPhotos(accidentId string, photoIds []string) error
+	NormalizePhotoOrder(accidentId string) error
 	DeletePhotosByAccidentID(accidentId string) error
 }
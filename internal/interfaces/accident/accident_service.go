@@ -10,12 +10,16 @@ import (
 )
 
 type ServiceAccidentInterface interface {
-	AddAccident(username string, req dto.AddAccident) (domainaccident.Accident, error)
-	GetAccidentById(id string) (domainaccident.Accident, error)
-	UpdateAccident(id, username string, req dto.UpdateAccident) (domainaccident.Accident, error)
+	AddAccident(username string, scope filter.RegionScope, req dto.AddAccident) (domainaccident.Accident, error)
+	GetAccidentById(id string, scope filter.RegionScope) (domainaccident.Accident, error)
+	UpdateAccident(id, username string, scope filter.RegionScope, req dto.UpdateAccident) (domainaccident.Accident, error)
 	FetchAccident(params filter.BaseParams) ([]domainaccident.Accident, int64, error)
-	DeleteAccident(id, username string) error
-	AddAccidentPhotos(accidentId, username string, photos []dto.AddAccidentPhoto) ([]domainaccident.AccidentPhoto, error)
-	DeleteAccidentPhoto(photoId, username string) error
-	AddAccidentPhotosFromFiles(ctx context.Context, accidentId, username string, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainaccident.AccidentPhoto, error)
+	DeleteAccident(id, username string, scope filter.RegionScope) error
+	AddAccidentPhotos(accidentId, username string, scope filter.RegionScope, photos []dto.AddAccidentPhoto) ([]domainaccident.AccidentPhoto, error)
+	DeleteAccidentPhoto(photoId, username string, scope filter.RegionScope) error
+	UpdateAccidentPhoto(photoId, username string, scope filter.RegionScope, req dto.UpdateAccidentPhoto) (domainaccident.AccidentPhoto, error)
+	ReorderAccidentPhotos(accidentId, username string, scope filter.RegionScope, req dto.ReorderAccidentPhotos) ([]domainaccident.AccidentPhoto, error)
+	RequestAccidentPhotoUploads(ctx context.Context, accidentId string, scope filter.RegionScope, req dto.RequestPhotoUploads) ([]dto.PhotoUploadSlot, error)
+	ConfirmAccidentPhotoUploads(ctx context.Context, accidentId, username string, scope filter.RegionScope, req dto.ConfirmPhotoUploads) ([]domainaccident.AccidentPhoto, error)
+	AddAccidentPhotosFromFiles(ctx context.Context, accidentId, username string, scope filter.RegionScope, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainaccident.AccidentPhoto, error)
 }
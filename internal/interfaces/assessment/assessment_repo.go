@@ -0,0 +1,31 @@
+package interfaceassessment
+
+import (
+	domainassessment "safety-riding/internal/domain/assessment"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+type RepoQuestionBankInterface interface {
+	Create(bank domainassessment.QuestionBank) error
+	GetByID(id string) (domainassessment.QuestionBank, error)
+	Update(bank domainassessment.QuestionBank, replaceQuestions bool) error
+	Fetch(params filter.BaseParams) ([]domainassessment.QuestionBank, int64, error)
+	Delete(id string) error
+	IsInUse(id string) (bool, error)
+	HasSubmissions(id string) (bool, error)
+}
+
+type RepoAssessmentInterface interface {
+	Create(assessment domainassessment.EventAssessment) error
+	GetByEvent(eventId, assessmentType string) (domainassessment.EventAssessment, error)
+	FetchSummaries(eventId string) ([]dto.EventAssessmentSummary, error)
+	Delete(id string) error
+	CountSubmissions(assessmentId string) (int64, error)
+
+	CreateSubmission(submission domainassessment.Submission) error
+	HasSubmitted(assessmentId, participantId string) (bool, error)
+	FetchSubmissions(assessmentId string, params filter.BaseParams) ([]domainassessment.Submission, int64, error)
+	FetchEventResults(eventId string) ([]dto.ParticipantAssessmentResult, error)
+	GetStats(groupBy string, params filter.BaseParams) ([]dto.AssessmentStatsItem, error)
+}
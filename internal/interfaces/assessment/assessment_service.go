@@ -0,0 +1,23 @@
+package interfaceassessment
+
+import (
+	domainassessment "safety-riding/internal/domain/assessment"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+type ServiceAssessmentInterface interface {
+	AddQuestionBank(username string, req dto.AddQuestionBank) (domainassessment.QuestionBank, error)
+	GetQuestionBankById(id string) (domainassessment.QuestionBank, error)
+	UpdateQuestionBank(id, username string, req dto.UpdateQuestionBank) (domainassessment.QuestionBank, error)
+	FetchQuestionBank(params filter.BaseParams) ([]domainassessment.QuestionBank, int64, error)
+	DeleteQuestionBank(id, username string) error
+
+	AddEventAssessment(eventId, username string, scope filter.RegionScope, req dto.AddEventAssessment) (domainassessment.EventAssessment, error)
+	GetEventAssessment(eventId, assessmentType string, scope filter.RegionScope) (domainassessment.EventAssessment, error)
+	DeleteEventAssessment(eventId, assessmentType, username string, scope filter.RegionScope) error
+	SubmitAssessment(eventId, assessmentType, username string, scope filter.RegionScope, req dto.SubmitAssessment) (domainassessment.Submission, error)
+	FetchSubmissions(eventId, assessmentType string, params filter.BaseParams) ([]domainassessment.Submission, int64, error)
+	GetEventResults(eventId string, scope filter.RegionScope) (dto.EventAssessmentResults, error)
+	GetAssessmentStats(groupBy string, params filter.BaseParams) (dto.AssessmentStatsResponse, error)
+}
@@ -0,0 +1,11 @@
+package interfaceattachment
+
+import domainattachment "safety-riding/internal/domain/attachment"
+
+type RepoAttachmentInterface interface {
+	CreateBatch(attachments []domainattachment.Attachment) error
+	GetByID(entityType, entityId, id string) (domainattachment.Attachment, error)
+	// FetchByEntity returns the attachments of a resource, newest first
+	FetchByEntity(entityType, entityId string) ([]domainattachment.Attachment, error)
+	Delete(id, username string) error
+}
@@ -0,0 +1,15 @@
+package interfaceattachment
+
+import (
+	"context"
+	"mime/multipart"
+
+	domainattachment "safety-riding/internal/domain/attachment"
+	"safety-riding/pkg/filter"
+)
+
+type ServiceAttachmentInterface interface {
+	FetchAttachments(ctx context.Context, entityType, entityId string, scope filter.RegionScope) ([]domainattachment.Attachment, error)
+	AddAttachments(ctx context.Context, entityType, entityId, username string, scope filter.RegionScope, files []*multipart.FileHeader, descriptions []string) ([]domainattachment.Attachment, error)
+	DeleteAttachment(ctx context.Context, entityType, entityId, id, username string, scope filter.RegionScope) error
+}
@@ -0,0 +1,12 @@
+package interfaceauditlog
+
+import (
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	"safety-riding/pkg/filter"
+)
+
+type RepoAuditLogInterface interface {
+	Create(log domainauditlog.AuditLog) error
+	GetByID(id string) (domainauditlog.AuditLog, error)
+	Fetch(params filter.BaseParams) ([]domainauditlog.AuditLog, int64, error)
+}
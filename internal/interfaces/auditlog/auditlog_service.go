@@ -0,0 +1,17 @@
+package interfaceauditlog
+
+import (
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	"safety-riding/pkg/filter"
+)
+
+// AuditRecorder is implemented by the audit log service and injected into services that change data.
+type AuditRecorder interface {
+	Record(actor, action, entityType, entityId string, before, after interface{})
+}
+
+type ServiceAuditLogInterface interface {
+	AuditRecorder
+	GetAuditLogById(id string) (domainauditlog.AuditLog, error)
+	FetchAuditLog(params filter.BaseParams) ([]domainauditlog.AuditLog, int64, error)
+}
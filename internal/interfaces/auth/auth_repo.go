@@ -1,8 +1,14 @@
 package interfaceauth
 
-import "safety-riding/internal/domain/auth"
+import (
+	"time"
+
+	"safety-riding/internal/domain/auth"
+)
 
 type RepoAuthInterface interface {
 	Store(m domainauth.Blacklist) error
 	GetByToken(token string) (domainauth.Blacklist, error)
+	RevokeUserTokens(userId string, revokedAt time.Time) error
+	GetRevocation(userId string) (domainauth.TokenRevocation, error)
 }
@@ -0,0 +1,10 @@
+package interfaceauth
+
+import "safety-riding/internal/domain/auth"
+
+type RepoUserTokenInterface interface {
+	Store(m domainauth.UserToken) error
+	GetByHash(purpose, tokenHash string) (domainauth.UserToken, error)
+	MarkUsed(id string) (bool, error)
+	RevokeByUser(userId, purpose string) error
+}
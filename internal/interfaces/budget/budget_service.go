@@ -7,14 +7,14 @@ import (
 )
 
 type ServiceBudgetInterface interface {
-	AddBudget(username string, req dto.AddEventBudget) (domainbudget.EventBudget, error)
-	GetBudgetById(id string) (domainbudget.EventBudget, error)
-	UpdateBudget(id, username string, canOverrideFinalized bool, req dto.UpdateEventBudget) (domainbudget.EventBudget, error)
+	AddBudget(username string, scope filter.RegionScope, req dto.AddEventBudget) (domainbudget.EventBudget, error)
+	GetBudgetById(id string, scope filter.RegionScope) (domainbudget.EventBudget, error)
+	UpdateBudget(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.UpdateEventBudget) (domainbudget.EventBudget, error)
 	FetchBudget(params filter.BaseParams) ([]domainbudget.EventBudget, int64, error)
-	DeleteBudget(id, username string, canOverrideFinalized bool) error
-	GetBudgetsByEvent(eventId string) ([]domainbudget.EventBudget, error)
-	GetBudgetsByMonthYear(month, year int) ([]domainbudget.EventBudget, error)
-	GetMonthlySummary(month, year int) (domainbudget.BudgetSummary, error)
-	GetYearlySummary(year int) ([]domainbudget.BudgetSummary, error)
-	GetEventSummary(eventId string) (domainbudget.BudgetSummary, error)
+	DeleteBudget(id, username string, canOverrideFinalized bool, scope filter.RegionScope) error
+	GetBudgetsByEvent(eventId string, scope filter.RegionScope) ([]domainbudget.EventBudget, error)
+	GetBudgetsByMonthYear(month, year int, scope filter.RegionScope) ([]domainbudget.EventBudget, error)
+	GetMonthlySummary(month, year int, scope filter.RegionScope) (domainbudget.BudgetSummary, error)
+	GetYearlySummary(year int, scope filter.RegionScope) ([]domainbudget.BudgetSummary, error)
+	GetEventSummary(eventId string, scope filter.RegionScope) (domainbudget.BudgetSummary, error)
 }
@@ -0,0 +1,23 @@
+package interfacecertificate
+
+import (
+	domaincertificate "safety-riding/internal/domain/certificate"
+	"safety-riding/pkg/filter"
+)
+
+type RepoTemplateInterface interface {
+	Create(template domaincertificate.Template) error
+	GetByID(id string) (domaincertificate.Template, error)
+	Update(template domaincertificate.Template) error
+	Fetch(params filter.BaseParams) ([]domaincertificate.Template, int64, error)
+	Delete(id string) error
+}
+
+type RepoCertificateInterface interface {
+	// Save stores the certificates and, for a merged generation, the batch replacing the previous one of the event
+	Save(certificates []domaincertificate.Certificate, batch *domaincertificate.Batch) error
+	GetBatch(eventId string) (domaincertificate.Batch, error)
+	GetByEvent(eventId string) ([]domaincertificate.Certificate, error)
+	FetchByEvent(eventId string, params filter.BaseParams) ([]domaincertificate.Certificate, int64, error)
+	GetByVerificationCode(code string) (domaincertificate.Certificate, error)
+}
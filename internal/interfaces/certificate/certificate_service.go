@@ -0,0 +1,24 @@
+package interfacecertificate
+
+import (
+	"context"
+	"mime/multipart"
+
+	domaincertificate "safety-riding/internal/domain/certificate"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+type ServiceCertificateInterface interface {
+	AddTemplate(username string, req dto.AddCertificateTemplate) (domaincertificate.Template, error)
+	GetTemplateById(id string) (domaincertificate.Template, error)
+	UpdateTemplate(id, username string, req dto.UpdateCertificateTemplate) (domaincertificate.Template, error)
+	UploadTemplateBackground(ctx context.Context, id, username string, fileHeader *multipart.FileHeader) (domaincertificate.Template, error)
+	FetchTemplate(params filter.BaseParams) ([]domaincertificate.Template, int64, error)
+	DeleteTemplate(id, username string) error
+
+	GenerateCertificates(ctx context.Context, eventId, username string, scope filter.RegionScope, req dto.GenerateCertificates) (dto.GenerateCertificatesResponse, error)
+	FetchCertificates(eventId string, params filter.BaseParams) ([]domaincertificate.Certificate, int64, error)
+	GetMergedCertificates(eventId string, scope filter.RegionScope) (domaincertificate.Batch, error)
+	VerifyCertificate(code string) (dto.CertificateVerification, error)
+}
@@ -1,9 +1,12 @@
 package interfacedashboard
 
-import "safety-riding/internal/dto"
+import (
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
 
 type RepoDashboardInterface interface {
-	GetBasicStats() (dto.BasicStats, error)
-	GetStats() (*dto.DashboardStats, error)
-	GetAccidentRecommendations() ([]dto.AccidentRecommendation, error)
+	GetBasicStats(scope filter.RegionScope) (dto.BasicStats, error)
+	GetStats(scope filter.RegionScope) (*dto.DashboardStats, error)
+	GetAccidentRecommendations(scope filter.RegionScope) ([]dto.AccidentRecommendation, error)
 }
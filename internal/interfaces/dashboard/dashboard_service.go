@@ -1,9 +1,12 @@
 package interfacedashboard
 
-import "safety-riding/internal/dto"
+import (
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
 
 type ServiceDashboardInterface interface {
-	GetSummary() (dto.DashboardSummary, error)
-	GetStats() (*dto.DashboardStats, error)
-	GetAccidentRecommendations() ([]dto.AccidentRecommendation, error)
+	GetSummary(scope filter.RegionScope) (dto.DashboardSummary, error)
+	GetStats(scope filter.RegionScope) (*dto.DashboardStats, error)
+	GetAccidentRecommendations(scope filter.RegionScope) ([]dto.AccidentRecommendation, error)
 }
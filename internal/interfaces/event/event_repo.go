@@ -9,20 +9,41 @@ import (
 
 type RepoEventInterface interface {
 	Create(event domainevent.Event) error
-	GetByID(id string) (domainevent.Event, error)
+	GetByID(id string, scope filter.RegionScope) (domainevent.Event, error)
 	Update(event domainevent.Event) error
 	UpdateById(id string, event domainevent.Event) error
 	Fetch(params filter.BaseParams) ([]domainevent.Event, int64, error)
 	Delete(id string) error
-	FetchCompletedWithCoords(since time.Time) ([]domainevent.Event, error)
+	FetchCompletedWithCoords(since time.Time, scope filter.RegionScope) ([]domainevent.Event, error)
+	FetchByDateRange(from, to string, scope filter.RegionScope) ([]domainevent.Event, error)
+	FetchForFeed(since time.Time, scope filter.RegionScope) ([]domainevent.Event, error)
+	// GetIdsByRequestNumbers maps the training request numbers that were converted to the id of their event
+	GetIdsByRequestNumbers(requestNumbers []int) (map[int]string, error)
 
 	// Event Photo methods
 	AddPhotos(photos []domainevent.EventPhoto) error
 	GetPhotosByEventID(eventId string) ([]domainevent.EventPhoto, error)
 	GetPhotoByID(photoId string) (domainevent.EventPhoto, error)
 	DeletePhoto(photoId string) error
+	UpdatePhoto(photo domainevent.EventPhoto) error
+	ReorderPhotos(eventId string, photoIds []string) error
+	NormalizePhotoOrder(eventId string) error
+	GetCoverPhotos(eventIds []string) (map[string]domainevent.EventPhoto, error)
 	DeletePhotosByEventID(eventId string) error
 
+	// CountParticipants returns the roster size, the attendees count is derived from it when it is not empty
+	CountParticipants(eventId string) (int64, error)
+
+	// Status history methods
+	AddStatusHistory(history domainevent.EventStatusHistory) error
+	// UpdateStatus moves the event from history.FromStatus to history.ToStatus and records the transition,
+	// it fails when the status was changed in the meantime
+	UpdateStatus(history domainevent.EventStatusHistory) error
+	// UpdateWithStatus saves the event fields together with a status transition, in one transaction,
+	// it fails like UpdateStatus when the status was changed in the meantime
+	UpdateWithStatus(id string, event domainevent.Event, history domainevent.EventStatusHistory) error
+	GetStatusHistory(eventId string) ([]domainevent.EventStatusHistory, error)
+
 	// On The Spot Sales methods
 	AddOnTheSpotSales(sales []domainevent.EventOnTheSpotSale) error
 	DeleteOnTheSpotSalesByEventID(eventId string) error
@@ -8,16 +8,32 @@ import (
 	domainevent "safety-riding/internal/domain/event"
 	"safety-riding/internal/dto"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/ical"
 )
 
 type ServiceEventInterface interface {
-	AddEvent(username string, req dto.AddEvent) (domainevent.Event, error)
-	GetEventById(id string) (domainevent.Event, error)
-	UpdateEvent(id, username string, canOverrideFinalized bool, req dto.UpdateEvent) (domainevent.Event, error)
+	AddEvent(username string, scope filter.RegionScope, req dto.AddEvent) (domainevent.Event, error)
+	GetEventById(id string, scope filter.RegionScope) (domainevent.Event, error)
+	UpdateEvent(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.UpdateEvent) (domainevent.Event, error)
+	TransitionStatus(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.TransitionEventStatus) (domainevent.Event, error)
+	GetStatusHistory(id string, scope filter.RegionScope) ([]domainevent.EventStatusHistory, error)
 	FetchEvent(params filter.BaseParams) ([]domainevent.Event, int64, error)
-	DeleteEvent(id, username string, canOverrideFinalized bool) error
-	AddEventPhotos(eventId, username string, photos []dto.AddEventPhoto) ([]domainevent.EventPhoto, error)
-	DeleteEventPhoto(photoId, username string) error
-	AddEventPhotosFromFiles(ctx context.Context, eventId, username string, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainevent.EventPhoto, error)
-	GetCompletedEventsForMap(since time.Time) ([]dto.EventMapData, error)
+	DeleteEvent(id, username string, canOverrideFinalized bool, scope filter.RegionScope) error
+	AddEventPhotos(eventId, username string, scope filter.RegionScope, photos []dto.AddEventPhoto) ([]domainevent.EventPhoto, error)
+	DeleteEventPhoto(photoId, username string, scope filter.RegionScope) error
+	UpdateEventPhoto(photoId, username string, scope filter.RegionScope, req dto.UpdateEventPhoto) (domainevent.EventPhoto, error)
+	ReorderEventPhotos(eventId, username string, scope filter.RegionScope, req dto.ReorderEventPhotos) ([]domainevent.EventPhoto, error)
+	RequestEventPhotoUploads(ctx context.Context, eventId string, scope filter.RegionScope, req dto.RequestPhotoUploads) ([]dto.PhotoUploadSlot, error)
+	ConfirmEventPhotoUploads(ctx context.Context, eventId, username string, scope filter.RegionScope, req dto.ConfirmPhotoUploads) ([]domainevent.EventPhoto, error)
+	AddEventPhotosFromFiles(ctx context.Context, eventId, username string, scope filter.RegionScope, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainevent.EventPhoto, error)
+	GetCompletedEventsForMap(since time.Time, scope filter.RegionScope) ([]dto.EventMapData, error)
+	GetCalendar(from, to time.Time, scope filter.RegionScope) ([]dto.EventCalendarDay, error)
+	CreateCalendarFeed(userId string) (dto.CalendarFeed, error)
+	RevokeCalendarFeed(userId string) error
+	GetCalendarFeed(scope filter.RegionScope) (ical.Calendar, error)
+	RecomputeVisits() (dto.VisitRecomputeResult, error)
+	FetchSchoolVisits(schoolId string, scope filter.RegionScope) ([]dto.Visit, error)
+	FetchPublicVisits(publicId string) ([]dto.Visit, error)
+	DraftFromRequest(formId string, scope filter.RegionScope) (dto.EventRequestDraft, error)
+	CreateFromRequest(formId, username string, scope filter.RegionScope, req dto.AddEvent) (domainevent.Event, error)
 }
@@ -0,0 +1,22 @@
+package interfaceinstructor
+
+import (
+	domaininstructor "safety-riding/internal/domain/instructor"
+	"safety-riding/pkg/filter"
+)
+
+type RepoInstructorInterface interface {
+	Create(instructor domaininstructor.Instructor) error
+	GetByID(id string) (domaininstructor.Instructor, error)
+	GetByIDs(ids []string) ([]domaininstructor.Instructor, error)
+	Update(instructor domaininstructor.Instructor) error
+	Fetch(params filter.BaseParams) ([]domaininstructor.Instructor, int64, error)
+	Delete(id string) error
+
+	// SetEventInstructors replaces the instructors of an event and refreshes its instructor_name and instructor_phone,
+	// an event left without instructors keeps them as free text
+	SetEventInstructors(eventId string, assignments []domaininstructor.EventInstructor) error
+	// FetchBookings returns the events of the instructors held between from and to (YYYY-MM-DD, both inclusive),
+	// cancelled events and excludeEventId are left out
+	FetchBookings(instructorIds []string, from, to, excludeEventId string, scope filter.RegionScope) ([]domaininstructor.Booking, error)
+}
@@ -0,0 +1,16 @@
+package interfaceinstructor
+
+import (
+	domaininstructor "safety-riding/internal/domain/instructor"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+type ServiceInstructorInterface interface {
+	AddInstructor(username string, req dto.AddInstructor) (domaininstructor.Instructor, error)
+	GetInstructorById(id string) (domaininstructor.Instructor, error)
+	UpdateInstructor(id, username string, req dto.UpdateInstructor) (domaininstructor.Instructor, error)
+	FetchInstructor(params filter.BaseParams) ([]domaininstructor.Instructor, int64, error)
+	DeleteInstructor(id, username string) error
+	GetSchedule(id, from, to string, scope filter.RegionScope) (dto.InstructorSchedule, error)
+}
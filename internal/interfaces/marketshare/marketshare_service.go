@@ -7,12 +7,12 @@ import (
 )
 
 type ServiceMarketShareInterface interface {
-	AddMarketShare(username string, req dto.AddMarketShare) (domainmarketshare.MarketShare, error)
-	GetMarketShareById(id string) (domainmarketshare.MarketShare, error)
-	UpdateMarketShare(id, username string, req dto.UpdateMarketShare) (domainmarketshare.MarketShare, error)
+	AddMarketShare(username string, scope filter.RegionScope, req dto.AddMarketShare) (domainmarketshare.MarketShare, error)
+	GetMarketShareById(id string, scope filter.RegionScope) (domainmarketshare.MarketShare, error)
+	UpdateMarketShare(id, username string, scope filter.RegionScope, req dto.UpdateMarketShare) (domainmarketshare.MarketShare, error)
 	FetchMarketShare(params filter.BaseParams) ([]domainmarketshare.MarketShare, int64, error)
-	DeleteMarketShare(id, username string) error
-	GetTopDistricts(year, month, limit int) ([]domainmarketshare.TopDistrict, error)
-	GetTopCities(year, month, limit int, sortOrder string) ([]domainmarketshare.TopCity, error)
-	GetSummary(level string, year, month int, provinceID, cityID, districtID string) ([]domainmarketshare.MarketShareSummary, error)
+	DeleteMarketShare(id, username string, scope filter.RegionScope) error
+	GetTopDistricts(year, month, limit int, scope filter.RegionScope) ([]domainmarketshare.TopDistrict, error)
+	GetTopCities(year, month, limit int, sortOrder string, scope filter.RegionScope) ([]domainmarketshare.TopCity, error)
+	GetSummary(level string, year, month int, provinceID, cityID, districtID string, scope filter.RegionScope) ([]domainmarketshare.MarketShareSummary, error)
 }
@@ -0,0 +1,21 @@
+package interfaceparticipant
+
+import (
+	"time"
+
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/pkg/filter"
+)
+
+// RepoParticipantInterface stores event rosters, CreateBatch and Delete also refresh events.attendees_count
+type RepoParticipantInterface interface {
+	CreateBatch(participants []domainparticipant.EventParticipant) error
+	GetByID(id string) (domainparticipant.EventParticipant, error)
+	FetchByEvent(eventId string, params filter.BaseParams) ([]domainparticipant.EventParticipant, int64, error)
+	GetByEvent(eventId string) ([]domainparticipant.EventParticipant, error)
+	GetIdentityKeys(eventId string) (map[string]bool, error)
+	GetByIdentityKey(eventId, identityKey string) (domainparticipant.EventParticipant, error)
+	MarkCheckedIn(id string, at time.Time) (bool, error)
+	CountCheckIns(eventId string) (registered, checkedIn int64, lastCheckInAt *time.Time, err error)
+	Delete(id string) error
+}
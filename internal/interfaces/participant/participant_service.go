@@ -0,0 +1,21 @@
+package interfaceparticipant
+
+import (
+	"mime/multipart"
+
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+type ServiceParticipantInterface interface {
+	AddParticipants(eventId, username string, scope filter.RegionScope, req dto.AddParticipants) ([]domainparticipant.EventParticipant, error)
+	ImportParticipants(eventId, username string, scope filter.RegionScope, fileHeader *multipart.FileHeader, dryRun bool) (dto.ImportParticipantResponse, error)
+	FetchParticipants(eventId string, params filter.BaseParams) ([]domainparticipant.EventParticipant, int64, error)
+	DeleteParticipant(eventId, participantId, username string, scope filter.RegionScope) error
+
+	GetCheckInCode(eventId string, scope filter.RegionScope) (dto.CheckInCode, error)
+	GetCheckInEvent(token string) (dto.CheckInEvent, error)
+	CheckIn(token string, req dto.CheckIn) (dto.CheckInResult, error)
+	GetAttendance(eventId string, scope filter.RegionScope) (dto.EventAttendance, error)
+}
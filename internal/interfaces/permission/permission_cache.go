@@ -0,0 +1,7 @@
+package interfacepermission
+
+// PermissionCacheInvalidator is implemented by permission repositories that cache resolved user permissions
+type PermissionCacheInvalidator interface {
+	InvalidateUser(userId string)
+	InvalidateAll()
+}
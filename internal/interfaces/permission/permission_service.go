@@ -7,11 +7,11 @@ import (
 )
 
 type ServicePermissionInterface interface {
-	Create(req dto.PermissionCreate) (domainpermission.Permission, error)
+	Create(username string, req dto.PermissionCreate) (domainpermission.Permission, error)
 	GetByID(id string) (domainpermission.Permission, error)
 	GetAll(params filter.BaseParams) ([]domainpermission.Permission, int64, error)
 	GetByResource(resource string) ([]domainpermission.Permission, error)
 	GetUserPermissions(userId string) ([]domainpermission.Permission, error)
-	Update(id string, req dto.PermissionUpdate) (domainpermission.Permission, error)
-	Delete(id string) error
+	Update(id, username string, req dto.PermissionUpdate) (domainpermission.Permission, error)
+	Delete(id, username string) error
 }
@@ -12,6 +12,8 @@ type RepoPublicInterface interface {
 	Update(public domainpublic.Public) error
 	Fetch(params filter.BaseParams) ([]domainpublic.Public, int64, error)
 	Delete(id string) error
+	// FindMatches returns the public entities named in text or located at address, longest names first
+	FindMatches(text, address string) ([]domainpublic.Public, error)
 	GetEducationStats(params filter.BaseParams) ([]map[string]interface{}, error)
 	GetSummary() (*dto.PublicSummary, error)
 	GetForMap() ([]dto.PublicMapItem, error)
@@ -7,13 +7,13 @@ import (
 )
 
 type ServiceRoleInterface interface {
-	Create(req dto.RoleCreate) (domainrole.Role, error)
+	Create(username string, req dto.RoleCreate) (domainrole.Role, error)
 	GetByID(id string) (domainrole.Role, error)
 	GetByIDWithDetails(id string) (dto.RoleWithDetails, error)
 	GetAll(params filter.BaseParams, currentUserRole string) ([]domainrole.Role, int64, error)
-	Update(id string, req dto.RoleUpdate) (domainrole.Role, error)
-	Delete(id string) error
-	AssignPermissions(roleId string, req dto.AssignPermissions, currentUserRole string) error
+	Update(id, username string, req dto.RoleUpdate) (domainrole.Role, error)
+	Delete(id, username string) error
+	AssignPermissions(roleId, username string, req dto.AssignPermissions, currentUserRole string) error
 	GetRolePermissions(roleId string) ([]string, error)
 	GetRoleMenus(roleId string) ([]string, error)
 }
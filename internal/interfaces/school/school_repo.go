@@ -8,12 +8,15 @@ import (
 
 type RepoSchoolInterface interface {
 	Create(school domainschool.School) error
-	GetByID(id string) (domainschool.School, error)
+	GetByID(id string, scope filter.RegionScope) (domainschool.School, error)
 	Update(school domainschool.School) error
 	Fetch(params filter.BaseParams) ([]domainschool.School, int64, error)
 	Delete(id string) error
+	GetByNPSNs(npsns []string) ([]domainschool.School, error)
+	// FindMatches returns the schools named in text or located at address, longest names first
+	FindMatches(text, address string, scope filter.RegionScope) ([]domainschool.School, error)
 	GetEducationStats(params filter.BaseParams) ([]map[string]interface{}, error)
 	GetEducationPriorityData(params filter.BaseParams) ([]map[string]interface{}, error)
-	GetSummary() (*dto.SchoolSummary, error)
-	GetForMap() ([]dto.SchoolMapItem, error)
+	GetSummary(scope filter.RegionScope) (*dto.SchoolSummary, error)
+	GetForMap(scope filter.RegionScope) ([]dto.SchoolMapItem, error)
 }
@@ -1,19 +1,22 @@
 package interfaceschool
 
 import (
+	"mime/multipart"
+
 	domainschool "safety-riding/internal/domain/school"
 	"safety-riding/internal/dto"
 	"safety-riding/pkg/filter"
 )
 
 type ServiceSchoolInterface interface {
-	AddSchool(username string, req dto.AddSchool) (domainschool.School, error)
-	GetSchoolById(id string) (domainschool.School, error)
-	UpdateSchool(id, username string, req dto.UpdateSchool) (domainschool.School, error)
+	AddSchool(username string, scope filter.RegionScope, req dto.AddSchool) (domainschool.School, error)
+	GetSchoolById(id string, scope filter.RegionScope) (domainschool.School, error)
+	UpdateSchool(id, username string, scope filter.RegionScope, req dto.UpdateSchool) (domainschool.School, error)
 	FetchSchool(params filter.BaseParams) ([]domainschool.School, int64, error)
-	DeleteSchool(id, username string) error
+	DeleteSchool(id, username string, scope filter.RegionScope) error
+	ImportSchools(username string, scope filter.RegionScope, fileHeader *multipart.FileHeader, dryRun bool) (dto.ImportSchoolResponse, error)
 	GetEducationStats(params filter.BaseParams) (dto.SchoolEducationStatsResponse, error)
 	GetEducationPriority(params filter.BaseParams) (dto.EducationPriorityResponse, error)
-	GetSummary() (*dto.SchoolSummary, error)
-	GetForMap() ([]dto.SchoolMapItem, error)
+	GetSummary(scope filter.RegionScope) (*dto.SchoolSummary, error)
+	GetForMap(scope filter.RegionScope) ([]dto.SchoolMapItem, error)
 }
@@ -20,6 +20,12 @@ type RepoSessionInterface interface {
 	// GetByToken retrieves a session by token
 	GetByToken(ctx context.Context, token string) (*domainsession.Session, error)
 
+	// GetByRefreshToken retrieves a session by the hash of a current or previously issued refresh token
+	GetByRefreshToken(ctx context.Context, refreshTokenHash string) (*domainsession.Session, error)
+
+	// RotateTokens replaces the access and refresh token of a session if oldRefreshTokenHash is still the current one
+	RotateTokens(ctx context.Context, sessionID, oldRefreshTokenHash, token, refreshTokenHash string) (*domainsession.Session, error)
+
 	// UpdateActivity updates the last activity time of a session
 	UpdateActivity(ctx context.Context, sessionID string) error
 
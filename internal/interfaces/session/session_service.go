@@ -10,7 +10,9 @@ import (
 )
 
 type ServiceSessionInterface interface {
-	CreateSession(ctx context.Context, user *domainuser.Users, token string, ginCtx *gin.Context) (*domainsession.Session, error)
+	CreateSession(ctx context.Context, user *domainuser.Users, token string, ginCtx *gin.Context) (*domainsession.Session, string, error)
+	GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*domainsession.Session, error)
+	RotateRefreshToken(ctx context.Context, refreshToken, newToken string) (*domainsession.Session, string, error)
 	ValidateSession(ctx context.Context, token string) (*domainsession.Session, error)
 	GetUserSessions(ctx context.Context, userID string, currentSessionID string) ([]*domainsession.SessionInfo, error)
 	DestroySession(ctx context.Context, sessionID string) error
@@ -0,0 +1,13 @@
+package interfacestoragegc
+
+import (
+	"time"
+
+	"safety-riding/internal/dto"
+)
+
+type RepoStorageGCInterface interface {
+	// FetchFileRefs returns the URLs kept in the columns of the live rows of a table
+	// and of the rows soft-deleted after deletedSince
+	FetchFileRefs(table string, columns []string, deletedSince time.Time) ([]dto.StoredFileRef, error)
+}
@@ -0,0 +1,11 @@
+package interfacestoragegc
+
+import (
+	"context"
+
+	"safety-riding/internal/dto"
+)
+
+type ServiceStorageGCInterface interface {
+	Reconcile(ctx context.Context, opts dto.StorageGCOptions) (dto.StorageGCReport, error)
+}
@@ -0,0 +1,11 @@
+package interfacestoragemigration
+
+import "safety-riding/internal/dto"
+
+type RepoStorageMigrationInterface interface {
+	// FetchFileURLs returns up to limit rows of table ordered by id after afterId, soft-deleted rows included,
+	// with the values of the URL columns
+	FetchFileURLs(table string, columns []string, afterId string, limit int) ([]dto.StoredFileURLs, error)
+	// UpdateFileURLs writes the URLs of the rows in one transaction
+	UpdateFileURLs(table string, rows []dto.StoredFileURLs) error
+}
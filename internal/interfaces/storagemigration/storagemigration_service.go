@@ -0,0 +1,11 @@
+package interfacestoragemigration
+
+import (
+	"context"
+
+	"safety-riding/internal/dto"
+)
+
+type ServiceStorageMigrationInterface interface {
+	Migrate(ctx context.Context, opts dto.StorageMigrationOptions) (dto.StorageMigrationReport, error)
+}
@@ -0,0 +1,9 @@
+package interfaceuser
+
+import domainuser "safety-riding/internal/domain/user"
+
+type RepoUserRegionInterface interface {
+	GetByUser(userId string) ([]domainuser.UserRegion, error)
+	// ReplaceByUser swaps every region of the user for the given ones in a single transaction
+	ReplaceByUser(userId string, regions []domainuser.UserRegion) error
+}
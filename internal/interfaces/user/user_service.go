@@ -9,7 +9,14 @@ import (
 type ServiceUserInterface interface {
 	RegisterUser(req dto.UserRegister) (domainuser.Users, error)
 	AdminCreateUser(req dto.AdminCreateUser, creatorRole string) (domainuser.Users, error)
-	LoginUser(req dto.Login, logId string) (string, error)
+	LoginUser(req dto.Login, logId string, policy dto.LoginPolicy) (dto.LoginResult, error)
+	LoginTwoFactor(req dto.TwoFactorLoginRequest, logId string) (domainuser.Users, dto.LoginResult, error)
+	SetupTwoFactorWithChallenge(req dto.TwoFactorChallengeRequest) (dto.TwoFactorSetup, error)
+	SetupTwoFactor(id string) (dto.TwoFactorSetup, error)
+	EnableTwoFactor(id string, req dto.TwoFactorCodeRequest) ([]string, error)
+	DisableTwoFactor(id string, req dto.TwoFactorDisableRequest) error
+	RegenerateRecoveryCodes(id string, req dto.TwoFactorCodeRequest) ([]string, error)
+	RefreshToken(req dto.RefreshTokenRequest, logId string) (string, string, error)
 	LogoutUser(token string) error
 	GetUserById(id string) (domainuser.Users, error)
 	GetUserByEmail(email string) (domainuser.Users, error)
@@ -17,7 +24,11 @@ type ServiceUserInterface interface {
 	GetAllUsers(params filter.BaseParams, currentUserRole string) ([]domainuser.Users, int64, error)
 	Update(id, role string, req dto.UserUpdate) (domainuser.Users, error)
 	ChangePassword(id string, req dto.ChangePassword) (domainuser.Users, error)
-	ForgotPassword(req dto.ForgotPasswordRequest) (string, error)
+	ForgotPassword(req dto.ForgotPasswordRequest) error
 	ResetPassword(req dto.ResetPasswordRequest) error
+	ResendVerificationEmail(req dto.ResendVerificationRequest) error
+	VerifyEmail(req dto.VerifyEmailRequest) error
 	Delete(id string) error
+	GetUserRegions(id string) ([]domainuser.UserRegion, error)
+	AssignUserRegions(id, callerId, username string, scope filter.RegionScope, req dto.AssignUserRegions) ([]domainuser.UserRegion, error)
 }
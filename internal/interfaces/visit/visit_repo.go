@@ -0,0 +1,13 @@
+package interfacevisit
+
+import "safety-riding/internal/dto"
+
+// RepoVisitInterface keeps the visit statistics of schools and publics derived from their completed events
+type RepoVisitInterface interface {
+	// Recompute rebuilds visit_count, is_educated and last_visit_at of the given schools and publics
+	Recompute(schoolIds, publicIds []string) error
+	// RecomputeAll rebuilds the visit statistics of every school and public
+	RecomputeAll() (dto.VisitRecomputeResult, error)
+	FetchBySchool(schoolId string) ([]dto.Visit, error)
+	FetchByPublic(publicId string) ([]dto.Visit, error)
+}
@@ -23,9 +23,13 @@ func (r *repo) Create(accident domainaccident.Accident) error {
 	return r.DB.Create(&accident).Error
 }
 
-func (r *repo) GetByID(id string) (domainaccident.Accident, error) {
+func (r *repo) GetByID(id string, scope filter.RegionScope) (domainaccident.Accident, error) {
 	var accident domainaccident.Accident
-	err := r.DB.Preload("Photos").Where("id = ?", id).First(&accident).Error
+	err := r.DB.Scopes(scope.Apply("province_id", "city_id")).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("photo_order ASC")
+		}).
+		Where("id = ?", id).First(&accident).Error
 	return accident, err
 }
 
@@ -34,7 +38,7 @@ func (r *repo) Update(accident domainaccident.Accident) error {
 }
 
 func (r *repo) Fetch(params filter.BaseParams) (ret []domainaccident.Accident, totalData int64, err error) {
-	query := r.DB.Model(&domainaccident.Accident{}).Debug()
+	query := r.DB.Model(&domainaccident.Accident{}).Debug().Scopes(params.Scope.Apply("province_id", "city_id"))
 
 	if len(params.Columns) > 0 {
 		query = query.Select(params.Columns)
@@ -89,7 +93,7 @@ func (r *repo) Fetch(params filter.BaseParams) (ret []domainaccident.Accident, t
 		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
 	}
 
-	if err := query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+	if err := query.Scopes(filter.Paginate(params)).Find(&ret).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -124,6 +128,47 @@ func (r *repo) DeletePhoto(photoId string) error {
 	return r.DB.Where("id = ?", photoId).Delete(&domainaccident.AccidentPhoto{}).Error
 }
 
+// UpdatePhoto saves the caption and cover flag of a photo, a new cover replaces the previous one of the accident
+func (r *repo) UpdatePhoto(photo domainaccident.AccidentPhoto) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if photo.IsCover {
+			if err := tx.Model(&domainaccident.AccidentPhoto{}).
+				Where("accident_id = ? AND id <> ? AND is_cover", photo.AccidentId, photo.ID).
+				Update("is_cover", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domainaccident.AccidentPhoto{}).Where("id = ?", photo.ID).
+			Updates(map[string]interface{}{"caption": photo.Caption, "is_cover": photo.IsCover}).Error
+	})
+}
+
+// ReorderPhotos numbers the photos 1..n in the given order
+func (r *repo) ReorderPhotos(accidentId string, photoIds []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range photoIds {
+			if err := tx.Model(&domainaccident.AccidentPhoto{}).
+				Where("id = ? AND accident_id = ?", id, accidentId).
+				Update("photo_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NormalizePhotoOrder renumbers the photos of the accident 1..n keeping their order, after photos were added or removed
+func (r *repo) NormalizePhotoOrder(accidentId string) error {
+	return r.DB.Exec(`
+		UPDATE accident_photos p SET photo_order = o.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY photo_order, created_at, id) AS position
+			FROM accident_photos
+			WHERE accident_id = ? AND deleted_at IS NULL
+		) o
+		WHERE p.id = o.id AND p.photo_order <> o.position`, accidentId).Error
+}
+
 func (r *repo) DeletePhotosByAccidentID(accidentId string) error {
 	return r.DB.Where("accident_id = ?", accidentId).Delete(&domainaccident.AccidentPhoto{}).Error
 }
@@ -0,0 +1,214 @@
+package repositoryassessment
+
+import (
+	"fmt"
+	"strconv"
+
+	domainassessment "safety-riding/internal/domain/assessment"
+	"safety-riding/internal/dto"
+	interfaceassessment "safety-riding/internal/interfaces/assessment"
+	"safety-riding/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+// statsGroups maps each group_by value to its id, name and GROUP BY expressions over events e and schools s,
+// with the extra joins the group needs. Instructors are grouped by the directory entries linked to the event,
+// events without linked instructors fall back to their free-text instructor name.
+var statsGroups = map[string]struct {
+	id, name, group, joins string
+}{
+	domainassessment.GroupByEvent:      {id: "e.id::text", name: "MIN(e.title)", group: "e.id"},
+	domainassessment.GroupByInstructor: {id: "COALESCE(i.id::text, LOWER(TRIM(e.instructor_name)))", name: "COALESCE(MIN(i.name), MIN(TRIM(e.instructor_name)))", group: "COALESCE(i.id::text, LOWER(TRIM(e.instructor_name)))", joins: "LEFT JOIN event_instructors ei ON ei.event_id = e.id LEFT JOIN instructors i ON i.id = ei.instructor_id"},
+	domainassessment.GroupBySchool:     {id: "COALESCE(e.school_id::text, '')", name: "COALESCE(MIN(s.name), '')", group: "e.school_id"},
+	domainassessment.GroupByRegion:     {id: "e.province_id || '-' || e.city_id", name: "COALESCE(MAX(s.city_name) || ', ' || MAX(s.province_name), '')", group: "e.province_id, e.city_id"},
+}
+
+type assessmentRepo struct {
+	DB *gorm.DB
+}
+
+func NewAssessmentRepo(db *gorm.DB) interfaceassessment.RepoAssessmentInterface {
+	return &assessmentRepo{
+		DB: db,
+	}
+}
+
+func (r *assessmentRepo) Create(assessment domainassessment.EventAssessment) error {
+	return r.DB.Omit("Bank").Create(&assessment).Error
+}
+
+// GetByEvent returns the pre-test or post-test of the event with its questions
+func (r *assessmentRepo) GetByEvent(eventId, assessmentType string) (domainassessment.EventAssessment, error) {
+	var assessment domainassessment.EventAssessment
+	err := r.DB.Preload("Bank").Preload("Bank.Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC")
+	}).Where("event_id = ? AND type = ?", eventId, assessmentType).First(&assessment).Error
+	return assessment, err
+}
+
+func (r *assessmentRepo) FetchSummaries(eventId string) ([]dto.EventAssessmentSummary, error) {
+	var ret []dto.EventAssessmentSummary
+	err := r.DB.Table("event_assessments a").
+		Select(`a.id, a.type, a.bank_id, b.name AS bank_name,
+			(SELECT COUNT(*) FROM assessment_questions q WHERE q.bank_id = a.bank_id AND q.deleted_at IS NULL) AS question_count,
+			COUNT(s.id) AS submission_count,
+			COALESCE(ROUND(AVG(s.score), 2), 0) AS avg_score`).
+		Joins("JOIN question_banks b ON b.id = a.bank_id").
+		Joins("LEFT JOIN assessment_submissions s ON s.assessment_id = a.id").
+		Where("a.event_id = ? AND a.deleted_at IS NULL", eventId).
+		Group("a.id, a.type, a.bank_id, b.name").
+		Order("a.type DESC").
+		Scan(&ret).Error
+	return ret, err
+}
+
+func (r *assessmentRepo) Delete(id string) error {
+	return r.DB.Where("id = ?", id).Delete(&domainassessment.EventAssessment{}).Error
+}
+
+func (r *assessmentRepo) CountSubmissions(assessmentId string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domainassessment.Submission{}).Where("assessment_id = ?", assessmentId).Count(&count).Error
+	return count, err
+}
+
+func (r *assessmentRepo) CreateSubmission(submission domainassessment.Submission) error {
+	return r.DB.Create(&submission).Error
+}
+
+func (r *assessmentRepo) HasSubmitted(assessmentId, participantId string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domainassessment.Submission{}).
+		Where("assessment_id = ? AND participant_id = ?", assessmentId, participantId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *assessmentRepo) FetchSubmissions(assessmentId string, params filter.BaseParams) (ret []domainassessment.Submission, totalData int64, err error) {
+	query := r.DB.Model(&domainassessment.Submission{}).
+		Joins("JOIN event_participants p ON p.id = assessment_submissions.participant_id AND p.deleted_at IS NULL").
+		Where("assessment_submissions.assessment_id = ?", assessmentId)
+
+	if params.Search != "" {
+		query = query.Where("LOWER(p.name) LIKE LOWER(?)", "%"+params.Search+"%")
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]string{
+			"participant_name": "p.name",
+			"score":            "assessment_submissions.score",
+			"submitted_at":     "assessment_submissions.submitted_at",
+		}
+
+		column, ok := validColumns[params.OrderBy]
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", column, params.OrderDirection))
+	}
+
+	err = query.Select("assessment_submissions.*, p.name AS participant_name").
+		Offset(params.Offset).Limit(params.Limit).Find(&ret).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+// FetchEventResults lists every participant on the roster with their pre-test and post-test score, if any
+func (r *assessmentRepo) FetchEventResults(eventId string) ([]dto.ParticipantAssessmentResult, error) {
+	var ret []dto.ParticipantAssessmentResult
+	err := r.DB.Table("event_participants p").
+		Select("p.id AS participant_id, p.name, pre.score AS pre_score, post.score AS post_score").
+		Joins(`LEFT JOIN assessment_submissions pre ON pre.participant_id = p.id AND pre.assessment_id =
+			(SELECT id FROM event_assessments WHERE event_id = p.event_id AND type = ? AND deleted_at IS NULL)`, domainassessment.TypePre).
+		Joins(`LEFT JOIN assessment_submissions post ON post.participant_id = p.id AND post.assessment_id =
+			(SELECT id FROM event_assessments WHERE event_id = p.event_id AND type = ? AND deleted_at IS NULL)`, domainassessment.TypePost).
+		Where("p.event_id = ? AND p.deleted_at IS NULL", eventId).
+		Order("p.name ASC").
+		Scan(&ret).Error
+	return ret, err
+}
+
+// GetStats averages the scores of participants who took both tests of an event, grouped by event, instructor, school or region
+func (r *assessmentRepo) GetStats(groupBy string, params filter.BaseParams) ([]dto.AssessmentStatsItem, error) {
+	group, ok := statsGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group_by: %s", groupBy)
+	}
+
+	pairs := r.DB.Table("assessment_submissions pre").
+		Select("pre.event_id, pre.participant_id, pre.score AS pre_score, post.score AS post_score").
+		Joins("JOIN event_assessments pa ON pa.id = pre.assessment_id AND pa.type = ? AND pa.deleted_at IS NULL", domainassessment.TypePre).
+		Joins("JOIN assessment_submissions post ON post.event_id = pre.event_id AND post.participant_id = pre.participant_id").
+		Joins("JOIN event_assessments qa ON qa.id = post.assessment_id AND qa.type = ? AND qa.deleted_at IS NULL", domainassessment.TypePost).
+		Joins("JOIN event_participants p ON p.id = pre.participant_id AND p.deleted_at IS NULL")
+
+	selectColumns := fmt.Sprintf(`%s AS group_id, %s AS group_name,
+		COUNT(DISTINCT e.id) AS event_count,
+		COUNT(*) AS participant_count,
+		ROUND(AVG(pairs.pre_score), 2) AS avg_pre_score,
+		ROUND(AVG(pairs.post_score), 2) AS avg_post_score,
+		ROUND(AVG(pairs.post_score - pairs.pre_score), 2) AS avg_improvement`, group.id, group.name)
+	if groupBy == domainassessment.GroupByRegion {
+		selectColumns += ", e.province_id, e.city_id"
+	}
+
+	query := r.DB.Table("(?) AS pairs", pairs).
+		Select(selectColumns).
+		Joins("JOIN events e ON e.id = pairs.event_id AND e.deleted_at IS NULL").
+		Joins("LEFT JOIN schools s ON s.id = e.school_id").
+		Scopes(params.Scope.Apply("e.province_id", "e.city_id")).
+		Group(group.group)
+	if group.joins != "" {
+		query = query.Joins(group.joins)
+	}
+
+	for key, value := range params.Filters {
+		v := fmt.Sprintf("%v", value)
+		if v == "" {
+			continue
+		}
+
+		switch key {
+		case "month":
+			if month, err := strconv.Atoi(v); err == nil && month >= 1 && month <= 12 {
+				query = query.Where("EXTRACT(MONTH FROM e.event_date::date) = ?", month)
+			}
+		case "year":
+			if year, err := strconv.Atoi(v); err == nil {
+				query = query.Where("EXTRACT(YEAR FROM e.event_date::date) = ?", year)
+			}
+		default:
+			query = query.Where(fmt.Sprintf("e.%s = ?", key), v)
+		}
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"group_name":        true,
+			"event_count":       true,
+			"participant_count": true,
+			"avg_pre_score":     true,
+			"avg_post_score":    true,
+			"avg_improvement":   true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	var ret []dto.AssessmentStatsItem
+	err := query.Scan(&ret).Error
+	return ret, err
+}
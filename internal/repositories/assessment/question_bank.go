@@ -0,0 +1,117 @@
+package repositoryassessment
+
+import (
+	"fmt"
+
+	domainassessment "safety-riding/internal/domain/assessment"
+	interfaceassessment "safety-riding/internal/interfaces/assessment"
+	"safety-riding/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+type questionBankRepo struct {
+	DB *gorm.DB
+}
+
+func NewQuestionBankRepo(db *gorm.DB) interfaceassessment.RepoQuestionBankInterface {
+	return &questionBankRepo{
+		DB: db,
+	}
+}
+
+// Create stores the bank together with its questions
+func (r *questionBankRepo) Create(bank domainassessment.QuestionBank) error {
+	return r.DB.Create(&bank).Error
+}
+
+func (r *questionBankRepo) GetByID(id string) (domainassessment.QuestionBank, error) {
+	var bank domainassessment.QuestionBank
+	err := r.DB.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC")
+	}).Where("id = ?", id).First(&bank).Error
+	return bank, err
+}
+
+// Update saves the bank fields, when replaceQuestions is true the current questions are replaced by bank.Questions
+func (r *questionBankRepo) Update(bank domainassessment.QuestionBank, replaceQuestions bool) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		questions := bank.Questions
+		bank.Questions = nil
+		if err := tx.Omit("Questions").Save(&bank).Error; err != nil {
+			return err
+		}
+		if !replaceQuestions {
+			return nil
+		}
+
+		if err := tx.Where("bank_id = ?", bank.ID).Delete(&domainassessment.Question{}).Error; err != nil {
+			return err
+		}
+		if len(questions) == 0 {
+			return nil
+		}
+		return tx.Create(&questions).Error
+	})
+}
+
+func (r *questionBankRepo) Fetch(params filter.BaseParams) (ret []domainassessment.QuestionBank, totalData int64, err error) {
+	query := r.DB.Model(&domainassessment.QuestionBank{})
+
+	if params.Search != "" {
+		query = query.Where("LOWER(name) LIKE LOWER(?)", "%"+params.Search+"%")
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"name":       true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	err = query.Select(`question_banks.*,
+		(SELECT COUNT(*) FROM assessment_questions q WHERE q.bank_id = question_banks.id AND q.deleted_at IS NULL) AS question_count`).
+		Offset(params.Offset).Limit(params.Limit).Find(&ret).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+func (r *questionBankRepo) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bank_id = ?", id).Delete(&domainassessment.Question{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domainassessment.QuestionBank{}).Error
+	})
+}
+
+// IsInUse reports whether an event assessment uses the bank
+func (r *questionBankRepo) IsInUse(id string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domainassessment.EventAssessment{}).Where("bank_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// HasSubmissions reports whether answers were already scored against the bank questions
+func (r *questionBankRepo) HasSubmissions(id string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domainassessment.Submission{}).
+		Joins("JOIN event_assessments a ON a.id = assessment_submissions.assessment_id").
+		Where("a.bank_id = ?", id).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,48 @@
+package repositoryattachment
+
+import (
+	domainattachment "safety-riding/internal/domain/attachment"
+	interfaceattachment "safety-riding/internal/interfaces/attachment"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewAttachmentRepo(db *gorm.DB) interfaceattachment.RepoAttachmentInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func (r *repo) CreateBatch(attachments []domainattachment.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	return r.DB.Create(&attachments).Error
+}
+
+func (r *repo) GetByID(entityType, entityId, id string) (domainattachment.Attachment, error) {
+	var attachment domainattachment.Attachment
+	err := r.DB.Where("id = ? AND entity_type = ? AND entity_id = ?", id, entityType, entityId).First(&attachment).Error
+	return attachment, err
+}
+
+func (r *repo) FetchByEntity(entityType, entityId string) ([]domainattachment.Attachment, error) {
+	ret := []domainattachment.Attachment{}
+	err := r.DB.Where("entity_type = ? AND entity_id = ?", entityType, entityId).
+		Order("created_at DESC").
+		Find(&ret).Error
+	return ret, err
+}
+
+func (r *repo) Delete(id, username string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domainattachment.Attachment{}).Where("id = ?", id).Update("deleted_by", username).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domainattachment.Attachment{}).Error
+	})
+}
@@ -0,0 +1,102 @@
+package repositoryauditlog
+
+import (
+	"fmt"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	"safety-riding/pkg/filter"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewAuditLogRepo(db *gorm.DB) interfaceauditlog.RepoAuditLogInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func (r *repo) Create(log domainauditlog.AuditLog) error {
+	return r.DB.Create(&log).Error
+}
+
+func (r *repo) GetByID(id string) (domainauditlog.AuditLog, error) {
+	var log domainauditlog.AuditLog
+	err := r.DB.Where("id = ?", id).First(&log).Error
+	return log, err
+}
+
+func (r *repo) Fetch(params filter.BaseParams) (ret []domainauditlog.AuditLog, totalData int64, err error) {
+	query := r.DB.Model(&domainauditlog.AuditLog{}).Debug()
+
+	if len(params.Columns) > 0 {
+		query = query.Select(params.Columns)
+	}
+
+	if params.Search != "" {
+		search := "%" + params.Search + "%"
+		query = query.Where("LOWER(actor) LIKE LOWER(?) OR LOWER(entity_id) LIKE LOWER(?)", search, search)
+	}
+
+	// apply filters
+	for key, value := range params.Filters {
+		if value == nil {
+			continue
+		}
+
+		switch key {
+		case "date_from", "date_to":
+			date, err := time.ParseInLocation("2006-01-02", fmt.Sprintf("%v", value), time.Local)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid %s, expected YYYY-MM-DD", key)
+			}
+			if key == "date_from" {
+				query = query.Where("created_at >= ?", date)
+			} else {
+				query = query.Where("created_at < ?", date.AddDate(0, 0, 1))
+			}
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+			query = query.Where(fmt.Sprintf("%s = ?", key), v)
+		case []string, []int:
+			query = query.Where(fmt.Sprintf("%s IN ?", key), v)
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", key), v)
+		}
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"created_at":  true,
+			"actor":       true,
+			"action":      true,
+			"entity_type": true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	if err := query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
@@ -1,10 +1,13 @@
 package repositoryauth
 
 import (
+	"time"
+
 	"safety-riding/internal/domain/auth"
 	"safety-riding/internal/interfaces/auth"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type blacklistRepo struct {
@@ -26,3 +29,17 @@ func (r *blacklistRepo) GetByToken(token string) (domainauth.Blacklist, error) {
 	err := r.DB.Session(&gorm.Session{Logger: r.DB.Logger.LogMode(0)}).Where("token = ?", token).First(&blacklist).Error
 	return blacklist, err
 }
+
+// RevokeUserTokens moves the revocation time of the user forward, a later reset replaces the earlier one
+func (r *blacklistRepo) RevokeUserTokens(userId string, revokedAt time.Time) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at"}),
+	}).Create(&domainauth.TokenRevocation{UserId: userId, RevokedAt: revokedAt}).Error
+}
+
+func (r *blacklistRepo) GetRevocation(userId string) (domainauth.TokenRevocation, error) {
+	var revocation domainauth.TokenRevocation
+	err := r.DB.Session(&gorm.Session{Logger: r.DB.Logger.LogMode(0)}).Where("user_id = ?", userId).First(&revocation).Error
+	return revocation, err
+}
@@ -0,0 +1,139 @@
+package repositoryauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	interfaceauth "safety-riding/internal/interfaces/auth"
+	"safety-riding/pkg/cache"
+	"safety-riding/pkg/logger"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	blacklistCachePrefix  = "blacklist_cache:"
+	revocationCachePrefix = "token_revocation_cache:"
+)
+
+// cachedBlacklistRepo remembers both blacklisted and clean tokens, and the token revocation of each user,
+// so an authenticated request normally skips the database
+type cachedBlacklistRepo struct {
+	repo  interfaceauth.RepoAuthInterface
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+type blacklistCacheEntry struct {
+	Blacklisted bool                 `json:"blacklisted"`
+	Blacklist   domainauth.Blacklist `json:"blacklist"`
+}
+
+type revocationCacheEntry struct {
+	Revoked    bool                       `json:"revoked"`
+	Revocation domainauth.TokenRevocation `json:"revocation"`
+}
+
+func NewCachedBlacklistRepo(repo interfaceauth.RepoAuthInterface, c cache.Cache, ttl time.Duration) interfaceauth.RepoAuthInterface {
+	return &cachedBlacklistRepo{repo: repo, cache: c, ttl: ttl}
+}
+
+// Store writes the entry to the database first and then overwrites any cached "not blacklisted" result
+func (r *cachedBlacklistRepo) Store(m domainauth.Blacklist) error {
+	if err := r.repo.Store(m); err != nil {
+		return err
+	}
+
+	key := blacklistCacheKey(m.Token)
+	if err := r.set(key, blacklistCacheEntry{Blacklisted: true, Blacklist: m}); err != nil {
+		// A stale "not blacklisted" entry must not outlive the logout
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[BlacklistCache]; Set %s; Error: %v", key, err))
+		_ = r.cache.Delete(context.Background(), key)
+	}
+	return nil
+}
+
+func (r *cachedBlacklistRepo) GetByToken(token string) (domainauth.Blacklist, error) {
+	key := blacklistCacheKey(token)
+
+	if raw, found, err := r.cache.Get(context.Background(), key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[BlacklistCache]; Get %s; Error: %v", key, err))
+	} else if found {
+		var entry blacklistCacheEntry
+		if err = json.Unmarshal(raw, &entry); err == nil {
+			if !entry.Blacklisted {
+				return domainauth.Blacklist{}, gorm.ErrRecordNotFound
+			}
+			return entry.Blacklist, nil
+		}
+	}
+
+	ret, err := r.repo.GetByToken(token)
+	switch {
+	case err == nil:
+		_ = r.set(key, blacklistCacheEntry{Blacklisted: true, Blacklist: ret})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		_ = r.set(key, blacklistCacheEntry{Blacklisted: false})
+	}
+
+	return ret, err
+}
+
+// RevokeUserTokens writes the revocation to the database first and then overwrites the cached one,
+// so the reset is enforced on the next request
+func (r *cachedBlacklistRepo) RevokeUserTokens(userId string, revokedAt time.Time) error {
+	if err := r.repo.RevokeUserTokens(userId, revokedAt); err != nil {
+		return err
+	}
+
+	key := revocationCachePrefix + userId
+	if err := r.set(key, revocationCacheEntry{Revoked: true, Revocation: domainauth.TokenRevocation{UserId: userId, RevokedAt: revokedAt}}); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[BlacklistCache]; Set %s; Error: %v", key, err))
+		_ = r.cache.Delete(context.Background(), key)
+	}
+	return nil
+}
+
+func (r *cachedBlacklistRepo) GetRevocation(userId string) (domainauth.TokenRevocation, error) {
+	key := revocationCachePrefix + userId
+
+	if raw, found, err := r.cache.Get(context.Background(), key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[BlacklistCache]; Get %s; Error: %v", key, err))
+	} else if found {
+		var entry revocationCacheEntry
+		if err = json.Unmarshal(raw, &entry); err == nil {
+			if !entry.Revoked {
+				return domainauth.TokenRevocation{}, gorm.ErrRecordNotFound
+			}
+			return entry.Revocation, nil
+		}
+	}
+
+	ret, err := r.repo.GetRevocation(userId)
+	switch {
+	case err == nil:
+		_ = r.set(key, revocationCacheEntry{Revoked: true, Revocation: ret})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		_ = r.set(key, revocationCacheEntry{Revoked: false})
+	}
+
+	return ret, err
+}
+
+func (r *cachedBlacklistRepo) set(key string, entry interface{}) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.cache.Set(context.Background(), key, raw, r.ttl)
+}
+
+// blacklistCacheKey hashes the token so raw JWTs are never used as cache keys
+func blacklistCacheKey(token string) string {
+	return blacklistCachePrefix + utils.HashToken(token)
+}
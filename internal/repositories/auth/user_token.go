@@ -0,0 +1,45 @@
+package repositoryauth
+
+import (
+	"safety-riding/internal/domain/auth"
+	"safety-riding/internal/interfaces/auth"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userTokenRepo struct {
+	DB *gorm.DB
+}
+
+func NewUserTokenRepo(db *gorm.DB) interfaceauth.RepoUserTokenInterface {
+	return &userTokenRepo{
+		DB: db,
+	}
+}
+
+func (r *userTokenRepo) Store(token domainauth.UserToken) error {
+	return r.DB.Create(&token).Error
+}
+
+func (r *userTokenRepo) GetByHash(purpose, tokenHash string) (domainauth.UserToken, error) {
+	var token domainauth.UserToken
+	err := r.DB.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	return token, err
+}
+
+// MarkUsed consumes the token and reports false when it was already used,
+// so two concurrent requests cannot both redeem the same token
+func (r *userTokenRepo) MarkUsed(id string) (bool, error) {
+	res := r.DB.Model(&domainauth.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+// RevokeByUser invalidates every unused token of the user for the given purpose
+func (r *userTokenRepo) RevokeByUser(userId, purpose string) error {
+	return r.DB.Model(&domainauth.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userId, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -23,9 +23,20 @@ func (r *repo) Create(budget domainbudget.EventBudget) error {
 	return r.DB.Create(&budget).Error
 }
 
-func (r *repo) GetByID(id string) (domainbudget.EventBudget, error) {
+// inScope limits budgets to the ones whose event is located inside the region scope
+func inScope(scope filter.RegionScope) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition, args := scope.Condition("province_id", "city_id")
+		if condition == "" {
+			return db
+		}
+		return db.Where("event_id IN (SELECT id FROM events WHERE "+condition+")", args...)
+	}
+}
+
+func (r *repo) GetByID(id string, scope filter.RegionScope) (domainbudget.EventBudget, error) {
 	var budget domainbudget.EventBudget
-	err := r.DB.Preload("Event.School").Where("id = ?", id).First(&budget).Error
+	err := r.DB.Preload("Event.School").Scopes(inScope(scope)).Where("id = ?", id).First(&budget).Error
 	return budget, err
 }
 
@@ -38,7 +49,7 @@ func (r *repo) UpdateById(id string, budget domainbudget.EventBudget) error {
 }
 
 func (r *repo) Fetch(params filter.BaseParams) (ret []domainbudget.EventBudget, totalData int64, err error) {
-	query := r.DB.Model(&domainbudget.EventBudget{}).Preload("Event.School").Debug()
+	query := r.DB.Model(&domainbudget.EventBudget{}).Preload("Event.School").Scopes(inScope(params.Scope)).Debug()
 
 	if len(params.Columns) > 0 {
 		query = query.Select(params.Columns)
@@ -90,7 +101,7 @@ func (r *repo) Fetch(params filter.BaseParams) (ret []domainbudget.EventBudget,
 		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
 	}
 
-	if err := query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+	if err := query.Scopes(filter.Paginate(params)).Find(&ret).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -102,21 +113,21 @@ func (r *repo) Delete(id string) error {
 }
 
 // Aggregation methods
-func (r *repo) GetByEventID(eventId string) ([]domainbudget.EventBudget, error) {
+func (r *repo) GetByEventID(eventId string, scope filter.RegionScope) ([]domainbudget.EventBudget, error) {
 	var budgets []domainbudget.EventBudget
-	err := r.DB.Preload("Event.School").Where("event_id = ?", eventId).Order("budget_date DESC").Find(&budgets).Error
+	err := r.DB.Preload("Event.School").Scopes(inScope(scope)).Where("event_id = ?", eventId).Order("budget_date DESC").Find(&budgets).Error
 	return budgets, err
 }
 
-func (r *repo) GetByMonthYear(month, year int) ([]domainbudget.EventBudget, error) {
+func (r *repo) GetByMonthYear(month, year int, scope filter.RegionScope) ([]domainbudget.EventBudget, error) {
 	var budgets []domainbudget.EventBudget
-	err := r.DB.Where("budget_month = ? AND budget_year = ?", month, year).
+	err := r.DB.Scopes(inScope(scope)).Where("budget_month = ? AND budget_year = ?", month, year).
 		Order("budget_date DESC").
 		Find(&budgets).Error
 	return budgets, err
 }
 
-func (r *repo) GetSummaryByMonth(month, year int) (domainbudget.BudgetSummary, error) {
+func (r *repo) GetSummaryByMonth(month, year int, scope filter.RegionScope) (domainbudget.BudgetSummary, error) {
 	var summary domainbudget.BudgetSummary
 
 	err := r.DB.Model(&domainbudget.EventBudget{}).
@@ -128,12 +139,13 @@ func (r *repo) GetSummaryByMonth(month, year int) (domainbudget.BudgetSummary, e
 			COUNT(DISTINCT event_id) as event_count
 		`, fmt.Sprintf("%d/%d", month, year)).
 		Where("budget_month = ? AND budget_year = ?", month, year).
+		Scopes(inScope(scope)).
 		Scan(&summary).Error
 
 	return summary, err
 }
 
-func (r *repo) GetSummaryByYear(year int) ([]domainbudget.BudgetSummary, error) {
+func (r *repo) GetSummaryByYear(year int, scope filter.RegionScope) ([]domainbudget.BudgetSummary, error) {
 	var summaries []domainbudget.BudgetSummary
 
 	err := r.DB.Model(&domainbudget.EventBudget{}).
@@ -147,6 +159,7 @@ func (r *repo) GetSummaryByYear(year int) ([]domainbudget.BudgetSummary, error)
 			COUNT(DISTINCT event_id) as event_count
 		`).
 		Where("budget_year = ?", year).
+		Scopes(inScope(scope)).
 		Group("budget_month, budget_year").
 		Order("budget_month ASC").
 		Scan(&summaries).Error
@@ -154,7 +167,7 @@ func (r *repo) GetSummaryByYear(year int) ([]domainbudget.BudgetSummary, error)
 	return summaries, err
 }
 
-func (r *repo) GetSummaryByEvent(eventId string) (domainbudget.BudgetSummary, error) {
+func (r *repo) GetSummaryByEvent(eventId string, scope filter.RegionScope) (domainbudget.BudgetSummary, error) {
 	var summary domainbudget.BudgetSummary
 
 	err := r.DB.Model(&domainbudget.EventBudget{}).
@@ -166,6 +179,7 @@ func (r *repo) GetSummaryByEvent(eventId string) (domainbudget.BudgetSummary, er
 			1 as event_count
 		`).
 		Where("event_id = ?", eventId).
+		Scopes(inScope(scope)).
 		Scan(&summary).Error
 
 	return summary, err
@@ -0,0 +1,96 @@
+package repositorycertificate
+
+import (
+	"fmt"
+
+	domaincertificate "safety-riding/internal/domain/certificate"
+	interfacecertificate "safety-riding/internal/interfaces/certificate"
+	"safety-riding/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewCertificateRepo(db *gorm.DB) interfacecertificate.RepoCertificateInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+// Save creates new certificates and updates the ones issued before, with the merged batch when given, in one transaction
+func (r *repo) Save(certificates []domaincertificate.Certificate, batch *domaincertificate.Batch) error {
+	if len(certificates) == 0 {
+		return nil
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range certificates {
+			if err := tx.Save(&certificates[i]).Error; err != nil {
+				return err
+			}
+		}
+		if batch != nil {
+			return tx.Save(batch).Error
+		}
+		return nil
+	})
+}
+
+// GetBatch returns the merged PDF of the event
+func (r *repo) GetBatch(eventId string) (domaincertificate.Batch, error) {
+	var batch domaincertificate.Batch
+	err := r.DB.Where("event_id = ?", eventId).First(&batch).Error
+	return batch, err
+}
+
+// GetByEvent returns every certificate issued for the event
+func (r *repo) GetByEvent(eventId string) ([]domaincertificate.Certificate, error) {
+	var ret []domaincertificate.Certificate
+	err := r.DB.Where("event_id = ?", eventId).Find(&ret).Error
+	return ret, err
+}
+
+func (r *repo) FetchByEvent(eventId string, params filter.BaseParams) (ret []domaincertificate.Certificate, totalData int64, err error) {
+	query := r.DB.Model(&domaincertificate.Certificate{}).Where("event_id = ?", eventId)
+
+	if params.Search != "" {
+		query = query.Where("LOWER(participant_name) LIKE LOWER(?) OR verification_code = UPPER(?)", "%"+params.Search+"%", params.Search)
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"participant_name": true,
+			"organization":     true,
+			"issued_at":        true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	if err = query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+// GetByVerificationCode finds a certificate, certificates of a deleted event are no longer valid
+func (r *repo) GetByVerificationCode(code string) (domaincertificate.Certificate, error) {
+	var certificate domaincertificate.Certificate
+	err := r.DB.
+		Joins("JOIN events e ON e.id = certificates.event_id AND e.deleted_at IS NULL").
+		Where("certificates.verification_code = ?", code).
+		First(&certificate).Error
+	return certificate, err
+}
@@ -0,0 +1,71 @@
+package repositorycertificate
+
+import (
+	"fmt"
+
+	domaincertificate "safety-riding/internal/domain/certificate"
+	interfacecertificate "safety-riding/internal/interfaces/certificate"
+	"safety-riding/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+type templateRepo struct {
+	DB *gorm.DB
+}
+
+func NewTemplateRepo(db *gorm.DB) interfacecertificate.RepoTemplateInterface {
+	return &templateRepo{
+		DB: db,
+	}
+}
+
+func (r *templateRepo) Create(template domaincertificate.Template) error {
+	return r.DB.Create(&template).Error
+}
+
+func (r *templateRepo) GetByID(id string) (domaincertificate.Template, error) {
+	var template domaincertificate.Template
+	err := r.DB.Where("id = ?", id).First(&template).Error
+	return template, err
+}
+
+func (r *templateRepo) Update(template domaincertificate.Template) error {
+	return r.DB.Save(&template).Error
+}
+
+func (r *templateRepo) Fetch(params filter.BaseParams) (ret []domaincertificate.Template, totalData int64, err error) {
+	query := r.DB.Model(&domaincertificate.Template{})
+
+	if params.Search != "" {
+		query = query.Where("LOWER(name) LIKE LOWER(?)", "%"+params.Search+"%")
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"name":       true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	if err = query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+func (r *templateRepo) Delete(id string) error {
+	return r.DB.Where("id = ?", id).Delete(&domaincertificate.Template{}).Error
+}
@@ -8,6 +8,7 @@ import (
 
 	"safety-riding/internal/dto"
 	interfacedashboard "safety-riding/internal/interfaces/dashboard"
+	"safety-riding/pkg/filter"
 
 	"gorm.io/gorm"
 )
@@ -20,12 +21,13 @@ func NewDashboardRepo(db *gorm.DB) interfacedashboard.RepoDashboardInterface {
 	return &DashboardRepo{DB: db}
 }
 
-func (r *DashboardRepo) GetBasicStats() (dto.BasicStats, error) {
+func (r *DashboardRepo) GetBasicStats(scope filter.RegionScope) (dto.BasicStats, error) {
 	// Implement existing GetBasicStats if needed
 	return dto.BasicStats{}, nil
 }
 
-func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
+// GetStats aggregates the dashboard, every count and list only covers the records inside the region scope
+func (r *DashboardRepo) GetStats(scope filter.RegionScope) (*dto.DashboardStats, error) {
 	// Get first day of current and previous month
 	now := time.Now()
 	firstDayCurrentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
@@ -47,14 +49,15 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	var stats dto.DashboardStats
 
 	// Count all schools and publics (no date filter)
-	if err := r.DB.Table("schools").Where("deleted_at IS NULL").Count(&stats.Schools).Error; err != nil {
+	if err := r.DB.Table("schools").Where("deleted_at IS NULL").Scopes(scope.Apply("province_id", "city_id")).Count(&stats.Schools).Error; err != nil {
 		return nil, err
 	}
-	if err := r.DB.Table("publics").Where("deleted_at IS NULL").Count(&stats.Publics).Error; err != nil {
+	if err := r.DB.Table("publics").Where("deleted_at IS NULL").Scopes(scope.Apply("province_id", "city_id")).Count(&stats.Publics).Error; err != nil {
 		return nil, err
 	}
 
 	// Count events and sum attendees from last 2 months (COMBINED QUERY)
+	// attendees_count follows the participant roster once an event has one
 	type EventStats struct {
 		EventCount     int64
 		TotalAttendees int64
@@ -63,6 +66,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("events").
 		Select("COUNT(*) as event_count, COALESCE(SUM(attendees_count), 0) as total_attendees").
 		Where("deleted_at IS NULL AND event_date >= ? AND event_date < ?", startDate, endDate).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Scan(&eventStats).Error; err != nil {
 		return nil, err
 	}
@@ -78,6 +82,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("accidents").
 		Select("COUNT(*) as accident_count, COALESCE(SUM(death_count), 0) as total_deaths, COALESCE(SUM(injured_count), 0) as total_injured").
 		Where("deleted_at IS NULL AND accident_date >= ? AND accident_date < ?", startDate, endDate).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Scan(&accidentStats).Error; err != nil {
 		return nil, err
 	}
@@ -93,6 +98,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("polda_accidents").
 		Select("COALESCE(SUM(total_accidents), 0) as total_accidents, COALESCE(SUM(total_deaths), 0) as total_deaths, COALESCE(SUM(total_severe_injury + total_minor_injury), 0) as total_injured").
 		Where("deleted_at IS NULL AND period IN (?, ?)", prevPeriod, currentPeriod).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Scan(&poldaStats).Error; err != nil {
 		return nil, err
 	}
@@ -101,6 +107,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	// Count budgets from last 2 months
 	if err := r.DB.Table("event_budgets").
 		Where("deleted_at IS NULL AND budget_date >= ? AND budget_date < ?", startDate, endDate).
+		Scopes(budgetsInScope(scope)).
 		Count(&stats.Budgets).Error; err != nil {
 		return nil, err
 	}
@@ -127,6 +134,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("event_budgets").
 		Select("COALESCE(SUM(budget_amount), 0) as total_allocated, COALESCE(SUM(actual_spent), 0) as total_spent").
 		Where("deleted_at IS NULL AND budget_date >= ? AND budget_date < ?", startDate, endDate).
+		Scopes(budgetsInScope(scope)).
 		Scan(&budgetSum).Error; err != nil {
 		return nil, err
 	}
@@ -137,26 +145,22 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 
 	// ===== TRAINED ENTITIES (Schools and Publics with completed events) =====
 	// Count schools with completed events
-	if err := r.DB.Raw(`
-		SELECT COUNT(DISTINCT schools.id)
-		FROM schools
-		JOIN events ON schools.id = events.school_id
-		WHERE schools.deleted_at IS NULL
-		  AND events.deleted_at IS NULL
-		  AND events.status = ?
-	`, "completed").Scan(&stats.AdditionalStats.TrainedSchools).Error; err != nil {
+	if err := r.DB.Table("schools").
+		Select("COUNT(DISTINCT schools.id)").
+		Joins("JOIN events ON schools.id = events.school_id").
+		Where("schools.deleted_at IS NULL AND events.deleted_at IS NULL AND events.status = ?", "completed").
+		Scopes(scope.Apply("schools.province_id", "schools.city_id")).
+		Scan(&stats.AdditionalStats.TrainedSchools).Error; err != nil {
 		return nil, err
 	}
 
 	// Count publics with completed events
-	if err := r.DB.Raw(`
-		SELECT COUNT(DISTINCT publics.id)
-		FROM publics
-		JOIN events ON publics.id = events.public_id
-		WHERE publics.deleted_at IS NULL
-		  AND events.deleted_at IS NULL
-		  AND events.status = ?
-	`, "completed").Scan(&stats.AdditionalStats.TrainedPublics).Error; err != nil {
+	if err := r.DB.Table("publics").
+		Select("COUNT(DISTINCT publics.id)").
+		Joins("JOIN events ON publics.id = events.public_id").
+		Where("publics.deleted_at IS NULL AND events.deleted_at IS NULL AND events.status = ?", "completed").
+		Scopes(scope.Apply("publics.province_id", "publics.city_id")).
+		Scan(&stats.AdditionalStats.TrainedPublics).Error; err != nil {
 		return nil, err
 	}
 
@@ -171,6 +175,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("accidents").
 		Select("TO_CHAR(accident_date::date, 'YYYY-MM') as year_month, COUNT(*) as count, COALESCE(SUM(death_count), 0) as deaths, COALESCE(SUM(injured_count), 0) as injured").
 		Where("deleted_at IS NULL AND accident_date >= ?", startDate12Months).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Group("year_month").
 		Order("year_month ASC").
 		Scan(&ahassTrends).Error; err != nil {
@@ -182,6 +187,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("polda_accidents").
 		Select("period as year_month, COALESCE(SUM(total_accidents), 0) as count, COALESCE(SUM(total_deaths), 0) as deaths, COALESCE(SUM(total_severe_injury + total_minor_injury), 0) as injured").
 		Where("deleted_at IS NULL AND period >= ?", firstDay12MonthsAgo.Format("2006-01")).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Group("period").
 		Order("period ASC").
 		Scan(&poldaTrends).Error; err != nil {
@@ -244,6 +250,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("events").
 		Select("COALESCE(event_type, 'Unknown') as event_type, COUNT(*) as count").
 		Where("deleted_at IS NULL AND event_date >= ? AND event_date < ?", startDate, endDate).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Group("event_type").
 		Scan(&eventDist).Error; err != nil {
 		return nil, err
@@ -266,6 +273,7 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 	if err := r.DB.Table("event_budgets").
 		Select("TO_CHAR(budget_date::date, 'YYYY-MM') as year_month, COALESCE(SUM(budget_amount), 0) as total_allocated, COALESCE(SUM(actual_spent), 0) as total_spent").
 		Where("deleted_at IS NULL AND budget_date >= ?", startDate12Months).
+		Scopes(budgetsInScope(scope)).
 		Group("year_month").
 		Order("year_month ASC").
 		Scan(&budgetUtil).Error; err != nil {
@@ -288,13 +296,16 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 		EventDate  string
 		SchoolName string
 		PublicName string
+		CoverUrl   string
 	}
 	var recentEvents []RecentEventRaw
 	if err := r.DB.Table("events").
-		Select("events.id, events.title, events.event_type, events.event_date, schools.name as school_name, publics.name as public_name").
+		Select("events.id, events.title, events.event_type, events.event_date, schools.name as school_name, publics.name as public_name, cover.thumbnail_url as cover_url").
 		Joins("LEFT JOIN schools ON events.school_id = schools.id").
 		Joins("LEFT JOIN publics ON events.public_id = publics.id").
+		Joins(coverPhotoJoin("event_photos", "event_id", "events")).
 		Where("events.deleted_at IS NULL").
+		Scopes(scope.Apply("events.province_id", "events.city_id")).
 		Order("events.created_at DESC").
 		Limit(5).
 		Scan(&recentEvents).Error; err != nil {
@@ -310,11 +321,12 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 			location = "-"
 		}
 		stats.RecentEvents = append(stats.RecentEvents, dto.RecentEvent{
-			ID:        e.ID,
-			Title:     e.Title,
-			EventType: e.EventType,
-			EventDate: e.EventDate,
-			Location:  location,
+			ID:            e.ID,
+			Title:         e.Title,
+			EventType:     e.EventType,
+			EventDate:     e.EventDate,
+			Location:      location,
+			CoverPhotoUrl: e.CoverUrl,
 		})
 	}
 
@@ -326,12 +338,15 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 		Location       string
 		DeathCount     int
 		InjuredCount   int
+		CoverUrl       string
 	}
 	var recentAccidents []RecentAccidentRaw
 	if err := r.DB.Table("accidents").
-		Select("id, police_report_no, accident_date, location, death_count, injured_count").
-		Where("deleted_at IS NULL").
-		Order("created_at DESC").
+		Select("accidents.id, accidents.police_report_no, accidents.accident_date, accidents.location, accidents.death_count, accidents.injured_count, cover.thumbnail_url as cover_url").
+		Joins(coverPhotoJoin("accident_photos", "accident_id", "accidents")).
+		Where("accidents.deleted_at IS NULL").
+		Scopes(scope.Apply("accidents.province_id", "accidents.city_id")).
+		Order("accidents.created_at DESC").
 		Limit(5).
 		Scan(&recentAccidents).Error; err != nil {
 		return nil, err
@@ -349,13 +364,14 @@ func (r *DashboardRepo) GetStats() (*dto.DashboardStats, error) {
 			Location:       location,
 			DeathCount:     a.DeathCount,
 			InjuredCount:   a.InjuredCount,
+			CoverPhotoUrl:  a.CoverUrl,
 		})
 	}
 
 	return &stats, nil
 }
 
-func (r *DashboardRepo) GetAccidentRecommendations() ([]dto.AccidentRecommendation, error) {
+func (r *DashboardRepo) GetAccidentRecommendations(scope filter.RegionScope) ([]dto.AccidentRecommendation, error) {
 	// Use same date calculation as GetStats()
 	now := time.Now()
 	firstDayCurrentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
@@ -386,6 +402,7 @@ func (r *DashboardRepo) GetAccidentRecommendations() ([]dto.AccidentRecommendati
 	if err := db.Table("accidents").
 		Select("CAST(city_id AS TEXT) as city_id, city_name, COUNT(*) as count").
 		Where("deleted_at IS NULL AND accident_date >= ? AND accident_date < ?", startDate, endDate).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Group("city_id, city_name").
 		Having("COUNT(*) > 0").
 		Find(&ahassData).Error; err != nil {
@@ -394,15 +411,13 @@ func (r *DashboardRepo) GetAccidentRecommendations() ([]dto.AccidentRecommendati
 
 	// Get POLDA accidents by city
 	var poldaData []CityCount
-	query := fmt.Sprintf(`
-		SELECT city_id, city_name, SUM(total_accidents) as count 
-		FROM polda_accidents 
-		WHERE deleted_at IS NULL AND period IN ('%s', '%s') 
-		GROUP BY city_id, city_name 
-		HAVING SUM(total_accidents) > 0
-	`, prevPeriod, currentPeriod)
-
-	if err := db.Raw(query).Scan(&poldaData).Error; err != nil {
+	if err := db.Table("polda_accidents").
+		Select("city_id, city_name, SUM(total_accidents) as count").
+		Where("deleted_at IS NULL AND period IN (?, ?)", prevPeriod, currentPeriod).
+		Scopes(scope.Apply("province_id", "city_id")).
+		Group("city_id, city_name").
+		Having("SUM(total_accidents) > 0").
+		Scan(&poldaData).Error; err != nil {
 		return recommendations, err
 	}
 
@@ -469,7 +484,28 @@ func (r *DashboardRepo) GetAccidentRecommendations() ([]dto.AccidentRecommendati
 	return recommendations, nil
 }
 
+// budgetsInScope limits budgets to the events inside the region scope, budgets have no region of their own
+func budgetsInScope(scope filter.RegionScope) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition, args := scope.Condition("province_id", "city_id")
+		if condition == "" {
+			return db
+		}
+		return db.Where("event_id IN (SELECT id FROM events WHERE "+condition+")", args...)
+	}
+}
+
 // Helper function to format period from "YYYY-MM" to "Mon YYYY"
+// coverPhotoJoin joins the cover photo of each row as "cover", falling back to its first photo when none was chosen
+func coverPhotoJoin(photoTable, foreignKey, table string) string {
+	return fmt.Sprintf(`LEFT JOIN LATERAL (
+		SELECT thumbnail_url FROM %[1]s
+		WHERE %[1]s.%[2]s = %[3]s.id AND %[1]s.deleted_at IS NULL
+		ORDER BY is_cover DESC, photo_order, created_at
+		LIMIT 1
+	) cover ON true`, photoTable, foreignKey, table)
+}
+
 func formatPeriod(period string) string {
 	t, err := time.Parse("2006-01", period)
 	if err != nil {
@@ -25,12 +25,19 @@ func (r *repo) Create(event domainevent.Event) error {
 	return r.DB.Create(&event).Error
 }
 
-func (r *repo) GetByID(id string) (domainevent.Event, error) {
+func (r *repo) GetByID(id string, scope filter.RegionScope) (domainevent.Event, error) {
 	var event domainevent.Event
-	err := r.DB.Preload("Photos").
+	err := r.DB.Scopes(scope.Apply("province_id", "city_id")).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("photo_order ASC")
+		}).
 		Preload("OnTheSpotSales").
 		Preload("School").
 		Preload("Public").
+		Preload("Instructors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("role = 'lead' DESC")
+		}).
+		Preload("Instructors.Instructor").
 		Where("id = ?", id).
 		First(&event).Error
 	return event, err
@@ -41,7 +48,8 @@ func (r *repo) Update(event domainevent.Event) error {
 }
 
 func (r *repo) UpdateById(id string, event domainevent.Event) error {
-	return r.DB.Model(&domainevent.Event{}).Where("id = ?", id).Updates(&event).Error
+	// Instructor assignments are replaced through the instructor repository
+	return r.DB.Model(&domainevent.Event{}).Omit("Instructors").Where("id = ?", id).Updates(&event).Error
 }
 
 func (r *repo) Fetch(params filter.BaseParams) (ret []domainevent.Event, totalData int64, err error) {
@@ -49,7 +57,9 @@ func (r *repo) Fetch(params filter.BaseParams) (ret []domainevent.Event, totalDa
 		Preload("Photos").
 		Preload("OnTheSpotSales").
 		Preload("School").
-		Preload("Public").Debug()
+		Preload("Public").
+		Preload("Instructors.Instructor").Debug().
+		Scopes(params.Scope.Apply("province_id", "city_id"))
 
 	if len(params.Columns) > 0 {
 		query = query.Select(params.Columns)
@@ -101,7 +111,7 @@ func (r *repo) Fetch(params filter.BaseParams) (ret []domainevent.Event, totalDa
 		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
 	}
 
-	if err := query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+	if err := query.Scopes(filter.Paginate(params)).Find(&ret).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -136,10 +146,123 @@ func (r *repo) DeletePhoto(photoId string) error {
 	return r.DB.Where("id = ?", photoId).Delete(&domainevent.EventPhoto{}).Error
 }
 
+// UpdatePhoto saves the caption and cover flag of a photo, a new cover replaces the previous one of the event
+func (r *repo) UpdatePhoto(photo domainevent.EventPhoto) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if photo.IsCover {
+			if err := tx.Model(&domainevent.EventPhoto{}).
+				Where("event_id = ? AND id <> ? AND is_cover", photo.EventId, photo.ID).
+				Update("is_cover", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domainevent.EventPhoto{}).Where("id = ?", photo.ID).
+			Updates(map[string]interface{}{"caption": photo.Caption, "is_cover": photo.IsCover}).Error
+	})
+}
+
+// ReorderPhotos numbers the photos 1..n in the given order
+func (r *repo) ReorderPhotos(eventId string, photoIds []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range photoIds {
+			if err := tx.Model(&domainevent.EventPhoto{}).
+				Where("id = ? AND event_id = ?", id, eventId).
+				Update("photo_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NormalizePhotoOrder renumbers the photos of the event 1..n keeping their order, after photos were added or removed
+func (r *repo) NormalizePhotoOrder(eventId string) error {
+	return r.DB.Exec(`
+		UPDATE event_photos p SET photo_order = o.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY photo_order, created_at, id) AS position
+			FROM event_photos
+			WHERE event_id = ? AND deleted_at IS NULL
+		) o
+		WHERE p.id = o.id AND p.photo_order <> o.position`, eventId).Error
+}
+
+// GetCoverPhotos returns the cover photo of each event, or its first photo when none was chosen
+func (r *repo) GetCoverPhotos(eventIds []string) (map[string]domainevent.EventPhoto, error) {
+	covers := make(map[string]domainevent.EventPhoto, len(eventIds))
+	if len(eventIds) == 0 {
+		return covers, nil
+	}
+
+	var photos []domainevent.EventPhoto
+	if err := r.DB.Raw(`
+		SELECT DISTINCT ON (event_id) *
+		FROM event_photos
+		WHERE event_id IN ? AND deleted_at IS NULL
+		ORDER BY event_id, is_cover DESC, photo_order, created_at`, eventIds).
+		Scan(&photos).Error; err != nil {
+		return nil, err
+	}
+	for _, photo := range photos {
+		covers[photo.EventId] = photo
+	}
+	return covers, nil
+}
+
 func (r *repo) DeletePhotosByEventID(eventId string) error {
 	return r.DB.Where("event_id = ?", eventId).Delete(&domainevent.EventPhoto{}).Error
 }
 
+func (r *repo) CountParticipants(eventId string) (int64, error) {
+	var count int64
+	err := r.DB.Table("event_participants").Where("event_id = ? AND deleted_at IS NULL", eventId).Count(&count).Error
+	return count, err
+}
+
+// Status history methods
+func (r *repo) AddStatusHistory(history domainevent.EventStatusHistory) error {
+	return r.DB.Create(&history).Error
+}
+
+func (r *repo) UpdateStatus(history domainevent.EventStatusHistory) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return updateStatus(tx, history)
+	})
+}
+
+func (r *repo) UpdateWithStatus(id string, event domainevent.Event, history domainevent.EventStatusHistory) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := updateStatus(tx, history); err != nil {
+			return err
+		}
+		return tx.Model(&domainevent.Event{}).Omit("Instructors").Where("id = ?", id).Updates(&event).Error
+	})
+}
+
+// updateStatus moves the event to the new status only when it still has the old one, and records the transition
+func updateStatus(tx *gorm.DB, history domainevent.EventStatusHistory) error {
+	res := tx.Model(&domainevent.Event{}).
+		Where("id = ? AND COALESCE(status, '') = ?", history.EventId, history.FromStatus).
+		Updates(map[string]interface{}{
+			"status":     history.ToStatus,
+			"updated_at": history.ChangedAt,
+			"updated_by": history.ChangedBy,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("event status is no longer '%s', reload the event and try again", history.FromStatus)
+	}
+	return tx.Create(&history).Error
+}
+
+func (r *repo) GetStatusHistory(eventId string) ([]domainevent.EventStatusHistory, error) {
+	var history []domainevent.EventStatusHistory
+	err := r.DB.Where("event_id = ?", eventId).Order("changed_at ASC").Find(&history).Error
+	return history, err
+}
+
 // On The Spot Sales methods
 func (r *repo) AddOnTheSpotSales(sales []domainevent.EventOnTheSpotSale) error {
 	if len(sales) == 0 {
@@ -152,10 +275,58 @@ func (r *repo) DeleteOnTheSpotSalesByEventID(eventId string) error {
 	return r.DB.Where("event_id = ?", eventId).Delete(&domainevent.EventOnTheSpotSale{}).Error
 }
 
-func (r *repo) FetchCompletedWithCoords(since time.Time) ([]domainevent.Event, error) {
+func (r *repo) FetchCompletedWithCoords(since time.Time, scope filter.RegionScope) ([]domainevent.Event, error) {
 	var events []domainevent.Event
 	err := r.DB.Preload("School").Preload("Public").
+		Scopes(scope.Apply("province_id", "city_id")).
 		Where("status = ? AND event_date >= ?", "completed", since.Format("2006-01-02")).
 		Find(&events).Error
 	return events, err
 }
+
+// FetchByDateRange returns the events held between from and to (YYYY-MM-DD, both inclusive) in calendar order
+func (r *repo) FetchByDateRange(from, to string, scope filter.RegionScope) ([]domainevent.Event, error) {
+	var events []domainevent.Event
+	err := r.DB.Preload("School").Preload("Public").
+		Scopes(scope.Apply("province_id", "city_id")).
+		Where("event_date >= ? AND event_date <= ?", from, to).
+		Order("event_date ASC, start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// FetchForFeed returns the events held since the given date, including the ones deleted since then
+// so calendar subscribers see them as cancelled instead of silently losing them
+func (r *repo) FetchForFeed(since time.Time, scope filter.RegionScope) ([]domainevent.Event, error) {
+	var events []domainevent.Event
+	err := r.DB.Unscoped().Preload("School").Preload("Public").
+		Scopes(scope.Apply("province_id", "city_id")).
+		Where("event_date >= ?", since.Format("2006-01-02")).
+		Where("(deleted_at IS NULL OR deleted_at >= ?)", since).
+		Order("event_date ASC, start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *repo) GetIdsByRequestNumbers(requestNumbers []int) (map[int]string, error) {
+	ids := make(map[int]string, len(requestNumbers))
+	if len(requestNumbers) == 0 {
+		return ids, nil
+	}
+
+	var rows []struct {
+		ID            string
+		RequestNumber int
+	}
+	if err := r.DB.Model(&domainevent.Event{}).
+		Select("id, request_number").
+		Where("request_number IN ?", requestNumbers).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		ids[row.RequestNumber] = row.ID
+	}
+	return ids, nil
+}
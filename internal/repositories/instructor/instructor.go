@@ -0,0 +1,138 @@
+package repositoryinstructor
+
+import (
+	"fmt"
+
+	domaininstructor "safety-riding/internal/domain/instructor"
+	interfaceinstructor "safety-riding/internal/interfaces/instructor"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewInstructorRepo(db *gorm.DB) interfaceinstructor.RepoInstructorInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func (r *repo) Create(instructor domaininstructor.Instructor) error {
+	return r.DB.Create(&instructor).Error
+}
+
+func (r *repo) GetByID(id string) (domaininstructor.Instructor, error) {
+	var instructor domaininstructor.Instructor
+	err := r.DB.Where("id = ?", id).First(&instructor).Error
+	return instructor, err
+}
+
+func (r *repo) GetByIDs(ids []string) ([]domaininstructor.Instructor, error) {
+	var ret []domaininstructor.Instructor
+	if len(ids) == 0 {
+		return ret, nil
+	}
+	err := r.DB.Where("id IN ?", ids).Find(&ret).Error
+	return ret, err
+}
+
+func (r *repo) Update(instructor domaininstructor.Instructor) error {
+	return r.DB.Save(&instructor).Error
+}
+
+func (r *repo) Fetch(params filter.BaseParams) (ret []domaininstructor.Instructor, totalData int64, err error) {
+	query := r.DB.Model(&domaininstructor.Instructor{})
+
+	if params.Search != "" {
+		query = query.Where("LOWER(name) LIKE LOWER(?) OR phone LIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+
+	for key, value := range params.Filters {
+		if v, ok := value.(string); ok && v != "" {
+			query = query.Where(fmt.Sprintf("%s = ?", key), v)
+		}
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"name":       true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	if err = query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+func (r *repo) Delete(id string) error {
+	return r.DB.Where("id = ?", id).Delete(&domaininstructor.Instructor{}).Error
+}
+
+func (r *repo) SetEventInstructors(eventId string, assignments []domaininstructor.EventInstructor) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventId).Delete(&domaininstructor.EventInstructor{}).Error; err != nil {
+			return err
+		}
+		// Without instructors the event keeps its last instructor_name and instructor_phone as free text
+		if len(assignments) == 0 {
+			return nil
+		}
+		if err := tx.Omit("Instructor").Create(&assignments).Error; err != nil {
+			return err
+		}
+
+		// Free-text columns kept for the screens, exports and reports that read them
+		return tx.Exec(`UPDATE events SET
+			instructor_name = (
+				SELECT STRING_AGG(i.name, ', ' ORDER BY ei.role = 'lead' DESC, LOWER(i.name))
+				FROM event_instructors ei JOIN instructors i ON i.id = ei.instructor_id
+				WHERE ei.event_id = events.id),
+			instructor_phone = COALESCE((
+				SELECT i.phone
+				FROM event_instructors ei JOIN instructors i ON i.id = ei.instructor_id
+				WHERE ei.event_id = events.id AND ei.role = ?
+				LIMIT 1), '')
+			WHERE id = ?`, domaininstructor.RoleLead, eventId).Error
+	})
+}
+
+func (r *repo) FetchBookings(instructorIds []string, from, to, excludeEventId string, scope filter.RegionScope) ([]domaininstructor.Booking, error) {
+	var ret []domaininstructor.Booking
+	if len(instructorIds) == 0 {
+		return ret, nil
+	}
+
+	query := r.DB.Table("event_instructors ei").
+		Select(`ei.instructor_id, i.name AS instructor_name, ei.role,
+			e.id AS event_id, e.title, e.event_date, e.start_time, e.end_time, e.location, e.status`).
+		Joins("JOIN events e ON e.id = ei.event_id AND e.deleted_at IS NULL").
+		Joins("JOIN instructors i ON i.id = ei.instructor_id").
+		Scopes(scope.Apply("e.province_id", "e.city_id")).
+		Where("ei.instructor_id IN ?", instructorIds).
+		Where("e.event_date >= ? AND e.event_date <= ?", from, to).
+		Where("LOWER(e.status) <> ?", utils.StsCancelled)
+	if excludeEventId != "" {
+		query = query.Where("e.id <> ?", excludeEventId)
+	}
+
+	err := query.Order("e.event_date ASC, e.start_time ASC").Scan(&ret).Error
+	return ret, err
+}
@@ -25,9 +25,9 @@ func (r *marketShareRepository) Create(marketShare domainmarketshare.MarketShare
 	return r.db.Create(&marketShare).Error
 }
 
-func (r *marketShareRepository) GetByID(id string) (domainmarketshare.MarketShare, error) {
+func (r *marketShareRepository) GetByID(id string, scope filter.RegionScope) (domainmarketshare.MarketShare, error) {
 	var marketShare domainmarketshare.MarketShare
-	err := r.db.Where("id = ?", id).First(&marketShare).Error
+	err := r.db.Scopes(scope.Apply("province_id", "city_id")).Where("id = ?", id).First(&marketShare).Error
 	return marketShare, err
 }
 
@@ -44,7 +44,8 @@ func (r *marketShareRepository) Fetch(params filter.BaseParams) ([]domainmarkets
 	var totalData int64
 
 	query := r.db.Model(&domainmarketshare.MarketShare{}).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL").
+		Scopes(params.Scope.Apply("province_id", "city_id"))
 
 	// Apply filters
 	for key, value := range params.Filters {
@@ -88,7 +89,7 @@ func (r *marketShareRepository) Fetch(params filter.BaseParams) ([]domainmarkets
 		query = query.Order(params.OrderBy + " " + params.OrderDirection)
 	}
 
-	if err := query.Limit(params.Limit).Offset((params.Page - 1) * params.Limit).Find(&marketShares).Error; err != nil {
+	if err := query.Scopes(filter.Paginate(params)).Find(&marketShares).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -119,7 +120,7 @@ func toInt(value interface{}) (int, error) {
 }
 
 // GetTopDistricts returns top performing districts by sales
-func (r *marketShareRepository) GetTopDistricts(year, month int, limit int) ([]domainmarketshare.TopDistrict, error) {
+func (r *marketShareRepository) GetTopDistricts(year, month int, limit int, scope filter.RegionScope) ([]domainmarketshare.TopDistrict, error) {
 	var results []domainmarketshare.TopDistrict
 
 	query := r.db.Model(&domainmarketshare.MarketShare{}).
@@ -137,7 +138,8 @@ func (r *marketShareRepository) GetTopDistricts(year, month int, limit int) ([]d
 			COALESCE(SUM(monthly_sales - monthly_competitor_sales), 0) as monthly_difference,
 			0 as recommend_score
 		`).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL").
+		Scopes(scope.Apply("province_id", "city_id"))
 
 	if year > 0 {
 		query = query.Where("year = ?", year)
@@ -165,7 +167,7 @@ func (r *marketShareRepository) GetTopDistricts(year, month int, limit int) ([]d
 	return results, err
 }
 
-func (r *marketShareRepository) GetTopCities(year, month int, limit int, sortOrder string) ([]domainmarketshare.TopCity, error) {
+func (r *marketShareRepository) GetTopCities(year, month int, limit int, sortOrder string, scope filter.RegionScope) ([]domainmarketshare.TopCity, error) {
 	var results []domainmarketshare.TopCity
 
 	query := r.db.Model(&domainmarketshare.MarketShare{}).
@@ -181,7 +183,8 @@ func (r *marketShareRepository) GetTopCities(year, month int, limit int, sortOrd
 			COALESCE(SUM(monthly_sales - monthly_competitor_sales), 0) as monthly_difference,
 			0 as recommend_score
 		`).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL").
+		Scopes(scope.Apply("province_id", "city_id"))
 
 	if year > 0 {
 		query = query.Where("year = ?", year)
@@ -213,10 +216,10 @@ func (r *marketShareRepository) GetTopCities(year, month int, limit int, sortOrd
 	return results, err
 }
 
-func (r *marketShareRepository) GetSummary(level string, year, month int, provinceID, cityID, districtID string) ([]domainmarketshare.MarketShareSummary, error) {
+func (r *marketShareRepository) GetSummary(level string, year, month int, provinceID, cityID, districtID string, scope filter.RegionScope) ([]domainmarketshare.MarketShareSummary, error) {
 	var results []domainmarketshare.MarketShareSummary
 
-	query := r.db.Model(&domainmarketshare.MarketShare{}).Where("deleted_at IS NULL")
+	query := r.db.Model(&domainmarketshare.MarketShare{}).Where("deleted_at IS NULL").Scopes(scope.Apply("province_id", "city_id"))
 
 	if year > 0 {
 		query = query.Where("year = ?", year)
@@ -308,6 +311,6 @@ func (r *marketShareRepository) GetByLocation(provinceID, cityID, districtID str
 }
 
 // GetSummaryByYear returns aggregated summary by year
-func (r *marketShareRepository) GetSummaryByYear(year int) ([]domainmarketshare.MarketShareSummary, error) {
-	return r.GetSummary("district", year, 0, "", "", "")
+func (r *marketShareRepository) GetSummaryByYear(year int, scope filter.RegionScope) ([]domainmarketshare.MarketShareSummary, error) {
+	return r.GetSummary("district", year, 0, "", "", "", scope)
 }
@@ -0,0 +1,158 @@
+package repositoryparticipant
+
+import (
+	"fmt"
+	"time"
+
+	domainparticipant "safety-riding/internal/domain/participant"
+	interfaceparticipant "safety-riding/internal/interfaces/participant"
+	"safety-riding/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewParticipantRepo(db *gorm.DB) interfaceparticipant.RepoParticipantInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+// CreateBatch stores the participants and refreshes the attendees count in one transaction
+func (r *repo) CreateBatch(participants []domainparticipant.EventParticipant) error {
+	if len(participants) == 0 {
+		return nil
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&participants).Error; err != nil {
+			return err
+		}
+		return syncAttendeesCount(tx, participants[0].EventId)
+	})
+}
+
+func (r *repo) GetByID(id string) (domainparticipant.EventParticipant, error) {
+	var participant domainparticipant.EventParticipant
+	err := r.DB.Where("id = ?", id).First(&participant).Error
+	return participant, err
+}
+
+func (r *repo) FetchByEvent(eventId string, params filter.BaseParams) (ret []domainparticipant.EventParticipant, totalData int64, err error) {
+	query := r.DB.Model(&domainparticipant.EventParticipant{}).Where("event_id = ?", eventId)
+
+	if params.Search != "" {
+		query = query.Where("LOWER(name) LIKE LOWER(?) OR LOWER(organization) LIKE LOWER(?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+
+	for key, value := range params.Filters {
+		if v, ok := value.(string); ok && v != "" {
+			query = query.Where(fmt.Sprintf("%s = ?", key), v)
+		}
+	}
+
+	if err = query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.OrderBy != "" && params.OrderDirection != "" {
+		validColumns := map[string]bool{
+			"name":         true,
+			"age":          true,
+			"grade":        true,
+			"organization": true,
+			"created_at":   true,
+		}
+
+		if _, ok := validColumns[params.OrderBy]; !ok {
+			return nil, 0, fmt.Errorf("invalid orderBy column: %s", params.OrderBy)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
+	}
+
+	// event_count shows people who attended more than one event
+	err = query.Select(`event_participants.*,
+		(SELECT COUNT(DISTINCT other.event_id) FROM event_participants other
+			JOIN events e ON e.id = other.event_id AND e.deleted_at IS NULL
+			WHERE other.identity_key = event_participants.identity_key AND other.deleted_at IS NULL) AS event_count`).
+		Offset(params.Offset).Limit(params.Limit).Find(&ret).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, totalData, nil
+}
+
+// GetByEvent returns the whole roster of the event ordered by name
+func (r *repo) GetByEvent(eventId string) ([]domainparticipant.EventParticipant, error) {
+	var ret []domainparticipant.EventParticipant
+	err := r.DB.Where("event_id = ?", eventId).Order("name ASC").Find(&ret).Error
+	return ret, err
+}
+
+// GetIdentityKeys returns the identity keys already on the roster of the event
+func (r *repo) GetIdentityKeys(eventId string) (map[string]bool, error) {
+	var keys []string
+	if err := r.DB.Model(&domainparticipant.EventParticipant{}).Where("event_id = ?", eventId).Pluck("identity_key", &keys).Error; err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		ret[key] = true
+	}
+	return ret, nil
+}
+
+func (r *repo) GetByIdentityKey(eventId, identityKey string) (domainparticipant.EventParticipant, error) {
+	var participant domainparticipant.EventParticipant
+	err := r.DB.Where("event_id = ? AND identity_key = ?", eventId, identityKey).First(&participant).Error
+	return participant, err
+}
+
+// MarkCheckedIn records the check-in time, it reports false when the participant had already checked in
+func (r *repo) MarkCheckedIn(id string, at time.Time) (bool, error) {
+	res := r.DB.Model(&domainparticipant.EventParticipant{}).
+		Where("id = ? AND checked_in_at IS NULL", id).
+		Update("checked_in_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// CountCheckIns returns the roster size, how many of them checked in and the latest check-in time
+func (r *repo) CountCheckIns(eventId string) (registered, checkedIn int64, lastCheckInAt *time.Time, err error) {
+	var stats struct {
+		Registered    int64
+		CheckedIn     int64
+		LastCheckInAt *time.Time
+	}
+	err = r.DB.Model(&domainparticipant.EventParticipant{}).
+		Select("COUNT(*) AS registered, COUNT(checked_in_at) AS checked_in, MAX(checked_in_at) AS last_check_in_at").
+		Where("event_id = ?", eventId).
+		Scan(&stats).Error
+	return stats.Registered, stats.CheckedIn, stats.LastCheckInAt, err
+}
+
+// Delete soft deletes the participant and refreshes the attendees count in one transaction
+func (r *repo) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var participant domainparticipant.EventParticipant
+		if err := tx.Where("id = ?", id).First(&participant).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&participant).Error; err != nil {
+			return err
+		}
+		return syncAttendeesCount(tx, participant.EventId)
+	})
+}
+
+// syncAttendeesCount keeps events.attendees_count equal to the roster size, so every report reading the column uses the roster
+func syncAttendeesCount(db *gorm.DB, eventId string) error {
+	return db.Exec(`UPDATE events SET attendees_count = (
+			SELECT COUNT(*) FROM event_participants WHERE event_id = ? AND deleted_at IS NULL
+		) WHERE id = ?`, eventId, eventId).Error
+}
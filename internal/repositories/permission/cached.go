@@ -0,0 +1,89 @@
+package repositorypermission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainpermission "safety-riding/internal/domain/permission"
+	interfacepermission "safety-riding/internal/interfaces/permission"
+	"safety-riding/pkg/cache"
+	"safety-riding/pkg/logger"
+)
+
+const userPermissionCachePrefix = "perm_cache:user:"
+
+// cachedRepo keeps the resolved permissions of each user so the permission middleware does not hit the database on every request
+type cachedRepo struct {
+	interfacepermission.RepoPermissionInterface
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+type CachedPermissionRepo interface {
+	interfacepermission.RepoPermissionInterface
+	interfacepermission.PermissionCacheInvalidator
+}
+
+func NewCachedPermissionRepo(repo interfacepermission.RepoPermissionInterface, c cache.Cache, ttl time.Duration) CachedPermissionRepo {
+	return &cachedRepo{RepoPermissionInterface: repo, cache: c, ttl: ttl}
+}
+
+func (r *cachedRepo) GetUserPermissions(userId string) ([]domainpermission.Permission, error) {
+	ctx := context.Background()
+	key := userPermissionCachePrefix + userId
+
+	if raw, found, err := r.cache.Get(ctx, key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[PermissionCache]; Get %s; Error: %v", key, err))
+	} else if found {
+		var ret []domainpermission.Permission
+		if err = json.Unmarshal(raw, &ret); err == nil {
+			return ret, nil
+		}
+	}
+
+	ret, err := r.RepoPermissionInterface.GetUserPermissions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err := json.Marshal(ret); err == nil {
+		if err = r.cache.Set(ctx, key, raw, r.ttl); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[PermissionCache]; Set %s; Error: %v", key, err))
+		}
+	}
+
+	return ret, nil
+}
+
+func (r *cachedRepo) Update(m domainpermission.Permission) error {
+	if err := r.RepoPermissionInterface.Update(m); err != nil {
+		return err
+	}
+	r.InvalidateAll()
+	return nil
+}
+
+func (r *cachedRepo) Delete(id string) error {
+	if err := r.RepoPermissionInterface.Delete(id); err != nil {
+		return err
+	}
+	r.InvalidateAll()
+	return nil
+}
+
+// InvalidateUser drops the cached permissions of one user, e.g. after a role change
+func (r *cachedRepo) InvalidateUser(userId string) {
+	key := userPermissionCachePrefix + userId
+	if err := r.cache.Delete(context.Background(), key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[PermissionCache]; Delete %s; Error: %v", key, err))
+	}
+}
+
+// InvalidateAll drops the cached permissions of every user, used when a role or permission changes
+func (r *cachedRepo) InvalidateAll() {
+	if err := r.cache.DeletePrefix(context.Background(), userPermissionCachePrefix); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[PermissionCache]; DeletePrefix %s; Error: %v", userPermissionCachePrefix, err))
+	}
+}
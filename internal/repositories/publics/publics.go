@@ -11,6 +11,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// visitColumns are derived from completed events by the visit repository, Update never writes them
+var visitColumns = []string{"visit_count", "is_educated", "last_visit_at"}
+
+// venueMatchCondition matches a name of at least the given length contained in a text, or an identical address.
+// Whitespace is collapsed on both sides so the text should be collapsed too.
+const venueMatchCondition = `((LENGTH(TRIM(name)) >= ? AND POSITION(REGEXP_REPLACE(LOWER(TRIM(name)), '\s+', ' ', 'g') IN LOWER(?)) > 0)
+	OR (TRIM(?) <> '' AND REGEXP_REPLACE(LOWER(TRIM(address)), '\s+', ' ', 'g') = LOWER(TRIM(?))))`
+
 type repo struct {
 	DB *gorm.DB
 }
@@ -32,7 +40,7 @@ func (r *repo) GetByID(id string) (domainpublic.Public, error) {
 }
 
 func (r *repo) Update(public domainpublic.Public) error {
-	return r.DB.Save(&public).Error
+	return r.DB.Omit(visitColumns...).Save(&public).Error
 }
 
 func (r *repo) Fetch(params filter.BaseParams) (ret []domainpublic.Public, totalData int64, err error) {
@@ -207,3 +215,13 @@ func (r *repo) GetForMap() ([]dto.PublicMapItem, error) {
 		Scan(&results).Error
 	return results, err
 }
+
+func (r *repo) FindMatches(text, address string) ([]domainpublic.Public, error) {
+	var publics []domainpublic.Public
+	err := r.DB.
+		Where(venueMatchCondition, 5, text, address, address).
+		Order("LENGTH(name) DESC").
+		Limit(5).
+		Find(&publics).Error
+	return publics, err
+}
@@ -11,6 +11,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// visitColumns are derived from completed events by the visit repository, Update never writes them
+var visitColumns = []string{"visit_count", "is_educated", "last_visit_at"}
+
+// venueMatchCondition matches a name of at least the given length contained in a text, or an identical address.
+// Whitespace is collapsed on both sides so the text should be collapsed too.
+const venueMatchCondition = `((LENGTH(TRIM(name)) >= ? AND POSITION(REGEXP_REPLACE(LOWER(TRIM(name)), '\s+', ' ', 'g') IN LOWER(?)) > 0)
+	OR (TRIM(?) <> '' AND REGEXP_REPLACE(LOWER(TRIM(address)), '\s+', ' ', 'g') = LOWER(TRIM(?))))`
+
 type repo struct {
 	DB *gorm.DB
 }
@@ -25,18 +33,18 @@ func (r *repo) Create(school domainschool.School) error {
 	return r.DB.Create(&school).Error
 }
 
-func (r *repo) GetByID(id string) (domainschool.School, error) {
+func (r *repo) GetByID(id string, scope filter.RegionScope) (domainschool.School, error) {
 	var school domainschool.School
-	err := r.DB.Where("id = ?", id).First(&school).Error
+	err := r.DB.Scopes(scope.Apply("province_id", "city_id")).Where("id = ?", id).First(&school).Error
 	return school, err
 }
 
 func (r *repo) Update(school domainschool.School) error {
-	return r.DB.Save(&school).Error
+	return r.DB.Omit(visitColumns...).Save(&school).Error
 }
 
 func (r *repo) Fetch(params filter.BaseParams) (ret []domainschool.School, totalData int64, err error) {
-	query := r.DB.Model(&domainschool.School{}).Debug()
+	query := r.DB.Model(&domainschool.School{}).Debug().Scopes(params.Scope.Apply("province_id", "city_id"))
 
 	if len(params.Columns) > 0 {
 		query = query.Select(params.Columns)
@@ -89,7 +97,7 @@ func (r *repo) Fetch(params filter.BaseParams) (ret []domainschool.School, total
 		query = query.Order(fmt.Sprintf("%s %s", params.OrderBy, params.OrderDirection))
 	}
 
-	if err := query.Offset(params.Offset).Limit(params.Limit).Find(&ret).Error; err != nil {
+	if err := query.Scopes(filter.Paginate(params)).Find(&ret).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -100,6 +108,15 @@ func (r *repo) Delete(id string) error {
 	return r.DB.Where("id = ?", id).Delete(&domainschool.School{}).Error
 }
 
+func (r *repo) GetByNPSNs(npsns []string) ([]domainschool.School, error) {
+	var schools []domainschool.School
+	if len(npsns) == 0 {
+		return schools, nil
+	}
+	err := r.DB.Where("npsn IN ?", npsns).Find(&schools).Error
+	return schools, err
+}
+
 func (r *repo) GetEducationStats(params filter.BaseParams) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
@@ -120,6 +137,7 @@ func (r *repo) GetEducationStats(params filter.BaseParams) ([]map[string]interfa
 		`).
 		Joins("LEFT JOIN events ON schools.id = events.school_id AND events.deleted_at IS NULL").
 		Where("schools.deleted_at IS NULL").
+		Scopes(params.Scope.Apply("schools.province_id", "schools.city_id")).
 		Group("schools.id, schools.name, schools.npsn, schools.district_id, schools.district_name, schools.city_id, schools.city_name, schools.province_id, schools.province_name, schools.student_count, schools.is_educated")
 
 	if monthVal, ok := params.Filters["month"]; ok {
@@ -196,6 +214,11 @@ func (r *repo) GetEducationStats(params filter.BaseParams) ([]map[string]interfa
 func (r *repo) GetEducationPriorityData(params filter.BaseParams) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
+	scopeFilter, scopeArgs := params.Scope.Condition("dm.province_id", "dm.city_id")
+	if scopeFilter != "" {
+		scopeFilter = " AND " + scopeFilter
+	}
+
 	// Build the complex query that joins market_shares, schools, and accidents by district
 	query := r.DB.Raw(`
 		WITH district_market AS (
@@ -211,7 +234,7 @@ func (r *repo) GetEducationPriorityData(params filter.BaseParams) ([]map[string]
 				COALESCE(AVG(monthly_competitor_percentage), 0) as competitor_share
 			FROM market_shares
 			WHERE deleted_at IS NULL
-			` + buildMarketShareFilters(params) + `
+			`+buildMarketShareFilters(params)+`
 			GROUP BY district_id, district_name, city_id, city_name, province_id, province_name
 		),
 		district_schools AS (
@@ -231,7 +254,7 @@ func (r *repo) GetEducationPriorityData(params filter.BaseParams) ([]map[string]
 			FROM schools s
 			LEFT JOIN events e ON s.id = e.school_id AND e.deleted_at IS NULL
 			WHERE s.deleted_at IS NULL AND s.is_educated = TRUE
-			` + buildEventFilters(params) + `
+			`+buildEventFilters(params)+`
 			GROUP BY s.district_id
 		),
 		district_accidents AS (
@@ -243,7 +266,7 @@ func (r *repo) GetEducationPriorityData(params filter.BaseParams) ([]map[string]
 				COALESCE(SUM(minor_injured_count), 0) as total_minor_injured
 			FROM accidents
 			WHERE deleted_at IS NULL
-			` + buildAccidentFilters(params) + `
+			`+buildAccidentFilters(params)+`
 			GROUP BY district_id
 		)
 		SELECT
@@ -269,9 +292,9 @@ func (r *repo) GetEducationPriorityData(params filter.BaseParams) ([]map[string]
 		LEFT JOIN district_school_educated dse ON dm.district_id = dse.district_id
 		LEFT JOIN district_accidents da ON dm.district_id = da.district_id
 		WHERE 1=1
-		` + buildLocationFilters(params) + `
+		`+buildLocationFilters(params)+scopeFilter+`
 		ORDER BY dm.market_share ASC, da.total_accidents DESC, ds.total_students DESC
-	`)
+	`, scopeArgs...)
 
 	err := query.Scan(&results).Error
 	return results, err
@@ -365,20 +388,32 @@ func buildLocationFilters(params filter.BaseParams) string {
 	return filters
 }
 
-func (r *repo) GetSummary() (*dto.SchoolSummary, error) {
+func (r *repo) GetSummary(scope filter.RegionScope) (*dto.SchoolSummary, error) {
 	var result dto.SchoolSummary
 	err := r.DB.Model(&domainschool.School{}).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Select("COUNT(*) as total_schools, COALESCE(SUM(student_count), 0) as total_students, COALESCE(SUM(teacher_count), 0) as total_teachers").
 		Where("deleted_at IS NULL").
 		Scan(&result).Error
 	return &result, err
 }
 
-func (r *repo) GetForMap() ([]dto.SchoolMapItem, error) {
+func (r *repo) GetForMap(scope filter.RegionScope) ([]dto.SchoolMapItem, error) {
 	var results []dto.SchoolMapItem
 	err := r.DB.Model(&domainschool.School{}).
+		Scopes(scope.Apply("province_id", "city_id")).
 		Select("id, name, npsn, address, phone, latitude, longitude").
 		Where("deleted_at IS NULL AND latitude IS NOT NULL AND longitude IS NOT NULL").
 		Scan(&results).Error
 	return results, err
 }
+
+func (r *repo) FindMatches(text, address string, scope filter.RegionScope) ([]domainschool.School, error) {
+	var schools []domainschool.School
+	err := r.DB.Scopes(scope.Apply("province_id", "city_id")).
+		Where(venueMatchCondition, 5, text, address, address).
+		Order("LENGTH(name) DESC").
+		Limit(5).
+		Find(&schools).Error
+	return schools, err
+}
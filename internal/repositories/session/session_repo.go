@@ -3,6 +3,7 @@ package repositorysession
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	domainsession "safety-riding/internal/domain/session"
 	"safety-riding/pkg/logger"
@@ -25,6 +26,7 @@ const (
 	sessionKeyPrefix = "session:"
 	userSessionsKey  = "user_sessions:"
 	tokenSessionKey  = "token_session:"
+	refreshTokenKey  = "refresh_token:"
 )
 
 // Create creates a new session in Redis
@@ -58,6 +60,11 @@ func (r *SessionRepository) Create(ctx context.Context, session *domainsession.S
 	// Map token to session ID
 	pipe.Set(ctx, tokenKey, session.SessionID, ttl)
 
+	// Map refresh token hash to session ID
+	if session.RefreshTokenHash != "" {
+		pipe.Set(ctx, fmt.Sprintf("%s%s", refreshTokenKey, session.RefreshTokenHash), session.SessionID, ttl)
+	}
+
 	// Execute pipeline
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -131,28 +138,116 @@ func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*doma
 	return r.GetBySessionID(ctx, sessionID)
 }
 
-// UpdateActivity updates the last activity time of a session
-func (r *SessionRepository) UpdateActivity(ctx context.Context, sessionID string) error {
-	session, err := r.GetBySessionID(ctx, sessionID)
+// GetByRefreshToken retrieves a session by refresh token hash.
+// Rotated refresh tokens keep pointing to their session until it expires so that reuse can be detected.
+func (r *SessionRepository) GetByRefreshToken(ctx context.Context, refreshTokenHash string) (*domainsession.Session, error) {
+	refreshKey := fmt.Sprintf("%s%s", refreshTokenKey, refreshTokenHash)
+
+	sessionID, err := r.Redis.Get(ctx, refreshKey).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("session not found for refresh token")
+	}
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to get session by refresh token: %w", err)
 	}
 
-	session.LastActivity = time.Now()
+	return r.GetBySessionID(ctx, sessionID)
+}
 
+// RotateTokens swaps the access and refresh token of a session in a single transaction.
+// It returns domainsession.ErrRefreshTokenReused when oldRefreshTokenHash is no longer the current refresh token.
+func (r *SessionRepository) RotateTokens(ctx context.Context, sessionID, oldRefreshTokenHash, token, refreshTokenHash string) (*domainsession.Session, error) {
 	sessionKey := fmt.Sprintf("%s%s", sessionKeyPrefix, sessionID)
-	sessionData, err := json.Marshal(session)
-	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
-	}
 
-	// Update session with remaining TTL
-	ttl, err := r.Redis.TTL(ctx, sessionKey).Result()
+	var session domainsession.Session
+	err := r.Redis.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, sessionKey).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("session not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get session: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return fmt.Errorf("failed to unmarshal session: %w", err)
+		}
+		if session.RefreshTokenHash != oldRefreshTokenHash {
+			return domainsession.ErrRefreshTokenReused
+		}
+
+		ttl := time.Until(session.ExpiresAt)
+		if ttl <= 0 {
+			return fmt.Errorf("session already expired")
+		}
+
+		oldToken := session.Token
+		session.Token = token
+		session.RefreshTokenHash = refreshTokenHash
+		session.LastActivity = time.Now()
+
+		sessionData, err := json.Marshal(session)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, sessionKey, sessionData, ttl)
+			pipe.Del(ctx, fmt.Sprintf("%s%s", tokenSessionKey, oldToken))
+			pipe.Set(ctx, fmt.Sprintf("%s%s", tokenSessionKey, token), sessionID, ttl)
+			pipe.Set(ctx, fmt.Sprintf("%s%s", refreshTokenKey, refreshTokenHash), sessionID, ttl)
+			return nil
+		})
+		return err
+	}, sessionKey)
+	if errors.Is(err, domainsession.ErrRefreshTokenReused) {
+		return &session, err
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get session TTL: %w", err)
+		return nil, fmt.Errorf("failed to rotate session tokens: %w", err)
 	}
 
-	err = r.Redis.Set(ctx, sessionKey, sessionData, ttl).Err()
+	return &session, nil
+}
+
+// UpdateActivity updates the last activity time of a session
+func (r *SessionRepository) UpdateActivity(ctx context.Context, sessionID string) error {
+	sessionKey := fmt.Sprintf("%s%s", sessionKeyPrefix, sessionID)
+
+	// Watch the key so a concurrent token rotation is not overwritten with stale data
+	err := r.Redis.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, sessionKey).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("session not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get session: %w", err)
+		}
+
+		var session domainsession.Session
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return fmt.Errorf("failed to unmarshal session: %w", err)
+		}
+
+		session.LastActivity = time.Now()
+
+		sessionData, err := json.Marshal(session)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session: %w", err)
+		}
+
+		// Update session with remaining TTL
+		ttl, err := tx.TTL(ctx, sessionKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get session TTL: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, sessionKey, sessionData, ttl)
+			return nil
+		})
+		return err
+	}, sessionKey)
 	if err != nil {
 		return fmt.Errorf("failed to update session activity: %w", err)
 	}
@@ -176,6 +271,9 @@ func (r *SessionRepository) Delete(ctx context.Context, sessionID string) error
 	pipe.Del(ctx, sessionKey)
 	pipe.SRem(ctx, userSessionKey, sessionID)
 	pipe.Del(ctx, tokenKey)
+	if session.RefreshTokenHash != "" {
+		pipe.Del(ctx, fmt.Sprintf("%s%s", refreshTokenKey, session.RefreshTokenHash))
+	}
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
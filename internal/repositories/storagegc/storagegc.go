@@ -0,0 +1,63 @@
+package repositorystoragegc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"safety-riding/internal/dto"
+	interfacestoragegc "safety-riding/internal/interfaces/storagegc"
+
+	"gorm.io/gorm"
+)
+
+// identifier guards the table and column names, they are put in the query as is
+var identifier = regexp.MustCompile(`^[a-z_]+$`)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewStorageGCRepo(db *gorm.DB) interfacestoragegc.RepoStorageGCInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func (r *repo) FetchFileRefs(table string, columns []string, deletedSince time.Time) ([]dto.StoredFileRef, error) {
+	for _, name := range append([]string{table}, columns...) {
+		if !identifier.MatchString(name) {
+			return nil, fmt.Errorf("invalid identifier '%s'", name)
+		}
+	}
+
+	selects := []string{"id::text AS id", "deleted_at IS NULL AS live"}
+	for _, column := range columns {
+		selects = append(selects, fmt.Sprintf("COALESCE(%s, '') AS %s", column, column))
+	}
+
+	var rows []map[string]interface{}
+	err := r.DB.Table(table).
+		Select(strings.Join(selects, ", ")).
+		Where("deleted_at IS NULL OR deleted_at > ?", deletedSince).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]dto.StoredFileRef, 0, len(rows)*len(columns))
+	for _, row := range rows {
+		live, _ := row["live"].(bool)
+		seen := map[string]bool{}
+		for _, column := range columns {
+			url := fmt.Sprint(row[column])
+			if url == "" || seen[url] {
+				continue
+			}
+			seen[url] = true
+			refs = append(refs, dto.StoredFileRef{RowId: fmt.Sprint(row["id"]), Url: url, Live: live})
+		}
+	}
+	return refs, nil
+}
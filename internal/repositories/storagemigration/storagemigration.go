@@ -0,0 +1,86 @@
+package repositorystoragemigration
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"safety-riding/internal/dto"
+	interfacestoragemigration "safety-riding/internal/interfaces/storagemigration"
+
+	"gorm.io/gorm"
+)
+
+// identifier guards the table and column names, they are put in the queries as is
+var identifier = regexp.MustCompile(`^[a-z_]+$`)
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewStorageMigrationRepo(db *gorm.DB) interfacestoragemigration.RepoStorageMigrationInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func checkIdentifiers(table string, columns []string) error {
+	for _, name := range append([]string{table}, columns...) {
+		if !identifier.MatchString(name) {
+			return fmt.Errorf("invalid identifier '%s'", name)
+		}
+	}
+	return nil
+}
+
+func (r *repo) FetchFileURLs(table string, columns []string, afterId string, limit int) ([]dto.StoredFileURLs, error) {
+	if err := checkIdentifiers(table, columns); err != nil {
+		return nil, err
+	}
+
+	selects := []string{"id::text AS id"}
+	for _, column := range columns {
+		selects = append(selects, fmt.Sprintf("COALESCE(%s, '') AS %s", column, column))
+	}
+
+	var rows []map[string]interface{}
+	err := r.DB.Table(table).
+		Select(strings.Join(selects, ", ")).
+		Where("id::text > ?", afterId).
+		Order("id::text").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]dto.StoredFileURLs, 0, len(rows))
+	for _, row := range rows {
+		urls := make(map[string]string, len(columns))
+		for _, column := range columns {
+			urls[column] = fmt.Sprint(row[column])
+		}
+		ret = append(ret, dto.StoredFileURLs{Id: fmt.Sprint(row["id"]), Urls: urls})
+	}
+	return ret, nil
+}
+
+func (r *repo) UpdateFileURLs(table string, rows []dto.StoredFileURLs) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			columns := make([]string, 0, len(row.Urls))
+			updates := make(map[string]interface{}, len(row.Urls))
+			for column, url := range row.Urls {
+				columns = append(columns, column)
+				updates[column] = url
+			}
+			if err := checkIdentifiers(table, columns); err != nil {
+				return err
+			}
+			if err := tx.Table(table).Where("id::text = ?", row.Id).UpdateColumns(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package repositoryuser
+
+import (
+	domainuser "safety-riding/internal/domain/user"
+	interfaceuser "safety-riding/internal/interfaces/user"
+
+	"gorm.io/gorm"
+)
+
+type userRegionRepo struct {
+	DB *gorm.DB
+}
+
+func NewUserRegionRepo(db *gorm.DB) interfaceuser.RepoUserRegionInterface {
+	return &userRegionRepo{DB: db}
+}
+
+func (r *userRegionRepo) GetByUser(userId string) (ret []domainuser.UserRegion, err error) {
+	if err = r.DB.Where("user_id = ?", userId).Order("province_id, city_id").Find(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *userRegionRepo) ReplaceByUser(userId string, regions []domainuser.UserRegion) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&domainuser.UserRegion{}).Error; err != nil {
+			return err
+		}
+		if len(regions) == 0 {
+			return nil
+		}
+		return tx.Create(&regions).Error
+	})
+}
@@ -0,0 +1,65 @@
+package repositoryuser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainuser "safety-riding/internal/domain/user"
+	interfaceuser "safety-riding/internal/interfaces/user"
+	"safety-riding/pkg/cache"
+	"safety-riding/pkg/logger"
+)
+
+const userRegionCachePrefix = "region_cache:user:"
+
+// cachedUserRegionRepo keeps region assignments in the shared cache, the auth middleware reads them on every request
+type cachedUserRegionRepo struct {
+	repo  interfaceuser.RepoUserRegionInterface
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewCachedUserRegionRepo(repo interfaceuser.RepoUserRegionInterface, c cache.Cache, ttl time.Duration) interfaceuser.RepoUserRegionInterface {
+	return &cachedUserRegionRepo{repo: repo, cache: c, ttl: ttl}
+}
+
+func (r *cachedUserRegionRepo) GetByUser(userId string) ([]domainuser.UserRegion, error) {
+	ctx := context.Background()
+	key := userRegionCachePrefix + userId
+
+	if raw, found, err := r.cache.Get(ctx, key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[UserRegionCache]; Get %s; Error: %v", key, err))
+	} else if found {
+		var ret []domainuser.UserRegion
+		if err = json.Unmarshal(raw, &ret); err == nil {
+			return ret, nil
+		}
+	}
+
+	ret, err := r.repo.GetByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err := json.Marshal(ret); err == nil {
+		if err = r.cache.Set(ctx, key, raw, r.ttl); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[UserRegionCache]; Set %s; Error: %v", key, err))
+		}
+	}
+
+	return ret, nil
+}
+
+func (r *cachedUserRegionRepo) ReplaceByUser(userId string, regions []domainuser.UserRegion) error {
+	if err := r.repo.ReplaceByUser(userId, regions); err != nil {
+		return err
+	}
+
+	key := userRegionCachePrefix + userId
+	if err := r.cache.Delete(context.Background(), key); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[UserRegionCache]; Delete %s; Error: %v", key, err))
+	}
+	return nil
+}
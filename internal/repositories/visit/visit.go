@@ -0,0 +1,100 @@
+package repositoryvisit
+
+import (
+	"fmt"
+
+	"safety-riding/internal/dto"
+	interfacevisit "safety-riding/internal/interfaces/visit"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+// lastVisitExpr is the end of the latest event, event_date and end_time are stored as text
+// so values that are not a valid date or time are skipped instead of failing the whole update
+const lastVisitExpr = `MAX(CASE
+	WHEN e.event_date !~ '^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$' THEN NULL
+	WHEN e.end_time ~ '^([01]?[0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$' THEN (e.event_date || ' ' || e.end_time)::timestamp
+	ELSE e.event_date::timestamp
+END)`
+
+type repo struct {
+	DB *gorm.DB
+}
+
+func NewVisitRepo(db *gorm.DB) interfacevisit.RepoVisitInterface {
+	return &repo{
+		DB: db,
+	}
+}
+
+func (r *repo) Recompute(schoolIds, publicIds []string) error {
+	if len(schoolIds) > 0 {
+		if _, err := r.recompute("schools", "school_id", schoolIds); err != nil {
+			return err
+		}
+	}
+	if len(publicIds) > 0 {
+		if _, err := r.recompute("publics", "public_id", publicIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repo) RecomputeAll() (dto.VisitRecomputeResult, error) {
+	var result dto.VisitRecomputeResult
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		txRepo := &repo{DB: tx}
+		var err error
+		if result.Schools, err = txRepo.recompute("schools", "school_id", nil); err != nil {
+			return err
+		}
+		result.Publics, err = txRepo.recompute("publics", "public_id", nil)
+		return err
+	})
+	return result, err
+}
+
+// recompute updates the venues of table from the completed events referencing them through column, all of them when ids is nil
+func (r *repo) recompute(table, column string, ids []string) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s AS venue SET
+			visit_count = COALESCE(v.visits, 0),
+			is_educated = COALESCE(v.visits, 0) > 0,
+			last_visit_at = v.last_visit_at
+		FROM %[1]s AS t
+		LEFT JOIN (
+			SELECT e.%[2]s AS venue_id, COUNT(*) AS visits, %[3]s AS last_visit_at
+			FROM events e
+			WHERE e.deleted_at IS NULL AND e.status = ? AND e.%[2]s IS NOT NULL
+			GROUP BY e.%[2]s
+		) v ON v.venue_id = t.id
+		WHERE venue.id = t.id`, table, column, lastVisitExpr)
+	args := []interface{}{utils.StsCompleted}
+	if ids != nil {
+		query += " AND venue.id IN ?"
+		args = append(args, ids)
+	}
+
+	res := r.DB.Exec(query, args...)
+	return res.RowsAffected, res.Error
+}
+
+func (r *repo) FetchBySchool(schoolId string) ([]dto.Visit, error) {
+	return r.fetch("school_id", schoolId)
+}
+
+func (r *repo) FetchByPublic(publicId string) ([]dto.Visit, error) {
+	return r.fetch("public_id", publicId)
+}
+
+func (r *repo) fetch(column, venueId string) ([]dto.Visit, error) {
+	visits := make([]dto.Visit, 0)
+	err := r.DB.Table("events").
+		Select("id AS event_id, title, event_type, event_date, start_time, end_time, attendees_count").
+		Where("deleted_at IS NULL AND status = ?", utils.StsCompleted).
+		Where(fmt.Sprintf("%s = ?", column), venueId).
+		Order("event_date DESC, end_time DESC").
+		Scan(&visits).Error
+	return visits, err
+}
@@ -1,7 +1,10 @@
 package router
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,17 +13,27 @@ import (
 	"gorm.io/gorm"
 
 	"safety-riding/infrastructure/database"
+	"safety-riding/infrastructure/mail"
 	"safety-riding/infrastructure/media"
+	domainattachment "safety-riding/internal/domain/attachment"
+	"safety-riding/internal/dto"
 	accidentHandler "safety-riding/internal/handlers/http/accident"
 	appConfigHandler "safety-riding/internal/handlers/http/appconfig"
 	approvalRecordHandler "safety-riding/internal/handlers/http/approvalrecord"
+	assessmentHandler "safety-riding/internal/handlers/http/assessment"
+	attachmentHandler "safety-riding/internal/handlers/http/attachment"
+	auditLogHandler "safety-riding/internal/handlers/http/auditlog"
 	budgetHandler "safety-riding/internal/handlers/http/budget"
+	certificateHandler "safety-riding/internal/handlers/http/certificate"
 	cityHandler "safety-riding/internal/handlers/http/city"
 	dashboardHandler "safety-riding/internal/handlers/http/dashboard"
 	districtHandler "safety-riding/internal/handlers/http/district"
 	eventHandler "safety-riding/internal/handlers/http/event"
+	fileHandler "safety-riding/internal/handlers/http/file"
+	instructorHandler "safety-riding/internal/handlers/http/instructor"
 	marketshareHandler "safety-riding/internal/handlers/http/marketshare"
 	menuHandler "safety-riding/internal/handlers/http/menu"
+	participantHandler "safety-riding/internal/handlers/http/participant"
 	permissionHandler "safety-riding/internal/handlers/http/permission"
 	poldaHandler "safety-riding/internal/handlers/http/polda"
 	provinceHandler "safety-riding/internal/handlers/http/province"
@@ -29,33 +42,51 @@ import (
 	schoolHandler "safety-riding/internal/handlers/http/school"
 	sessionHandler "safety-riding/internal/handlers/http/session"
 	userHandler "safety-riding/internal/handlers/http/user"
+	interfaceauth "safety-riding/internal/interfaces/auth"
+	interfacepermission "safety-riding/internal/interfaces/permission"
+	interfacesession "safety-riding/internal/interfaces/session"
+	interfaceuser "safety-riding/internal/interfaces/user"
 	accidentRepo "safety-riding/internal/repositories/accident"
 	appConfigRepo "safety-riding/internal/repositories/appconfig"
 	approvalRecordRepo "safety-riding/internal/repositories/approvalrecord"
+	assessmentRepo "safety-riding/internal/repositories/assessment"
+	attachmentRepo "safety-riding/internal/repositories/attachment"
+	auditLogRepo "safety-riding/internal/repositories/auditlog"
 	authRepo "safety-riding/internal/repositories/auth"
 	budgetRepo "safety-riding/internal/repositories/budget"
+	certificateRepo "safety-riding/internal/repositories/certificate"
 	repodashboard "safety-riding/internal/repositories/dashboard"
 	eventRepo "safety-riding/internal/repositories/event"
+	instructorRepo "safety-riding/internal/repositories/instructor"
 	marketshareRepo "safety-riding/internal/repositories/marketshare"
 	menuRepo "safety-riding/internal/repositories/menu"
+	participantRepo "safety-riding/internal/repositories/participant"
 	permissionRepo "safety-riding/internal/repositories/permission"
 	poldaRepo "safety-riding/internal/repositories/polda"
 	publicsRepo "safety-riding/internal/repositories/publics"
 	roleRepo "safety-riding/internal/repositories/role"
 	schoolRepo "safety-riding/internal/repositories/school"
 	sessionRepo "safety-riding/internal/repositories/session"
+	storageGCRepo "safety-riding/internal/repositories/storagegc"
 	submittedFormRepo "safety-riding/internal/repositories/submittedform"
 	userRepo "safety-riding/internal/repositories/user"
+	visitRepo "safety-riding/internal/repositories/visit"
 	accidentSvc "safety-riding/internal/services/accident"
 	appConfigSvc "safety-riding/internal/services/appconfig"
 	approvalRecordSvc "safety-riding/internal/services/approvalrecord"
+	assessmentSvc "safety-riding/internal/services/assessment"
+	attachmentSvc "safety-riding/internal/services/attachment"
+	auditLogSvc "safety-riding/internal/services/auditlog"
 	budgetSvc "safety-riding/internal/services/budget"
+	certificateSvc "safety-riding/internal/services/certificate"
 	kabupatenSvc "safety-riding/internal/services/city"
 	dashboardSvc "safety-riding/internal/services/dashboard"
 	kecamatanSvc "safety-riding/internal/services/district"
 	eventSvc "safety-riding/internal/services/event"
+	instructorSvc "safety-riding/internal/services/instructor"
 	marketshareSvc "safety-riding/internal/services/marketshare"
 	menuSvc "safety-riding/internal/services/menu"
+	participantSvc "safety-riding/internal/services/participant"
 	permissionSvc "safety-riding/internal/services/permission"
 	poldaSvc "safety-riding/internal/services/polda"
 	provinsiSvc "safety-riding/internal/services/province"
@@ -63,16 +94,22 @@ import (
 	roleSvc "safety-riding/internal/services/role"
 	schoolSvc "safety-riding/internal/services/school"
 	sessionSvc "safety-riding/internal/services/session"
+	storageGCSvc "safety-riding/internal/services/storagegc"
 	userSvc "safety-riding/internal/services/user"
 	"safety-riding/middlewares"
+	"safety-riding/pkg/cache"
 	"safety-riding/pkg/logger"
+	"safety-riding/pkg/mailer"
 	"safety-riding/pkg/security"
+	"safety-riding/pkg/storage"
 	"safety-riding/utils"
 )
 
 type Routes struct {
-	App *gin.Engine
-	DB  *gorm.DB
+	App     *gin.Engine
+	DB      *gorm.DB
+	Cache   cache.Cache
+	Storage storage.StorageProvider
 }
 
 func NewRoutes() *Routes {
@@ -92,19 +129,74 @@ func NewRoutes() *Routes {
 	app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return &Routes{
-		App: app,
+		App:   app,
+		Cache: cache.New(database.GetRedisClient()),
 	}
 }
 
+// storageProvider returns the file storage shared by the routes. When it cannot be initialized the app still starts,
+// only file uploads and downloads fail with storage.ErrUnavailable until the configuration is fixed
+func (r *Routes) storageProvider() storage.StorageProvider {
+	if r.Storage == nil {
+		provider, err := media.InitStorage()
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, "Failed to initialize storage provider, file uploads are disabled: "+err.Error())
+			provider = storage.NewUnavailableProvider(err)
+		}
+		r.Storage = provider
+	}
+	return r.Storage
+}
+
+// blacklistRepo returns the token blacklist behind the shared cache, it is checked on every authenticated request
+func (r *Routes) blacklistRepo() interfaceauth.RepoAuthInterface {
+	ttl := time.Duration(utils.GetEnv("BLACKLIST_CACHE_TTL_SECONDS", 300).(int)) * time.Second
+	return authRepo.NewCachedBlacklistRepo(authRepo.NewBlacklistRepo(r.DB), r.Cache, ttl)
+}
+
+// userRegionRepo returns the region assignments behind the shared cache, the auth middleware reads them on every request
+func (r *Routes) userRegionRepo() interfaceuser.RepoUserRegionInterface {
+	ttl := time.Duration(utils.GetEnv("PERMISSION_CACHE_TTL_SECONDS", 300).(int)) * time.Second
+	return userRepo.NewCachedUserRegionRepo(userRepo.NewUserRegionRepo(r.DB), r.Cache, ttl)
+}
+
+// permissionRepo returns the permission repository with user permissions kept in the shared cache
+func (r *Routes) permissionRepo() interfacepermission.RepoPermissionInterface {
+	ttl := time.Duration(utils.GetEnv("PERMISSION_CACHE_TTL_SECONDS", 300).(int)) * time.Second
+	return permissionRepo.NewCachedPermissionRepo(permissionRepo.NewPermissionRepo(r.DB), r.Cache, ttl)
+}
+
+// attachmentHandler returns the handler of the documents attached to events, accidents and budgets,
+// its routes are registered below each parent resource with the permission of that resource
+func (r *Routes) attachmentHandler() *attachmentHandler.AttachmentHandler {
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := attachmentSvc.NewAttachmentService(attachmentRepo.NewAttachmentRepo(r.DB), eventRepo.NewEventRepo(r.DB), accidentRepo.NewAccidentRepo(r.DB), budgetRepo.NewBudgetRepo(r.DB), r.storageProvider(), auditRecorder)
+	return attachmentHandler.NewAttachmentHandler(svc)
+}
+
 func (r *Routes) UserRoutes() {
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
+	blacklistRepo := r.blacklistRepo()
 	repo := userRepo.NewUserRepo(r.DB)
 	rRepo := roleRepo.NewRoleRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
+	pRepo := r.permissionRepo()
 	configRepo := appConfigRepo.NewAppConfigRepo(r.DB)
 	configSvc := appConfigSvc.NewAppConfigService(configRepo)
-	uc := userSvc.NewUserService(repo, blacklistRepo, rRepo, pRepo)
+	tokenRepo := authRepo.NewUserTokenRepo(r.DB)
 	redisClient := database.GetRedisClient()
+
+	mailSender, err := mail.InitMailer()
+	if err != nil {
+		logger.WriteLog(logger.LogLevelError, "Failed to initialize mailer, emails are disabled: "+err.Error())
+		mailSender = mailer.NewUnavailableMailer(err)
+	}
+
+	// Sessions live in Redis, without it a password reset cannot revoke them
+	var sessionService interfacesession.ServiceSessionInterface
+	if redisClient != nil {
+		sessionService = sessionSvc.NewSessionService(sessionRepo.NewSessionRepository(redisClient))
+	}
+
+	uc := userSvc.NewUserService(repo, blacklistRepo, rRepo, pRepo, tokenRepo, r.userRegionRepo(), mailSender, sessionService)
 	loginLimiter := security.NewRedisLoginLimiter(
 		redisClient,
 		utils.GetEnv("LOGIN_ATTEMPT_LIMIT", 5).(int),
@@ -112,7 +204,7 @@ func (r *Routes) UserRoutes() {
 		time.Duration(utils.GetEnv("LOGIN_BLOCK_DURATION_SECONDS", 300).(int))*time.Second,
 	)
 	h := userHandler.NewUserHandler(uc, configSvc, loginLimiter)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	registerLimit := utils.GetEnv("REGISTER_RATE_LIMIT", 5).(int)
 	registerWindowSeconds := utils.GetEnv("REGISTER_RATE_WINDOW_SECONDS", 60).(int)
@@ -126,22 +218,40 @@ func (r *Routes) UserRoutes() {
 		time.Duration(registerWindowSeconds)*time.Second,
 	)
 
+	resendLimiter := middlewares.IPRateLimitMiddleware(
+		redisClient,
+		"user_verify_email_resend",
+		utils.GetEnv("VERIFY_EMAIL_RESEND_RATE_LIMIT", 3).(int),
+		time.Duration(utils.GetEnv("VERIFY_EMAIL_RESEND_RATE_WINDOW_SECONDS", 300).(int))*time.Second,
+	)
+
 	user := r.App.Group("/api/user")
 	{
 		user.GET("/register/status", h.GetRegisterStatus)
 		user.POST("/register", registerLimiter, h.Register)
 		user.POST("/login", h.Login)
+		user.POST("/login/2fa", h.LoginTwoFactor)
+		user.POST("/login/2fa/setup", h.LoginTwoFactorSetup)
+		user.POST("/token/refresh", h.RefreshToken)
 		user.POST("/forgot-password", h.ForgotPassword)
 		user.POST("/reset-password", h.ResetPassword)
+		user.POST("/verify-email", h.VerifyEmail)
+		user.POST("/verify-email/resend", resendLimiter, h.ResendVerificationEmail)
 
 		userPriv := user.Group("").Use(mdw.AuthMiddleware())
 		{
 			userPriv.POST("/logout", h.Logout)
 			userPriv.GET("", h.GetUserByAuth)
 			userPriv.GET("/:id", mdw.PermissionMiddleware("users", "view"), h.GetUserById)
+			userPriv.GET("/:id/regions", mdw.PermissionMiddleware("users", "view"), h.GetUserRegions)
+			userPriv.PUT("/:id/regions", mdw.PermissionMiddleware("users", "assign_regions"), h.AssignUserRegions)
 			userPriv.PUT("", h.Update)
 			userPriv.PUT("/:id", mdw.PermissionMiddleware("users", "update"), h.UpdateUserById)
 			userPriv.PUT("/change/password", h.ChangePassword)
+			userPriv.POST("/2fa/setup", h.SetupTwoFactor)
+			userPriv.POST("/2fa/enable", h.EnableTwoFactor)
+			userPriv.POST("/2fa/disable", h.DisableTwoFactor)
+			userPriv.POST("/2fa/recovery-codes", h.RegenerateRecoveryCodes)
 			userPriv.DELETE("", h.Delete)
 			userPriv.DELETE("/:id", mdw.PermissionMiddleware("users", "delete"), h.DeleteUserById)
 
@@ -155,15 +265,18 @@ func (r *Routes) UserRoutes() {
 
 func (r *Routes) SchoolRoutes() {
 	repo := schoolRepo.NewSchoolRepo(r.DB)
-	svc := schoolSvc.NewSchoolService(repo)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := schoolSvc.NewSchoolService(repo, auditRecorder)
 	h := schoolHandler.NewSchoolHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	r.App.GET("/api/schools", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), h.FetchSchool)
 	r.App.GET("/api/schools/summary", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), h.GetSummary)
 	r.App.GET("/api/schools/map", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), h.GetForMap)
+	r.App.GET("/api/schools/export", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), h.ExportSchool)
+	r.App.POST("/api/schools/import", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "create"), mdw.PermissionMiddleware("schools", "update"), h.ImportSchool)
 
 	// Education endpoints (cross-domain analytics)
 	r.App.GET("/api/education/stats", mdw.AuthMiddleware(), mdw.PermissionMiddleware("education_stats", "view"), h.GetEducationStats)
@@ -180,11 +293,12 @@ func (r *Routes) SchoolRoutes() {
 
 func (r *Routes) PublicRoutes() {
 	repo := publicsRepo.NewPublicRepo(r.DB)
-	svc := publicsSvc.NewPublicService(repo)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := publicsSvc.NewPublicService(repo, auditRecorder)
 	h := publicsHandler.NewPublicHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	r.App.GET("/api/publics", mdw.AuthMiddleware(), mdw.PermissionMiddleware("publics", "view"), h.FetchPublic)
 	r.App.GET("/api/publics/summary", mdw.AuthMiddleware(), mdw.PermissionMiddleware("publics", "view"), h.GetSummary)
@@ -231,21 +345,19 @@ func (r *Routes) DistrictRoutes() {
 }
 
 func (r *Routes) AccidentRoutes() {
-	// Initialize storage provider (MinIO or R2) from infrastructure
-	storageProvider, err := media.InitStorage()
-	if err != nil {
-		logger.WriteLog(logger.LogLevelError, "Failed to initialize storage provider: "+err.Error())
-		panic("Failed to initialize storage provider: " + err.Error())
-	}
+	storageProvider := r.storageProvider()
 
 	repo := accidentRepo.NewAccidentRepo(r.DB)
-	svc := accidentSvc.NewAccidentService(repo, storageProvider)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := accidentSvc.NewAccidentService(repo, storageProvider, auditRecorder)
 	h := accidentHandler.NewAccidentHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	hAttachment := r.attachmentHandler()
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	r.App.GET("/api/accidents", mdw.AuthMiddleware(), mdw.PermissionMiddleware("accidents", "view"), h.FetchAccident)
+	r.App.GET("/api/accidents/export", mdw.AuthMiddleware(), mdw.PermissionMiddleware("accidents", "view"), h.ExportAccident)
 	accident := r.App.Group("/api/accident").Use(mdw.AuthMiddleware())
 	{
 		accident.POST("", mdw.PermissionMiddleware("accidents", "create"), h.AddAccident)
@@ -255,49 +367,202 @@ func (r *Routes) AccidentRoutes() {
 
 		// Photo endpoints
 		accident.POST("/:id/photos", mdw.PermissionMiddleware("accidents", "update"), h.AddAccidentPhotos)
+		accident.POST("/:id/photos/uploads", mdw.PermissionMiddleware("accidents", "update"), h.RequestAccidentPhotoUploads)
+		accident.POST("/:id/photos/uploads/confirm", mdw.PermissionMiddleware("accidents", "update"), h.ConfirmAccidentPhotoUploads)
+		accident.PUT("/:id/photos/order", mdw.PermissionMiddleware("accidents", "update"), h.ReorderAccidentPhotos)
+		accident.PUT("/photo/:photoId", mdw.PermissionMiddleware("accidents", "update"), h.UpdateAccidentPhoto)
 		accident.DELETE("/photo/:photoId", mdw.PermissionMiddleware("accidents", "delete"), h.DeleteAccidentPhoto)
+
+		// Attachment endpoints
+		accident.GET("/:id/attachments", mdw.PermissionMiddleware("accidents", "view"), hAttachment.FetchAttachments(domainattachment.EntityAccident))
+		accident.POST("/:id/attachments", mdw.PermissionMiddleware("accidents", "update"), hAttachment.AddAttachments(domainattachment.EntityAccident))
+		accident.DELETE("/:id/attachments/:attachmentId", mdw.PermissionMiddleware("accidents", "update"), hAttachment.DeleteAttachment(domainattachment.EntityAccident))
 	}
 }
 
 func (r *Routes) EventRoutes() {
-	// Initialize storage provider (MinIO or R2) from infrastructure
-	storageProvider, err := media.InitStorage()
-	if err != nil {
-		logger.WriteLog(logger.LogLevelError, "Failed to initialize storage provider: "+err.Error())
-		panic("Failed to initialize storage provider: " + err.Error())
-	}
+	storageProvider := r.storageProvider()
 
 	repo := eventRepo.NewEventRepo(r.DB)
 	repoSchool := schoolRepo.NewSchoolRepo(r.DB)
 	repoPublic := publicsRepo.NewPublicRepo(r.DB)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	svc := eventSvc.NewEventService(repo, repoSchool, repoPublic, storageProvider)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	tokenRepo := authRepo.NewUserTokenRepo(r.DB)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := eventSvc.NewEventService(repo, repoSchool, repoPublic, instructorRepo.NewInstructorRepo(r.DB), visitRepo.NewVisitRepo(r.DB), submittedFormRepo.NewSubmittedFormRepo(r.DB), tokenRepo, storageProvider, auditRecorder)
 	h := eventHandler.NewEventHandler(svc, pRepo)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	hAttachment := r.attachmentHandler()
+	hParticipant := participantHandler.NewParticipantHandler(participantSvc.NewParticipantService(participantRepo.NewParticipantRepo(r.DB), repo, auditRecorder))
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
+
+	// Check-in is public, a whole class may share one school network so the limit per IP is generous
+	checkInLimiter := middlewares.IPRateLimitMiddleware(
+		database.GetRedisClient(),
+		"event_checkin",
+		utils.GetEnv("CHECKIN_RATE_LIMIT", 120).(int),
+		time.Duration(utils.GetEnv("CHECKIN_RATE_WINDOW_SECONDS", 60).(int))*time.Second,
+	)
+	r.App.GET("/api/checkin/:token", checkInLimiter, hParticipant.GetCheckInEvent)
+	r.App.POST("/api/checkin/:token", checkInLimiter, hParticipant.CheckIn)
 
 	r.App.GET("/api/events", mdw.AuthMiddleware(), mdw.PermissionMiddleware("events", "view"), h.FetchEvent)
 	r.App.GET("/api/events/map", mdw.AuthMiddleware(), mdw.PermissionMiddleware("events", "view"), h.GetEventsForMap)
+	r.App.GET("/api/events/export", mdw.AuthMiddleware(), mdw.PermissionMiddleware("events", "view"), h.ExportEvent)
+	r.App.GET("/api/events/calendar", mdw.AuthMiddleware(), mdw.PermissionMiddleware("events", "view"), h.GetCalendar)
+	r.App.POST("/api/events/calendar/feed", mdw.AuthMiddleware(), mdw.PermissionMiddleware("events", "view"), h.CreateCalendarFeed)
+	r.App.DELETE("/api/events/calendar/feed", mdw.AuthMiddleware(), h.RevokeCalendarFeed)
+	r.App.POST("/api/education/visits/recompute", mdw.AuthMiddleware(), mdw.PermissionMiddleware("education_stats", "recompute"), h.RecomputeVisits)
+	// Visits are the completed events of a venue, the venue permission is enough to see them
+	r.App.GET("/api/school/:id/visits", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), h.FetchSchoolVisits)
+	r.App.GET("/api/public/:id/visits", mdw.AuthMiddleware(), mdw.PermissionMiddleware("publics", "view"), h.FetchPublicVisits)
+	// Approved training requests become events, which needs both the request and the event permission
+	r.App.GET("/api/approval-records/:id/event-draft", mdw.AuthMiddleware(), mdw.PermissionMiddleware("approval_records", "view"), mdw.PermissionMiddleware("events", "create"), h.DraftFromRequest)
+	r.App.POST("/api/approval-records/:id/event", mdw.AuthMiddleware(), mdw.PermissionMiddleware("approval_records", "view"), mdw.PermissionMiddleware("events", "create"), h.CreateFromRequest)
+	// Calendar apps cannot send a JWT, the feed token in the URL identifies the user instead
+	r.App.GET("/api/events/calendar/feed/:token", mdw.CalendarFeedMiddleware(tokenRepo, userRepo.NewUserRepo(r.DB)), mdw.PermissionMiddleware("events", "view"), h.GetCalendarFeed)
 	event := r.App.Group("/api/event").Use(mdw.AuthMiddleware())
 	{
 		event.POST("", mdw.PermissionMiddleware("events", "create"), h.AddEvent)
 		event.GET("/:id", mdw.PermissionMiddleware("events", "view"), h.GetEventById)
 		event.PUT("/:id", mdw.PermissionMiddleware("events", "update"), h.UpdateEvent)
 		event.DELETE("/:id", mdw.PermissionMiddleware("events", "delete"), h.DeleteEvent)
+		event.POST("/:id/status", mdw.PermissionMiddleware("events", "update"), h.TransitionStatus)
+		event.GET("/:id/status-history", mdw.PermissionMiddleware("events", "view"), h.GetStatusHistory)
 
 		// Photo endpoints
 		event.POST("/:id/photos", mdw.PermissionMiddleware("events", "update"), h.AddEventPhotos)
+		event.POST("/:id/photos/uploads", mdw.PermissionMiddleware("events", "update"), h.RequestEventPhotoUploads)
+		event.POST("/:id/photos/uploads/confirm", mdw.PermissionMiddleware("events", "update"), h.ConfirmEventPhotoUploads)
+		event.PUT("/:id/photos/order", mdw.PermissionMiddleware("events", "update"), h.ReorderEventPhotos)
+		event.PUT("/photo/:photoId", mdw.PermissionMiddleware("events", "update"), h.UpdateEventPhoto)
 		event.DELETE("/photo/:photoId", mdw.PermissionMiddleware("events", "delete"), h.DeleteEventPhoto)
+
+		// Participant roster endpoints
+		event.GET("/:id/participants", mdw.PermissionMiddleware("events", "view"), hParticipant.FetchParticipants)
+		event.POST("/:id/participants", mdw.PermissionMiddleware("events", "update"), hParticipant.AddParticipants)
+		event.POST("/:id/participants/import", mdw.PermissionMiddleware("events", "update"), hParticipant.ImportParticipants)
+		event.DELETE("/:id/participants/:participantId", mdw.PermissionMiddleware("events", "update"), hParticipant.DeleteParticipant)
+		event.GET("/:id/checkin-code", mdw.PermissionMiddleware("events", "update"), hParticipant.GetCheckInCode)
+		event.GET("/:id/attendance", mdw.PermissionMiddleware("events", "view"), hParticipant.GetAttendance)
+
+		// Attachment endpoints
+		event.GET("/:id/attachments", mdw.PermissionMiddleware("events", "view"), hAttachment.FetchAttachments(domainattachment.EntityEvent))
+		event.POST("/:id/attachments", mdw.PermissionMiddleware("events", "update"), hAttachment.AddAttachments(domainattachment.EntityEvent))
+		event.DELETE("/:id/attachments/:attachmentId", mdw.PermissionMiddleware("events", "update"), hAttachment.DeleteAttachment(domainattachment.EntityEvent))
+	}
+}
+
+func (r *Routes) AssessmentRoutes() {
+	repoEvent := eventRepo.NewEventRepo(r.DB)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := assessmentSvc.NewAssessmentService(
+		assessmentRepo.NewQuestionBankRepo(r.DB),
+		assessmentRepo.NewAssessmentRepo(r.DB),
+		repoEvent,
+		participantRepo.NewParticipantRepo(r.DB),
+		auditRecorder,
+	)
+	h := assessmentHandler.NewAssessmentHandler(svc)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
+
+	r.App.GET("/api/question-banks", mdw.AuthMiddleware(), mdw.PermissionMiddleware("question_banks", "view"), h.FetchQuestionBank)
+	bank := r.App.Group("/api/question-bank").Use(mdw.AuthMiddleware())
+	{
+		bank.POST("", mdw.PermissionMiddleware("question_banks", "create"), h.AddQuestionBank)
+		bank.GET("/:id", mdw.PermissionMiddleware("question_banks", "view"), h.GetQuestionBankById)
+		bank.PUT("/:id", mdw.PermissionMiddleware("question_banks", "update"), h.UpdateQuestionBank)
+		bank.DELETE("/:id", mdw.PermissionMiddleware("question_banks", "delete"), h.DeleteQuestionBank)
+	}
+
+	// Served next to /api/education/stats, knowledge improvement is part of the education analytics
+	r.App.GET("/api/education/assessment-stats", mdw.AuthMiddleware(), mdw.PermissionMiddleware("education_stats", "view"), h.GetAssessmentStats)
+
+	event := r.App.Group("/api/event").Use(mdw.AuthMiddleware())
+	{
+		event.GET("/:id/assessments", mdw.PermissionMiddleware("events", "view"), h.GetEventResults)
+		event.POST("/:id/assessments", mdw.PermissionMiddleware("events", "update"), h.AddEventAssessment)
+		event.GET("/:id/assessments/:type", mdw.PermissionMiddleware("events", "view"), h.GetEventAssessment)
+		event.DELETE("/:id/assessments/:type", mdw.PermissionMiddleware("events", "update"), h.DeleteEventAssessment)
+		event.GET("/:id/assessments/:type/submissions", mdw.PermissionMiddleware("events", "view"), h.FetchSubmissions)
+		event.POST("/:id/assessments/:type/submissions", mdw.PermissionMiddleware("events", "update"), h.SubmitAssessment)
+	}
+}
+
+func (r *Routes) CertificateRoutes() {
+	storageProvider := r.storageProvider()
+
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := certificateSvc.NewCertificateService(
+		certificateRepo.NewTemplateRepo(r.DB),
+		certificateRepo.NewCertificateRepo(r.DB),
+		eventRepo.NewEventRepo(r.DB),
+		participantRepo.NewParticipantRepo(r.DB),
+		storageProvider,
+		auditRecorder,
+	)
+	h := certificateHandler.NewCertificateHandler(svc)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
+
+	// Anyone holding a certificate can verify it, the limit keeps codes from being guessed
+	verifyLimiter := middlewares.IPRateLimitMiddleware(
+		database.GetRedisClient(),
+		"certificate_verify",
+		utils.GetEnv("CERTIFICATE_VERIFY_RATE_LIMIT", 30).(int),
+		time.Duration(utils.GetEnv("CERTIFICATE_VERIFY_RATE_WINDOW_SECONDS", 60).(int))*time.Second,
+	)
+	r.App.GET("/api/certificates/verify/:code", verifyLimiter, h.VerifyCertificate)
+
+	r.App.GET("/api/certificate-templates", mdw.AuthMiddleware(), mdw.PermissionMiddleware("certificate_templates", "view"), h.FetchTemplate)
+	template := r.App.Group("/api/certificate-template").Use(mdw.AuthMiddleware())
+	{
+		template.POST("", mdw.PermissionMiddleware("certificate_templates", "create"), h.AddTemplate)
+		template.GET("/:id", mdw.PermissionMiddleware("certificate_templates", "view"), h.GetTemplateById)
+		template.PUT("/:id", mdw.PermissionMiddleware("certificate_templates", "update"), h.UpdateTemplate)
+		template.POST("/:id/background", mdw.PermissionMiddleware("certificate_templates", "update"), h.UploadTemplateBackground)
+		template.DELETE("/:id", mdw.PermissionMiddleware("certificate_templates", "delete"), h.DeleteTemplate)
+	}
+
+	event := r.App.Group("/api/event").Use(mdw.AuthMiddleware())
+	{
+		event.GET("/:id/certificates", mdw.PermissionMiddleware("events", "view"), h.FetchCertificates)
+		event.GET("/:id/certificates/merged", mdw.PermissionMiddleware("events", "view"), h.GetMergedCertificates)
+		event.POST("/:id/certificates", mdw.PermissionMiddleware("events", "update"), h.GenerateCertificates)
+	}
+}
+
+func (r *Routes) InstructorRoutes() {
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := instructorSvc.NewInstructorService(instructorRepo.NewInstructorRepo(r.DB), auditRecorder)
+	h := instructorHandler.NewInstructorHandler(svc)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
+
+	r.App.GET("/api/instructors", mdw.AuthMiddleware(), mdw.PermissionMiddleware("instructors", "view"), h.FetchInstructor)
+	instructor := r.App.Group("/api/instructor").Use(mdw.AuthMiddleware())
+	{
+		instructor.POST("", mdw.PermissionMiddleware("instructors", "create"), h.AddInstructor)
+		instructor.GET("/:id", mdw.PermissionMiddleware("instructors", "view"), h.GetInstructorById)
+		instructor.PUT("/:id", mdw.PermissionMiddleware("instructors", "update"), h.UpdateInstructor)
+		instructor.DELETE("/:id", mdw.PermissionMiddleware("instructors", "delete"), h.DeleteInstructor)
+		instructor.GET("/:id/schedule", mdw.PermissionMiddleware("instructors", "view"), h.GetSchedule)
 	}
 }
 
 func (r *Routes) BudgetRoutes() {
 	repo := budgetRepo.NewBudgetRepo(r.DB)
-	svc := budgetSvc.NewBudgetService(repo)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := budgetSvc.NewBudgetService(repo, eventRepo.NewEventRepo(r.DB), auditRecorder)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
 	h := budgetHandler.NewBudgetHandler(svc, pRepo)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	hAttachment := r.attachmentHandler()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	// Summary endpoints (read-only for users with budget view permission)
 	r.App.GET("/api/budget/summary/event/:eventId", mdw.AuthMiddleware(), mdw.PermissionMiddleware("budgets", "view"), h.GetEventSummary)
@@ -306,6 +571,7 @@ func (r *Routes) BudgetRoutes() {
 
 	// List endpoints
 	r.App.GET("/api/budgets", mdw.AuthMiddleware(), mdw.PermissionMiddleware("budgets", "view"), h.FetchBudget)
+	r.App.GET("/api/budgets/export", mdw.AuthMiddleware(), mdw.PermissionMiddleware("budgets", "view"), h.ExportBudget)
 	r.App.GET("/api/budgets/event/:eventId", mdw.AuthMiddleware(), mdw.PermissionMiddleware("budgets", "view"), h.GetBudgetsByEvent)
 	r.App.GET("/api/budgets/month-year", mdw.AuthMiddleware(), mdw.PermissionMiddleware("budgets", "view"), h.GetBudgetsByMonthYear)
 
@@ -316,16 +582,22 @@ func (r *Routes) BudgetRoutes() {
 		budget.GET("/:id", mdw.PermissionMiddleware("budgets", "view"), h.GetBudgetById)
 		budget.PUT("/:id", mdw.PermissionMiddleware("budgets", "update"), h.UpdateBudget)
 		budget.DELETE("/:id", mdw.PermissionMiddleware("budgets", "delete"), h.DeleteBudget)
+
+		// Attachment endpoints
+		budget.GET("/:id/attachments", mdw.PermissionMiddleware("budgets", "view"), hAttachment.FetchAttachments(domainattachment.EntityBudget))
+		budget.POST("/:id/attachments", mdw.PermissionMiddleware("budgets", "update"), hAttachment.AddAttachments(domainattachment.EntityBudget))
+		budget.DELETE("/:id/attachments/:attachmentId", mdw.PermissionMiddleware("budgets", "update"), hAttachment.DeleteAttachment(domainattachment.EntityBudget))
 	}
 }
 
 func (r *Routes) MarketShareRoutes() {
 	repo := marketshareRepo.NewMarketShareRepository(r.DB)
-	svc := marketshareSvc.NewMarketShareService(repo)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := marketshareSvc.NewMarketShareService(repo, auditRecorder)
 	h := marketshareHandler.NewMarketShareHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	// Dashboard endpoint (read-only for users with market share view permission)
 	r.App.GET("/api/marketshare/top-districts", mdw.AuthMiddleware(), mdw.PermissionMiddleware("market_shares", "view"), h.GetTopDistricts)
@@ -334,6 +606,7 @@ func (r *Routes) MarketShareRoutes() {
 
 	// List endpoints
 	r.App.GET("/api/marketshares", mdw.AuthMiddleware(), mdw.PermissionMiddleware("market_shares", "view"), h.FetchMarketShare)
+	r.App.GET("/api/marketshares/export", mdw.AuthMiddleware(), mdw.PermissionMiddleware("market_shares", "view"), h.ExportMarketShare)
 
 	// CRUD endpoints (admin/staff only)
 	marketshare := r.App.Group("/api/marketshare").Use(mdw.AuthMiddleware())
@@ -347,12 +620,13 @@ func (r *Routes) MarketShareRoutes() {
 
 func (r *Routes) RoleRoutes() {
 	repoRole := roleRepo.NewRoleRepo(r.DB)
-	repoPermission := permissionRepo.NewPermissionRepo(r.DB)
+	repoPermission := r.permissionRepo()
 	repoMenu := menuRepo.NewMenuRepo(r.DB)
-	svc := roleSvc.NewRoleService(repoRole, repoPermission, repoMenu)
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := roleSvc.NewRoleService(repoRole, repoPermission, repoMenu, auditRecorder)
 	h := roleHandler.NewRoleHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, repoPermission)
+	blacklistRepo := r.blacklistRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, repoPermission, r.userRegionRepo())
 
 	// List endpoints
 	r.App.GET("/api/roles", mdw.AuthMiddleware(), mdw.PermissionMiddleware("roles", "view"), h.GetAll)
@@ -371,11 +645,12 @@ func (r *Routes) RoleRoutes() {
 }
 
 func (r *Routes) PermissionRoutes() {
-	repo := permissionRepo.NewPermissionRepo(r.DB)
-	svc := permissionSvc.NewPermissionService(repo)
+	repo := r.permissionRepo()
+	auditRecorder := auditLogSvc.NewAuditLogService(auditLogRepo.NewAuditLogRepo(r.DB))
+	svc := permissionSvc.NewPermissionService(repo, auditRecorder)
 	h := permissionHandler.NewPermissionHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, repo)
+	blacklistRepo := r.blacklistRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, repo, r.userRegionRepo())
 
 	// List endpoints
 	r.App.GET("/api/permissions", mdw.AuthMiddleware(), mdw.PermissionMiddleware("permissions", "view"), h.GetAll)
@@ -393,13 +668,25 @@ func (r *Routes) PermissionRoutes() {
 	}
 }
 
+func (r *Routes) AuditLogRoutes() {
+	repo := auditLogRepo.NewAuditLogRepo(r.DB)
+	svc := auditLogSvc.NewAuditLogService(repo)
+	h := auditLogHandler.NewAuditLogHandler(svc)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
+
+	r.App.GET("/api/audit-logs", mdw.AuthMiddleware(), mdw.PermissionMiddleware("audit_logs", "view"), h.FetchAuditLog)
+	r.App.GET("/api/audit-log/:id", mdw.AuthMiddleware(), mdw.PermissionMiddleware("audit_logs", "view"), h.GetAuditLogById)
+}
+
 func (r *Routes) MenuRoutes() {
 	repo := menuRepo.NewMenuRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
+	pRepo := r.permissionRepo()
 	svc := menuSvc.NewMenuService(repo, pRepo)
 	h := menuHandler.NewMenuHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	// Public endpoints for authenticated users
 	r.App.GET("/api/menus/active", mdw.AuthMiddleware(), h.GetActiveMenus)
@@ -429,9 +716,9 @@ func (r *Routes) SessionRoutes() {
 	repo := sessionRepo.NewSessionRepository(redisClient)
 	svc := sessionSvc.NewSessionService(repo)
 	h := sessionHandler.NewSessionHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	// Session management endpoints (authenticated users only)
 	sessionGroup := r.App.Group("/api/user").Use(mdw.AuthMiddleware())
@@ -448,9 +735,9 @@ func (r *Routes) PoldaRoutes() {
 	repo := poldaRepo.NewPoldaAccidentRepo(r.DB)
 	svc := poldaSvc.NewPoldaAccidentService(repo)
 	h := poldaHandler.NewPoldaAccidentHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	r.App.GET("/api/polda-accidents", mdw.AuthMiddleware(), mdw.PermissionMiddleware("polda_accidents", "list"), h.GetAll)
 	polda := r.App.Group("/api/polda-accident").Use(mdw.AuthMiddleware())
@@ -462,13 +749,66 @@ func (r *Routes) PoldaRoutes() {
 	}
 }
 
+// FileRoutes serves the files of the local storage provider from the path of STORAGE_BASE_URL, with the signature
+// in the URL standing in for authentication so the files can be used in <img> tags
+func (r *Routes) FileRoutes() {
+	local, ok := r.storageProvider().(*storage.LocalAdapter)
+	if !ok {
+		return
+	}
+
+	h := fileHandler.NewFileHandler(local)
+	r.App.GET(strings.TrimRight(local.URLPath(), "/")+"/*path", h.ServeFile)
+}
+
+// StorageGCJob reconciles the photo and attachment folders of the storage with the database every STORAGE_GC_INTERVAL_HOURS,
+// the job is off while the interval is 0. Orphaned objects are only logged unless STORAGE_GC_DELETE is true
+func (r *Routes) StorageGCJob() {
+	interval := time.Duration(utils.GetEnv("STORAGE_GC_INTERVAL_HOURS", 0).(int)) * time.Hour
+	if interval <= 0 {
+		return
+	}
+
+	opts := dto.StorageGCOptions{
+		GracePeriod: time.Duration(utils.GetEnv("STORAGE_GC_GRACE_HOURS", 168).(int)) * time.Hour,
+		Delete:      utils.GetEnv("STORAGE_GC_DELETE", false).(bool),
+	}
+	service := storageGCSvc.NewStorageGCService(storageGCRepo.NewStorageGCRepo(r.DB), r.storageProvider())
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := service.Reconcile(context.Background(), opts)
+			if err != nil {
+				logger.WriteLog(logger.LogLevelError, "StorageGCJob; Reconcile; Error: "+err.Error())
+				continue
+			}
+			for _, folder := range report.Folders {
+				deleted := 0
+				for _, orphan := range folder.Orphans {
+					if orphan.Deleted {
+						deleted++
+					}
+				}
+				msg := fmt.Sprintf("StorageGCJob; %s: %d objects, %d orphans (%d bytes, %d deleted), %d rows missing their object",
+					folder.Folder, folder.Objects, len(folder.Orphans), folder.OrphanBytes, deleted, len(folder.Missing))
+				if folder.Skipped != "" {
+					msg += "; deletion skipped: " + folder.Skipped
+				}
+				logger.WriteLog(logger.LogLevelInfo, msg)
+			}
+		}
+	}()
+}
+
 func (r *Routes) DashboardRoutes() {
 	dashboardRepo := repodashboard.NewDashboardRepo(r.DB)
 	dashboardService := dashboardSvc.NewDashboardService(dashboardRepo)
 	h := dashboardHandler.NewDashboardHandler(dashboardService)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	// Dashboard stats endpoint - aggregated statistics
 	r.App.GET("/api/dashboard/stats", mdw.AuthMiddleware(), mdw.PermissionMiddleware("dashboard", "view"), h.GetStats)
@@ -478,11 +818,11 @@ func (r *Routes) ApprovalRecordRoutes() {
 	repo := approvalRecordRepo.NewApprovalRecordRepo(r.DB)
 	submittedRepo := submittedFormRepo.NewSubmittedFormRepo(r.DB)
 	configRepo := appConfigRepo.NewAppConfigRepo(r.DB)
-	svc := approvalRecordSvc.NewApprovalRecordService(repo, submittedRepo, configRepo)
+	svc := approvalRecordSvc.NewApprovalRecordService(repo, submittedRepo, configRepo, eventRepo.NewEventRepo(r.DB))
 	h := approvalRecordHandler.NewApprovalRecordHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	records := r.App.Group("/api/approval-records").Use(mdw.AuthMiddleware())
 	{
@@ -497,9 +837,9 @@ func (r *Routes) AppConfigRoutes() {
 	repo := appConfigRepo.NewAppConfigRepo(r.DB)
 	svc := appConfigSvc.NewAppConfigService(repo)
 	h := appConfigHandler.NewAppConfigHandler(svc)
-	blacklistRepo := authRepo.NewBlacklistRepo(r.DB)
-	pRepo := permissionRepo.NewPermissionRepo(r.DB)
-	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo)
+	blacklistRepo := r.blacklistRepo()
+	pRepo := r.permissionRepo()
+	mdw := middlewares.NewMiddleware(blacklistRepo, pRepo, r.userRegionRepo())
 
 	configs := r.App.Group("/api").Use(mdw.AuthMiddleware())
 	{
@@ -0,0 +1,200 @@
+package serviceaccident
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"safety-riding/internal/domain/accident"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
+	"safety-riding/utils"
+)
+
+// UpdateAccidentPhoto changes the caption of a photo or makes it the cover of its accident
+func (s *AccidentService) UpdateAccidentPhoto(photoId, username string, scope filter.RegionScope, req dto.UpdateAccidentPhoto) (domainaccident.AccidentPhoto, error) {
+	photo, err := s.AccidentRepo.GetPhotoByID(photoId)
+	if err != nil {
+		return domainaccident.AccidentPhoto{}, err
+	}
+
+	// The photo belongs to an accident that must be inside the user's region
+	if _, err = s.AccidentRepo.GetByID(photo.AccidentId, scope); err != nil {
+		return domainaccident.AccidentPhoto{}, err
+	}
+
+	before := photo
+	if req.Caption != nil {
+		photo.Caption = *req.Caption
+	}
+	if req.IsCover != nil {
+		photo.IsCover = *req.IsCover
+	}
+
+	if err := s.AccidentRepo.UpdatePhoto(photo); err != nil {
+		return domainaccident.AccidentPhoto{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityAccidentPhoto, photoId, before, photo)
+
+	return photo, nil
+}
+
+// ReorderAccidentPhotos stores the order of all photos of the accident as 1..n
+func (s *AccidentService) ReorderAccidentPhotos(accidentId, username string, scope filter.RegionScope, req dto.ReorderAccidentPhotos) ([]domainaccident.AccidentPhoto, error) {
+	accident, err := s.AccidentRepo.GetByID(accidentId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIds := make([]string, 0, len(accident.Photos))
+	for _, photo := range accident.Photos {
+		currentIds = append(currentIds, photo.ID)
+	}
+	if err := utils.ValidatePhotoOrder(currentIds, req.PhotoIds); err != nil {
+		return nil, err
+	}
+
+	if err := s.AccidentRepo.ReorderPhotos(accidentId, req.PhotoIds); err != nil {
+		return nil, err
+	}
+
+	photos, err := s.AccidentRepo.GetPhotosByAccidentID(accidentId)
+	if err != nil {
+		return nil, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityAccident, accidentId, map[string]interface{}{"photo_ids": currentIds}, map[string]interface{}{"photo_ids": req.PhotoIds})
+
+	return photos, nil
+}
+
+// RequestAccidentPhotoUploads reserves presigned URLs to upload photos of the accident directly to the bucket,
+// the photos are created by ConfirmAccidentPhotoUploads once the files are uploaded
+func (s *AccidentService) RequestAccidentPhotoUploads(ctx context.Context, accidentId string, scope filter.RegionScope, req dto.RequestPhotoUploads) ([]dto.PhotoUploadSlot, error) {
+	accident, err := s.AccidentRepo.GetByID(accidentId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAccidentPhotos := utils.GetEnv("MAX_ACCIDENT_PHOTOS", 4).(int)
+	if err := utils.ValidatePhotoLimit(len(accident.Photos), len(req.Files), maxAccidentPhotos); err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(utils.GetEnv("MAX_PHOTO_SIZE_BYTES", 5*1024*1024).(int))
+	expiry := time.Duration(utils.GetEnv("PHOTO_UPLOAD_URL_EXPIRY_SECONDS", 900).(int)) * time.Second
+	slots := make([]dto.PhotoUploadSlot, 0, len(req.Files))
+	for _, file := range req.Files {
+		if file.Size > maxSize {
+			return nil, fmt.Errorf("file %s exceeds maximum size of %d bytes", file.Filename, maxSize)
+		}
+
+		slot, err := imaging.PresignUpload(ctx, s.StorageProvider, accidentPhotoStaging(accidentId), file.ContentType, expiry)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, dto.PhotoUploadSlot{
+			UploadId:  slot.ObjectName,
+			Filename:  file.Filename,
+			UploadUrl: slot.URL,
+			Method:    http.MethodPut,
+			Headers:   map[string]string{"Content-Type": slot.ContentType},
+			ExpiresAt: slot.ExpiresAt,
+		})
+	}
+
+	return slots, nil
+}
+
+// ConfirmAccidentPhotoUploads checks the direct uploads of the accident and stores them as photos, the same way
+// as the files uploaded through the API: resized, without EXIF data and with their renditions
+func (s *AccidentService) ConfirmAccidentPhotoUploads(ctx context.Context, accidentId, username string, scope filter.RegionScope, req dto.ConfirmPhotoUploads) ([]domainaccident.AccidentPhoto, error) {
+	accident, err := s.AccidentRepo.GetByID(accidentId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAccidentPhotos := utils.GetEnv("MAX_ACCIDENT_PHOTOS", 4).(int)
+	if err := utils.ValidatePhotoLimit(len(accident.Photos), len(req.Photos), maxAccidentPhotos); err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(utils.GetEnv("MAX_PHOTO_SIZE_BYTES", 5*1024*1024).(int))
+	captions := make([]string, 0, len(req.Photos))
+	requestedOrders := make([]int, 0, len(req.Photos))
+	uploaded := make([]imaging.Photo, 0, len(req.Photos))
+	seen := make(map[string]bool, len(req.Photos))
+	for _, p := range req.Photos {
+		if seen[p.UploadId] {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, fmt.Errorf("upload %s is confirmed twice", path.Base(p.UploadId))
+		}
+		seen[p.UploadId] = true
+
+		photo, err := imaging.ImportUpload(ctx, s.StorageProvider, p.UploadId, accidentPhotoStaging(accidentId), "accident-photos", maxSize, imaging.DefaultOptions)
+		if err != nil {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, err
+		}
+		uploaded = append(uploaded, photo)
+		captions = append(captions, p.Caption)
+		requestedOrders = append(requestedOrders, p.PhotoOrder)
+	}
+
+	return s.saveUploadedPhotos(ctx, accidentId, username, len(accident.Photos), uploaded, captions, requestedOrders)
+}
+
+// saveUploadedPhotos creates the photos of stored uploads after the current photos of the accident,
+// the stored files are removed when they cannot be saved
+func (s *AccidentService) saveUploadedPhotos(ctx context.Context, accidentId, username string, currentCount int, uploaded []imaging.Photo, captions []string, requestedOrders []int) ([]domainaccident.AccidentPhoto, error) {
+	keepLocation := utils.GetEnv("PHOTO_KEEP_LOCATION", false).(bool)
+	orders := utils.AppendPhotoOrders(currentCount, requestedOrders)
+
+	accidentPhotos := make([]domainaccident.AccidentPhoto, 0, len(uploaded))
+	for i, photo := range uploaded {
+		accidentPhoto := domainaccident.AccidentPhoto{
+			ID:           utils.CreateUUID(),
+			AccidentId:   accidentId,
+			PhotoUrl:     photo.URL,
+			MediumUrl:    photo.MediumURL,
+			ThumbnailUrl: photo.ThumbnailURL,
+			PhotoOrder:   orders[i],
+			CreatedAt:    time.Now(),
+			CreatedBy:    username,
+		}
+		if i < len(captions) {
+			accidentPhoto.Caption = captions[i]
+		}
+		if keepLocation && photo.Location != nil {
+			accidentPhoto.Latitude, accidentPhoto.Longitude = &photo.Location.Latitude, &photo.Location.Longitude
+		}
+		accidentPhotos = append(accidentPhotos, accidentPhoto)
+	}
+
+	if err := s.AccidentRepo.AddPhotos(accidentPhotos); err != nil {
+		imaging.Delete(ctx, s.StorageProvider, uploaded...)
+		return nil, err
+	}
+	for _, photo := range accidentPhotos {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityAccidentPhoto, photo.ID, nil, photo)
+	}
+
+	return accidentPhotos, nil
+}
+
+// accidentPhotoStaging is the staging folder of the direct uploads of an accident
+func accidentPhotoStaging(accidentId string) string {
+	return path.Join("accident-photos", accidentId)
+}
+
+// requestedPhotoOrders lists the photo_order asked for each new photo
+func requestedPhotoOrders(photos []dto.AddAccidentPhoto) []int {
+	orders := make([]int, 0, len(photos))
+	for _, p := range photos {
+		orders = append(orders, p.PhotoOrder)
+	}
+	return orders
+}
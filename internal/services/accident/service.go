@@ -2,12 +2,14 @@ package serviceaccident
 
 import (
 	"context"
-	"fmt"
 	"mime/multipart"
 	"safety-riding/internal/domain/accident"
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	"safety-riding/internal/dto"
 	interfaceaccident "safety-riding/internal/interfaces/accident"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
 	"safety-riding/pkg/storage"
 	"safety-riding/utils"
 	"strconv"
@@ -17,16 +19,22 @@ import (
 type AccidentService struct {
 	AccidentRepo    interfaceaccident.RepoAccidentInterface
 	StorageProvider storage.StorageProvider
+	AuditRecorder   interfaceauditlog.AuditRecorder
 }
 
-func NewAccidentService(accidentRepo interfaceaccident.RepoAccidentInterface, storageProvider storage.StorageProvider) *AccidentService {
+func NewAccidentService(accidentRepo interfaceaccident.RepoAccidentInterface, storageProvider storage.StorageProvider, auditRecorder interfaceauditlog.AuditRecorder) *AccidentService {
 	return &AccidentService{
 		AccidentRepo:    accidentRepo,
 		StorageProvider: storageProvider,
+		AuditRecorder:   auditRecorder,
 	}
 }
 
-func (s *AccidentService) AddAccident(username string, req dto.AddAccident) (domainaccident.Accident, error) {
+func (s *AccidentService) AddAccident(username string, scope filter.RegionScope, req dto.AddAccident) (domainaccident.Accident, error) {
+	if !scope.Allows(req.ProvinceId, req.CityId) {
+		return domainaccident.Accident{}, filter.ErrOutOfRegion
+	}
+
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"death_count":         req.DeathCount,
 		"injured_count":       req.InjuredCount,
@@ -70,15 +78,16 @@ func (s *AccidentService) AddAccident(username string, req dto.AddAccident) (dom
 	if err := s.AccidentRepo.Create(data); err != nil {
 		return domainaccident.Accident{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityAccident, data.ID, nil, data)
 
 	return data, nil
 }
 
-func (s *AccidentService) GetAccidentById(id string) (domainaccident.Accident, error) {
-	return s.AccidentRepo.GetByID(id)
+func (s *AccidentService) GetAccidentById(id string, scope filter.RegionScope) (domainaccident.Accident, error) {
+	return s.AccidentRepo.GetByID(id, scope)
 }
 
-func (s *AccidentService) UpdateAccident(id, username string, req dto.UpdateAccident) (domainaccident.Accident, error) {
+func (s *AccidentService) UpdateAccident(id, username string, scope filter.RegionScope, req dto.UpdateAccident) (domainaccident.Accident, error) {
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"death_count":         req.DeathCount,
 		"injured_count":       req.InjuredCount,
@@ -89,10 +98,11 @@ func (s *AccidentService) UpdateAccident(id, username string, req dto.UpdateAcci
 	}
 
 	// Get existing accident
-	accident, err := s.AccidentRepo.GetByID(id)
+	accident, err := s.AccidentRepo.GetByID(id, scope)
 	if err != nil {
 		return domainaccident.Accident{}, err
 	}
+	before := accident
 
 	// Update fields if provided
 	if req.PoliceReportNo != "" {
@@ -171,12 +181,17 @@ func (s *AccidentService) UpdateAccident(id, username string, req dto.UpdateAcci
 		accident.OfficerName = req.OfficerName
 	}
 
+	if !scope.Allows(accident.ProvinceId, accident.CityId) {
+		return domainaccident.Accident{}, filter.ErrOutOfRegion
+	}
+
 	accident.UpdatedAt = time.Now()
 	accident.UpdatedBy = username
 
 	if err := s.AccidentRepo.Update(accident); err != nil {
 		return domainaccident.Accident{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityAccident, id, before, accident)
 
 	return accident, nil
 }
@@ -185,9 +200,9 @@ func (s *AccidentService) FetchAccident(params filter.BaseParams) ([]domainaccid
 	return s.AccidentRepo.Fetch(params)
 }
 
-func (s *AccidentService) DeleteAccident(id, username string) error {
+func (s *AccidentService) DeleteAccident(id, username string, scope filter.RegionScope) error {
 	// Check if accident exists
-	accident, err := s.AccidentRepo.GetByID(id)
+	accident, err := s.AccidentRepo.GetByID(id, scope)
 	if err != nil {
 		return err
 	}
@@ -199,14 +214,15 @@ func (s *AccidentService) DeleteAccident(id, username string) error {
 	if err := s.AccidentRepo.Delete(id); err != nil {
 		return err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityAccident, id, accident, nil)
 
 	return nil
 }
 
 // Photo methods
-func (s *AccidentService) AddAccidentPhotos(accidentId, username string, photos []dto.AddAccidentPhoto) ([]domainaccident.AccidentPhoto, error) {
+func (s *AccidentService) AddAccidentPhotos(accidentId, username string, scope filter.RegionScope, photos []dto.AddAccidentPhoto) ([]domainaccident.AccidentPhoto, error) {
 	// Verify accident exists
-	accident, err := s.AccidentRepo.GetByID(accidentId)
+	accident, err := s.AccidentRepo.GetByID(accidentId, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -217,42 +233,56 @@ func (s *AccidentService) AddAccidentPhotos(accidentId, username string, photos
 	}
 
 	accidentPhotos := make([]domainaccident.AccidentPhoto, 0, len(photos))
-	for _, p := range photos {
+	orders := utils.AppendPhotoOrders(len(accident.Photos), requestedPhotoOrders(photos))
+	for i, p := range photos {
 		accidentPhotos = append(accidentPhotos, domainaccident.AccidentPhoto{
-			ID:         utils.CreateUUID(),
-			AccidentId: accidentId,
-			PhotoUrl:   p.PhotoUrl,
-			Caption:    p.Caption,
-			PhotoOrder: p.PhotoOrder,
-			CreatedAt:  time.Now(),
-			CreatedBy:  username,
+			ID:           utils.CreateUUID(),
+			AccidentId:   accidentId,
+			PhotoUrl:     p.PhotoUrl,
+			MediumUrl:    p.PhotoUrl,
+			ThumbnailUrl: p.PhotoUrl,
+			Caption:      p.Caption,
+			PhotoOrder:   orders[i],
+			CreatedAt:    time.Now(),
+			CreatedBy:    username,
 		})
 	}
 
 	if err := s.AccidentRepo.AddPhotos(accidentPhotos); err != nil {
 		return nil, err
 	}
+	for _, photo := range accidentPhotos {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityAccidentPhoto, photo.ID, nil, photo)
+	}
 
 	return accidentPhotos, nil
 }
 
-func (s *AccidentService) DeleteAccidentPhoto(photoId, username string) error {
+func (s *AccidentService) DeleteAccidentPhoto(photoId, username string, scope filter.RegionScope) error {
 	accidentPhoto, err := s.AccidentRepo.GetPhotoByID(photoId)
 	if err != nil {
 		return err
 	}
 
-	if err = s.AccidentRepo.DeletePhoto(photoId); err == nil {
-		_ = s.StorageProvider.DeleteFile(context.Background(), accidentPhoto.PhotoUrl)
+	// The photo belongs to an accident that must be inside the user's region
+	if _, err = s.AccidentRepo.GetByID(accidentPhoto.AccidentId, scope); err != nil {
+		return err
+	}
+
+	if err = s.AccidentRepo.DeletePhoto(photoId); err != nil {
+		return err
 	}
+	imaging.Delete(context.Background(), s.StorageProvider, imaging.Photo{URL: accidentPhoto.PhotoUrl, MediumURL: accidentPhoto.MediumUrl, ThumbnailURL: accidentPhoto.ThumbnailUrl})
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityAccidentPhoto, photoId, accidentPhoto, nil)
 
-	return err
+	// Close the gap the photo leaves in photo_order
+	return s.AccidentRepo.NormalizePhotoOrder(accidentPhoto.AccidentId)
 }
 
-// AddAccidentPhotosFromFiles uploads photos to storage and saves to database
-func (s *AccidentService) AddAccidentPhotosFromFiles(ctx context.Context, accidentId, username string, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainaccident.AccidentPhoto, error) {
+// AddAccidentPhotosFromFiles checks and resizes the uploaded photos, stores their renditions without EXIF data and saves them to database
+func (s *AccidentService) AddAccidentPhotosFromFiles(ctx context.Context, accidentId, username string, scope filter.RegionScope, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainaccident.AccidentPhoto, error) {
 	// Verify accident exists
-	accident, err := s.AccidentRepo.GetByID(accidentId)
+	accident, err := s.AccidentRepo.GetByID(accidentId, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -262,62 +292,32 @@ func (s *AccidentService) AddAccidentPhotosFromFiles(ctx context.Context, accide
 		return nil, err
 	}
 
-	accidentPhotos := make([]domainaccident.AccidentPhoto, 0, len(files))
+	requestedOrders := make([]int, len(files))
+	uploaded := make([]imaging.Photo, 0, len(files))
 
-	var photoURL string
 	for i, fileHeader := range files {
 		if err := utils.ValidatePhotoFileSize(fileHeader); err != nil {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
 			return nil, err
 		}
 
-		// Open file
-		file, err := fileHeader.Open()
-		if err != nil {
-			return nil, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
-		}
-		defer file.Close()
-
-		// Upload to storage provider (MinIO or R2)
-		photoURL, err = s.StorageProvider.UploadFile(ctx, file, fileHeader, "accident-photos")
+		// Upload the renditions to storage provider (MinIO or R2)
+		photo, err := imaging.UploadFile(ctx, s.StorageProvider, fileHeader, "accident-photos", imaging.DefaultOptions)
 		if err != nil {
-			return nil, fmt.Errorf("failed to upload file %s to storage: %w", fileHeader.Filename, err)
-		}
-
-		// Get caption if provided
-		caption := ""
-		if i < len(captions) {
-			caption = captions[i]
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, err
 		}
+		uploaded = append(uploaded, photo)
 
 		// Get photo order if provided
-		photoOrder := 0
 		if i < len(photoOrders) {
 			if order, err := strconv.Atoi(photoOrders[i]); err == nil {
-				photoOrder = order
+				requestedOrders[i] = order
 			}
 		}
-
-		// Create photo record
-		accidentPhoto := domainaccident.AccidentPhoto{
-			ID:         utils.CreateUUID(),
-			AccidentId: accidentId,
-			PhotoUrl:   photoURL,
-			Caption:    caption,
-			PhotoOrder: photoOrder,
-			CreatedAt:  time.Now(),
-			CreatedBy:  username,
-		}
-
-		accidentPhotos = append(accidentPhotos, accidentPhoto)
 	}
 
-	// Save photos to database
-	if err := s.AccidentRepo.AddPhotos(accidentPhotos); err != nil {
-		_ = s.StorageProvider.DeleteFile(ctx, photoURL)
-		return nil, err
-	}
-
-	return accidentPhotos, nil
+	return s.saveUploadedPhotos(ctx, accidentId, username, len(accident.Photos), uploaded, captions, requestedOrders)
 }
 
 var _ interfaceaccident.ServiceAccidentInterface = (*AccidentService)(nil)
@@ -6,6 +6,7 @@ import (
 	"safety-riding/internal/dto"
 	interfaceappconfig "safety-riding/internal/interfaces/appconfig"
 	interfaceapprovalrecord "safety-riding/internal/interfaces/approvalrecord"
+	interfaceevent "safety-riding/internal/interfaces/event"
 	interfacesubmittedform "safety-riding/internal/interfaces/submittedform"
 	"safety-riding/pkg/filter"
 	"sync"
@@ -29,6 +30,7 @@ type ApprovalRecordService struct {
 	Repo              interfaceapprovalrecord.RepoApprovalRecordInterface
 	SubmittedFormRepo interfacesubmittedform.RepoSubmittedFormInterface
 	ConfigRepo        interfaceappconfig.RepoAppConfigInterface
+	EventRepo         interfaceevent.RepoEventInterface
 	HTTPClient        *http.Client
 	syncMu            sync.Mutex
 }
@@ -37,11 +39,13 @@ func NewApprovalRecordService(
 	repo interfaceapprovalrecord.RepoApprovalRecordInterface,
 	submittedFormRepo interfacesubmittedform.RepoSubmittedFormInterface,
 	configRepo interfaceappconfig.RepoAppConfigInterface,
+	eventRepo interfaceevent.RepoEventInterface,
 ) *ApprovalRecordService {
 	return &ApprovalRecordService{
 		Repo:              repo,
 		SubmittedFormRepo: submittedFormRepo,
 		ConfigRepo:        configRepo,
+		EventRepo:         eventRepo,
 		HTTPClient: &http.Client{
 			Timeout: 20 * time.Second,
 		},
@@ -80,6 +84,17 @@ func (s *ApprovalRecordService) Fetch(params filter.BaseParams) ([]dto.ApprovalR
 		return nil, 0, err
 	}
 
+	requestNumbers := make([]int, 0, len(forms))
+	for _, form := range forms {
+		if form.RequestNumber != 0 {
+			requestNumbers = append(requestNumbers, form.RequestNumber)
+		}
+	}
+	eventIds, err := s.EventRepo.GetIdsByRequestNumbers(requestNumbers)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	items := make([]dto.ApprovalRecordListItem, 0, len(forms))
 	for _, form := range forms {
 		items = append(items, dto.ApprovalRecordListItem{
@@ -92,6 +107,7 @@ func (s *ApprovalRecordService) Fetch(params filter.BaseParams) ([]dto.ApprovalR
 			ActivityName:     form.ActivityName,
 			ParticipantCount: form.ParticipantCount,
 			LatestStatus:     form.LatestStatus,
+			EventId:          eventIds[form.RequestNumber],
 			SyncedAt:         form.SyncedAt,
 		})
 	}
@@ -110,6 +126,13 @@ func (s *ApprovalRecordService) GetByID(id string) (dto.ApprovalRecordDetail, er
 		return dto.ApprovalRecordDetail{}, err
 	}
 
+	var eventIds map[int]string
+	if form.RequestNumber != 0 {
+		if eventIds, err = s.EventRepo.GetIdsByRequestNumbers([]int{form.RequestNumber}); err != nil {
+			return dto.ApprovalRecordDetail{}, err
+		}
+	}
+
 	return dto.ApprovalRecordDetail{
 		ID:                   form.ID,
 		RequestNumber:        form.RequestNumber,
@@ -130,6 +153,7 @@ func (s *ApprovalRecordService) GetByID(id string) (dto.ApprovalRecordDetail, er
 		TrainingType:         form.TrainingType,
 		SheetStatus:          form.SheetStatus,
 		LatestStatus:         form.LatestStatus,
+		EventId:              eventIds[form.RequestNumber],
 		SyncedAt:             form.SyncedAt,
 		Approvals:            approvals,
 	}, nil
@@ -0,0 +1,441 @@
+package serviceassessment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	domainassessment "safety-riding/internal/domain/assessment"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/internal/dto"
+	interfaceassessment "safety-riding/internal/interfaces/assessment"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfaceevent "safety-riding/internal/interfaces/event"
+	interfaceparticipant "safety-riding/internal/interfaces/participant"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+type AssessmentService struct {
+	BankRepo        interfaceassessment.RepoQuestionBankInterface
+	AssessmentRepo  interfaceassessment.RepoAssessmentInterface
+	EventRepo       interfaceevent.RepoEventInterface
+	ParticipantRepo interfaceparticipant.RepoParticipantInterface
+	AuditRecorder   interfaceauditlog.AuditRecorder
+}
+
+func NewAssessmentService(bankRepo interfaceassessment.RepoQuestionBankInterface, assessmentRepo interfaceassessment.RepoAssessmentInterface, eventRepo interfaceevent.RepoEventInterface, participantRepo interfaceparticipant.RepoParticipantInterface, auditRecorder interfaceauditlog.AuditRecorder) *AssessmentService {
+	return &AssessmentService{
+		BankRepo:        bankRepo,
+		AssessmentRepo:  assessmentRepo,
+		EventRepo:       eventRepo,
+		ParticipantRepo: participantRepo,
+		AuditRecorder:   auditRecorder,
+	}
+}
+
+func (s *AssessmentService) AddQuestionBank(username string, req dto.AddQuestionBank) (domainassessment.QuestionBank, error) {
+	bank := domainassessment.QuestionBank{
+		ID:          utils.CreateUUID(),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   time.Now(),
+		CreatedBy:   username,
+	}
+
+	questions, err := newQuestions(bank.ID, bank.CreatedAt, req.Questions)
+	if err != nil {
+		return domainassessment.QuestionBank{}, err
+	}
+	bank.Questions = questions
+
+	if err := s.BankRepo.Create(bank); err != nil {
+		return domainassessment.QuestionBank{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityQuestionBank, bank.ID, nil, bank)
+
+	return bank, nil
+}
+
+func (s *AssessmentService) GetQuestionBankById(id string) (domainassessment.QuestionBank, error) {
+	return s.BankRepo.GetByID(id)
+}
+
+// UpdateQuestionBank changes the bank and replaces its questions when they are given.
+// Questions that already scored answers cannot change, otherwise earlier scores would no longer be comparable.
+func (s *AssessmentService) UpdateQuestionBank(id, username string, req dto.UpdateQuestionBank) (domainassessment.QuestionBank, error) {
+	bank, err := s.BankRepo.GetByID(id)
+	if err != nil {
+		return domainassessment.QuestionBank{}, err
+	}
+	before := bank
+
+	if req.Name != "" {
+		bank.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Description != "" {
+		bank.Description = strings.TrimSpace(req.Description)
+	}
+
+	now := time.Now()
+	replaceQuestions := len(req.Questions) > 0
+	if replaceQuestions {
+		hasSubmissions, err := s.BankRepo.HasSubmissions(id)
+		if err != nil {
+			return domainassessment.QuestionBank{}, err
+		}
+		if hasSubmissions {
+			return domainassessment.QuestionBank{}, errors.New("questions that already have submissions cannot be changed, create a new question bank instead")
+		}
+
+		if bank.Questions, err = newQuestions(bank.ID, now, req.Questions); err != nil {
+			return domainassessment.QuestionBank{}, err
+		}
+	}
+
+	bank.UpdatedAt = &now
+	bank.UpdatedBy = username
+	if err := s.BankRepo.Update(bank, replaceQuestions); err != nil {
+		return domainassessment.QuestionBank{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityQuestionBank, bank.ID, before, bank)
+
+	return bank, nil
+}
+
+func (s *AssessmentService) FetchQuestionBank(params filter.BaseParams) ([]domainassessment.QuestionBank, int64, error) {
+	return s.BankRepo.Fetch(params)
+}
+
+func (s *AssessmentService) DeleteQuestionBank(id, username string) error {
+	bank, err := s.BankRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := s.BankRepo.IsInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("question bank is used by an event assessment")
+	}
+
+	if err := s.BankRepo.Delete(id); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityQuestionBank, id, bank, nil)
+
+	return nil
+}
+
+// AddEventAssessment attaches a question bank to the event as its pre-test or post-test
+func (s *AssessmentService) AddEventAssessment(eventId, username string, scope filter.RegionScope, req dto.AddEventAssessment) (domainassessment.EventAssessment, error) {
+	if _, err := s.getAssessableEvent(eventId, scope); err != nil {
+		return domainassessment.EventAssessment{}, err
+	}
+
+	bank, err := s.BankRepo.GetByID(req.BankId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainassessment.EventAssessment{}, errors.New("question bank not found")
+		}
+		return domainassessment.EventAssessment{}, err
+	}
+	if len(bank.Questions) == 0 {
+		return domainassessment.EventAssessment{}, errors.New("question bank has no questions")
+	}
+
+	if _, err := s.AssessmentRepo.GetByEvent(eventId, req.Type); err == nil {
+		return domainassessment.EventAssessment{}, fmt.Errorf("event already has a %s-test", req.Type)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainassessment.EventAssessment{}, err
+	}
+
+	assessment := domainassessment.EventAssessment{
+		ID:        utils.CreateUUID(),
+		EventId:   eventId,
+		BankId:    bank.ID,
+		Type:      req.Type,
+		CreatedAt: time.Now(),
+		CreatedBy: username,
+	}
+	if err := s.AssessmentRepo.Create(assessment); err != nil {
+		return domainassessment.EventAssessment{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEventAssessment, assessment.ID, nil, assessment)
+
+	assessment.Bank = &bank
+	return assessment, nil
+}
+
+func (s *AssessmentService) GetEventAssessment(eventId, assessmentType string, scope filter.RegionScope) (domainassessment.EventAssessment, error) {
+	if _, err := s.EventRepo.GetByID(eventId, scope); err != nil {
+		return domainassessment.EventAssessment{}, err
+	}
+
+	return s.AssessmentRepo.GetByEvent(eventId, assessmentType)
+}
+
+func (s *AssessmentService) DeleteEventAssessment(eventId, assessmentType, username string, scope filter.RegionScope) error {
+	if _, err := s.getAssessableEvent(eventId, scope); err != nil {
+		return err
+	}
+
+	assessment, err := s.AssessmentRepo.GetByEvent(eventId, assessmentType)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.AssessmentRepo.CountSubmissions(assessment.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("the %s-test already has %d submissions and cannot be removed", assessmentType, count)
+	}
+
+	if err := s.AssessmentRepo.Delete(assessment.ID); err != nil {
+		return err
+	}
+	assessment.Bank = nil
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityEventAssessment, assessment.ID, assessment, nil)
+
+	return nil
+}
+
+// SubmitAssessment scores the answer sheet of a participant on the event roster, each participant answers a test once
+func (s *AssessmentService) SubmitAssessment(eventId, assessmentType, username string, scope filter.RegionScope, req dto.SubmitAssessment) (domainassessment.Submission, error) {
+	if _, err := s.getAssessableEvent(eventId, scope); err != nil {
+		return domainassessment.Submission{}, err
+	}
+
+	assessment, err := s.AssessmentRepo.GetByEvent(eventId, assessmentType)
+	if err != nil {
+		return domainassessment.Submission{}, err
+	}
+	if assessment.Bank == nil {
+		return domainassessment.Submission{}, errors.New("question bank of the assessment no longer exists")
+	}
+
+	participant, err := s.ParticipantRepo.GetByID(req.ParticipantId)
+	if err != nil {
+		return domainassessment.Submission{}, err
+	}
+	if participant.EventId != eventId {
+		return domainassessment.Submission{}, gorm.ErrRecordNotFound
+	}
+
+	submitted, err := s.AssessmentRepo.HasSubmitted(assessment.ID, participant.ID)
+	if err != nil {
+		return domainassessment.Submission{}, err
+	}
+	if submitted {
+		return domainassessment.Submission{}, fmt.Errorf("%s already submitted the %s-test", participant.Name, assessmentType)
+	}
+
+	correct, err := scoreAnswers(assessment.Bank.Questions, req.Answers)
+	if err != nil {
+		return domainassessment.Submission{}, err
+	}
+
+	total := len(assessment.Bank.Questions)
+	submission := domainassessment.Submission{
+		ID:              utils.CreateUUID(),
+		AssessmentId:    assessment.ID,
+		EventId:         eventId,
+		ParticipantId:   participant.ID,
+		Answers:         req.Answers,
+		CorrectCount:    correct,
+		TotalQuestions:  total,
+		Score:           round2(float64(correct) / float64(total) * 100),
+		SubmittedAt:     time.Now(),
+		SubmittedBy:     username,
+		ParticipantName: participant.Name,
+	}
+	if err := s.AssessmentRepo.CreateSubmission(submission); err != nil {
+		return domainassessment.Submission{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityAssessmentSubmission, submission.ID, nil, submission)
+
+	return submission, nil
+}
+
+func (s *AssessmentService) FetchSubmissions(eventId, assessmentType string, params filter.BaseParams) ([]domainassessment.Submission, int64, error) {
+	if _, err := s.EventRepo.GetByID(eventId, params.Scope); err != nil {
+		return nil, 0, err
+	}
+
+	assessment, err := s.AssessmentRepo.GetByEvent(eventId, assessmentType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.AssessmentRepo.FetchSubmissions(assessment.ID, params)
+}
+
+// GetEventResults compares the pre-test and post-test score of every participant of the event
+func (s *AssessmentService) GetEventResults(eventId string, scope filter.RegionScope) (dto.EventAssessmentResults, error) {
+	if _, err := s.EventRepo.GetByID(eventId, scope); err != nil {
+		return dto.EventAssessmentResults{}, err
+	}
+
+	assessments, err := s.AssessmentRepo.FetchSummaries(eventId)
+	if err != nil {
+		return dto.EventAssessmentResults{}, err
+	}
+
+	participants, err := s.AssessmentRepo.FetchEventResults(eventId)
+	if err != nil {
+		return dto.EventAssessmentResults{}, err
+	}
+
+	result := dto.EventAssessmentResults{
+		EventId:      eventId,
+		Assessments:  assessments,
+		Participants: participants,
+	}
+
+	var sumPre, sumPost float64
+	for i, p := range result.Participants {
+		if p.PreScore == nil || p.PostScore == nil {
+			continue
+		}
+		improvement := round2(*p.PostScore - *p.PreScore)
+		result.Participants[i].Improvement = &improvement
+
+		result.PairedCount++
+		sumPre += *p.PreScore
+		sumPost += *p.PostScore
+	}
+	if result.PairedCount > 0 {
+		result.AvgPreScore = round2(sumPre / float64(result.PairedCount))
+		result.AvgPostScore = round2(sumPost / float64(result.PairedCount))
+		result.AvgImprovement = round2((sumPost - sumPre) / float64(result.PairedCount))
+	}
+
+	return result, nil
+}
+
+// GetAssessmentStats returns the average knowledge improvement grouped by event, instructor, school or region
+func (s *AssessmentService) GetAssessmentStats(groupBy string, params filter.BaseParams) (dto.AssessmentStatsResponse, error) {
+	if groupBy == "" {
+		groupBy = domainassessment.GroupByEvent
+	}
+
+	groups, err := s.AssessmentRepo.GetStats(groupBy, params)
+	if err != nil {
+		return dto.AssessmentStatsResponse{}, err
+	}
+	res := summarizeStats(groupBy, groups)
+
+	// An event with several instructors is counted in each of their groups, so the totals come from the events instead
+	if groupBy == domainassessment.GroupByInstructor {
+		events, err := s.AssessmentRepo.GetStats(domainassessment.GroupByEvent, params)
+		if err != nil {
+			return dto.AssessmentStatsResponse{}, err
+		}
+		totals := summarizeStats(groupBy, events)
+		totals.Groups = res.Groups
+		res = totals
+	}
+
+	return res, nil
+}
+
+// getAssessableEvent returns the event when its assessments can be changed, a cancelled event has no sessions to assess
+func (s *AssessmentService) getAssessableEvent(eventId string, scope filter.RegionScope) (domainevent.Event, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+	if strings.EqualFold(event.Status, utils.StsCancelled) {
+		return domainevent.Event{}, errors.New("cannot change the assessments of a cancelled event")
+	}
+	return event, nil
+}
+
+func newQuestions(bankId string, now time.Time, req []dto.AssessmentQuestion) ([]domainassessment.Question, error) {
+	questions := make([]domainassessment.Question, 0, len(req))
+	for i, item := range req {
+		options := make([]string, len(item.Options))
+		for j, option := range item.Options {
+			options[j] = strings.TrimSpace(option)
+		}
+		if item.CorrectOption >= len(options) {
+			return nil, fmt.Errorf("question %d: correct_option must be between 0 and %d", i+1, len(options)-1)
+		}
+
+		questions = append(questions, domainassessment.Question{
+			ID:            utils.CreateUUID(),
+			BankId:        bankId,
+			Question:      strings.TrimSpace(item.Question),
+			Options:       options,
+			CorrectOption: item.CorrectOption,
+			SortOrder:     i + 1,
+			CreatedAt:     now,
+		})
+	}
+	return questions, nil
+}
+
+// scoreAnswers counts the correct answers, unanswered questions count as wrong
+func scoreAnswers(questions []domainassessment.Question, answers map[string]int) (int, error) {
+	byId := make(map[string]domainassessment.Question, len(questions))
+	for _, q := range questions {
+		byId[q.ID] = q
+	}
+
+	correct := 0
+	for questionId, option := range answers {
+		q, ok := byId[questionId]
+		if !ok {
+			return 0, fmt.Errorf("question %s is not part of this assessment", questionId)
+		}
+		if option < 0 || option >= len(q.Options) {
+			return 0, fmt.Errorf("answer to question %s must be between 0 and %d", questionId, len(q.Options)-1)
+		}
+		if option == q.CorrectOption {
+			correct++
+		}
+	}
+	return correct, nil
+}
+
+// summarizeStats adds the overall averages, weighted by the number of participants of each group
+func summarizeStats(groupBy string, groups []dto.AssessmentStatsItem) dto.AssessmentStatsResponse {
+	res := dto.AssessmentStatsResponse{
+		GroupBy: groupBy,
+		Groups:  groups,
+	}
+	if res.Groups == nil {
+		res.Groups = []dto.AssessmentStatsItem{}
+	}
+
+	var sumPre, sumPost, sumImprovement float64
+	for _, g := range groups {
+		res.EventCount += g.EventCount
+		res.ParticipantCount += g.ParticipantCount
+		sumPre += g.AvgPreScore * float64(g.ParticipantCount)
+		sumPost += g.AvgPostScore * float64(g.ParticipantCount)
+		sumImprovement += g.AvgImprovement * float64(g.ParticipantCount)
+	}
+	if res.ParticipantCount > 0 {
+		res.AvgPreScore = round2(sumPre / float64(res.ParticipantCount))
+		res.AvgPostScore = round2(sumPost / float64(res.ParticipantCount))
+		res.AvgImprovement = round2(sumImprovement / float64(res.ParticipantCount))
+	}
+
+	return res
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+var _ interfaceassessment.ServiceAssessmentInterface = (*AssessmentService)(nil)
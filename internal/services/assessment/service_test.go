@@ -0,0 +1,113 @@
+package serviceassessment
+
+import (
+	"testing"
+	"time"
+
+	domainassessment "safety-riding/internal/domain/assessment"
+	"safety-riding/internal/dto"
+	interfaceassessment "safety-riding/internal/interfaces/assessment"
+	"safety-riding/pkg/filter"
+)
+
+func TestScoreAnswers(t *testing.T) {
+	questions := []domainassessment.Question{
+		{ID: "q1", Options: []string{"a", "b", "c"}, CorrectOption: 1},
+		{ID: "q2", Options: []string{"a", "b"}, CorrectOption: 0},
+		{ID: "q3", Options: []string{"a", "b", "c", "d"}, CorrectOption: 3},
+	}
+
+	cases := []struct {
+		name    string
+		answers map[string]int
+		want    int
+		wantErr bool
+	}{
+		{name: "all correct", answers: map[string]int{"q1": 1, "q2": 0, "q3": 3}, want: 3},
+		{name: "unanswered counts as wrong", answers: map[string]int{"q1": 1}, want: 1},
+		{name: "wrong answers", answers: map[string]int{"q1": 0, "q2": 1, "q3": 3}, want: 1},
+		{name: "unknown question", answers: map[string]int{"q9": 0}, wantErr: true},
+		{name: "option out of range", answers: map[string]int{"q2": 2}, wantErr: true},
+		{name: "negative option", answers: map[string]int{"q1": -1}, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := scoreAnswers(questions, c.answers)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("scoreAnswers() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if !c.wantErr && got != c.want {
+				t.Fatalf("scoreAnswers() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNewQuestions(t *testing.T) {
+	questions, err := newQuestions("bank-1", time.Now(), []dto.AssessmentQuestion{
+		{Question: " Helmet? ", Options: []string{" Yes ", "No"}, CorrectOption: 0},
+		{Question: "Speed limit near schools?", Options: []string{"20", "40", "60"}, CorrectOption: 0},
+	})
+	if err != nil {
+		t.Fatalf("newQuestions() error = %v", err)
+	}
+	if len(questions) != 2 || questions[0].Question != "Helmet?" || questions[0].Options[0] != "Yes" || questions[1].SortOrder != 2 || questions[1].BankId != "bank-1" {
+		t.Fatalf("newQuestions() = %+v", questions)
+	}
+
+	if _, err := newQuestions("bank-1", time.Now(), []dto.AssessmentQuestion{{Question: "?", Options: []string{"a", "b"}, CorrectOption: 2}}); err == nil {
+		t.Fatalf("newQuestions() accepted a correct option outside the options")
+	}
+}
+
+func TestSummarizeStats(t *testing.T) {
+	res := summarizeStats(domainassessment.GroupBySchool, []dto.AssessmentStatsItem{
+		{GroupId: "s1", EventCount: 2, ParticipantCount: 30, AvgPreScore: 50, AvgPostScore: 80, AvgImprovement: 30},
+		{GroupId: "s2", EventCount: 1, ParticipantCount: 10, AvgPreScore: 70, AvgPostScore: 80, AvgImprovement: 10},
+	})
+
+	if res.EventCount != 3 || res.ParticipantCount != 40 {
+		t.Fatalf("summarizeStats() counts = %d events, %d participants", res.EventCount, res.ParticipantCount)
+	}
+	if res.AvgPreScore != 55 || res.AvgPostScore != 80 || res.AvgImprovement != 25 {
+		t.Fatalf("summarizeStats() averages = %v / %v / %v, want 55 / 80 / 25", res.AvgPreScore, res.AvgPostScore, res.AvgImprovement)
+	}
+
+	if empty := summarizeStats(domainassessment.GroupByEvent, nil); empty.Groups == nil || empty.AvgImprovement != 0 {
+		t.Fatalf("summarizeStats(nil) = %+v", empty)
+	}
+}
+
+type statsRepo struct {
+	interfaceassessment.RepoAssessmentInterface
+	groups map[string][]dto.AssessmentStatsItem
+}
+
+func (r *statsRepo) GetStats(groupBy string, params filter.BaseParams) ([]dto.AssessmentStatsItem, error) {
+	return r.groups[groupBy], nil
+}
+
+func TestGetAssessmentStatsByInstructor(t *testing.T) {
+	// One event of 10 participants led by two instructors
+	repo := &statsRepo{groups: map[string][]dto.AssessmentStatsItem{
+		domainassessment.GroupByInstructor: {
+			{GroupId: "i1", EventCount: 1, ParticipantCount: 10, AvgPreScore: 50, AvgPostScore: 70, AvgImprovement: 20},
+			{GroupId: "i2", EventCount: 1, ParticipantCount: 10, AvgPreScore: 50, AvgPostScore: 70, AvgImprovement: 20},
+		},
+		domainassessment.GroupByEvent: {
+			{GroupId: "e1", EventCount: 1, ParticipantCount: 10, AvgPreScore: 50, AvgPostScore: 70, AvgImprovement: 20},
+		},
+	}}
+	svc := NewAssessmentService(nil, repo, nil, nil, nil)
+
+	res, err := svc.GetAssessmentStats(domainassessment.GroupByInstructor, filter.BaseParams{})
+	if err != nil {
+		t.Fatalf("GetAssessmentStats() error = %v", err)
+	}
+	if len(res.Groups) != 2 {
+		t.Fatalf("GetAssessmentStats() groups = %d, want 2", len(res.Groups))
+	}
+	if res.EventCount != 1 || res.ParticipantCount != 10 || res.AvgImprovement != 20 {
+		t.Fatalf("GetAssessmentStats() totals = %d events, %d participants, %v improvement", res.EventCount, res.ParticipantCount, res.AvgImprovement)
+	}
+}
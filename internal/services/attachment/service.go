@@ -0,0 +1,259 @@
+package serviceattachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	domainattachment "safety-riding/internal/domain/attachment"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	interfaceaccident "safety-riding/internal/interfaces/accident"
+	interfaceattachment "safety-riding/internal/interfaces/attachment"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfacebudget "safety-riding/internal/interfaces/budget"
+	interfaceevent "safety-riding/internal/interfaces/event"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"github.com/google/uuid"
+)
+
+// Folder holds the stored attachments, below the entity type and id of their parent
+const Folder = "attachments"
+
+const (
+	maxFilesPerUpload = 10
+	maxFileNameLength = 255
+)
+
+// contentTypes maps the accepted content types to the extension of the stored object
+var contentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type AttachmentService struct {
+	AttachmentRepo  interfaceattachment.RepoAttachmentInterface
+	EventRepo       interfaceevent.RepoEventInterface
+	AccidentRepo    interfaceaccident.RepoAccidentInterface
+	BudgetRepo      interfacebudget.RepoBudgetInterface
+	StorageProvider storage.StorageProvider
+	AuditRecorder   interfaceauditlog.AuditRecorder
+}
+
+func NewAttachmentService(attachmentRepo interfaceattachment.RepoAttachmentInterface, eventRepo interfaceevent.RepoEventInterface, accidentRepo interfaceaccident.RepoAccidentInterface, budgetRepo interfacebudget.RepoBudgetInterface, storageProvider storage.StorageProvider, auditRecorder interfaceauditlog.AuditRecorder) *AttachmentService {
+	return &AttachmentService{
+		AttachmentRepo:  attachmentRepo,
+		EventRepo:       eventRepo,
+		AccidentRepo:    accidentRepo,
+		BudgetRepo:      budgetRepo,
+		StorageProvider: storageProvider,
+		AuditRecorder:   auditRecorder,
+	}
+}
+
+// FetchAttachments lists the documents of a resource with download URLs that expire, the bucket is not public
+// so only users allowed to see the resource can download its documents
+func (s *AttachmentService) FetchAttachments(ctx context.Context, entityType, entityId string, scope filter.RegionScope) ([]domainattachment.Attachment, error) {
+	if err := s.checkParent(entityType, entityId, scope); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.AttachmentRepo.FetchByEntity(entityType, entityId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.presign(ctx, attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// AddAttachments stores documents with a resource, every file is checked before the first one is uploaded
+// so a rejected file leaves nothing behind. descriptions are matched to the files by position
+func (s *AttachmentService) AddAttachments(ctx context.Context, entityType, entityId, username string, scope filter.RegionScope, files []*multipart.FileHeader, descriptions []string) ([]domainattachment.Attachment, error) {
+	if err := s.checkParent(entityType, entityId, scope); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("at least one file is required")
+	}
+	if len(files) > maxFilesPerUpload {
+		return nil, fmt.Errorf("at most %d files can be uploaded at once", maxFilesPerUpload)
+	}
+
+	maxSize := int64(utils.GetEnv("ATTACHMENT_MAX_SIZE_MB", 10).(int)) << 20
+	types := make([]string, len(files))
+	for i, fileHeader := range files {
+		contentType, err := checkFile(fileHeader, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileHeader.Filename, err)
+		}
+		types[i] = contentType
+	}
+
+	now := time.Now()
+	folder := path.Join(Folder, entityType, entityId)
+	attachments := make([]domainattachment.Attachment, 0, len(files))
+	uploaded := make([]string, 0, len(files))
+	for i, fileHeader := range files {
+		objectName, err := s.upload(ctx, fileHeader, folder, types[i])
+		if err != nil {
+			s.deleteFiles(ctx, uploaded)
+			return nil, err
+		}
+		uploaded = append(uploaded, objectName)
+
+		description := ""
+		if i < len(descriptions) {
+			description = strings.TrimSpace(descriptions[i])
+		}
+		attachments = append(attachments, domainattachment.Attachment{
+			ID:          utils.CreateUUID(),
+			EntityType:  entityType,
+			EntityId:    entityId,
+			FileName:    fileName(fileHeader.Filename),
+			ObjectName:  objectName,
+			ContentType: types[i],
+			Size:        fileHeader.Size,
+			Description: description,
+			CreatedAt:   now,
+			CreatedBy:   username,
+		})
+	}
+
+	if err := s.AttachmentRepo.CreateBatch(attachments); err != nil {
+		s.deleteFiles(ctx, uploaded)
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityAttachment, attachment.ID, nil, attachment)
+	}
+
+	if err := s.presign(ctx, attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment soft-deletes the attachment and removes its file, a file that cannot be removed is left
+// to the storage garbage collector
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, entityType, entityId, id, username string, scope filter.RegionScope) error {
+	if err := s.checkParent(entityType, entityId, scope); err != nil {
+		return err
+	}
+
+	attachment, err := s.AttachmentRepo.GetByID(entityType, entityId, id)
+	if err != nil {
+		return err
+	}
+	if err := s.AttachmentRepo.Delete(id, username); err != nil {
+		return err
+	}
+	s.deleteFiles(ctx, []string{attachment.ObjectName})
+
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityAttachment, id, attachment, nil)
+	return nil
+}
+
+// checkParent makes sure the resource exists and is in the region scope of the user
+func (s *AttachmentService) checkParent(entityType, entityId string, scope filter.RegionScope) error {
+	var err error
+	switch entityType {
+	case domainattachment.EntityEvent:
+		_, err = s.EventRepo.GetByID(entityId, scope)
+	case domainattachment.EntityAccident:
+		_, err = s.AccidentRepo.GetByID(entityId, scope)
+	case domainattachment.EntityBudget:
+		_, err = s.BudgetRepo.GetByID(entityId, scope)
+	default:
+		err = fmt.Errorf("unknown attachment entity type '%s'", entityType)
+	}
+	return err
+}
+
+func (s *AttachmentService) upload(ctx context.Context, fileHeader *multipart.FileHeader, folder, contentType string) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	objectName := path.Join(folder, uuid.NewString()+contentTypes[contentType])
+	if err := s.StorageProvider.PutFile(ctx, objectName, file, fileHeader.Size, contentType); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// presign fills the download URL of each attachment
+func (s *AttachmentService) presign(ctx context.Context, attachments []domainattachment.Attachment) error {
+	expiry := time.Duration(utils.GetEnv("ATTACHMENT_URL_EXPIRY_SECONDS", 300).(int)) * time.Second
+	for i := range attachments {
+		fileURL, err := s.StorageProvider.PresignedGetURL(ctx, attachments[i].ObjectName, expiry)
+		if err != nil {
+			return err
+		}
+		attachments[i].FileUrl = fileURL
+	}
+	return nil
+}
+
+func (s *AttachmentService) deleteFiles(ctx context.Context, objectNames []string) {
+	for _, objectName := range objectNames {
+		_ = s.StorageProvider.DeleteFile(ctx, s.StorageProvider.GetFileURL(objectName))
+	}
+}
+
+// checkFile returns the content type of an upload sniffed from its first bytes
+func checkFile(fileHeader *multipart.FileHeader, maxSize int64) (string, error) {
+	if fileHeader.Size > maxSize {
+		return "", fmt.Errorf("%w, the limit is %d MB", domainattachment.ErrFileTooLarge, maxSize>>20)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", domainattachment.ErrFileType
+	}
+	return detectContentType(head[:n])
+}
+
+func detectContentType(head []byte) (string, error) {
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if _, ok := contentTypes[contentType]; !ok {
+		return "", domainattachment.ErrFileType
+	}
+	return contentType, nil
+}
+
+// fileName keeps the base name of an upload for display, without directories a browser may send
+func fileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == "/" || name == "" {
+		name = "attachment"
+	}
+	if len(name) > maxFileNameLength {
+		ext := filepath.Ext(name)
+		if len(ext) > 10 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:maxFileNameLength-len(ext)], "") + ext
+	}
+	return name
+}
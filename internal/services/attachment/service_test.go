@@ -0,0 +1,107 @@
+package serviceattachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domainattachment "safety-riding/internal/domain/attachment"
+	domainevent "safety-riding/internal/domain/event"
+	interfaceattachment "safety-riding/internal/interfaces/attachment"
+	interfaceevent "safety-riding/internal/interfaces/event"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/storage"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		head    []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "pdf", head: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), want: "application/pdf"},
+		{name: "jpeg", head: []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), want: "image/jpeg"},
+		{name: "png", head: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), want: "image/png"},
+		{name: "pdf extension on a script", head: []byte("#!/bin/sh\necho hello\n"), wantErr: true},
+		{name: "html", head: []byte("<html><body>invoice</body></html>"), wantErr: true},
+		{name: "zip based office file", head: []byte("PK\x03\x04\x14\x00\x06\x00"), wantErr: true},
+		{name: "empty", head: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectContentType(tt.head)
+			if tt.wantErr {
+				if !errors.Is(err, domainattachment.ErrFileType) {
+					t.Fatalf("detectContentType() error = %v, want ErrFileType", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("detectContentType() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileName(t *testing.T) {
+	long := strings.Repeat("a", 300) + ".pdf"
+
+	tests := map[string]string{
+		"police report.pdf":               "police report.pdf",
+		`C:\Users\staff\Desktop\scan.pdf`: "scan.pdf",
+		"../../etc/passwd":                "passwd",
+		"  ":                              "attachment",
+		long:                              strings.Repeat("a", 251) + ".pdf",
+	}
+	for name, want := range tests {
+		if got := fileName(name); got != want {
+			t.Errorf("fileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+type stubEventRepo struct {
+	interfaceevent.RepoEventInterface
+}
+
+func (stubEventRepo) GetByID(id string, scope filter.RegionScope) (domainevent.Event, error) {
+	return domainevent.Event{ID: id}, nil
+}
+
+type stubAttachmentRepo struct {
+	interfaceattachment.RepoAttachmentInterface
+	attachments []domainattachment.Attachment
+}
+
+func (r *stubAttachmentRepo) FetchByEntity(entityType, entityId string) ([]domainattachment.Attachment, error) {
+	return r.attachments, nil
+}
+
+// presignStorage signs every object with its expiry so the test can tell a presigned URL from a bucket URL
+type presignStorage struct {
+	storage.StorageProvider
+}
+
+func (presignStorage) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("https://bucket/%s?expires=%d", objectName, int(expiry.Seconds())), nil
+}
+
+func TestFetchAttachmentsPresignsDownloads(t *testing.T) {
+	repo := &stubAttachmentRepo{attachments: []domainattachment.Attachment{
+		{ID: "a1", ObjectName: "attachments/event/e1/report.pdf"},
+	}}
+	svc := NewAttachmentService(repo, stubEventRepo{}, nil, nil, presignStorage{}, nil)
+
+	attachments, err := svc.FetchAttachments(context.Background(), domainattachment.EntityEvent, "e1", filter.RegionScope{})
+	if err != nil {
+		t.Fatalf("FetchAttachments() error = %v", err)
+	}
+	if want := "https://bucket/attachments/event/e1/report.pdf?expires=300"; attachments[0].FileUrl != want {
+		t.Fatalf("FetchAttachments() file_url = %q, want %q", attachments[0].FileUrl, want)
+	}
+}
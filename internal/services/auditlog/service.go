@@ -0,0 +1,150 @@
+package serviceauditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/utils"
+	"time"
+)
+
+// ignoredDiffFields are bookkeeping fields that change on every write and carry no meaning in a diff
+var ignoredDiffFields = map[string]struct{}{
+	"created_at": {},
+	"created_by": {},
+	"updated_at": {},
+	"updated_by": {},
+}
+
+type AuditLogService struct {
+	AuditLogRepo interfaceauditlog.RepoAuditLogInterface
+}
+
+func NewAuditLogService(auditLogRepo interfaceauditlog.RepoAuditLogInterface) *AuditLogService {
+	return &AuditLogService{
+		AuditLogRepo: auditLogRepo,
+	}
+}
+
+// Record stores who did what to an entity together with its before/after snapshots.
+// Failures are only logged so auditing never breaks the operation being audited.
+func (s *AuditLogService) Record(actor, action, entityType, entityId string, before, after interface{}) {
+	if s == nil || s.AuditLogRepo == nil {
+		return
+	}
+
+	beforeData := snapshot(before)
+	afterData := snapshot(after)
+
+	data := domainauditlog.AuditLog{
+		ID:         utils.CreateUUID(),
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		BeforeData: marshalSnapshot(beforeData),
+		AfterData:  marshalSnapshot(afterData),
+		CreatedAt:  time.Now(),
+	}
+
+	if diff := buildDiff(beforeData, afterData); len(diff) > 0 {
+		data.Diff = marshalSnapshot(diff)
+	}
+
+	if err := s.AuditLogRepo.Create(data); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[AuditLogService][Record]; %s %s %s by %s; Error: %+v", action, entityType, entityId, actor, err))
+	}
+}
+
+func (s *AuditLogService) GetAuditLogById(id string) (domainauditlog.AuditLog, error) {
+	return s.AuditLogRepo.GetByID(id)
+}
+
+func (s *AuditLogService) FetchAuditLog(params filter.BaseParams) ([]domainauditlog.AuditLog, int64, error) {
+	return s.AuditLogRepo.Fetch(params)
+}
+
+// snapshot converts an entity into a flat map keyed by its json names.
+// Nested relations (objects and lists of objects) are dropped, they are audited on their own.
+func snapshot(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil
+	}
+
+	for key, value := range data {
+		switch val := value.(type) {
+		case map[string]interface{}:
+			delete(data, key)
+		case []interface{}:
+			if len(val) > 0 {
+				if _, ok := val[0].(map[string]interface{}); ok {
+					delete(data, key)
+				}
+			}
+		}
+	}
+
+	return data
+}
+
+// buildDiff returns the fields whose value differs between before and after
+func buildDiff(before, after map[string]interface{}) map[string]interface{} {
+	diff := make(map[string]interface{})
+
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	for key := range keys {
+		if _, ignored := ignoredDiffFields[key]; ignored {
+			continue
+		}
+
+		oldValue, newValue := before[key], after[key]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		diff[key] = map[string]interface{}{
+			"before": oldValue,
+			"after":  newValue,
+		}
+	}
+
+	return diff
+}
+
+func marshalSnapshot(data map[string]interface{}) json.RawMessage {
+	if data == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+var _ interfaceauditlog.ServiceAuditLogInterface = (*AuditLogService)(nil)
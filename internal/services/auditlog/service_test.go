@@ -0,0 +1,101 @@
+package serviceauditlog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	"safety-riding/pkg/filter"
+)
+
+type stubAuditLogRepo struct {
+	logs []domainauditlog.AuditLog
+	err  error
+}
+
+func (s *stubAuditLogRepo) Create(log domainauditlog.AuditLog) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+func (s *stubAuditLogRepo) GetByID(id string) (domainauditlog.AuditLog, error) {
+	return domainauditlog.AuditLog{}, nil
+}
+
+func (s *stubAuditLogRepo) Fetch(params filter.BaseParams) ([]domainauditlog.AuditLog, int64, error) {
+	return s.logs, int64(len(s.logs)), nil
+}
+
+type auditedEntity struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	Count     int               `json:"count"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Photos    []auditedEntity   `json:"photos,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+func TestRecord(t *testing.T) {
+	before := auditedEntity{ID: "1", Status: "completed", Count: 10, UpdatedAt: time.Now().Add(-time.Hour)}
+	after := before
+	after.Count = 12
+	after.UpdatedAt = time.Now()
+	after.Photos = []auditedEntity{{ID: "2"}}
+	after.Meta = map[string]string{"source": "import"}
+
+	tests := []struct {
+		name       string
+		action     string
+		before     interface{}
+		after      interface{}
+		wantFields []string
+	}{
+		{name: "create", action: domainauditlog.ActionCreate, before: nil, after: before, wantFields: []string{"id", "status", "count"}},
+		{name: "update ignores bookkeeping and relations", action: domainauditlog.ActionOverrideFinalized, before: before, after: after, wantFields: []string{"count"}},
+		{name: "unchanged update has no diff", action: domainauditlog.ActionUpdate, before: before, after: before, wantFields: nil},
+		{name: "delete", action: domainauditlog.ActionDelete, before: &before, after: (*auditedEntity)(nil), wantFields: []string{"id", "status", "count"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubAuditLogRepo{}
+			NewAuditLogService(repo).Record("admin", tt.action, domainauditlog.EntityEvent, "1", tt.before, tt.after)
+
+			if len(repo.logs) != 1 {
+				t.Fatalf("Record() stored %d logs, want 1", len(repo.logs))
+			}
+			log := repo.logs[0]
+			if log.Actor != "admin" || log.Action != tt.action || log.EntityType != domainauditlog.EntityEvent || log.EntityId != "1" {
+				t.Fatalf("Record() stored unexpected log %+v", log)
+			}
+
+			var diff map[string]interface{}
+			if len(log.Diff) > 0 {
+				if err := json.Unmarshal(log.Diff, &diff); err != nil {
+					t.Fatalf("diff is not valid json: %v", err)
+				}
+			}
+			if len(diff) != len(tt.wantFields) {
+				t.Fatalf("diff = %s, want fields %v", log.Diff, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := diff[field]; !ok {
+					t.Fatalf("diff = %s, missing field %s", log.Diff, field)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordIgnoresRepoError(t *testing.T) {
+	repo := &stubAuditLogRepo{err: errors.New("db down")}
+	NewAuditLogService(repo).Record("admin", domainauditlog.ActionDelete, domainauditlog.EntityRole, "1", nil, nil)
+
+	var nilService *AuditLogService
+	nilService.Record("admin", domainauditlog.ActionDelete, domainauditlog.EntityRole, "1", nil, nil)
+}
@@ -1,27 +1,52 @@
 package servicebudget
 
 import (
+	"errors"
 	"fmt"
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	"safety-riding/internal/domain/budget"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	interfacebudget "safety-riding/internal/interfaces/budget"
+	interfaceevent "safety-riding/internal/interfaces/event"
 	"safety-riding/pkg/filter"
 	"safety-riding/utils"
 	"strconv"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type BudgetService struct {
-	BudgetRepo interfacebudget.RepoBudgetInterface
+	BudgetRepo    interfacebudget.RepoBudgetInterface
+	EventRepo     interfaceevent.RepoEventInterface
+	AuditRecorder interfaceauditlog.AuditRecorder
 }
 
-func NewBudgetService(budgetRepo interfacebudget.RepoBudgetInterface) *BudgetService {
+func NewBudgetService(budgetRepo interfacebudget.RepoBudgetInterface, eventRepo interfaceevent.RepoEventInterface, auditRecorder interfaceauditlog.AuditRecorder) *BudgetService {
 	return &BudgetService{
-		BudgetRepo: budgetRepo,
+		BudgetRepo:    budgetRepo,
+		EventRepo:     eventRepo,
+		AuditRecorder: auditRecorder,
 	}
 }
 
+// checkEventScope makes sure a budget is only attached to an event inside the region scope
+func (s *BudgetService) checkEventScope(eventId string, scope filter.RegionScope) error {
+	if !scope.IsRestricted() {
+		return nil
+	}
+
+	if _, err := s.EventRepo.GetByID(eventId, scope); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return filter.ErrOutOfRegion
+		}
+		return err
+	}
+	return nil
+}
+
 func parseDateToMonthYear(dateStr string) (int, int, error) {
 	// Expected format: YYYY-MM-DD
 	parts := strings.Split(dateStr, "-")
@@ -46,12 +71,16 @@ func parseDateToMonthYear(dateStr string) (int, int, error) {
 	return month, year, nil
 }
 
-func (s *BudgetService) AddBudget(username string, req dto.AddEventBudget) (domainbudget.EventBudget, error) {
+func (s *BudgetService) AddBudget(username string, scope filter.RegionScope, req dto.AddEventBudget) (domainbudget.EventBudget, error) {
 	month, year, err := parseDateToMonthYear(req.BudgetDate)
 	if err != nil {
 		return domainbudget.EventBudget{}, err
 	}
 
+	if err := s.checkEventScope(req.EventId, scope); err != nil {
+		return domainbudget.EventBudget{}, err
+	}
+
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"budget_amount": req.BudgetAmount,
 		"actual_spent":  req.ActualSpent,
@@ -78,17 +107,18 @@ func (s *BudgetService) AddBudget(username string, req dto.AddEventBudget) (doma
 	if err := s.BudgetRepo.Create(data); err != nil {
 		return domainbudget.EventBudget{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityBudget, data.ID, nil, data)
 
 	return data, nil
 }
 
-func (s *BudgetService) GetBudgetById(id string) (domainbudget.EventBudget, error) {
-	return s.BudgetRepo.GetByID(id)
+func (s *BudgetService) GetBudgetById(id string, scope filter.RegionScope) (domainbudget.EventBudget, error) {
+	return s.BudgetRepo.GetByID(id, scope)
 }
 
-func (s *BudgetService) UpdateBudget(id, username string, canOverrideFinalized bool, req dto.UpdateEventBudget) (domainbudget.EventBudget, error) {
+func (s *BudgetService) UpdateBudget(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.UpdateEventBudget) (domainbudget.EventBudget, error) {
 	// Get existing budget
-	budget, err := s.BudgetRepo.GetByID(id)
+	budget, err := s.BudgetRepo.GetByID(id, scope)
 	if err != nil {
 		return domainbudget.EventBudget{}, err
 	}
@@ -99,9 +129,13 @@ func (s *BudgetService) UpdateBudget(id, username string, canOverrideFinalized b
 	if isFinalized && !canOverrideFinalized {
 		return domainbudget.EventBudget{}, fmt.Errorf("cannot update budget with status '%s'. Budget is already finalized", budget.Status)
 	}
+	before := budget
 
 	// Update fields if provided
-	if req.EventId != "" {
+	if req.EventId != "" && req.EventId != budget.EventId {
+		if err := s.checkEventScope(req.EventId, scope); err != nil {
+			return domainbudget.EventBudget{}, err
+		}
 		budget.EventId = req.EventId
 	}
 	if req.Category != "" {
@@ -139,6 +173,13 @@ func (s *BudgetService) UpdateBudget(id, username string, canOverrideFinalized b
 		return domainbudget.EventBudget{}, err
 	}
 
+	// Changes to a finalized budget are only possible with the override permission
+	action := domainauditlog.ActionUpdate
+	if isFinalized {
+		action = domainauditlog.ActionOverrideFinalized
+	}
+	s.AuditRecorder.Record(username, action, domainauditlog.EntityBudget, id, before, budget)
+
 	return budget, nil
 }
 
@@ -146,9 +187,9 @@ func (s *BudgetService) FetchBudget(params filter.BaseParams) ([]domainbudget.Ev
 	return s.BudgetRepo.Fetch(params)
 }
 
-func (s *BudgetService) DeleteBudget(id, username string, canOverrideFinalized bool) error {
+func (s *BudgetService) DeleteBudget(id, username string, canOverrideFinalized bool, scope filter.RegionScope) error {
 	// Check if budget exists
-	budget, err := s.BudgetRepo.GetByID(id)
+	budget, err := s.BudgetRepo.GetByID(id, scope)
 	if err != nil {
 		return err
 	}
@@ -166,28 +207,34 @@ func (s *BudgetService) DeleteBudget(id, username string, canOverrideFinalized b
 		return err
 	}
 
+	action := domainauditlog.ActionDelete
+	if isFinalized {
+		action = domainauditlog.ActionOverrideFinalized
+	}
+	s.AuditRecorder.Record(username, action, domainauditlog.EntityBudget, id, budget, nil)
+
 	return nil
 }
 
 // Aggregation methods
-func (s *BudgetService) GetBudgetsByEvent(eventId string) ([]domainbudget.EventBudget, error) {
-	return s.BudgetRepo.GetByEventID(eventId)
+func (s *BudgetService) GetBudgetsByEvent(eventId string, scope filter.RegionScope) ([]domainbudget.EventBudget, error) {
+	return s.BudgetRepo.GetByEventID(eventId, scope)
 }
 
-func (s *BudgetService) GetBudgetsByMonthYear(month, year int) ([]domainbudget.EventBudget, error) {
-	return s.BudgetRepo.GetByMonthYear(month, year)
+func (s *BudgetService) GetBudgetsByMonthYear(month, year int, scope filter.RegionScope) ([]domainbudget.EventBudget, error) {
+	return s.BudgetRepo.GetByMonthYear(month, year, scope)
 }
 
-func (s *BudgetService) GetMonthlySummary(month, year int) (domainbudget.BudgetSummary, error) {
-	return s.BudgetRepo.GetSummaryByMonth(month, year)
+func (s *BudgetService) GetMonthlySummary(month, year int, scope filter.RegionScope) (domainbudget.BudgetSummary, error) {
+	return s.BudgetRepo.GetSummaryByMonth(month, year, scope)
 }
 
-func (s *BudgetService) GetYearlySummary(year int) ([]domainbudget.BudgetSummary, error) {
-	return s.BudgetRepo.GetSummaryByYear(year)
+func (s *BudgetService) GetYearlySummary(year int, scope filter.RegionScope) ([]domainbudget.BudgetSummary, error) {
+	return s.BudgetRepo.GetSummaryByYear(year, scope)
 }
 
-func (s *BudgetService) GetEventSummary(eventId string) (domainbudget.BudgetSummary, error) {
-	return s.BudgetRepo.GetSummaryByEvent(eventId)
+func (s *BudgetService) GetEventSummary(eventId string, scope filter.RegionScope) (domainbudget.BudgetSummary, error) {
+	return s.BudgetRepo.GetSummaryByEvent(eventId, scope)
 }
 
 var _ interfacebudget.ServiceBudgetInterface = (*BudgetService)(nil)
@@ -0,0 +1,339 @@
+package servicecertificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domaincertificate "safety-riding/internal/domain/certificate"
+	domainevent "safety-riding/internal/domain/event"
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfacecertificate "safety-riding/internal/interfaces/certificate"
+	interfaceevent "safety-riding/internal/interfaces/event"
+	interfaceparticipant "safety-riding/internal/interfaces/participant"
+	"safety-riding/pkg/certificate"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/storage"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+const printedDateLayout = "2 January 2006"
+
+// Folder holds the individual PDFs and BatchFolder the merged ones, below the event id
+const (
+	Folder      = "certificates"
+	BatchFolder = "certificate-batches"
+)
+
+type CertificateService struct {
+	TemplateRepo    interfacecertificate.RepoTemplateInterface
+	CertificateRepo interfacecertificate.RepoCertificateInterface
+	EventRepo       interfaceevent.RepoEventInterface
+	ParticipantRepo interfaceparticipant.RepoParticipantInterface
+	StorageProvider storage.StorageProvider
+	AuditRecorder   interfaceauditlog.AuditRecorder
+}
+
+func NewCertificateService(templateRepo interfacecertificate.RepoTemplateInterface, certificateRepo interfacecertificate.RepoCertificateInterface, eventRepo interfaceevent.RepoEventInterface, participantRepo interfaceparticipant.RepoParticipantInterface, storageProvider storage.StorageProvider, auditRecorder interfaceauditlog.AuditRecorder) *CertificateService {
+	return &CertificateService{
+		TemplateRepo:    templateRepo,
+		CertificateRepo: certificateRepo,
+		EventRepo:       eventRepo,
+		ParticipantRepo: participantRepo,
+		StorageProvider: storageProvider,
+		AuditRecorder:   auditRecorder,
+	}
+}
+
+// GenerateCertificates renders the certificates of a completed event and stores the PDFs.
+// A participant keeps the verification code of an earlier certificate, so regenerating with another template
+// does not invalidate what was already printed.
+func (s *CertificateService) GenerateCertificates(ctx context.Context, eventId, username string, scope filter.RegionScope, req dto.GenerateCertificates) (dto.GenerateCertificatesResponse, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return dto.GenerateCertificatesResponse{}, err
+	}
+	if !strings.EqualFold(event.Status, utils.StsCompleted) {
+		return dto.GenerateCertificatesResponse{}, errors.New(messages.ErrEventNotCompleted)
+	}
+
+	template, err := s.TemplateRepo.GetByID(req.TemplateId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.GenerateCertificatesResponse{}, errors.New("certificate template not found")
+		}
+		return dto.GenerateCertificatesResponse{}, err
+	}
+	background, err := s.loadBackground(ctx, template.BackgroundUrl)
+	if err != nil {
+		return dto.GenerateCertificatesResponse{}, err
+	}
+
+	roster, err := s.ParticipantRepo.GetByEvent(eventId)
+	if err != nil {
+		return dto.GenerateCertificatesResponse{}, err
+	}
+	participants, err := selectParticipants(roster, req.ParticipantIds, req.CheckedInOnly)
+	if err != nil {
+		return dto.GenerateCertificatesResponse{}, err
+	}
+
+	issued, err := s.CertificateRepo.GetByEvent(eventId)
+	if err != nil {
+		return dto.GenerateCertificatesResponse{}, err
+	}
+	issuedByParticipant := make(map[string]domaincertificate.Certificate, len(issued))
+	for _, c := range issued {
+		issuedByParticipant[c.ParticipantId] = c
+	}
+
+	now := time.Now()
+	certificates := make([]domaincertificate.Certificate, 0, len(participants))
+	previous := make([]*domaincertificate.Certificate, 0, len(participants))
+	pages := make([]map[string]string, 0, len(participants))
+	for _, participant := range participants {
+		c, ok := issuedByParticipant[participant.ID]
+		if ok {
+			before := c
+			previous = append(previous, &before)
+		} else {
+			code, err := certificate.NewVerificationCode()
+			if err != nil {
+				return dto.GenerateCertificatesResponse{}, err
+			}
+			c = domaincertificate.Certificate{
+				ID:               utils.CreateUUID(),
+				EventId:          event.ID,
+				ParticipantId:    participant.ID,
+				VerificationCode: code,
+			}
+			previous = append(previous, nil)
+		}
+
+		c.TemplateId = template.ID
+		c.ParticipantName = participant.Name
+		c.Organization = participant.Organization
+		c.EventTitle = event.Title
+		c.EventDate = event.EventDate
+		c.IssuedAt = now
+		c.IssuedBy = username
+
+		certificates = append(certificates, c)
+		pages = append(pages, certificateValues(event, participant, c))
+	}
+
+	layout := certificate.Template{
+		PageSize:    template.PageSize,
+		Orientation: template.Orientation,
+		Background:  background,
+		Fields:      template.Fields,
+	}
+	res := dto.GenerateCertificatesResponse{
+		EventId:   event.ID,
+		Generated: len(certificates),
+	}
+
+	folder := Folder + "/" + event.ID
+	var (
+		uploaded      []string
+		batch         *domaincertificate.Batch
+		previousBatch domaincertificate.Batch
+	)
+	if req.Merged {
+		// The batch row is updated in place, its previous PDF is removed once the new one is saved
+		previousBatch, err = s.CertificateRepo.GetBatch(event.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.GenerateCertificatesResponse{}, err
+		}
+
+		data, err := certificate.Render(layout, pages)
+		if err != nil {
+			return dto.GenerateCertificatesResponse{}, err
+		}
+		if res.MergedUrl, err = s.StorageProvider.UploadFileFromBytes(ctx, data, "certificates.pdf", BatchFolder+"/"+event.ID, "application/pdf"); err != nil {
+			return dto.GenerateCertificatesResponse{}, fmt.Errorf("failed to upload certificates to storage: %w", err)
+		}
+		uploaded = append(uploaded, res.MergedUrl)
+
+		batch = &domaincertificate.Batch{
+			ID:               previousBatch.ID,
+			EventId:          event.ID,
+			TemplateId:       template.ID,
+			FileUrl:          res.MergedUrl,
+			CertificateCount: len(certificates),
+			GeneratedAt:      now,
+			GeneratedBy:      username,
+		}
+		if batch.ID == "" {
+			batch.ID = utils.CreateUUID()
+		}
+	} else {
+		for i := range certificates {
+			data, err := certificate.Render(layout, pages[i:i+1])
+			if err == nil {
+				certificates[i].FileUrl, err = s.StorageProvider.UploadFileFromBytes(ctx, data, "certificate.pdf", folder, "application/pdf")
+			}
+			if err != nil {
+				s.deleteFiles(ctx, uploaded)
+				return dto.GenerateCertificatesResponse{}, fmt.Errorf("failed to generate the certificate of %s: %w", certificates[i].ParticipantName, err)
+			}
+			uploaded = append(uploaded, certificates[i].FileUrl)
+		}
+	}
+
+	if err := s.CertificateRepo.Save(certificates, batch); err != nil {
+		s.deleteFiles(ctx, uploaded)
+		return dto.GenerateCertificatesResponse{}, err
+	}
+	if previousBatch.FileUrl != "" {
+		_ = s.StorageProvider.DeleteFile(ctx, previousBatch.FileUrl)
+	}
+
+	for i, c := range certificates {
+		if previous[i] == nil {
+			s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityCertificate, c.ID, nil, c)
+			continue
+		}
+		// The replaced individual PDF is no longer linked anywhere
+		if !req.Merged && previous[i].FileUrl != "" {
+			_ = s.StorageProvider.DeleteFile(ctx, previous[i].FileUrl)
+		}
+		s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityCertificate, c.ID, previous[i], c)
+	}
+
+	res.Certificates = certificates
+	return res, nil
+}
+
+func (s *CertificateService) FetchCertificates(eventId string, params filter.BaseParams) ([]domaincertificate.Certificate, int64, error) {
+	if _, err := s.EventRepo.GetByID(eventId, params.Scope); err != nil {
+		return nil, 0, err
+	}
+
+	return s.CertificateRepo.FetchByEvent(eventId, params)
+}
+
+// GetMergedCertificates returns the merged PDF of the latest merged generation of the event
+func (s *CertificateService) GetMergedCertificates(eventId string, scope filter.RegionScope) (domaincertificate.Batch, error) {
+	if _, err := s.EventRepo.GetByID(eventId, scope); err != nil {
+		return domaincertificate.Batch{}, err
+	}
+
+	return s.CertificateRepo.GetBatch(eventId)
+}
+
+// VerifyCertificate looks up the code printed on a certificate for the public verify page
+func (s *CertificateService) VerifyCertificate(code string) (dto.CertificateVerification, error) {
+	code = certificate.NormalizeVerificationCode(code)
+	if code == "" {
+		return dto.CertificateVerification{}, errors.New(messages.ErrInvalidCertificate)
+	}
+
+	c, err := s.CertificateRepo.GetByVerificationCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.CertificateVerification{}, errors.New(messages.ErrInvalidCertificate)
+		}
+		return dto.CertificateVerification{}, err
+	}
+
+	return dto.CertificateVerification{
+		VerificationCode: c.VerificationCode,
+		ParticipantName:  c.ParticipantName,
+		Organization:     c.Organization,
+		EventTitle:       c.EventTitle,
+		EventDate:        c.EventDate,
+		IssuedAt:         c.IssuedAt,
+	}, nil
+}
+
+// loadBackground downloads the template background, a template without background renders on a blank page
+func (s *CertificateService) loadBackground(ctx context.Context, backgroundURL string) ([]byte, error) {
+	if backgroundURL == "" {
+		return nil, nil
+	}
+
+	objectName := strings.TrimPrefix(backgroundURL, s.StorageProvider.GetFileURL(""))
+	object, err := s.StorageProvider.DownloadFile(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate background: %w", err)
+	}
+	return data, nil
+}
+
+func (s *CertificateService) deleteFiles(ctx context.Context, fileURLs []string) {
+	for _, fileURL := range fileURLs {
+		_ = s.StorageProvider.DeleteFile(ctx, fileURL)
+	}
+}
+
+// selectParticipants keeps the requested participants of the roster, every participant when none is requested
+func selectParticipants(roster []domainparticipant.EventParticipant, participantIds []string, checkedInOnly bool) ([]domainparticipant.EventParticipant, error) {
+	limited := len(participantIds) > 0
+	requested := make(map[string]bool, len(participantIds))
+	for _, id := range participantIds {
+		requested[id] = true
+	}
+
+	selected := make([]domainparticipant.EventParticipant, 0, len(roster))
+	for _, participant := range roster {
+		if limited && !requested[participant.ID] {
+			continue
+		}
+		// What is left in requested is not on the roster
+		delete(requested, participant.ID)
+		if checkedInOnly && participant.CheckedInAt == nil {
+			continue
+		}
+		selected = append(selected, participant)
+	}
+
+	if len(requested) > 0 {
+		missing := slices.Sorted(maps.Keys(requested))
+		return nil, fmt.Errorf("participants %s are not on the roster of this event", strings.Join(missing, ", "))
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("there are no participants to issue certificates to")
+	}
+	return selected, nil
+}
+
+// certificateValues returns the placeholder values printed on the certificate of a participant
+func certificateValues(event domainevent.Event, participant domainparticipant.EventParticipant, c domaincertificate.Certificate) map[string]string {
+	eventDate := event.EventDate
+	if date, err := time.Parse("2006-01-02", event.EventDate); err == nil {
+		eventDate = date.Format(printedDateLayout)
+	}
+	verifyURL := utils.GetEnv("CERTIFICATE_VERIFY_URL", "http://localhost:3000/certificates/verify").(string)
+
+	return map[string]string{
+		certificate.PlaceholderParticipantName:  c.ParticipantName,
+		certificate.PlaceholderOrganization:     c.Organization,
+		certificate.PlaceholderGrade:            participant.Grade,
+		certificate.PlaceholderEventTitle:       c.EventTitle,
+		certificate.PlaceholderEventDate:        eventDate,
+		certificate.PlaceholderEventLocation:    event.Location,
+		certificate.PlaceholderInstructorName:   event.InstructorName,
+		certificate.PlaceholderVerificationCode: c.VerificationCode,
+		certificate.PlaceholderVerifyURL:        verifyURL + "?code=" + url.QueryEscape(c.VerificationCode),
+		certificate.PlaceholderIssuedDate:       c.IssuedAt.Format(printedDateLayout),
+	}
+}
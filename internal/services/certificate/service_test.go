@@ -0,0 +1,86 @@
+package servicecertificate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domaincertificate "safety-riding/internal/domain/certificate"
+	domainevent "safety-riding/internal/domain/event"
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/pkg/certificate"
+)
+
+func TestSelectParticipants(t *testing.T) {
+	checkedIn := time.Now()
+	roster := []domainparticipant.EventParticipant{
+		{ID: "p1", Name: "Budi", CheckedInAt: &checkedIn},
+		{ID: "p2", Name: "Siti"},
+		{ID: "p3", Name: "Agus", CheckedInAt: &checkedIn},
+	}
+
+	tests := []struct {
+		name          string
+		ids           []string
+		checkedInOnly bool
+		want          []string
+		wantErr       bool
+	}{
+		{name: "whole roster", want: []string{"p1", "p2", "p3"}},
+		{name: "checked in only", checkedInOnly: true, want: []string{"p1", "p3"}},
+		{name: "requested participants", ids: []string{"p3", "p2"}, want: []string{"p2", "p3"}},
+		{name: "requested but not checked in", ids: []string{"p2"}, checkedInOnly: true, wantErr: true},
+		{name: "not on the roster", ids: []string{"p1", "p9"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectParticipants(roster, tt.ids, tt.checkedInOnly)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectParticipants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			ids := make([]string, 0, len(got))
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+			if !tt.wantErr && strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("selectParticipants() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestCertificateValues(t *testing.T) {
+	t.Setenv("CERTIFICATE_VERIFY_URL", "https://safety.example.com/verify")
+
+	event := domainevent.Event{Title: "Safety Riding Goes to School", EventDate: "2025-03-07", Location: "Aula", InstructorName: "Andi"}
+	participant := domainparticipant.EventParticipant{Name: "Budi", Grade: "XI"}
+	c := domaincertificate.Certificate{
+		ParticipantName:  "Budi",
+		Organization:     "SMA Negeri 1",
+		EventTitle:       event.Title,
+		VerificationCode: "ABCD-EFGH-JKMN",
+		IssuedAt:         time.Date(2025, 3, 8, 9, 0, 0, 0, time.Local),
+	}
+
+	values := certificateValues(event, participant, c)
+	want := map[string]string{
+		certificate.PlaceholderParticipantName:  "Budi",
+		certificate.PlaceholderGrade:            "XI",
+		certificate.PlaceholderEventDate:        "7 March 2025",
+		certificate.PlaceholderIssuedDate:       "8 March 2025",
+		certificate.PlaceholderVerifyURL:        "https://safety.example.com/verify?code=ABCD-EFGH-JKMN",
+		certificate.PlaceholderInstructorName:   "Andi",
+		certificate.PlaceholderVerificationCode: "ABCD-EFGH-JKMN",
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Fatalf("certificateValues()[%s] = %q, want %q", key, values[key], value)
+		}
+	}
+	for _, placeholder := range certificate.Placeholders() {
+		if _, ok := values[placeholder]; !ok {
+			t.Fatalf("certificateValues() has no value for {{%s}}", placeholder)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package servicecertificate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domaincertificate "safety-riding/internal/domain/certificate"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/certificate"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+func (s *CertificateService) AddTemplate(username string, req dto.AddCertificateTemplate) (domaincertificate.Template, error) {
+	if err := certificate.ValidateFields(req.Fields); err != nil {
+		return domaincertificate.Template{}, err
+	}
+
+	template := domaincertificate.Template{
+		ID:          utils.CreateUUID(),
+		Name:        strings.TrimSpace(req.Name),
+		PageSize:    certificate.PageA4,
+		Orientation: certificate.OrientationLandscape,
+		Fields:      req.Fields,
+		CreatedAt:   time.Now(),
+		CreatedBy:   username,
+	}
+	if req.PageSize != "" {
+		template.PageSize = req.PageSize
+	}
+	if req.Orientation != "" {
+		template.Orientation = req.Orientation
+	}
+
+	if err := s.TemplateRepo.Create(template); err != nil {
+		return domaincertificate.Template{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityCertificateTemplate, template.ID, nil, template)
+
+	return template, nil
+}
+
+func (s *CertificateService) GetTemplateById(id string) (domaincertificate.Template, error) {
+	return s.TemplateRepo.GetByID(id)
+}
+
+// UpdateTemplate changes the layout used by the next generation, certificates already issued keep their PDF
+func (s *CertificateService) UpdateTemplate(id, username string, req dto.UpdateCertificateTemplate) (domaincertificate.Template, error) {
+	template, err := s.TemplateRepo.GetByID(id)
+	if err != nil {
+		return domaincertificate.Template{}, err
+	}
+	before := template
+
+	if req.Name != "" {
+		template.Name = strings.TrimSpace(req.Name)
+	}
+	if req.PageSize != "" {
+		template.PageSize = req.PageSize
+	}
+	if req.Orientation != "" {
+		template.Orientation = req.Orientation
+	}
+	if len(req.Fields) > 0 {
+		if err := certificate.ValidateFields(req.Fields); err != nil {
+			return domaincertificate.Template{}, err
+		}
+		template.Fields = req.Fields
+	}
+
+	now := time.Now()
+	template.UpdatedAt = &now
+	template.UpdatedBy = username
+	if err := s.TemplateRepo.Update(template); err != nil {
+		return domaincertificate.Template{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityCertificateTemplate, template.ID, before, template)
+
+	return template, nil
+}
+
+// UploadTemplateBackground stores a JPEG or PNG background and replaces the previous one
+func (s *CertificateService) UploadTemplateBackground(ctx context.Context, id, username string, fileHeader *multipart.FileHeader) (domaincertificate.Template, error) {
+	template, err := s.TemplateRepo.GetByID(id)
+	if err != nil {
+		return domaincertificate.Template{}, err
+	}
+	before := template
+
+	if err := utils.ValidatePhotoFileSize(fileHeader); err != nil {
+		return domaincertificate.Template{}, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return domaincertificate.Template{}, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return domaincertificate.Template{}, fmt.Errorf("failed to read file %s: %w", fileHeader.Filename, err)
+	}
+	if contentType := http.DetectContentType(head[:n]); contentType != "image/jpeg" && contentType != "image/png" {
+		return domaincertificate.Template{}, fmt.Errorf("certificate background must be a JPEG or PNG image")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return domaincertificate.Template{}, err
+	}
+
+	backgroundURL, err := s.StorageProvider.UploadFile(ctx, file, fileHeader, "certificate-templates")
+	if err != nil {
+		return domaincertificate.Template{}, fmt.Errorf("failed to upload file %s to storage: %w", fileHeader.Filename, err)
+	}
+
+	now := time.Now()
+	template.BackgroundUrl = backgroundURL
+	template.UpdatedAt = &now
+	template.UpdatedBy = username
+	if err := s.TemplateRepo.Update(template); err != nil {
+		_ = s.StorageProvider.DeleteFile(ctx, backgroundURL)
+		return domaincertificate.Template{}, err
+	}
+	if before.BackgroundUrl != "" {
+		_ = s.StorageProvider.DeleteFile(ctx, before.BackgroundUrl)
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityCertificateTemplate, template.ID, before, template)
+
+	return template, nil
+}
+
+func (s *CertificateService) FetchTemplate(params filter.BaseParams) ([]domaincertificate.Template, int64, error) {
+	return s.TemplateRepo.Fetch(params)
+}
+
+func (s *CertificateService) DeleteTemplate(id, username string) error {
+	template, err := s.TemplateRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.TemplateRepo.Delete(id); err != nil {
+		return err
+	}
+	if template.BackgroundUrl != "" {
+		_ = s.StorageProvider.DeleteFile(context.Background(), template.BackgroundUrl)
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityCertificateTemplate, id, template, nil)
+
+	return nil
+}
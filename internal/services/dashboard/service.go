@@ -3,6 +3,7 @@ package servicedashboard
 import (
 	"safety-riding/internal/dto"
 	interfacedashboard "safety-riding/internal/interfaces/dashboard"
+	"safety-riding/pkg/filter"
 )
 
 type DashboardService struct {
@@ -13,19 +14,19 @@ func NewDashboardService(dashboardRepo interfacedashboard.RepoDashboardInterface
 	return &DashboardService{DashboardRepo: dashboardRepo}
 }
 
-func (s *DashboardService) GetSummary() (dto.DashboardSummary, error) {
+func (s *DashboardService) GetSummary(scope filter.RegionScope) (dto.DashboardSummary, error) {
 	// Implement existing GetSummary if needed
 	return dto.DashboardSummary{}, nil
 }
 
-func (s *DashboardService) GetStats() (*dto.DashboardStats, error) {
-	stats, err := s.DashboardRepo.GetStats()
+func (s *DashboardService) GetStats(scope filter.RegionScope) (*dto.DashboardStats, error) {
+	stats, err := s.DashboardRepo.GetStats(scope)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get accident recommendations separately
-	recommendations, err := s.DashboardRepo.GetAccidentRecommendations()
+	recommendations, err := s.DashboardRepo.GetAccidentRecommendations(scope)
 	if err != nil {
 		// Don't fail the whole request if recommendations fail
 		recommendations = []dto.AccidentRecommendation{}
@@ -35,6 +36,6 @@ func (s *DashboardService) GetStats() (*dto.DashboardStats, error) {
 	return stats, nil
 }
 
-func (s *DashboardService) GetAccidentRecommendations() ([]dto.AccidentRecommendation, error) {
-	return s.DashboardRepo.GetAccidentRecommendations()
+func (s *DashboardService) GetAccidentRecommendations(scope filter.RegionScope) ([]dto.AccidentRecommendation, error) {
+	return s.DashboardRepo.GetAccidentRecommendations(scope)
 }
@@ -0,0 +1,176 @@
+package serviceevent
+
+import (
+	"strings"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/ical"
+	"safety-riding/utils"
+)
+
+const (
+	calendarDateLayout   = "2006-01-02"
+	calendarFeedPastDays = 90 // how far back the feed goes, older events stay in the subscriber's calendar
+)
+
+// GetCalendar returns the events between from and to grouped by day, days without events are left out
+func (s *EventService) GetCalendar(from, to time.Time, scope filter.RegionScope) ([]dto.EventCalendarDay, error) {
+	events, err := s.EventRepo.FetchByDateRange(from.Format(calendarDateLayout), to.Format(calendarDateLayout), scope)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]dto.EventCalendarDay, 0)
+	for _, e := range events {
+		item := dto.EventCalendarItem{
+			ID:        e.ID,
+			Title:     e.Title,
+			EventType: e.EventType,
+			Status:    e.Status,
+			StartTime: e.StartTime,
+			EndTime:   e.EndTime,
+			Location:  e.Location,
+			VenueName: eventVenueName(e),
+		}
+
+		// events are ordered by date, so a new day starts whenever the date changes
+		if n := len(days); n > 0 && days[n-1].Date == e.EventDate {
+			days[n-1].Events = append(days[n-1].Events, item)
+			continue
+		}
+		days = append(days, dto.EventCalendarDay{Date: e.EventDate, Events: []dto.EventCalendarItem{item}})
+	}
+
+	return days, nil
+}
+
+// CreateCalendarFeed issues a new feed token for the user, the previous feed URL stops working
+func (s *EventService) CreateCalendarFeed(userId string) (dto.CalendarFeed, error) {
+	if err := s.UserTokenRepo.RevokeByUser(userId, domainauth.TokenPurposeCalendarFeed); err != nil {
+		return dto.CalendarFeed{}, err
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return dto.CalendarFeed{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.AddDate(0, 0, utils.GetEnv("CALENDAR_FEED_TOKEN_TTL_DAYS", 365).(int))
+	if err = s.UserTokenRepo.Store(domainauth.UserToken{
+		ID:        utils.CreateUUID(),
+		UserId:    userId,
+		Purpose:   domainauth.TokenPurposeCalendarFeed,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return dto.CalendarFeed{}, err
+	}
+
+	feedURL := strings.TrimRight(utils.GetEnv("CALENDAR_FEED_URL", "http://localhost:8080/api/events/calendar/feed").(string), "/")
+	return dto.CalendarFeed{
+		URL:       feedURL + "/" + token + ".ics",
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RevokeCalendarFeed disables the current feed URL of the user
+func (s *EventService) RevokeCalendarFeed(userId string) error {
+	return s.UserTokenRepo.RevokeByUser(userId, domainauth.TokenPurposeCalendarFeed)
+}
+
+// GetCalendarFeed builds the iCalendar feed, cancelled and deleted events are published as STATUS:CANCELLED
+func (s *EventService) GetCalendarFeed(scope filter.RegionScope) (ical.Calendar, error) {
+	events, err := s.EventRepo.FetchForFeed(time.Now().AddDate(0, 0, -calendarFeedPastDays), scope)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	calendar := ical.Calendar{
+		ProdID: "-//Safety Riding//Events//EN",
+		Name:   "Safety Riding Events",
+		Events: make([]ical.Event, 0, len(events)),
+	}
+	for _, e := range events {
+		item, ok := feedEvent(e)
+		if !ok {
+			continue
+		}
+		calendar.Events = append(calendar.Events, item)
+	}
+
+	return calendar, nil
+}
+
+// feedEvent converts an event into a VEVENT, it reports false when the event date cannot be parsed.
+// Dates and times are stored in local time, an event without a valid start time becomes an all day event.
+func feedEvent(e domainevent.Event) (ical.Event, bool) {
+	date, err := time.ParseInLocation(calendarDateLayout, e.EventDate, time.Local)
+	if err != nil {
+		return ical.Event{}, false
+	}
+
+	item := ical.Event{
+		UID:          e.ID + "@safety-riding",
+		Summary:      e.Title,
+		Description:  e.Description,
+		Location:     e.Location,
+		Status:       feedStatus(e),
+		LastModified: e.CreatedAt,
+	}
+	if item.Location == "" {
+		item.Location = eventVenueName(e)
+	}
+	if e.UpdatedAt.After(item.LastModified) {
+		item.LastModified = e.UpdatedAt
+	}
+	if e.DeletedAt.Valid && e.DeletedAt.Time.After(item.LastModified) {
+		item.LastModified = e.DeletedAt.Time
+	}
+
+	start, err := utils.ParseEventDateTime(e.EventDate, e.StartTime)
+	if err != nil {
+		item.AllDay = true
+		item.Start, item.End = date, date.AddDate(0, 0, 1)
+		return item, true
+	}
+	item.Start = inLocal(start)
+
+	item.End = item.Start.Add(time.Hour)
+	if end, err := utils.ParseEventDateTime(e.EventDate, e.EndTime); err == nil && inLocal(end).After(item.Start) {
+		item.End = inLocal(end)
+	}
+
+	return item, true
+}
+
+func feedStatus(e domainevent.Event) string {
+	switch {
+	case e.DeletedAt.Valid, strings.EqualFold(e.Status, utils.StsCancelled):
+		return ical.StatusCancelled
+	case strings.EqualFold(e.Status, utils.StsPending), strings.EqualFold(e.Status, utils.StsPlanned):
+		return ical.StatusTentative
+	default:
+		return ical.StatusConfirmed
+	}
+}
+
+// inLocal reads the wall clock of a parsed UTC time as local time
+func inLocal(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+}
+
+func eventVenueName(e domainevent.Event) string {
+	if e.School != nil {
+		return e.School.Name
+	}
+	if e.Public != nil {
+		return e.Public.Name
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package serviceevent
+
+import (
+	"testing"
+	"time"
+
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/pkg/ical"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+func TestFeedEvent(t *testing.T) {
+	created := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	t.Run("timed event uses local wall clock", func(t *testing.T) {
+		item, ok := feedEvent(domainevent.Event{ID: "e1", Title: "Clinic", EventDate: "2026-02-01", StartTime: "08:00", EndTime: "10:30:00", Status: utils.StsConfirmed, CreatedAt: created})
+		if !ok {
+			t.Fatalf("feedEvent() skipped a valid event")
+		}
+		wantStart := time.Date(2026, 2, 1, 8, 0, 0, 0, time.Local)
+		if !item.Start.Equal(wantStart) || !item.End.Equal(wantStart.Add(150*time.Minute)) || item.AllDay {
+			t.Fatalf("feedEvent() = %v - %v (all day %v), want %v - 10:30", item.Start, item.End, item.AllDay, wantStart)
+		}
+		if item.Status != ical.StatusConfirmed || item.UID != "e1@safety-riding" {
+			t.Fatalf("feedEvent() status = %s, uid = %s", item.Status, item.UID)
+		}
+	})
+
+	t.Run("invalid start time becomes an all day event", func(t *testing.T) {
+		item, ok := feedEvent(domainevent.Event{ID: "e2", EventDate: "2026-02-01", StartTime: "morning", CreatedAt: created})
+		if !ok || !item.AllDay || !item.End.Equal(item.Start.AddDate(0, 0, 1)) {
+			t.Fatalf("feedEvent() = %+v, %v, want an all day event", item, ok)
+		}
+	})
+
+	t.Run("end before start lasts an hour", func(t *testing.T) {
+		item, _ := feedEvent(domainevent.Event{ID: "e3", EventDate: "2026-02-01", StartTime: "14:00", EndTime: "09:00", CreatedAt: created})
+		if item.End.Sub(item.Start) != time.Hour {
+			t.Fatalf("feedEvent() duration = %v, want 1h", item.End.Sub(item.Start))
+		}
+	})
+
+	t.Run("invalid date is skipped", func(t *testing.T) {
+		if _, ok := feedEvent(domainevent.Event{ID: "e4", EventDate: "01/02/2026"}); ok {
+			t.Fatalf("feedEvent() accepted an invalid date")
+		}
+	})
+
+	t.Run("cancelled and deleted events are cancelled", func(t *testing.T) {
+		deletedAt := created.Add(48 * time.Hour)
+		cases := []struct {
+			event domainevent.Event
+			want  string
+		}{
+			{domainevent.Event{Status: utils.StsCancelled}, ical.StatusCancelled},
+			{domainevent.Event{Status: utils.StsConfirmed, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}, ical.StatusCancelled},
+			{domainevent.Event{Status: utils.StsPlanned}, ical.StatusTentative},
+			{domainevent.Event{Status: utils.StsCompleted}, ical.StatusConfirmed},
+		}
+		for _, c := range cases {
+			c.event.EventDate, c.event.CreatedAt = "2026-02-01", created
+			item, _ := feedEvent(c.event)
+			if item.Status != c.want {
+				t.Fatalf("feedEvent(status %q, deleted %v) = %s, want %s", c.event.Status, c.event.DeletedAt.Valid, item.Status, c.want)
+			}
+			if c.event.DeletedAt.Valid && !item.LastModified.Equal(deletedAt) {
+				t.Fatalf("deleted event LAST-MODIFIED = %v, want %v", item.LastModified, deletedAt)
+			}
+		}
+	})
+}
@@ -1,46 +1,40 @@
 package serviceevent
 
 import (
+	"slices"
+	"strings"
+
+	domainevent "safety-riding/internal/domain/event"
 	"safety-riding/utils"
-	"time"
 )
 
-// markSchoolAsEducated updates school to educated status when event is completed
-func (s *EventService) markSchoolAsEducated(schoolId, username, eventDate, endTime string) error {
-	school, err := s.SchoolRepo.GetByID(schoolId)
-	if err != nil {
-		return nil // Ignore error if school not found
+// refreshVisits recomputes the visit statistics of the schools and public entities of the given events
+// when one of them is, or was, completed. Pass the event before and after a change so a venue losing a visit is updated too.
+func (s *EventService) refreshVisits(events ...domainevent.Event) error {
+	completed := false
+	for _, event := range events {
+		if strings.EqualFold(event.Status, utils.StsCompleted) {
+			completed = true
+			break
+		}
 	}
-
-	school.IsEducated = true
-	school.VisitCount++
-	school.LastVisitAt = s.getEventDateTime(eventDate, endTime)
-	school.UpdatedBy = username
-
-	return s.SchoolRepo.Update(school)
-}
-
-// markPublicAsEducated updates public entity to educated status when event is completed
-func (s *EventService) markPublicAsEducated(publicId, username, eventDate, endTime string) error {
-	public, err := s.PublicRepo.GetByID(publicId)
-	if err != nil {
-		return nil // Ignore error if public not found
+	if !completed {
+		return nil
 	}
 
-	public.IsEducated = true
-	public.VisitCount++
-	public.LastVisitAt = s.getEventDateTime(eventDate, endTime)
-	public.UpdatedBy = username
-
-	return s.PublicRepo.Update(public)
+	schoolIds, publicIds := visitVenues(events)
+	return s.VisitRepo.Recompute(schoolIds, publicIds)
 }
 
-// getEventDateTime parses event date and time, fallback to current time if parsing fails
-func (s *EventService) getEventDateTime(eventDate, endTime string) *time.Time {
-	eventDateTime, err := utils.ParseEventDateTime(eventDate, endTime)
-	if err != nil {
-		now := time.Now()
-		return &now
+// visitVenues returns the distinct schools and public entities the events are held at
+func visitVenues(events []domainevent.Event) (schoolIds, publicIds []string) {
+	for _, event := range events {
+		if event.SchoolId != nil && *event.SchoolId != "" && !slices.Contains(schoolIds, *event.SchoolId) {
+			schoolIds = append(schoolIds, *event.SchoolId)
+		}
+		if event.PublicId != nil && *event.PublicId != "" && !slices.Contains(publicIds, *event.PublicId) {
+			publicIds = append(publicIds, *event.PublicId)
+		}
 	}
-	return &eventDateTime
+	return schoolIds, publicIds
 }
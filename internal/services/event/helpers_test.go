@@ -0,0 +1,26 @@
+package serviceevent
+
+import (
+	"slices"
+	"testing"
+
+	domainevent "safety-riding/internal/domain/event"
+)
+
+func TestVisitVenues(t *testing.T) {
+	schoolA, schoolB, public, empty := "school-a", "school-b", "public-a", ""
+	events := []domainevent.Event{
+		{SchoolId: &schoolA},
+		{SchoolId: &schoolB, PublicId: &empty},
+		{SchoolId: &schoolA, PublicId: &public},
+		{},
+	}
+
+	schoolIds, publicIds := visitVenues(events)
+	if !slices.Equal(schoolIds, []string{schoolA, schoolB}) {
+		t.Fatalf("visitVenues() schools = %v, want [%s %s]", schoolIds, schoolA, schoolB)
+	}
+	if !slices.Equal(publicIds, []string{public}) {
+		t.Fatalf("visitVenues() publics = %v, want [%s]", publicIds, public)
+	}
+}
@@ -0,0 +1,135 @@
+package serviceevent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainevent "safety-riding/internal/domain/event"
+	domaininstructor "safety-riding/internal/domain/instructor"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+// resolveInstructors loads the instructors to assign in the requested order, each one must exist and be active
+func (s *EventService) resolveInstructors(instructorIds []string) ([]domaininstructor.Instructor, error) {
+	ids := make([]string, 0, len(instructorIds))
+	for _, id := range instructorIds {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.InstructorRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]domaininstructor.Instructor, len(found))
+	for _, instructor := range found {
+		byId[instructor.ID] = instructor
+	}
+
+	instructors := make([]domaininstructor.Instructor, 0, len(ids))
+	for _, id := range ids {
+		instructor, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("instructor %s not found", id)
+		}
+		if !instructor.IsActive {
+			return nil, fmt.Errorf("instructor %s is inactive", instructor.Name)
+		}
+		instructors = append(instructors, instructor)
+	}
+
+	return instructors, nil
+}
+
+// scheduleConflicts returns the bookings of the instructors that overlap the event.
+// Bookings are checked across every region, an instructor cannot be in two places at once.
+func (s *EventService) scheduleConflicts(event domainevent.Event, instructors []domaininstructor.Instructor) ([]domaininstructor.Booking, error) {
+	if len(instructors) == 0 || strings.EqualFold(event.Status, utils.StsCancelled) {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(instructors))
+	for _, instructor := range instructors {
+		ids = append(ids, instructor.ID)
+	}
+
+	bookings, err := s.InstructorRepo.FetchBookings(ids, event.EventDate, event.EventDate, event.ID, filter.RegionScope{})
+	if err != nil {
+		return nil, err
+	}
+
+	return overlappingBookings(event, bookings), nil
+}
+
+// assignInstructors replaces the instructors of the event, the first instructor leads it
+func (s *EventService) assignInstructors(eventId, username string, instructors []domaininstructor.Instructor) error {
+	now := time.Now()
+	assignments := make([]domaininstructor.EventInstructor, 0, len(instructors))
+	for i, instructor := range instructors {
+		role := domaininstructor.RoleAssistant
+		if i == 0 {
+			role = domaininstructor.RoleLead
+		}
+		assignments = append(assignments, domaininstructor.EventInstructor{
+			EventId:      eventId,
+			InstructorId: instructor.ID,
+			Role:         role,
+			CreatedAt:    now,
+			CreatedBy:    username,
+		})
+	}
+
+	return s.InstructorRepo.SetEventInstructors(eventId, assignments)
+}
+
+// overlappingBookings keeps the bookings whose time range overlaps the event, ranges touching end to start do not overlap
+func overlappingBookings(event domainevent.Event, bookings []domaininstructor.Booking) []domaininstructor.Booking {
+	start, end := eventWindow(event.EventDate, event.StartTime, event.EndTime)
+
+	var conflicts []domaininstructor.Booking
+	for _, b := range bookings {
+		bookingStart, bookingEnd := eventWindow(b.EventDate, b.StartTime, b.EndTime)
+		if bookingStart.Before(end) && start.Before(bookingEnd) {
+			conflicts = append(conflicts, b)
+		}
+	}
+	return conflicts
+}
+
+// eventWindow returns the time range of an event.
+// Without a valid start time the event takes the whole day, without a valid end time it lasts until the end of the day.
+func eventWindow(eventDate, startTime, endTime string) (time.Time, time.Time) {
+	day, err := utils.ParseEventDateTime(eventDate, "00:00")
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	endOfDay := day.AddDate(0, 0, 1)
+
+	start, err := utils.ParseEventDateTime(eventDate, startTime)
+	if err != nil {
+		return day, endOfDay
+	}
+	end, err := utils.ParseEventDateTime(eventDate, endTime)
+	if err != nil || !end.After(start) {
+		return start, endOfDay
+	}
+	return start, end
+}
+
+// sameInstructors reports whether the event is already assigned exactly these instructors with the same lead
+func sameInstructors(assigned []domaininstructor.EventInstructor, instructors []domaininstructor.Instructor) bool {
+	if len(assigned) != len(instructors) {
+		return false
+	}
+	for _, a := range assigned {
+		idx := slices.IndexFunc(instructors, func(i domaininstructor.Instructor) bool { return i.ID == a.InstructorId })
+		if idx < 0 || (idx == 0) != (a.Role == domaininstructor.RoleLead) {
+			return false
+		}
+	}
+	return true
+}
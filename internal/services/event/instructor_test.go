@@ -0,0 +1,67 @@
+package serviceevent
+
+import (
+	"strings"
+	"testing"
+
+	domainevent "safety-riding/internal/domain/event"
+	domaininstructor "safety-riding/internal/domain/instructor"
+)
+
+func TestOverlappingBookings(t *testing.T) {
+	event := domainevent.Event{EventDate: "2026-03-10", StartTime: "09:00", EndTime: "12:00"}
+	bookings := []domaininstructor.Booking{
+		{EventId: "before", EventDate: "2026-03-10", StartTime: "07:00", EndTime: "09:00"},
+		{EventId: "overlap-start", EventDate: "2026-03-10", StartTime: "08:00", EndTime: "09:30:00"},
+		{EventId: "inside", EventDate: "2026-03-10", StartTime: "10:00", EndTime: "11:00"},
+		{EventId: "after", EventDate: "2026-03-10", StartTime: "12:00", EndTime: "14:00"},
+		{EventId: "no-end", EventDate: "2026-03-10", StartTime: "11:30", EndTime: ""},
+		{EventId: "whole-day", EventDate: "2026-03-10", StartTime: "tbd", EndTime: "tbd"},
+		{EventId: "evening", EventDate: "2026-03-10", StartTime: "18:00", EndTime: "20:00"},
+	}
+
+	got := overlappingBookings(event, bookings)
+	ids := make([]string, 0, len(got))
+	for _, b := range got {
+		ids = append(ids, b.EventId)
+	}
+	want := "overlap-start,inside,no-end,whole-day"
+	if strings.Join(ids, ",") != want {
+		t.Fatalf("overlappingBookings() = %v, want %s", ids, want)
+	}
+
+	allDay := domainevent.Event{EventDate: "2026-03-10"}
+	if got := overlappingBookings(allDay, bookings[len(bookings)-1:]); len(got) != 1 {
+		t.Fatalf("an event without times should conflict with every booking of the day, got %v", got)
+	}
+}
+
+func TestSameInstructors(t *testing.T) {
+	assigned := []domaininstructor.EventInstructor{
+		{InstructorId: "a", Role: domaininstructor.RoleLead},
+		{InstructorId: "b", Role: domaininstructor.RoleAssistant},
+	}
+
+	tests := []struct {
+		name string
+		ids  []string
+		want bool
+	}{
+		{name: "same order", ids: []string{"a", "b"}, want: true},
+		{name: "other lead", ids: []string{"b", "a"}, want: false},
+		{name: "instructor added", ids: []string{"a", "b", "c"}, want: false},
+		{name: "instructor replaced", ids: []string{"a", "c"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructors := make([]domaininstructor.Instructor, 0, len(tt.ids))
+			for _, id := range tt.ids {
+				instructors = append(instructors, domaininstructor.Instructor{ID: id})
+			}
+			if got := sameInstructors(assigned, instructors); got != tt.want {
+				t.Fatalf("sameInstructors(%v) = %v, want %v", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
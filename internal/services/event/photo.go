@@ -0,0 +1,200 @@
+package serviceevent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
+	"safety-riding/utils"
+)
+
+// UpdateEventPhoto changes the caption of a photo or makes it the cover of its event
+func (s *EventService) UpdateEventPhoto(photoId, username string, scope filter.RegionScope, req dto.UpdateEventPhoto) (domainevent.EventPhoto, error) {
+	photo, err := s.EventRepo.GetPhotoByID(photoId)
+	if err != nil {
+		return domainevent.EventPhoto{}, err
+	}
+
+	// The photo belongs to an event that must be inside the user's region
+	if _, err = s.EventRepo.GetByID(photo.EventId, scope); err != nil {
+		return domainevent.EventPhoto{}, err
+	}
+
+	before := photo
+	if req.Caption != nil {
+		photo.Caption = *req.Caption
+	}
+	if req.IsCover != nil {
+		photo.IsCover = *req.IsCover
+	}
+
+	if err := s.EventRepo.UpdatePhoto(photo); err != nil {
+		return domainevent.EventPhoto{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityEventPhoto, photoId, before, photo)
+
+	return photo, nil
+}
+
+// ReorderEventPhotos stores the order of all photos of the event as 1..n
+func (s *EventService) ReorderEventPhotos(eventId, username string, scope filter.RegionScope, req dto.ReorderEventPhotos) ([]domainevent.EventPhoto, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIds := make([]string, 0, len(event.Photos))
+	for _, photo := range event.Photos {
+		currentIds = append(currentIds, photo.ID)
+	}
+	if err := utils.ValidatePhotoOrder(currentIds, req.PhotoIds); err != nil {
+		return nil, err
+	}
+
+	if err := s.EventRepo.ReorderPhotos(eventId, req.PhotoIds); err != nil {
+		return nil, err
+	}
+
+	photos, err := s.EventRepo.GetPhotosByEventID(eventId)
+	if err != nil {
+		return nil, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityEvent, eventId, map[string]interface{}{"photo_ids": currentIds}, map[string]interface{}{"photo_ids": req.PhotoIds})
+
+	return photos, nil
+}
+
+// RequestEventPhotoUploads reserves presigned URLs to upload photos of the event directly to the bucket,
+// the photos are created by ConfirmEventPhotoUploads once the files are uploaded
+func (s *EventService) RequestEventPhotoUploads(ctx context.Context, eventId string, scope filter.RegionScope, req dto.RequestPhotoUploads) ([]dto.PhotoUploadSlot, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	maxEventPhotos := utils.GetEnv("MAX_EVENT_PHOTOS", 5).(int)
+	if err := utils.ValidatePhotoLimit(len(event.Photos), len(req.Files), maxEventPhotos); err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(utils.GetEnv("MAX_PHOTO_SIZE_BYTES", 5*1024*1024).(int))
+	expiry := time.Duration(utils.GetEnv("PHOTO_UPLOAD_URL_EXPIRY_SECONDS", 900).(int)) * time.Second
+	slots := make([]dto.PhotoUploadSlot, 0, len(req.Files))
+	for _, file := range req.Files {
+		if file.Size > maxSize {
+			return nil, fmt.Errorf("file %s exceeds maximum size of %d bytes", file.Filename, maxSize)
+		}
+
+		slot, err := imaging.PresignUpload(ctx, s.StorageProvider, eventPhotoStaging(eventId), file.ContentType, expiry)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, dto.PhotoUploadSlot{
+			UploadId:  slot.ObjectName,
+			Filename:  file.Filename,
+			UploadUrl: slot.URL,
+			Method:    http.MethodPut,
+			Headers:   map[string]string{"Content-Type": slot.ContentType},
+			ExpiresAt: slot.ExpiresAt,
+		})
+	}
+
+	return slots, nil
+}
+
+// ConfirmEventPhotoUploads checks the direct uploads of the event and stores them as photos, the same way
+// as the files uploaded through the API: resized, without EXIF data and with their renditions
+func (s *EventService) ConfirmEventPhotoUploads(ctx context.Context, eventId, username string, scope filter.RegionScope, req dto.ConfirmPhotoUploads) ([]domainevent.EventPhoto, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	maxEventPhotos := utils.GetEnv("MAX_EVENT_PHOTOS", 5).(int)
+	if err := utils.ValidatePhotoLimit(len(event.Photos), len(req.Photos), maxEventPhotos); err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(utils.GetEnv("MAX_PHOTO_SIZE_BYTES", 5*1024*1024).(int))
+	captions := make([]string, 0, len(req.Photos))
+	requestedOrders := make([]int, 0, len(req.Photos))
+	uploaded := make([]imaging.Photo, 0, len(req.Photos))
+	seen := make(map[string]bool, len(req.Photos))
+	for _, p := range req.Photos {
+		if seen[p.UploadId] {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, fmt.Errorf("upload %s is confirmed twice", path.Base(p.UploadId))
+		}
+		seen[p.UploadId] = true
+
+		photo, err := imaging.ImportUpload(ctx, s.StorageProvider, p.UploadId, eventPhotoStaging(eventId), "event-photos", maxSize, imaging.DefaultOptions)
+		if err != nil {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, err
+		}
+		uploaded = append(uploaded, photo)
+		captions = append(captions, p.Caption)
+		requestedOrders = append(requestedOrders, p.PhotoOrder)
+	}
+
+	return s.saveUploadedPhotos(ctx, eventId, username, len(event.Photos), uploaded, captions, requestedOrders)
+}
+
+// saveUploadedPhotos creates the photos of stored uploads after the current photos of the event,
+// the stored files are removed when they cannot be saved
+func (s *EventService) saveUploadedPhotos(ctx context.Context, eventId, username string, currentCount int, uploaded []imaging.Photo, captions []string, requestedOrders []int) ([]domainevent.EventPhoto, error) {
+	keepLocation := utils.GetEnv("PHOTO_KEEP_LOCATION", false).(bool)
+	orders := utils.AppendPhotoOrders(currentCount, requestedOrders)
+
+	eventPhotos := make([]domainevent.EventPhoto, 0, len(uploaded))
+	for i, photo := range uploaded {
+		eventPhoto := domainevent.EventPhoto{
+			ID:           utils.CreateUUID(),
+			EventId:      eventId,
+			PhotoUrl:     photo.URL,
+			MediumUrl:    photo.MediumURL,
+			ThumbnailUrl: photo.ThumbnailURL,
+			PhotoOrder:   orders[i],
+			CreatedAt:    time.Now(),
+			CreatedBy:    username,
+		}
+		if i < len(captions) {
+			eventPhoto.Caption = captions[i]
+		}
+		if keepLocation && photo.Location != nil {
+			eventPhoto.Latitude, eventPhoto.Longitude = &photo.Location.Latitude, &photo.Location.Longitude
+		}
+		eventPhotos = append(eventPhotos, eventPhoto)
+	}
+
+	if err := s.EventRepo.AddPhotos(eventPhotos); err != nil {
+		imaging.Delete(ctx, s.StorageProvider, uploaded...)
+		return nil, err
+	}
+	for _, photo := range eventPhotos {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEventPhoto, photo.ID, nil, photo)
+	}
+
+	return eventPhotos, nil
+}
+
+// eventPhotoStaging is the staging folder of the direct uploads of an event
+func eventPhotoStaging(eventId string) string {
+	return path.Join("event-photos", eventId)
+}
+
+// requestedPhotoOrders lists the photo_order asked for each new photo
+func requestedPhotoOrders(photos []dto.AddEventPhoto) []int {
+	orders := make([]int, 0, len(photos))
+	for _, p := range photos {
+		orders = append(orders, p.PhotoOrder)
+	}
+	return orders
+}
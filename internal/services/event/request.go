@@ -0,0 +1,252 @@
+package serviceevent
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	domainevent "safety-riding/internal/domain/event"
+	domainsubmittedform "safety-riding/internal/domain/submittedform"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+var (
+	requestTimePattern     = regexp.MustCompile(`\b([01]?[0-9]|2[0-3])[:.]([0-5][0-9])\b`)
+	requestDurationPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(jam|hours?|menit|minutes?)`)
+	spacePattern           = regexp.MustCompile(`\s+`)
+)
+
+// venueCandidate is a school or public entity that may be the venue of a training request
+type venueCandidate struct {
+	ID, Name, Address              string
+	DistrictId, CityId, ProvinceId string
+}
+
+// DraftFromRequest pre-fills an event from an approved training request and matches its school or public entity by name and address
+func (s *EventService) DraftFromRequest(formId string, scope filter.RegionScope) (dto.EventRequestDraft, error) {
+	form, err := s.convertibleRequest(formId)
+	if err != nil {
+		return dto.EventRequestDraft{}, err
+	}
+
+	text := strings.Join([]string{form.ActivityName, form.EventLocationAddress, form.FullAddress}, " | ")
+	text = normalizeVenueText(text)
+	address := normalizeVenueText(form.EventLocationAddress)
+
+	schools, err := s.SchoolRepo.FindMatches(text, address, scope)
+	if err != nil {
+		return dto.EventRequestDraft{}, err
+	}
+	candidates := make([]venueCandidate, 0, len(schools))
+	for _, school := range schools {
+		candidates = append(candidates, venueCandidate{school.ID, school.Name, school.Address, school.DistrictId, school.CityId, school.ProvinceId})
+	}
+	venue, school := bestVenueMatch(candidates, text, address)
+
+	// A public entity is only looked up when the request does not name a school
+	var public *dto.VenueMatch
+	if school == nil {
+		publics, err := s.PublicRepo.FindMatches(text, address)
+		if err != nil {
+			return dto.EventRequestDraft{}, err
+		}
+		candidates = candidates[:0]
+		for _, p := range publics {
+			if scope.Allows(p.ProvinceId, p.CityId) {
+				candidates = append(candidates, venueCandidate{p.ID, p.Name, p.Address, p.DistrictId, p.CityId, p.ProvinceId})
+			}
+		}
+		venue, public = bestVenueMatch(candidates, text, address)
+	}
+
+	event := draftEvent(form)
+	if venue != nil {
+		event.DistrictId, event.CityId, event.ProvinceId = venue.DistrictId, venue.CityId, venue.ProvinceId
+		if school != nil {
+			event.SchoolId = venue.ID
+		} else {
+			event.PublicId = venue.ID
+		}
+	}
+
+	return dto.EventRequestDraft{
+		RequestNumber: form.RequestNumber,
+		Event:         event,
+		School:        school,
+		Public:        public,
+		MissingFields: missingEventFields(event),
+	}, nil
+}
+
+// CreateFromRequest creates the reviewed draft of an approved training request as a planned event linked to its request number
+func (s *EventService) CreateFromRequest(formId, username string, scope filter.RegionScope, req dto.AddEvent) (domainevent.Event, error) {
+	form, err := s.convertibleRequest(formId)
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+
+	req.RequestNumber = form.RequestNumber
+	return s.AddEvent(username, scope, req)
+}
+
+// convertibleRequest returns a training request that is approved and has no event yet
+func (s *EventService) convertibleRequest(formId string) (domainsubmittedform.SubmittedForm, error) {
+	form, err := s.SubmittedFormRepo.GetByID(formId)
+	if err != nil {
+		return domainsubmittedform.SubmittedForm{}, err
+	}
+	if form.RequestNumber == 0 {
+		return domainsubmittedform.SubmittedForm{}, fmt.Errorf("training request has no request number")
+	}
+	if !strings.EqualFold(form.LatestStatus, utils.StsApproved) {
+		return domainsubmittedform.SubmittedForm{}, fmt.Errorf("training request #%d is not approved, its status is '%s'", form.RequestNumber, form.LatestStatus)
+	}
+
+	events, err := s.EventRepo.GetIdsByRequestNumbers([]int{form.RequestNumber})
+	if err != nil {
+		return domainsubmittedform.SubmittedForm{}, err
+	}
+	if eventId, ok := events[form.RequestNumber]; ok {
+		return domainsubmittedform.SubmittedForm{}, fmt.Errorf("training request #%d is already event %s", form.RequestNumber, eventId)
+	}
+	return form, nil
+}
+
+// draftEvent fills the event fields a training request has, the venue and region are left to the caller
+func draftEvent(form domainsubmittedform.SubmittedForm) dto.AddEvent {
+	event := dto.AddEvent{
+		Title:           strings.TrimSpace(form.ActivityName),
+		Location:        strings.TrimSpace(form.EventLocationAddress),
+		EventType:       truncate(strings.TrimSpace(form.TrainingType), 50),
+		TargetAudience:  truncate(strings.TrimSpace(form.AreaType), 100),
+		TargetAttendees: form.ParticipantCount,
+		Status:          utils.StsPlanned,
+	}
+	if event.Location == "" {
+		event.Location = strings.TrimSpace(form.FullAddress)
+	}
+	if form.EventDate != nil {
+		event.EventDate = form.EventDate.Format("2006-01-02")
+	}
+	event.StartTime, event.EndTime = requestTimes(form.EventTime, form.TrainingDuration)
+
+	var description []string
+	if material := strings.TrimSpace(form.Material); material != "" {
+		description = append(description, "Material: "+material)
+	}
+	if duration := strings.TrimSpace(form.TrainingDuration); duration != "" {
+		description = append(description, "Training duration: "+duration)
+	}
+	event.Description = strings.Join(description, "\n")
+
+	event.Notes = fmt.Sprintf("Created from training request #%d", form.RequestNumber)
+	if requester := strings.TrimSpace(form.FullName); requester != "" {
+		event.Notes += fmt.Sprintf(", requested by %s", requester)
+		if form.Whatsapp != "" {
+			event.Notes += fmt.Sprintf(" (%s)", strings.TrimSpace(form.Whatsapp))
+		}
+	}
+	return event
+}
+
+// requestTimes reads the start and end of a request time such as "08.00 - 10.00 WIB" as HH:MM.
+// Without an end time the end follows the training duration, e.g. "2 jam" or "90 menit".
+func requestTimes(eventTime, duration string) (start, end string) {
+	times := requestTimePattern.FindAllStringSubmatch(eventTime, 2)
+	if len(times) == 0 {
+		return "", ""
+	}
+	clock := func(match []string) int {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		return hour*60 + minute
+	}
+	format := func(minutes int) string {
+		return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+	}
+
+	startMinutes := clock(times[0])
+	if len(times) > 1 {
+		return format(startMinutes), format(clock(times[1]))
+	}
+
+	match := requestDurationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return format(startMinutes), ""
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil || amount <= 0 {
+		return format(startMinutes), ""
+	}
+	length := int(amount)
+	if unit := strings.ToLower(match[2]); unit == "jam" || strings.HasPrefix(unit, "hour") {
+		length = int(amount * 60)
+	}
+	return format(startMinutes), format(min(startMinutes+length, 23*60+59))
+}
+
+// bestVenueMatch picks the candidate both named in text and located at address, then one named in text,
+// then one at address. Candidates come longest name first so the most specific name wins a tie.
+func bestVenueMatch(candidates []venueCandidate, text, address string) (*venueCandidate, *dto.VenueMatch) {
+	var best *venueCandidate
+	bestScore := 0
+	for i := range candidates {
+		score := 0
+		if name := normalizeVenueText(candidates[i].Name); len(name) >= 5 && strings.Contains(text, name) {
+			score += 2
+		}
+		if address != "" && normalizeVenueText(candidates[i].Address) == address {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	matchedBy := map[int]string{1: "address", 2: "name", 3: "name_and_address"}[bestScore]
+	return best, &dto.VenueMatch{ID: best.ID, Name: best.Name, Address: best.Address, MatchedBy: matchedBy}
+}
+
+// missingEventFields lists the required fields of an event that are still empty
+func missingEventFields(event dto.AddEvent) []string {
+	fields := []struct {
+		name, value string
+	}{
+		{"title", event.Title},
+		{"description", event.Description},
+		{"event_date", event.EventDate},
+		{"start_time", event.StartTime},
+		{"end_time", event.EndTime},
+		{"location", event.Location},
+		{"district_id", event.DistrictId},
+		{"city_id", event.CityId},
+		{"province_id", event.ProvinceId},
+		{"event_type", event.EventType},
+	}
+
+	var missing []string
+	for _, field := range fields {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
+
+func normalizeVenueText(text string) string {
+	return spacePattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(text)), " ")
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
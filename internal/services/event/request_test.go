@@ -0,0 +1,53 @@
+package serviceevent
+
+import "testing"
+
+func TestRequestTimes(t *testing.T) {
+	tests := []struct {
+		name                string
+		eventTime, duration string
+		wantStart, wantEnd  string
+	}{
+		{name: "range with dots", eventTime: "08.00 - 10.30 WIB", wantStart: "08:00", wantEnd: "10:30"},
+		{name: "range with colons", eventTime: "13:00-15:00", duration: "3 jam", wantStart: "13:00", wantEnd: "15:00"},
+		{name: "end from hours", eventTime: "9.00", duration: "2 Jam", wantStart: "09:00", wantEnd: "11:00"},
+		{name: "end from half hours", eventTime: "09:15", duration: "1,5 jam", wantStart: "09:15", wantEnd: "10:45"},
+		{name: "end from minutes", eventTime: "10:00", duration: "90 menit", wantStart: "10:00", wantEnd: "11:30"},
+		{name: "end stays within the day", eventTime: "22:00", duration: "4 hours", wantStart: "22:00", wantEnd: "23:59"},
+		{name: "unknown duration", eventTime: "08:00", duration: "half day", wantStart: "08:00"},
+		{name: "no time", eventTime: "pagi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := requestTimes(tt.eventTime, tt.duration)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Fatalf("requestTimes(%q, %q) = %q, %q, want %q, %q", tt.eventTime, tt.duration, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestBestVenueMatch(t *testing.T) {
+	text := normalizeVenueText("Safety Riding SMAN 1  Bandung | Jl. Riau No. 1 | Jl. Merdeka 5")
+	address := normalizeVenueText("Jl. Riau No. 1")
+	candidates := []venueCandidate{
+		{ID: "by-name", Name: "SMAN 1 Bandung", Address: "Jl. Lain"},
+		{ID: "by-address", Name: "SMK Tujuh", Address: "JL. RIAU  NO. 1"},
+		{ID: "short-name", Name: "SMA", Address: "Jl. Lain"},
+	}
+
+	venue, match := bestVenueMatch(candidates, text, address)
+	if venue == nil || venue.ID != "by-name" || match.MatchedBy != "name" {
+		t.Fatalf("bestVenueMatch() = %+v, want by-name matched by name", match)
+	}
+
+	candidates = append(candidates, venueCandidate{ID: "both", Name: "SMAN 1 Bandung", Address: "Jl. Riau No. 1"})
+	if _, match = bestVenueMatch(candidates, text, address); match == nil || match.ID != "both" || match.MatchedBy != "name_and_address" {
+		t.Fatalf("bestVenueMatch() = %+v, want both matched by name_and_address", match)
+	}
+
+	if venue, match = bestVenueMatch(candidates[2:3], text, address); venue != nil || match != nil {
+		t.Fatalf("bestVenueMatch() = %+v, want no match for a short name", match)
+	}
+}
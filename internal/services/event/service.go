@@ -4,36 +4,59 @@ import (
 	"context"
 	"fmt"
 	"mime/multipart"
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	"safety-riding/internal/domain/event"
+	domaininstructor "safety-riding/internal/domain/instructor"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfaceauth "safety-riding/internal/interfaces/auth"
 	interfaceevent "safety-riding/internal/interfaces/event"
+	interfaceinstructor "safety-riding/internal/interfaces/instructor"
 	interfacepublic "safety-riding/internal/interfaces/publics"
 	interfaceschool "safety-riding/internal/interfaces/school"
+	interfacesubmittedform "safety-riding/internal/interfaces/submittedform"
+	interfacevisit "safety-riding/internal/interfaces/visit"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/imaging"
 	"safety-riding/pkg/storage"
 	"safety-riding/utils"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type EventService struct {
-	EventRepo       interfaceevent.RepoEventInterface
-	SchoolRepo      interfaceschool.RepoSchoolInterface
-	PublicRepo      interfacepublic.RepoPublicInterface
-	StorageProvider storage.StorageProvider
+	EventRepo         interfaceevent.RepoEventInterface
+	SchoolRepo        interfaceschool.RepoSchoolInterface
+	PublicRepo        interfacepublic.RepoPublicInterface
+	InstructorRepo    interfaceinstructor.RepoInstructorInterface
+	VisitRepo         interfacevisit.RepoVisitInterface
+	SubmittedFormRepo interfacesubmittedform.RepoSubmittedFormInterface
+	UserTokenRepo     interfaceauth.RepoUserTokenInterface
+	StorageProvider   storage.StorageProvider
+	AuditRecorder     interfaceauditlog.AuditRecorder
 }
 
-func NewEventService(eventRepo interfaceevent.RepoEventInterface, schoolRepo interfaceschool.RepoSchoolInterface, publicRepo interfacepublic.RepoPublicInterface, storageProvider storage.StorageProvider) *EventService {
+func NewEventService(eventRepo interfaceevent.RepoEventInterface, schoolRepo interfaceschool.RepoSchoolInterface, publicRepo interfacepublic.RepoPublicInterface, instructorRepo interfaceinstructor.RepoInstructorInterface, visitRepo interfacevisit.RepoVisitInterface, submittedFormRepo interfacesubmittedform.RepoSubmittedFormInterface, userTokenRepo interfaceauth.RepoUserTokenInterface, storageProvider storage.StorageProvider, auditRecorder interfaceauditlog.AuditRecorder) *EventService {
 	return &EventService{
-		EventRepo:       eventRepo,
-		SchoolRepo:      schoolRepo,
-		PublicRepo:      publicRepo,
-		StorageProvider: storageProvider,
+		EventRepo:         eventRepo,
+		SchoolRepo:        schoolRepo,
+		PublicRepo:        publicRepo,
+		InstructorRepo:    instructorRepo,
+		VisitRepo:         visitRepo,
+		SubmittedFormRepo: submittedFormRepo,
+		UserTokenRepo:     userTokenRepo,
+		StorageProvider:   storageProvider,
+		AuditRecorder:     auditRecorder,
 	}
 }
 
-func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.Event, error) {
+func (s *EventService) AddEvent(username string, scope filter.RegionScope, req dto.AddEvent) (domainevent.Event, error) {
+	if !scope.Allows(req.ProvinceId, req.CityId) {
+		return domainevent.Event{}, filter.ErrOutOfRegion
+	}
+
 	eventId := utils.CreateUUID()
 	phone := utils.NormalizePhoneTo62(req.InstructorPhone)
 
@@ -51,8 +74,13 @@ func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.
 		return domainevent.Event{}, err
 	}
 
+	status := normalizeStatus(req.Status)
+	if !slices.Contains(initialStatuses, status) {
+		return domainevent.Event{}, fmt.Errorf("event cannot be created as '%s', it must be one of %s", status, strings.Join(initialStatuses, ", "))
+	}
+
 	// Validate: if status is "completed", attendees_count must be filled (> 0)
-	if strings.EqualFold(req.Status, utils.StsCompleted) && req.AttendeesCount == 0 {
+	if status == utils.StsCompleted && req.AttendeesCount == 0 {
 		return domainevent.Event{}, fmt.Errorf("attendees_count must be greater than 0 when event status is 'completed'")
 	}
 
@@ -65,6 +93,10 @@ func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.
 	if req.PublicId != "" {
 		publicId = &req.PublicId
 	}
+	var requestNumber *int
+	if req.RequestNumber != 0 {
+		requestNumber = &req.RequestNumber
+	}
 
 	data := domainevent.Event{
 		ID:                       eventId,
@@ -87,10 +119,11 @@ func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.
 		VisitingServiceProfit:    req.VisitingServiceProfit,
 		InstructorName:           utils.TitleCase(req.InstructorName),
 		InstructorPhone:          phone,
-		Status:                   strings.ToLower(req.Status),
+		Status:                   status,
 		Notes:                    req.Notes,
 		AppsDownloaded:           req.AppsDownloaded,
 		AppsName:                 req.AppsName,
+		RequestNumber:            requestNumber,
 		CreatedAt:                time.Now(),
 		CreatedBy:                username,
 	}
@@ -98,23 +131,52 @@ func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.
 	// Add photos if provided
 	if len(req.Photos) > 0 {
 		photos := make([]domainevent.EventPhoto, 0, len(req.Photos))
-		for _, p := range req.Photos {
+		orders := utils.AppendPhotoOrders(0, requestedPhotoOrders(req.Photos))
+		for i, p := range req.Photos {
 			photos = append(photos, domainevent.EventPhoto{
-				ID:         utils.CreateUUID(),
-				EventId:    eventId,
-				PhotoUrl:   p.PhotoUrl,
-				Caption:    p.Caption,
-				PhotoOrder: p.PhotoOrder,
-				CreatedAt:  time.Now(),
-				CreatedBy:  username,
+				ID:           utils.CreateUUID(),
+				EventId:      eventId,
+				PhotoUrl:     p.PhotoUrl,
+				MediumUrl:    p.PhotoUrl,
+				ThumbnailUrl: p.PhotoUrl,
+				Caption:      p.Caption,
+				PhotoOrder:   orders[i],
+				CreatedAt:    time.Now(),
+				CreatedBy:    username,
 			})
 		}
 		data.Photos = photos
 	}
 
+	var instructors []domaininstructor.Instructor
+	var conflicts []domaininstructor.Booking
+	if len(req.InstructorIds) > 0 {
+		var err error
+		if instructors, err = s.resolveInstructors(req.InstructorIds); err != nil {
+			return domainevent.Event{}, err
+		}
+		if conflicts, err = s.scheduleConflicts(data, instructors); err != nil {
+			return domainevent.Event{}, err
+		}
+		if len(conflicts) > 0 && !req.AllowScheduleConflict {
+			return domainevent.Event{}, &dto.ScheduleConflictError{Conflicts: conflicts}
+		}
+	}
+
 	if err := s.EventRepo.Create(data); err != nil {
 		return domainevent.Event{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEvent, data.ID, nil, data)
+
+	if err := s.EventRepo.AddStatusHistory(domainevent.EventStatusHistory{
+		ID:        utils.CreateUUID(),
+		EventId:   eventId,
+		ToStatus:  status,
+		ChangedAt: data.CreatedAt,
+		ChangedBy: username,
+	}); err != nil {
+		return domainevent.Event{}, err
+	}
 
 	// Persist on the spot sales entries
 	if len(req.OnTheSpotSales) > 0 {
@@ -125,37 +187,32 @@ func (s *EventService) AddEvent(username string, req dto.AddEvent) (domainevent.
 		data.OnTheSpotSales = sales
 	}
 
-	// Update school data if schoolId is provided
-	if req.SchoolId != "" && strings.EqualFold(req.Status, utils.StsCompleted) {
-		school, err := s.SchoolRepo.GetByID(req.SchoolId)
-		if err == nil {
-			school.IsEducated = true
-			school.VisitCount++
-
-			// Use event_date + end_time as lastVisitAt
-			eventDateTime, err := utils.ParseEventDateTime(req.EventDate, req.EndTime)
-			if err != nil {
-				now := time.Now()
-				school.LastVisitAt = &now
-			} else {
-				school.LastVisitAt = &eventDateTime
-			}
+	// A past event recorded as completed counts as a visit right away
+	if err := s.refreshVisits(data); err != nil {
+		return domainevent.Event{}, err
+	}
 
-			school.UpdatedBy = username
-			if err = s.SchoolRepo.Update(school); err != nil {
-				return domainevent.Event{}, err
-			}
+	// The instructor name and phone of the event follow the assigned instructors
+	if len(instructors) > 0 {
+		if err := s.assignInstructors(eventId, username, instructors); err != nil {
+			return domainevent.Event{}, err
+		}
+		event, err := s.EventRepo.GetByID(eventId, filter.RegionScope{})
+		if err != nil {
+			return domainevent.Event{}, err
 		}
+		data = event
+		data.ScheduleConflicts = conflicts
 	}
 
 	return data, nil
 }
 
-func (s *EventService) GetEventById(id string) (domainevent.Event, error) {
-	return s.EventRepo.GetByID(id)
+func (s *EventService) GetEventById(id string, scope filter.RegionScope) (domainevent.Event, error) {
+	return s.EventRepo.GetByID(id, scope)
 }
 
-func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized bool, req dto.UpdateEvent) (domainevent.Event, error) {
+func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.UpdateEvent) (domainevent.Event, error) {
 	// Validate non-negative values
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"target_attendees":            req.TargetAttendees,
@@ -173,7 +230,7 @@ func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized boo
 	}
 
 	// Get existing event
-	event, err := s.EventRepo.GetByID(id)
+	event, err := s.EventRepo.GetByID(id, scope)
 	if err != nil {
 		return domainevent.Event{}, err
 	}
@@ -184,9 +241,27 @@ func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized boo
 	if isFinalized && !canOverrideFinalized {
 		return domainevent.Event{}, fmt.Errorf("cannot update event with status '%s'. Event is already finalized", event.Status)
 	}
+	before := event
+
+	// Once a roster exists the attendees count follows it and cannot be set by hand
+	rosterSize, err := s.EventRepo.CountParticipants(id)
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+	if rosterSize > 0 {
+		req.AttendeesCount = 0
+	}
+
+	// Status changes follow the same transitions as the status endpoint
+	statusChanged := req.Status != "" && normalizeStatus(req.Status) != normalizeStatus(event.Status)
+	if statusChanged {
+		if err := validateTransition(event.Status, req.Status, req.StatusReason, canOverrideFinalized); err != nil {
+			return domainevent.Event{}, err
+		}
+	}
 
 	// Validate: if changing status to "completed", attendees_count must be filled (> 0)
-	if req.Status != "" && strings.EqualFold(req.Status, utils.StsCompleted) {
+	if statusChanged && normalizeStatus(req.Status) == utils.StsCompleted {
 		attendeesCount := event.AttendeesCount
 		if req.AttendeesCount != 0 {
 			attendeesCount = req.AttendeesCount
@@ -248,16 +323,18 @@ func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized boo
 	if req.VisitingServiceProfit != 0 {
 		event.VisitingServiceProfit = req.VisitingServiceProfit
 	}
-	if req.InstructorName != "" {
+	// Linked instructors own the instructor name and phone, the free text is only kept for events without them
+	linked := len(event.Instructors) > 0
+	if req.InstructorIds != nil {
+		linked = len(req.InstructorIds) > 0
+	}
+	if req.InstructorName != "" && !linked {
 		event.InstructorName = req.InstructorName
 	}
-	if req.InstructorPhone != "" {
+	if req.InstructorPhone != "" && !linked {
 		phone := utils.NormalizePhoneTo62(req.InstructorPhone)
 		event.InstructorPhone = phone
 	}
-	if req.Status != "" {
-		event.Status = req.Status
-	}
 	if req.Notes != "" {
 		event.Notes = req.Notes
 	}
@@ -268,13 +345,68 @@ func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized boo
 		event.AppsName = req.AppsName
 	}
 
+	if !scope.Allows(event.ProvinceId, event.CityId) {
+		return domainevent.Event{}, filter.ErrOutOfRegion
+	}
+
+	// Instructors are checked again when the event moves or is assigned other instructors
+	var instructors []domaininstructor.Instructor
+	if req.InstructorIds != nil {
+		if instructors, err = s.resolveInstructors(req.InstructorIds); err != nil {
+			return domainevent.Event{}, err
+		}
+	} else {
+		for _, assigned := range event.Instructors {
+			if assigned.Instructor != nil {
+				instructors = append(instructors, *assigned.Instructor)
+			}
+		}
+	}
+	rescheduled := event.EventDate != before.EventDate || event.StartTime != before.StartTime || event.EndTime != before.EndTime
+	reassigned := req.InstructorIds != nil && !sameInstructors(before.Instructors, instructors)
+
+	var conflicts []domaininstructor.Booking
+	if rescheduled || reassigned {
+		scheduled := event
+		if statusChanged {
+			scheduled.Status = normalizeStatus(req.Status)
+		}
+		if conflicts, err = s.scheduleConflicts(scheduled, instructors); err != nil {
+			return domainevent.Event{}, err
+		}
+		if len(conflicts) > 0 && !req.AllowScheduleConflict {
+			return domainevent.Event{}, &dto.ScheduleConflictError{Conflicts: conflicts}
+		}
+	}
+
 	event.UpdatedAt = time.Now()
 	event.UpdatedBy = username
 
-	if err := s.EventRepo.UpdateById(id, event); err != nil {
+	// The fields and the status transition are saved together, a refused transition leaves the event untouched
+	if statusChanged {
+		history, err := newStatusHistory(event, req.Status, req.StatusReason, username, canOverrideFinalized)
+		if err != nil {
+			return domainevent.Event{}, err
+		}
+		applyStatus(&event, history)
+		if err := s.EventRepo.UpdateWithStatus(id, event, history); err != nil {
+			return domainevent.Event{}, err
+		}
+	} else if err := s.EventRepo.UpdateById(id, event); err != nil {
+		return domainevent.Event{}, err
+	}
+	// A completed event moved to another venue or date, or leaving completed, changes the visits of both venues
+	if err := s.refreshVisits(before, event); err != nil {
 		return domainevent.Event{}, err
 	}
 
+	// Changes to a finalized event are only possible with the override permission
+	action := domainauditlog.ActionUpdate
+	if isFinalized {
+		action = domainauditlog.ActionOverrideFinalized
+	}
+	s.AuditRecorder.Record(username, action, domainauditlog.EntityEvent, id, before, event)
+
 	// Replace on the spot sales entries when provided
 	if req.OnTheSpotSales != nil {
 		if err := s.EventRepo.DeleteOnTheSpotSalesByEventID(id); err != nil {
@@ -288,19 +420,15 @@ func (s *EventService) UpdateEvent(id, username string, canOverrideFinalized boo
 		event.OnTheSpotSales = sales
 	}
 
-	// Update school data if schoolId is provided
-	if req.SchoolId != "" && strings.EqualFold(req.Status, utils.StsCompleted) {
-		if err := s.markSchoolAsEducated(req.SchoolId, username, event.EventDate, event.EndTime); err != nil {
+	if req.InstructorIds != nil {
+		if err := s.assignInstructors(id, username, instructors); err != nil {
 			return domainevent.Event{}, err
 		}
-	}
-
-	// Update public data if publicId is provided
-	if req.PublicId != "" && strings.EqualFold(req.Status, utils.StsCompleted) {
-		if err := s.markPublicAsEducated(req.PublicId, username, event.EventDate, event.EndTime); err != nil {
+		if event, err = s.EventRepo.GetByID(id, filter.RegionScope{}); err != nil {
 			return domainevent.Event{}, err
 		}
 	}
+	event.ScheduleConflicts = conflicts
 
 	return event, nil
 }
@@ -309,14 +437,15 @@ func (s *EventService) FetchEvent(params filter.BaseParams) ([]domainevent.Event
 	return s.EventRepo.Fetch(params)
 }
 
-func (s *EventService) DeleteEvent(id, username string, canOverrideFinalized bool) error {
+func (s *EventService) DeleteEvent(id, username string, canOverrideFinalized bool, scope filter.RegionScope) error {
 	// Check if event exists
-	event, err := s.EventRepo.GetByID(id)
+	event, err := s.EventRepo.GetByID(id, scope)
 	if err != nil {
 		return err
 	}
 
-	if (strings.EqualFold(event.Status, utils.StsCompleted) || strings.EqualFold(event.Status, utils.StsCancelled)) && !canOverrideFinalized {
+	isFinalized := strings.EqualFold(event.Status, utils.StsCompleted) || strings.EqualFold(event.Status, utils.StsCancelled)
+	if isFinalized && !canOverrideFinalized {
 		return fmt.Errorf("cannot delete event with status '%s'. Event is already finalized", event.Status)
 	}
 
@@ -337,14 +466,23 @@ func (s *EventService) DeleteEvent(id, username string, canOverrideFinalized boo
 	if err := s.EventRepo.Delete(id); err != nil {
 		return err
 	}
+	if err := s.refreshVisits(event); err != nil {
+		return err
+	}
+
+	action := domainauditlog.ActionDelete
+	if isFinalized {
+		action = domainauditlog.ActionOverrideFinalized
+	}
+	s.AuditRecorder.Record(username, action, domainauditlog.EntityEvent, id, event, nil)
 
 	return nil
 }
 
 // Photo methods
-func (s *EventService) AddEventPhotos(eventId, username string, photos []dto.AddEventPhoto) ([]domainevent.EventPhoto, error) {
+func (s *EventService) AddEventPhotos(eventId, username string, scope filter.RegionScope, photos []dto.AddEventPhoto) ([]domainevent.EventPhoto, error) {
 	// Verify event exists
-	eventData, err := s.EventRepo.GetByID(eventId)
+	eventData, err := s.EventRepo.GetByID(eventId, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -355,42 +493,56 @@ func (s *EventService) AddEventPhotos(eventId, username string, photos []dto.Add
 	}
 
 	eventPhotos := make([]domainevent.EventPhoto, 0, len(photos))
-	for _, p := range photos {
+	orders := utils.AppendPhotoOrders(len(eventData.Photos), requestedPhotoOrders(photos))
+	for i, p := range photos {
 		eventPhotos = append(eventPhotos, domainevent.EventPhoto{
-			ID:         utils.CreateUUID(),
-			EventId:    eventId,
-			PhotoUrl:   p.PhotoUrl,
-			Caption:    p.Caption,
-			PhotoOrder: p.PhotoOrder,
-			CreatedAt:  time.Now(),
-			CreatedBy:  username,
+			ID:           utils.CreateUUID(),
+			EventId:      eventId,
+			PhotoUrl:     p.PhotoUrl,
+			MediumUrl:    p.PhotoUrl,
+			ThumbnailUrl: p.PhotoUrl,
+			Caption:      p.Caption,
+			PhotoOrder:   orders[i],
+			CreatedAt:    time.Now(),
+			CreatedBy:    username,
 		})
 	}
 
 	if err := s.EventRepo.AddPhotos(eventPhotos); err != nil {
 		return nil, err
 	}
+	for _, photo := range eventPhotos {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEventPhoto, photo.ID, nil, photo)
+	}
 
 	return eventPhotos, nil
 }
 
-func (s *EventService) DeleteEventPhoto(photoId, username string) error {
+func (s *EventService) DeleteEventPhoto(photoId, username string, scope filter.RegionScope) error {
 	eventPhoto, err := s.EventRepo.GetPhotoByID(photoId)
 	if err != nil {
 		return err
 	}
 
-	if err = s.EventRepo.DeletePhoto(photoId); err == nil {
-		_ = s.StorageProvider.DeleteFile(context.Background(), eventPhoto.PhotoUrl)
+	// The photo belongs to an event that must be inside the user's region
+	if _, err = s.EventRepo.GetByID(eventPhoto.EventId, scope); err != nil {
+		return err
 	}
 
-	return err
+	if err = s.EventRepo.DeletePhoto(photoId); err != nil {
+		return err
+	}
+	imaging.Delete(context.Background(), s.StorageProvider, imaging.Photo{URL: eventPhoto.PhotoUrl, MediumURL: eventPhoto.MediumUrl, ThumbnailURL: eventPhoto.ThumbnailUrl})
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityEventPhoto, photoId, eventPhoto, nil)
+
+	// Close the gap the photo leaves in photo_order
+	return s.EventRepo.NormalizePhotoOrder(eventPhoto.EventId)
 }
 
-// AddEventPhotosFromFiles uploads photos to storage and saves to database
-func (s *EventService) AddEventPhotosFromFiles(ctx context.Context, eventId, username string, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainevent.EventPhoto, error) {
+// AddEventPhotosFromFiles checks and resizes the uploaded photos, stores their renditions without EXIF data and saves them to database
+func (s *EventService) AddEventPhotosFromFiles(ctx context.Context, eventId, username string, scope filter.RegionScope, files []*multipart.FileHeader, captions []string, photoOrders []string) ([]domainevent.EventPhoto, error) {
 	// Verify event exists
-	eventData, err := s.EventRepo.GetByID(eventId)
+	eventData, err := s.EventRepo.GetByID(eventId, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -400,68 +552,38 @@ func (s *EventService) AddEventPhotosFromFiles(ctx context.Context, eventId, use
 		return nil, err
 	}
 
-	eventPhotos := make([]domainevent.EventPhoto, 0, len(files))
+	requestedOrders := make([]int, len(files))
+	uploaded := make([]imaging.Photo, 0, len(files))
 
-	var photoURL string
 	for i, fileHeader := range files {
 		if err := utils.ValidatePhotoFileSize(fileHeader); err != nil {
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
 			return nil, err
 		}
 
-		// Open file
-		file, err := fileHeader.Open()
-		if err != nil {
-			return nil, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
-		}
-		defer file.Close()
-
-		// Upload to storage provider (MinIO or R2)
-		photoURL, err = s.StorageProvider.UploadFile(ctx, file, fileHeader, "event-photos")
+		// Upload the renditions to storage provider (MinIO or R2)
+		photo, err := imaging.UploadFile(ctx, s.StorageProvider, fileHeader, "event-photos", imaging.DefaultOptions)
 		if err != nil {
-			return nil, fmt.Errorf("failed to upload file %s to storage: %w", fileHeader.Filename, err)
-		}
-
-		// Get caption if provided
-		caption := ""
-		if i < len(captions) {
-			caption = captions[i]
+			imaging.Delete(ctx, s.StorageProvider, uploaded...)
+			return nil, err
 		}
+		uploaded = append(uploaded, photo)
 
 		// Get photo order if provided
-		photoOrder := 0
 		if i < len(photoOrders) {
 			if order, err := strconv.Atoi(photoOrders[i]); err == nil {
-				photoOrder = order
+				requestedOrders[i] = order
 			}
 		}
-
-		// Create photo record
-		eventPhoto := domainevent.EventPhoto{
-			ID:         utils.CreateUUID(),
-			EventId:    eventId,
-			PhotoUrl:   photoURL,
-			Caption:    caption,
-			PhotoOrder: photoOrder,
-			CreatedAt:  time.Now(),
-			CreatedBy:  username,
-		}
-
-		eventPhotos = append(eventPhotos, eventPhoto)
 	}
 
-	// Save photos to database
-	if err := s.EventRepo.AddPhotos(eventPhotos); err != nil {
-		_ = s.StorageProvider.DeleteFile(ctx, photoURL)
-		return nil, err
-	}
-
-	return eventPhotos, nil
+	return s.saveUploadedPhotos(ctx, eventId, username, len(eventData.Photos), uploaded, captions, requestedOrders)
 }
 
 var _ interfaceevent.ServiceEventInterface = (*EventService)(nil)
 
-func (s *EventService) GetCompletedEventsForMap(since time.Time) ([]dto.EventMapData, error) {
-	events, err := s.EventRepo.FetchCompletedWithCoords(since)
+func (s *EventService) GetCompletedEventsForMap(since time.Time, scope filter.RegionScope) ([]dto.EventMapData, error) {
+	events, err := s.EventRepo.FetchCompletedWithCoords(since, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -500,5 +622,17 @@ func (s *EventService) GetCompletedEventsForMap(since time.Time) ([]dto.EventMap
 		})
 	}
 
+	eventIds := make([]string, 0, len(result))
+	for _, e := range result {
+		eventIds = append(eventIds, e.ID)
+	}
+	covers, err := s.EventRepo.GetCoverPhotos(eventIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].CoverPhotoUrl = covers[result[i].ID].MediumUrl
+	}
+
 	return result, nil
 }
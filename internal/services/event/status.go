@@ -0,0 +1,134 @@
+package serviceevent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+// statusTransitions lists where an event may go from each status, cancelled is final
+var statusTransitions = map[string][]string{
+	utils.StsPlanned:   {utils.StsOnGoing, utils.StsCancelled},
+	utils.StsOnGoing:   {utils.StsCompleted, utils.StsCancelled},
+	utils.StsCompleted: {utils.StsCancelled},
+}
+
+// initialStatuses are the statuses an event can be created with, past events may be recorded as completed
+var initialStatuses = []string{utils.StsPlanned, utils.StsOnGoing, utils.StsCompleted}
+
+// normalizeStatus lower-cases a status, an empty status and the older pending and confirmed mean planned
+func normalizeStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "", utils.StsPending, utils.StsConfirmed:
+		return utils.StsPlanned
+	}
+	return status
+}
+
+// validateTransition checks a status change, a cancellation needs a reason and
+// changing a completed event needs the override permission
+func validateTransition(from, to, reason string, canOverrideFinalized bool) error {
+	from, to = normalizeStatus(from), normalizeStatus(to)
+	if from == to {
+		return fmt.Errorf("event is already '%s'", from)
+	}
+
+	allowed := false
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("event status cannot change from '%s' to '%s'", from, to)
+	}
+
+	if to == utils.StsCancelled && strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("a reason is required to cancel an event")
+	}
+	if from == utils.StsCompleted && !canOverrideFinalized {
+		return fmt.Errorf("cannot change the status of a completed event without the override permission")
+	}
+	return nil
+}
+
+// TransitionStatus moves the event to the next status of its lifecycle and records who changed it
+func (s *EventService) TransitionStatus(id, username string, canOverrideFinalized bool, scope filter.RegionScope, req dto.TransitionEventStatus) (domainevent.Event, error) {
+	event, err := s.EventRepo.GetByID(id, scope)
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+	before := event
+
+	if err := s.changeStatus(&event, req.Status, req.Reason, username, canOverrideFinalized); err != nil {
+		return domainevent.Event{}, err
+	}
+	if err := s.refreshVisits(before, event); err != nil {
+		return domainevent.Event{}, err
+	}
+
+	action := domainauditlog.ActionUpdate
+	if strings.EqualFold(before.Status, utils.StsCompleted) {
+		action = domainauditlog.ActionOverrideFinalized
+	}
+	s.AuditRecorder.Record(username, action, domainauditlog.EntityEvent, id, before, event)
+
+	return event, nil
+}
+
+func (s *EventService) GetStatusHistory(id string, scope filter.RegionScope) ([]domainevent.EventStatusHistory, error) {
+	if _, err := s.EventRepo.GetByID(id, scope); err != nil {
+		return nil, err
+	}
+
+	return s.EventRepo.GetStatusHistory(id)
+}
+
+// changeStatus validates and stores a status transition of a saved event, the caller refreshes the visits of its venue
+func (s *EventService) changeStatus(event *domainevent.Event, status, reason, username string, canOverrideFinalized bool) error {
+	history, err := newStatusHistory(*event, status, reason, username, canOverrideFinalized)
+	if err != nil {
+		return err
+	}
+	if err := s.EventRepo.UpdateStatus(history); err != nil {
+		return err
+	}
+	applyStatus(event, history)
+	return nil
+}
+
+// newStatusHistory validates a status transition of the event and returns it as a history entry
+func newStatusHistory(event domainevent.Event, status, reason, username string, canOverrideFinalized bool) (domainevent.EventStatusHistory, error) {
+	if err := validateTransition(event.Status, status, reason, canOverrideFinalized); err != nil {
+		return domainevent.EventStatusHistory{}, err
+	}
+
+	to := normalizeStatus(status)
+	if to == utils.StsCompleted && event.AttendeesCount == 0 {
+		return domainevent.EventStatusHistory{}, fmt.Errorf("attendees_count must be greater than 0 when changing event status to 'completed'")
+	}
+
+	return domainevent.EventStatusHistory{
+		ID:         utils.CreateUUID(),
+		EventId:    event.ID,
+		FromStatus: event.Status,
+		ToStatus:   to,
+		Reason:     strings.TrimSpace(reason),
+		ChangedAt:  time.Now(),
+		ChangedBy:  username,
+	}, nil
+}
+
+func applyStatus(event *domainevent.Event, history domainevent.EventStatusHistory) {
+	event.Status = history.ToStatus
+	event.UpdatedAt = history.ChangedAt
+	event.UpdatedBy = history.ChangedBy
+}
@@ -0,0 +1,63 @@
+package serviceevent
+
+import (
+	"testing"
+
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/utils"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    string
+		reason      string
+		canOverride bool
+		wantErr     bool
+	}{
+		{name: "planned to ongoing", from: utils.StsPlanned, to: utils.StsOnGoing},
+		{name: "ongoing to completed", from: utils.StsOnGoing, to: utils.StsCompleted},
+		{name: "legacy pending starts as planned", from: "Pending", to: utils.StsOnGoing},
+		{name: "empty status starts as planned", from: "", to: "Ongoing"},
+		{name: "planned cannot skip to completed", from: utils.StsPlanned, to: utils.StsCompleted, wantErr: true},
+		{name: "completed cannot go back", from: utils.StsCompleted, to: utils.StsOnGoing, canOverride: true, wantErr: true},
+		{name: "same status", from: utils.StsOnGoing, to: utils.StsOnGoing, wantErr: true},
+		{name: "unknown status", from: utils.StsPlanned, to: "postponed", wantErr: true},
+		{name: "cancel with reason", from: utils.StsPlanned, to: utils.StsCancelled, reason: "school closed"},
+		{name: "cancel without reason", from: utils.StsOnGoing, to: utils.StsCancelled, reason: "  ", wantErr: true},
+		{name: "cancelled is final", from: utils.StsCancelled, to: utils.StsPlanned, canOverride: true, wantErr: true},
+		{name: "cancel completed needs override", from: utils.StsCompleted, to: utils.StsCancelled, reason: "duplicate", wantErr: true},
+		{name: "cancel completed with override", from: utils.StsCompleted, to: utils.StsCancelled, reason: "duplicate", canOverride: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTransition(tt.from, tt.to, tt.reason, tt.canOverride)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateTransition(%q, %q) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStatusHistory(t *testing.T) {
+	event := domainevent.Event{ID: "event-1", Status: utils.StsOnGoing}
+
+	if _, err := newStatusHistory(event, utils.StsCompleted, "", "admin", false); err == nil {
+		t.Fatalf("newStatusHistory() completed an event without attendees")
+	}
+
+	event.AttendeesCount = 12
+	history, err := newStatusHistory(event, utils.StsCompleted, "  done ", "admin", false)
+	if err != nil {
+		t.Fatalf("newStatusHistory() error = %v", err)
+	}
+	if history.EventId != "event-1" || history.FromStatus != utils.StsOnGoing || history.ToStatus != utils.StsCompleted || history.Reason != "done" || history.ChangedBy != "admin" {
+		t.Fatalf("newStatusHistory() = %+v", history)
+	}
+
+	applyStatus(&event, history)
+	if event.Status != utils.StsCompleted || event.UpdatedBy != "admin" || !event.UpdatedAt.Equal(history.ChangedAt) {
+		t.Fatalf("applyStatus() event = %+v", event)
+	}
+}
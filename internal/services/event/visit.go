@@ -0,0 +1,29 @@
+package serviceevent
+
+import (
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+// RecomputeVisits rebuilds the visit count, educated flag and last visit of every school and public entity from their completed events
+func (s *EventService) RecomputeVisits() (dto.VisitRecomputeResult, error) {
+	return s.VisitRepo.RecomputeAll()
+}
+
+// FetchSchoolVisits returns the completed events of a school, latest first
+func (s *EventService) FetchSchoolVisits(schoolId string, scope filter.RegionScope) ([]dto.Visit, error) {
+	if _, err := s.SchoolRepo.GetByID(schoolId, scope); err != nil {
+		return nil, err
+	}
+
+	return s.VisitRepo.FetchBySchool(schoolId)
+}
+
+// FetchPublicVisits returns the completed events of a public entity, latest first
+func (s *EventService) FetchPublicVisits(publicId string) ([]dto.Visit, error) {
+	if _, err := s.PublicRepo.GetByID(publicId); err != nil {
+		return nil, err
+	}
+
+	return s.VisitRepo.FetchByPublic(publicId)
+}
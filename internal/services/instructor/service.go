@@ -0,0 +1,217 @@
+package serviceinstructor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domaininstructor "safety-riding/internal/domain/instructor"
+	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfaceinstructor "safety-riding/internal/interfaces/instructor"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+const (
+	scheduleDateLayout  = "2006-01-02"
+	scheduleDefaultDays = 30
+	scheduleMaxDays     = 366
+)
+
+type InstructorService struct {
+	InstructorRepo interfaceinstructor.RepoInstructorInterface
+	AuditRecorder  interfaceauditlog.AuditRecorder
+}
+
+func NewInstructorService(instructorRepo interfaceinstructor.RepoInstructorInterface, auditRecorder interfaceauditlog.AuditRecorder) *InstructorService {
+	return &InstructorService{
+		InstructorRepo: instructorRepo,
+		AuditRecorder:  auditRecorder,
+	}
+}
+
+func (s *InstructorService) AddInstructor(username string, req dto.AddInstructor) (domaininstructor.Instructor, error) {
+	certifications := req.Certifications
+	if certifications == nil {
+		certifications = []domaininstructor.Certification{}
+	}
+
+	data := domaininstructor.Instructor{
+		ID:             utils.CreateUUID(),
+		Name:           utils.TitleCase(strings.TrimSpace(req.Name)),
+		Phone:          utils.NormalizePhoneTo62(req.Phone),
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		HomeProvinceId: req.HomeProvinceId,
+		HomeCityId:     req.HomeCityId,
+		Certifications: certifications,
+		IsActive:       true,
+		Notes:          req.Notes,
+		CreatedAt:      time.Now(),
+		CreatedBy:      username,
+	}
+
+	if err := s.InstructorRepo.Create(data); err != nil {
+		return domaininstructor.Instructor{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityInstructor, data.ID, nil, data)
+
+	return data, nil
+}
+
+func (s *InstructorService) GetInstructorById(id string) (domaininstructor.Instructor, error) {
+	return s.InstructorRepo.GetByID(id)
+}
+
+// UpdateInstructor changes the directory entry, events keep the instructor_name they were saved with until their instructors are set again
+func (s *InstructorService) UpdateInstructor(id, username string, req dto.UpdateInstructor) (domaininstructor.Instructor, error) {
+	instructor, err := s.InstructorRepo.GetByID(id)
+	if err != nil {
+		return domaininstructor.Instructor{}, err
+	}
+	before := instructor
+
+	if req.Name != "" {
+		instructor.Name = utils.TitleCase(strings.TrimSpace(req.Name))
+	}
+	if req.Phone != "" {
+		instructor.Phone = utils.NormalizePhoneTo62(req.Phone)
+	}
+	if req.Email != "" {
+		instructor.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	}
+	if req.HomeProvinceId != "" {
+		instructor.HomeProvinceId = req.HomeProvinceId
+	}
+	if req.HomeCityId != "" {
+		instructor.HomeCityId = req.HomeCityId
+	}
+	if req.Certifications != nil {
+		instructor.Certifications = req.Certifications
+	}
+	if req.IsActive != nil {
+		instructor.IsActive = *req.IsActive
+	}
+	if req.Notes != "" {
+		instructor.Notes = req.Notes
+	}
+
+	now := time.Now()
+	instructor.UpdatedAt = &now
+	instructor.UpdatedBy = username
+	if err := s.InstructorRepo.Update(instructor); err != nil {
+		return domaininstructor.Instructor{}, err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityInstructor, instructor.ID, before, instructor)
+
+	return instructor, nil
+}
+
+func (s *InstructorService) FetchInstructor(params filter.BaseParams) ([]domaininstructor.Instructor, int64, error) {
+	return s.InstructorRepo.Fetch(params)
+}
+
+// DeleteInstructor removes an instructor without upcoming bookings, the past events keep their instructor_name
+func (s *InstructorService) DeleteInstructor(id, username string) error {
+	instructor, err := s.InstructorRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	upcoming, err := s.InstructorRepo.FetchBookings([]string{id}, time.Now().Format(scheduleDateLayout), "9999-12-31", "", filter.RegionScope{})
+	if err != nil {
+		return err
+	}
+	if len(upcoming) > 0 {
+		return fmt.Errorf("instructor is booked for %d upcoming events, reassign them or deactivate the instructor instead", len(upcoming))
+	}
+
+	if err := s.InstructorRepo.Delete(id); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityInstructor, id, instructor, nil)
+
+	return nil
+}
+
+// GetSchedule returns the bookings of the instructor between from and to (YYYY-MM-DD), the next 30 days by default.
+// Events outside the region scope are left out.
+func (s *InstructorService) GetSchedule(id, from, to string, scope filter.RegionScope) (dto.InstructorSchedule, error) {
+	start, end, err := scheduleRange(from, to, time.Now())
+	if err != nil {
+		return dto.InstructorSchedule{}, err
+	}
+
+	instructor, err := s.InstructorRepo.GetByID(id)
+	if err != nil {
+		return dto.InstructorSchedule{}, err
+	}
+
+	bookings, err := s.InstructorRepo.FetchBookings([]string{id}, start, end, "", scope)
+	if err != nil {
+		return dto.InstructorSchedule{}, err
+	}
+	if bookings == nil {
+		bookings = []domaininstructor.Booking{}
+	}
+
+	return dto.InstructorSchedule{
+		Instructor: instructor,
+		From:       start,
+		To:         end,
+		EventCount: len(bookings),
+		TotalHours: totalHours(bookings),
+		Bookings:   bookings,
+	}, nil
+}
+
+// scheduleRange validates the requested period, a missing bound defaults to today and 30 days later
+func scheduleRange(from, to string, now time.Time) (string, string, error) {
+	start := now
+	if from != "" {
+		parsed, err := time.Parse(scheduleDateLayout, from)
+		if err != nil {
+			return "", "", fmt.Errorf("from must be a date in YYYY-MM-DD format")
+		}
+		start = parsed
+	}
+
+	end := start.AddDate(0, 0, scheduleDefaultDays)
+	if to != "" {
+		parsed, err := time.Parse(scheduleDateLayout, to)
+		if err != nil {
+			return "", "", fmt.Errorf("to must be a date in YYYY-MM-DD format")
+		}
+		end = parsed
+	}
+
+	startDate, endDate := start.Format(scheduleDateLayout), end.Format(scheduleDateLayout)
+	if endDate < startDate {
+		return "", "", fmt.Errorf("to must not be before from")
+	}
+	if end.Sub(start) > scheduleMaxDays*24*time.Hour {
+		return "", "", fmt.Errorf("schedule period cannot exceed %d days", scheduleMaxDays)
+	}
+
+	return startDate, endDate, nil
+}
+
+// totalHours sums the booked time, bookings without a valid start and end time are not counted
+func totalHours(bookings []domaininstructor.Booking) float64 {
+	var total time.Duration
+	for _, b := range bookings {
+		start, err := utils.ParseEventDateTime(b.EventDate, b.StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := utils.ParseEventDateTime(b.EventDate, b.EndTime)
+		if err != nil || !end.After(start) {
+			continue
+		}
+		total += end.Sub(start)
+	}
+	return total.Hours()
+}
+
+var _ interfaceinstructor.ServiceInstructorInterface = (*InstructorService)(nil)
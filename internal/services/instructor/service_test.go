@@ -0,0 +1,51 @@
+package serviceinstructor
+
+import (
+	"testing"
+	"time"
+
+	domaininstructor "safety-riding/internal/domain/instructor"
+)
+
+func TestScheduleRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     [2]string
+		wantErr  bool
+	}{
+		{name: "defaults to the next 30 days", want: [2]string{"2026-03-10", "2026-04-09"}},
+		{name: "from only", from: "2026-05-01", want: [2]string{"2026-05-01", "2026-05-31"}},
+		{name: "both bounds", from: "2026-01-01", to: "2026-01-01", want: [2]string{"2026-01-01", "2026-01-01"}},
+		{name: "to before from", from: "2026-02-01", to: "2026-01-31", wantErr: true},
+		{name: "invalid date", from: "01/02/2026", wantErr: true},
+		{name: "period too long", from: "2026-01-01", to: "2027-06-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := scheduleRange(tt.from, tt.to, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scheduleRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (from != tt.want[0] || to != tt.want[1]) {
+				t.Fatalf("scheduleRange() = %s - %s, want %s - %s", from, to, tt.want[0], tt.want[1])
+			}
+		})
+	}
+}
+
+func TestTotalHours(t *testing.T) {
+	bookings := []domaininstructor.Booking{
+		{EventDate: "2026-03-10", StartTime: "08:00", EndTime: "10:30"},
+		{EventDate: "2026-03-11", StartTime: "13:00:00", EndTime: "15:00:00"},
+		{EventDate: "2026-03-12", StartTime: "tbd", EndTime: "12:00"},
+		{EventDate: "2026-03-13", StartTime: "14:00", EndTime: "09:00"},
+	}
+
+	if got := totalHours(bookings); got != 4.5 {
+		t.Fatalf("totalHours() = %v, want 4.5", got)
+	}
+}
@@ -1,8 +1,10 @@
 package servicemarketshare
 
 import (
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	domainmarketshare "safety-riding/internal/domain/marketshare"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	interfacemarketshare "safety-riding/internal/interfaces/marketshare"
 	"safety-riding/pkg/filter"
 	"safety-riding/utils"
@@ -11,15 +13,21 @@ import (
 
 type MarketShareService struct {
 	MarketShareRepo interfacemarketshare.RepoMarketShareInterface
+	AuditRecorder   interfaceauditlog.AuditRecorder
 }
 
-func NewMarketShareService(marketShareRepo interfacemarketshare.RepoMarketShareInterface) *MarketShareService {
+func NewMarketShareService(marketShareRepo interfacemarketshare.RepoMarketShareInterface, auditRecorder interfaceauditlog.AuditRecorder) *MarketShareService {
 	return &MarketShareService{
 		MarketShareRepo: marketShareRepo,
+		AuditRecorder:   auditRecorder,
 	}
 }
 
-func (s *MarketShareService) AddMarketShare(username string, req dto.AddMarketShare) (domainmarketshare.MarketShare, error) {
+func (s *MarketShareService) AddMarketShare(username string, scope filter.RegionScope, req dto.AddMarketShare) (domainmarketshare.MarketShare, error) {
+	if !scope.Allows(req.ProvinceID, req.CityID) {
+		return domainmarketshare.MarketShare{}, filter.ErrOutOfRegion
+	}
+
 	// Validate numeric fields are non-negative
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"monthly_sales":                 req.MonthlySales,
@@ -60,20 +68,22 @@ func (s *MarketShareService) AddMarketShare(username string, req dto.AddMarketSh
 	if err := s.MarketShareRepo.Create(data); err != nil {
 		return domainmarketshare.MarketShare{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityMarketShare, data.ID, nil, data)
 
 	return data, nil
 }
 
-func (s *MarketShareService) GetMarketShareById(id string) (domainmarketshare.MarketShare, error) {
-	return s.MarketShareRepo.GetByID(id)
+func (s *MarketShareService) GetMarketShareById(id string, scope filter.RegionScope) (domainmarketshare.MarketShare, error) {
+	return s.MarketShareRepo.GetByID(id, scope)
 }
 
-func (s *MarketShareService) UpdateMarketShare(id, username string, req dto.UpdateMarketShare) (domainmarketshare.MarketShare, error) {
+func (s *MarketShareService) UpdateMarketShare(id, username string, scope filter.RegionScope, req dto.UpdateMarketShare) (domainmarketshare.MarketShare, error) {
 	// Get existing market share
-	marketShare, err := s.MarketShareRepo.GetByID(id)
+	marketShare, err := s.MarketShareRepo.GetByID(id, scope)
 	if err != nil {
 		return domainmarketshare.MarketShare{}, err
 	}
+	before := marketShare
 
 	// Update fields if provided
 	if req.ProvinceID != "" {
@@ -128,12 +138,17 @@ func (s *MarketShareService) UpdateMarketShare(id, username string, req dto.Upda
 		marketShare.Notes = req.Notes
 	}
 
+	if !scope.Allows(marketShare.ProvinceID, marketShare.CityID) {
+		return domainmarketshare.MarketShare{}, filter.ErrOutOfRegion
+	}
+
 	marketShare.UpdatedAt = time.Now()
 	marketShare.UpdatedBy = username
 
 	if err := s.MarketShareRepo.UpdateById(id, marketShare); err != nil {
 		return domainmarketshare.MarketShare{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityMarketShare, id, before, marketShare)
 
 	return marketShare, nil
 }
@@ -142,9 +157,9 @@ func (s *MarketShareService) FetchMarketShare(params filter.BaseParams) ([]domai
 	return s.MarketShareRepo.Fetch(params)
 }
 
-func (s *MarketShareService) DeleteMarketShare(id, username string) error {
+func (s *MarketShareService) DeleteMarketShare(id, username string, scope filter.RegionScope) error {
 	// Check if market share exists
-	marketShare, err := s.MarketShareRepo.GetByID(id)
+	marketShare, err := s.MarketShareRepo.GetByID(id, scope)
 	if err != nil {
 		return err
 	}
@@ -156,24 +171,25 @@ func (s *MarketShareService) DeleteMarketShare(id, username string) error {
 	if err := s.MarketShareRepo.Delete(id); err != nil {
 		return err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityMarketShare, id, marketShare, nil)
 
 	return nil
 }
 
 // GetTopDistricts returns top districts by sales
-func (s *MarketShareService) GetTopDistricts(year, month, limit int) ([]domainmarketshare.TopDistrict, error) {
-	return s.MarketShareRepo.GetTopDistricts(year, month, limit)
+func (s *MarketShareService) GetTopDistricts(year, month, limit int, scope filter.RegionScope) ([]domainmarketshare.TopDistrict, error) {
+	return s.MarketShareRepo.GetTopDistricts(year, month, limit, scope)
 }
 
-func (s *MarketShareService) GetTopCities(year, month, limit int, sortOrder string) ([]domainmarketshare.TopCity, error) {
-	return s.MarketShareRepo.GetTopCities(year, month, limit, sortOrder)
+func (s *MarketShareService) GetTopCities(year, month, limit int, sortOrder string, scope filter.RegionScope) ([]domainmarketshare.TopCity, error) {
+	return s.MarketShareRepo.GetTopCities(year, month, limit, sortOrder, scope)
 }
 
-func (s *MarketShareService) GetSummary(level string, year, month int, provinceID, cityID, districtID string) ([]domainmarketshare.MarketShareSummary, error) {
+func (s *MarketShareService) GetSummary(level string, year, month int, provinceID, cityID, districtID string, scope filter.RegionScope) ([]domainmarketshare.MarketShareSummary, error) {
 	if level == "" {
 		level = "province"
 	}
-	return s.MarketShareRepo.GetSummary(level, year, month, provinceID, cityID, districtID)
+	return s.MarketShareRepo.GetSummary(level, year, month, provinceID, cityID, districtID, scope)
 }
 
 var _ interfacemarketshare.ServiceMarketShareInterface = (*MarketShareService)(nil)
@@ -0,0 +1,204 @@
+package serviceparticipant
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/security"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+const checkInTokenPurpose = "event_checkin"
+
+// GetCheckInCode returns the signed check-in link of the event. The link is the same every time it is requested,
+// so a printed QR code keeps working until check-in closes.
+func (s *ParticipantService) GetCheckInCode(eventId string, scope filter.RegionScope) (dto.CheckInCode, error) {
+	event, err := s.getRosterEvent(eventId, scope)
+	if err != nil {
+		return dto.CheckInCode{}, err
+	}
+
+	secret, err := checkInSecret()
+	if err != nil {
+		return dto.CheckInCode{}, err
+	}
+
+	token := security.SignValue(secret, checkInTokenPurpose, event.ID)
+	checkInURL := utils.GetEnv("CHECKIN_URL", "http://localhost:3000/checkin").(string)
+	opensAt, closesAt := checkInWindow(event)
+
+	return dto.CheckInCode{
+		EventId:  event.ID,
+		Token:    token,
+		URL:      checkInURL + "?token=" + url.QueryEscape(token),
+		OpensAt:  opensAt,
+		ClosesAt: closesAt,
+	}, nil
+}
+
+// GetCheckInEvent returns the event behind a check-in token for the public check-in page
+func (s *ParticipantService) GetCheckInEvent(token string) (dto.CheckInEvent, error) {
+	event, err := s.getCheckInEvent(token)
+	if err != nil {
+		return dto.CheckInEvent{}, err
+	}
+
+	opensAt, closesAt := checkInWindow(event)
+
+	return dto.CheckInEvent{
+		Title:     event.Title,
+		EventDate: event.EventDate,
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+		Location:  event.Location,
+		Open:      checkInOpen(event, time.Now()),
+		OpensAt:   opensAt,
+		ClosesAt:  closesAt,
+	}, nil
+}
+
+// CheckIn records the arrival of a participant on the roster. Checking in twice is not an error,
+// the first check-in time is kept and returned.
+func (s *ParticipantService) CheckIn(token string, req dto.CheckIn) (dto.CheckInResult, error) {
+	event, err := s.getCheckInEvent(token)
+	if err != nil {
+		return dto.CheckInResult{}, err
+	}
+
+	now := time.Now()
+	opensAt, closesAt := checkInWindow(event)
+	if strings.EqualFold(event.Status, utils.StsCancelled) || !now.Before(closesAt) {
+		return dto.CheckInResult{}, errors.New(messages.ErrCheckInClosed)
+	}
+	if now.Before(opensAt) {
+		return dto.CheckInResult{}, errors.New(messages.ErrCheckInNotOpen)
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone != "" {
+		phone = utils.NormalizePhoneTo62(phone)
+	}
+	participant, err := s.ParticipantRepo.GetByIdentityKey(event.ID, identityKey(req.Name, phone, req.Organization))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.CheckInResult{}, errors.New(messages.ErrNotOnRoster)
+		}
+		return dto.CheckInResult{}, err
+	}
+
+	result := dto.CheckInResult{
+		ParticipantId: participant.ID,
+		Name:          participant.Name,
+	}
+	if participant.CheckedInAt == nil {
+		updated, err := s.ParticipantRepo.MarkCheckedIn(participant.ID, now)
+		if err != nil {
+			return dto.CheckInResult{}, err
+		}
+		if updated {
+			result.CheckedInAt = now
+			return result, nil
+		}
+
+		// Checked in by a concurrent request, read the time that was stored
+		if participant, err = s.ParticipantRepo.GetByID(participant.ID); err != nil {
+			return dto.CheckInResult{}, err
+		}
+	}
+
+	result.AlreadyCheckedIn = true
+	if participant.CheckedInAt != nil {
+		result.CheckedInAt = *participant.CheckedInAt
+	}
+	return result, nil
+}
+
+// GetAttendance returns the live check-in count of the event
+func (s *ParticipantService) GetAttendance(eventId string, scope filter.RegionScope) (dto.EventAttendance, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return dto.EventAttendance{}, err
+	}
+
+	registered, checkedIn, lastCheckInAt, err := s.ParticipantRepo.CountCheckIns(eventId)
+	if err != nil {
+		return dto.EventAttendance{}, err
+	}
+
+	_, closesAt := checkInWindow(event)
+
+	return dto.EventAttendance{
+		EventId:       event.ID,
+		Registered:    registered,
+		CheckedIn:     checkedIn,
+		NotCheckedIn:  registered - checkedIn,
+		LastCheckInAt: lastCheckInAt,
+		CheckInOpen:   checkInOpen(event, time.Now()),
+		ClosesAt:      closesAt,
+	}, nil
+}
+
+// getCheckInEvent resolves a check-in token, an unknown or deleted event makes the link invalid
+func (s *ParticipantService) getCheckInEvent(token string) (domainevent.Event, error) {
+	secret, err := checkInSecret()
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+
+	eventId, err := security.VerifySignedValue(secret, checkInTokenPurpose, token)
+	if err != nil {
+		return domainevent.Event{}, errors.New(messages.ErrInvalidCheckIn)
+	}
+
+	event, err := s.EventRepo.GetByID(eventId, filter.RegionScope{})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainevent.Event{}, errors.New(messages.ErrInvalidCheckIn)
+		}
+		return domainevent.Event{}, err
+	}
+	return event, nil
+}
+
+// checkInSecret signs check-in tokens with CHECKIN_TOKEN_SECRET, or with JWT_KEY when it is not set
+func checkInSecret() (string, error) {
+	secret := utils.GetEnv("CHECKIN_TOKEN_SECRET", "").(string)
+	if secret == "" {
+		secret = utils.GetEnv("JWT_KEY", "").(string)
+	}
+	if secret == "" {
+		return "", errors.New("check-in token secret is not configured")
+	}
+	return secret, nil
+}
+
+func checkInOpen(event domainevent.Event, now time.Time) bool {
+	opensAt, closesAt := checkInWindow(event)
+	return !strings.EqualFold(event.Status, utils.StsCancelled) && !now.Before(opensAt) && now.Before(closesAt)
+}
+
+// checkInWindow returns when check-in opens and closes. It opens at the start of the event day and closes at the
+// event end time, or at the end of the day when the end time is missing. Event times are local wall clock times.
+func checkInWindow(event domainevent.Event) (opensAt, closesAt time.Time) {
+	day, err := time.ParseInLocation("2006-01-02", event.EventDate, time.Local)
+	if err != nil {
+		// Without a valid date the event can never be checked in
+		return time.Time{}, time.Time{}
+	}
+
+	opensAt, closesAt = day, day.AddDate(0, 0, 1)
+	if end, err := utils.ParseEventDateTime(event.EventDate, event.EndTime); err == nil {
+		if end := time.Date(end.Year(), end.Month(), end.Day(), end.Hour(), end.Minute(), end.Second(), 0, time.Local); end.After(day) {
+			closesAt = end
+		}
+	}
+	return opensAt, closesAt
+}
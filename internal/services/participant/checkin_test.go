@@ -0,0 +1,60 @@
+package serviceparticipant
+
+import (
+	"testing"
+	"time"
+
+	domainevent "safety-riding/internal/domain/event"
+	"safety-riding/utils"
+)
+
+func TestCheckInWindow(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+
+	cases := []struct {
+		name      string
+		endTime   string
+		wantClose time.Time
+	}{
+		{"closes at the end time", "11:30", day.Add(11*time.Hour + 30*time.Minute)},
+		{"end time with seconds", "15:00:00", day.Add(15 * time.Hour)},
+		{"missing end time closes at midnight", "", day.AddDate(0, 0, 1)},
+		{"midnight end time closes at the end of the day", "00:00", day.AddDate(0, 0, 1)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opensAt, closesAt := checkInWindow(domainevent.Event{EventDate: "2026-03-10", EndTime: c.endTime})
+			if !opensAt.Equal(day) || !closesAt.Equal(c.wantClose) {
+				t.Fatalf("checkInWindow() = %v - %v, want %v - %v", opensAt, closesAt, day, c.wantClose)
+			}
+		})
+	}
+}
+
+func TestCheckInOpen(t *testing.T) {
+	event := domainevent.Event{EventDate: "2026-03-10", EndTime: "12:00", Status: utils.StsOnGoing}
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"day before", day.Add(-time.Minute), false},
+		{"during the event", day.Add(9 * time.Hour), true},
+		{"after the end time", day.Add(12 * time.Hour), false},
+	}
+	for _, c := range cases {
+		if got := checkInOpen(event, c.now); got != c.want {
+			t.Fatalf("checkInOpen(%s) = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	event.Status = utils.StsCancelled
+	if checkInOpen(event, day.Add(9*time.Hour)) {
+		t.Fatalf("checkInOpen() = true for a cancelled event")
+	}
+	if checkInOpen(domainevent.Event{EventDate: "10/03/2026"}, day.Add(9*time.Hour)) {
+		t.Fatalf("checkInOpen() = true for an invalid event date")
+	}
+}
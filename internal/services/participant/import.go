@@ -0,0 +1,181 @@
+package serviceparticipant
+
+import (
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/spreadsheet"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	importStatusCreated  = "created"
+	importStatusRejected = "rejected"
+)
+
+// importParticipantRow is a parsed spreadsheet row waiting to be validated and stored
+type importParticipantRow struct {
+	Row     int
+	Data    dto.AddParticipant
+	Reasons []string
+}
+
+// ImportParticipants adds the roster rows of a CSV or XLSX file to the event.
+// Invalid rows and people already on the roster are rejected while the other rows are stored.
+// When dryRun is true nothing is written and the report shows what would happen.
+func (s *ParticipantService) ImportParticipants(eventId, username string, scope filter.RegionScope, fileHeader *multipart.FileHeader, dryRun bool) (dto.ImportParticipantResponse, error) {
+	if _, err := s.getRosterEvent(eventId, scope); err != nil {
+		return dto.ImportParticipantResponse{}, err
+	}
+
+	format, err := spreadsheet.DetectFormat(fileHeader.Filename)
+	if err != nil {
+		return dto.ImportParticipantResponse{}, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return dto.ImportParticipantResponse{}, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	records, err := spreadsheet.ReadRows(file, format)
+	if err != nil {
+		return dto.ImportParticipantResponse{}, fmt.Errorf("failed to read file %s: %w", fileHeader.Filename, err)
+	}
+
+	rows, err := parseImportParticipantRows(records)
+	if err != nil {
+		return dto.ImportParticipantResponse{}, err
+	}
+
+	maxRows := utils.GetEnv("MAX_IMPORT_ROWS", 5000).(int)
+	if len(rows) > maxRows {
+		return dto.ImportParticipantResponse{}, fmt.Errorf("maximum %d rows are allowed per import (got %d)", maxRows, len(rows))
+	}
+
+	existing, err := s.ParticipantRepo.GetIdentityKeys(eventId)
+	if err != nil {
+		return dto.ImportParticipantResponse{}, err
+	}
+
+	result := dto.ImportParticipantResponse{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Rows:      make([]dto.ImportParticipantRowResult, 0, len(rows)),
+	}
+
+	now := time.Now()
+	seen := make(map[string]int, len(rows))
+	participants := make([]domainparticipant.EventParticipant, 0, len(rows))
+	for _, row := range rows {
+		participant := newParticipant(eventId, username, now, row.Data)
+		report := dto.ImportParticipantRowResult{
+			Row:     row.Row,
+			Name:    participant.Name,
+			Reasons: row.Reasons,
+		}
+
+		if existing[participant.IdentityKey] {
+			report.Reasons = append(report.Reasons, "already on the roster")
+		} else if firstRow, ok := seen[participant.IdentityKey]; ok {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("duplicate participant, already listed in row %d", firstRow))
+		} else {
+			seen[participant.IdentityKey] = row.Row
+		}
+
+		if len(report.Reasons) > 0 {
+			report.Status = importStatusRejected
+			result.Rejected++
+		} else {
+			report.Status = importStatusCreated
+			report.ParticipantId = participant.ID
+			participants = append(participants, participant)
+			result.Created++
+		}
+		result.Rows = append(result.Rows, report)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.ParticipantRepo.CreateBatch(participants); err != nil {
+		return dto.ImportParticipantResponse{}, err
+	}
+	for _, participant := range participants {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEventParticipant, participant.ID, nil, participant)
+	}
+
+	return result, nil
+}
+
+// parseImportParticipantRows converts spreadsheet records into validated import rows.
+// The first record must be the header row using the dto.AddParticipant json field names.
+func parseImportParticipantRows(records [][]string) ([]importParticipantRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	headers := make([]string, len(records[0]))
+	hasName := false
+	for i, h := range records[0] {
+		headers[i] = spreadsheet.NormalizeHeader(h)
+		if headers[i] == "name" {
+			hasName = true
+		}
+	}
+	if !hasName {
+		return nil, fmt.Errorf("missing required column: name")
+	}
+
+	rows := make([]importParticipantRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if spreadsheet.IsEmptyRow(record) {
+			continue
+		}
+
+		// Row numbers follow the spreadsheet, the header is row 1
+		rows = append(rows, parseImportParticipantRow(i+2, spreadsheet.RowMap(headers, record)))
+	}
+
+	return rows, nil
+}
+
+func parseImportParticipantRow(rowNumber int, data map[string]string) importParticipantRow {
+	row := importParticipantRow{Row: rowNumber}
+	row.Data = dto.AddParticipant{
+		Name:          data["name"],
+		Gender:        strings.ToLower(data["gender"]),
+		Grade:         data["grade"],
+		Organization:  data["organization"],
+		Phone:         data["phone"],
+		LicenseStatus: strings.ToLower(data["license_status"]),
+	}
+
+	if value := data["age"]; value != "" {
+		age, err := strconv.Atoi(value)
+		if err != nil {
+			row.Reasons = append(row.Reasons, "age: must be a whole number")
+		}
+		row.Data.Age = age
+	}
+
+	if err := binding.Validator.ValidateStruct(row.Data); err != nil {
+		for _, msg := range utils.ValidateError(err, reflect.TypeOf(row.Data), "json") {
+			row.Reasons = append(row.Reasons, fmt.Sprintf("%s: %s", msg.Field, msg.Message))
+		}
+	}
+
+	return row
+}
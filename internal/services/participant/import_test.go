@@ -0,0 +1,57 @@
+package serviceparticipant
+
+import "testing"
+
+func TestParseImportParticipantRows(t *testing.T) {
+	header := []string{"Name", "Gender", "Age", "Grade", "Organization", "Phone", "License Status"}
+	valid := []string{"Budi Santoso", "Male", "17", "XI", "SMA Negeri 1", "081234567", "in_process"}
+
+	tests := []struct {
+		name        string
+		records     [][]string
+		wantErr     bool
+		wantRows    int
+		wantReasons []int
+	}{
+		{name: "empty file", records: nil, wantErr: true},
+		{name: "missing name column", records: [][]string{{"gender", "age"}}, wantErr: true},
+		{name: "valid row", records: [][]string{header, valid}, wantRows: 1, wantReasons: []int{0}},
+		{name: "only name is required", records: [][]string{{"name"}, {"Siti"}}, wantRows: 1, wantReasons: []int{0}},
+		{name: "blank rows are skipped", records: [][]string{header, {"", " "}, valid}, wantRows: 1, wantReasons: []int{0}},
+		{
+			name:        "invalid gender, age and license are rejected",
+			records:     [][]string{header, {"Budi", "x", "seventeen", "XI", "", "", "unknown"}},
+			wantRows:    1,
+			wantReasons: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportParticipantRows(tt.records)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImportParticipantRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rows) != tt.wantRows {
+				t.Fatalf("parseImportParticipantRows() returned %d rows, want %d", len(rows), tt.wantRows)
+			}
+			for i, row := range rows {
+				if len(row.Reasons) != tt.wantReasons[i] {
+					t.Fatalf("row %d has %d reasons (%v), want %d", row.Row, len(row.Reasons), row.Reasons, tt.wantReasons[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIdentityKey(t *testing.T) {
+	if identityKey("Budi  Santoso", "6281234567", "SMA 1") != identityKey("budi santoso", "6281234567", "SMA 2") {
+		t.Fatalf("identityKey() should ignore the organization when the phone is known")
+	}
+	if identityKey("Budi", "", "SMA 1") == identityKey("Budi", "", "SMA 2") {
+		t.Fatalf("identityKey() should tell people apart by organization when the phone is missing")
+	}
+}
@@ -0,0 +1,143 @@
+package serviceparticipant
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	domainevent "safety-riding/internal/domain/event"
+	domainparticipant "safety-riding/internal/domain/participant"
+	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
+	interfaceevent "safety-riding/internal/interfaces/event"
+	interfaceparticipant "safety-riding/internal/interfaces/participant"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+
+	"gorm.io/gorm"
+)
+
+type ParticipantService struct {
+	ParticipantRepo interfaceparticipant.RepoParticipantInterface
+	EventRepo       interfaceevent.RepoEventInterface
+	AuditRecorder   interfaceauditlog.AuditRecorder
+}
+
+func NewParticipantService(participantRepo interfaceparticipant.RepoParticipantInterface, eventRepo interfaceevent.RepoEventInterface, auditRecorder interfaceauditlog.AuditRecorder) *ParticipantService {
+	return &ParticipantService{
+		ParticipantRepo: participantRepo,
+		EventRepo:       eventRepo,
+		AuditRecorder:   auditRecorder,
+	}
+}
+
+// AddParticipants adds people to the roster of an event, the whole request is rejected when one of them is already on it
+func (s *ParticipantService) AddParticipants(eventId, username string, scope filter.RegionScope, req dto.AddParticipants) ([]domainparticipant.EventParticipant, error) {
+	if _, err := s.getRosterEvent(eventId, scope); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ParticipantRepo.GetIdentityKeys(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	participants := make([]domainparticipant.EventParticipant, 0, len(req.Participants))
+	for _, item := range req.Participants {
+		participant := newParticipant(eventId, username, now, item)
+		if existing[participant.IdentityKey] {
+			return nil, fmt.Errorf("participant %q is already on the roster", participant.Name)
+		}
+		existing[participant.IdentityKey] = true
+		participants = append(participants, participant)
+	}
+
+	if err := s.ParticipantRepo.CreateBatch(participants); err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityEventParticipant, participant.ID, nil, participant)
+	}
+
+	return participants, nil
+}
+
+func (s *ParticipantService) FetchParticipants(eventId string, params filter.BaseParams) ([]domainparticipant.EventParticipant, int64, error) {
+	if _, err := s.EventRepo.GetByID(eventId, params.Scope); err != nil {
+		return nil, 0, err
+	}
+
+	return s.ParticipantRepo.FetchByEvent(eventId, params)
+}
+
+func (s *ParticipantService) DeleteParticipant(eventId, participantId, username string, scope filter.RegionScope) error {
+	if _, err := s.getRosterEvent(eventId, scope); err != nil {
+		return err
+	}
+
+	participant, err := s.ParticipantRepo.GetByID(participantId)
+	if err != nil {
+		return err
+	}
+	if participant.EventId != eventId {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := s.ParticipantRepo.Delete(participantId); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityEventParticipant, participantId, participant, nil)
+
+	return nil
+}
+
+// getRosterEvent returns the event when its roster can be changed, nobody attends a cancelled event
+func (s *ParticipantService) getRosterEvent(eventId string, scope filter.RegionScope) (domainevent.Event, error) {
+	event, err := s.EventRepo.GetByID(eventId, scope)
+	if err != nil {
+		return domainevent.Event{}, err
+	}
+	if strings.EqualFold(event.Status, utils.StsCancelled) {
+		return domainevent.Event{}, errors.New("cannot change the roster of a cancelled event")
+	}
+	return event, nil
+}
+
+func newParticipant(eventId, username string, now time.Time, req dto.AddParticipant) domainparticipant.EventParticipant {
+	participant := domainparticipant.EventParticipant{
+		ID:            utils.CreateUUID(),
+		EventId:       eventId,
+		Name:          strings.Join(strings.Fields(req.Name), " "),
+		Gender:        strings.ToLower(req.Gender),
+		Age:           req.Age,
+		Grade:         strings.TrimSpace(req.Grade),
+		Organization:  strings.TrimSpace(req.Organization),
+		LicenseStatus: strings.ToLower(req.LicenseStatus),
+		CreatedAt:     now,
+		CreatedBy:     username,
+	}
+	if phone := strings.TrimSpace(req.Phone); phone != "" {
+		participant.Phone = utils.NormalizePhoneTo62(phone)
+	}
+	participant.IdentityKey = identityKey(participant.Name, participant.Phone, participant.Organization)
+
+	return participant
+}
+
+// identityKey recognizes the same person across events by the normalized name and phone number,
+// or by name and organization when the phone is unknown
+func identityKey(name, phone, organization string) string {
+	normalize := func(v string) string {
+		return strings.Join(strings.Fields(strings.ToLower(v)), " ")
+	}
+
+	if phone != "" {
+		return normalize(name) + "|" + phone
+	}
+	return normalize(name) + "|" + normalize(organization)
+}
+
+var _ interfaceparticipant.ServiceParticipantInterface = (*ParticipantService)(nil)
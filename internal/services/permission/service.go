@@ -2,8 +2,10 @@ package servicepermission
 
 import (
 	"errors"
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	"safety-riding/internal/domain/permission"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	"safety-riding/internal/interfaces/permission"
 	"safety-riding/pkg/filter"
 	"safety-riding/utils"
@@ -12,15 +14,17 @@ import (
 
 type PermissionService struct {
 	PermissionRepo interfacepermission.RepoPermissionInterface
+	AuditRecorder  interfaceauditlog.AuditRecorder
 }
 
-func NewPermissionService(permissionRepo interfacepermission.RepoPermissionInterface) *PermissionService {
+func NewPermissionService(permissionRepo interfacepermission.RepoPermissionInterface, auditRecorder interfaceauditlog.AuditRecorder) *PermissionService {
 	return &PermissionService{
 		PermissionRepo: permissionRepo,
+		AuditRecorder:  auditRecorder,
 	}
 }
 
-func (s *PermissionService) Create(req dto.PermissionCreate) (domainpermission.Permission, error) {
+func (s *PermissionService) Create(username string, req dto.PermissionCreate) (domainpermission.Permission, error) {
 	// Check if permission with same name already exists
 	existing, _ := s.PermissionRepo.GetByName(req.Name)
 	if existing.Id != "" {
@@ -40,6 +44,7 @@ func (s *PermissionService) Create(req dto.PermissionCreate) (domainpermission.P
 	if err := s.PermissionRepo.Store(data); err != nil {
 		return domainpermission.Permission{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityPermission, data.Id, nil, data)
 
 	return data, nil
 }
@@ -60,11 +65,12 @@ func (s *PermissionService) GetUserPermissions(userId string) ([]domainpermissio
 	return s.PermissionRepo.GetUserPermissions(userId)
 }
 
-func (s *PermissionService) Update(id string, req dto.PermissionUpdate) (domainpermission.Permission, error) {
+func (s *PermissionService) Update(id, username string, req dto.PermissionUpdate) (domainpermission.Permission, error) {
 	permission, err := s.PermissionRepo.GetByID(id)
 	if err != nil {
 		return domainpermission.Permission{}, err
 	}
+	before := permission
 
 	// Update only provided fields
 	if req.DisplayName != "" {
@@ -83,12 +89,23 @@ func (s *PermissionService) Update(id string, req dto.PermissionUpdate) (domainp
 	if err := s.PermissionRepo.Update(permission); err != nil {
 		return domainpermission.Permission{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityPermission, id, before, permission)
 
 	return permission, nil
 }
 
-func (s *PermissionService) Delete(id string) error {
-	return s.PermissionRepo.Delete(id)
+func (s *PermissionService) Delete(id, username string) error {
+	permission, err := s.PermissionRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.PermissionRepo.Delete(id); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityPermission, id, permission, nil)
+
+	return nil
 }
 
 var _ interfacepermission.ServicePermissionInterface = (*PermissionService)(nil)
@@ -1,8 +1,10 @@
 package servicepublic
 
 import (
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	domainpublic "safety-riding/internal/domain/publics"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	interfacespublic "safety-riding/internal/interfaces/publics"
 	"safety-riding/pkg/filter"
 	"safety-riding/utils"
@@ -11,12 +13,14 @@ import (
 )
 
 type PublicService struct {
-	PublicRepo interfacespublic.RepoPublicInterface
+	PublicRepo    interfacespublic.RepoPublicInterface
+	AuditRecorder interfaceauditlog.AuditRecorder
 }
 
-func NewPublicService(publicRepo interfacespublic.RepoPublicInterface) *PublicService {
+func NewPublicService(publicRepo interfacespublic.RepoPublicInterface, auditRecorder interfaceauditlog.AuditRecorder) *PublicService {
 	return &PublicService{
-		PublicRepo: publicRepo,
+		PublicRepo:    publicRepo,
+		AuditRecorder: auditRecorder,
 	}
 }
 
@@ -28,7 +32,6 @@ func (s *PublicService) AddPublic(username string, req dto.AddPublic) (domainpub
 
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"employee_count": req.EmployeeCount,
-		"visit_count":    req.VisitCount,
 	}); err != nil {
 		return domainpublic.Public{}, err
 	}
@@ -50,9 +53,6 @@ func (s *PublicService) AddPublic(username string, req dto.AddPublic) (domainpub
 		Latitude:      req.Latitude,
 		Longitude:     req.Longitude,
 		EmployeeCount: req.EmployeeCount,
-		VisitCount:    req.VisitCount,
-		IsEducated:    req.IsEducated,
-		LastVisitAt:   req.LastVisitAt,
 		CreatedAt:     time.Now(),
 		CreatedBy:     username,
 	}
@@ -60,6 +60,7 @@ func (s *PublicService) AddPublic(username string, req dto.AddPublic) (domainpub
 	if err := s.PublicRepo.Create(data); err != nil {
 		return domainpublic.Public{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityPublic, data.ID, nil, data)
 
 	return data, nil
 }
@@ -71,7 +72,6 @@ func (s *PublicService) GetPublicById(id string) (domainpublic.Public, error) {
 func (s *PublicService) UpdatePublic(id, username string, req dto.UpdatePublic) (domainpublic.Public, error) {
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"employee_count": req.EmployeeCount,
-		"visit_count":    req.VisitCount,
 	}); err != nil {
 		return domainpublic.Public{}, err
 	}
@@ -81,6 +81,7 @@ func (s *PublicService) UpdatePublic(id, username string, req dto.UpdatePublic)
 	if err != nil {
 		return domainpublic.Public{}, err
 	}
+	before := public
 
 	// Update fields if provided
 	if req.Name != "" {
@@ -129,13 +130,6 @@ func (s *PublicService) UpdatePublic(id, username string, req dto.UpdatePublic)
 	if req.EmployeeCount != 0 {
 		public.EmployeeCount = req.EmployeeCount
 	}
-	if req.VisitCount != 0 {
-		public.VisitCount = req.VisitCount
-	}
-	public.IsEducated = req.IsEducated
-	if req.LastVisitAt != nil {
-		public.LastVisitAt = req.LastVisitAt
-	}
 
 	public.UpdatedAt = time.Now()
 	public.UpdatedBy = username
@@ -143,6 +137,7 @@ func (s *PublicService) UpdatePublic(id, username string, req dto.UpdatePublic)
 	if err := s.PublicRepo.Update(public); err != nil {
 		return domainpublic.Public{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityPublic, id, before, public)
 
 	return public, nil
 }
@@ -165,6 +160,7 @@ func (s *PublicService) DeletePublic(id, username string) error {
 	if err := s.PublicRepo.Delete(id); err != nil {
 		return err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityPublic, id, public, nil)
 
 	return nil
 }
@@ -2,10 +2,12 @@ package servicerole
 
 import (
 	"errors"
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	domainmenu "safety-riding/internal/domain/menu"
 	domainpermission "safety-riding/internal/domain/permission"
 	"safety-riding/internal/domain/role"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	"safety-riding/internal/interfaces/menu"
 	"safety-riding/internal/interfaces/permission"
 	"safety-riding/internal/interfaces/role"
@@ -18,21 +20,24 @@ type RoleService struct {
 	RoleRepo       interfacerole.RepoRoleInterface
 	PermissionRepo interfacepermission.RepoPermissionInterface
 	MenuRepo       interfacemenu.RepoMenuInterface
+	AuditRecorder  interfaceauditlog.AuditRecorder
 }
 
 func NewRoleService(
 	roleRepo interfacerole.RepoRoleInterface,
 	permissionRepo interfacepermission.RepoPermissionInterface,
 	menuRepo interfacemenu.RepoMenuInterface,
+	auditRecorder interfaceauditlog.AuditRecorder,
 ) *RoleService {
 	return &RoleService{
 		RoleRepo:       roleRepo,
 		PermissionRepo: permissionRepo,
 		MenuRepo:       menuRepo,
+		AuditRecorder:  auditRecorder,
 	}
 }
 
-func (s *RoleService) Create(req dto.RoleCreate) (domainrole.Role, error) {
+func (s *RoleService) Create(username string, req dto.RoleCreate) (domainrole.Role, error) {
 	// Check if role with same name already exists
 	existing, _ := s.RoleRepo.GetByName(req.Name)
 	if existing.Id != "" {
@@ -51,6 +56,7 @@ func (s *RoleService) Create(req dto.RoleCreate) (domainrole.Role, error) {
 	if err := s.RoleRepo.Store(data); err != nil {
 		return domainrole.Role{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntityRole, data.Id, nil, data)
 
 	return data, nil
 }
@@ -115,7 +121,7 @@ func (s *RoleService) GetAll(params filter.BaseParams, currentUserRole string) (
 	return roles, total, nil
 }
 
-func (s *RoleService) Update(id string, req dto.RoleUpdate) (domainrole.Role, error) {
+func (s *RoleService) Update(id, username string, req dto.RoleUpdate) (domainrole.Role, error) {
 	role, err := s.RoleRepo.GetByID(id)
 	if err != nil {
 		return domainrole.Role{}, err
@@ -125,6 +131,7 @@ func (s *RoleService) Update(id string, req dto.RoleUpdate) (domainrole.Role, er
 	if role.IsSystem {
 		return domainrole.Role{}, errors.New("cannot update system roles")
 	}
+	before := role
 
 	// Update only provided fields
 	if req.DisplayName != "" {
@@ -137,11 +144,13 @@ func (s *RoleService) Update(id string, req dto.RoleUpdate) (domainrole.Role, er
 	if err := s.RoleRepo.Update(role); err != nil {
 		return domainrole.Role{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntityRole, id, before, role)
+	s.invalidatePermissionCache()
 
 	return role, nil
 }
 
-func (s *RoleService) Delete(id string) error {
+func (s *RoleService) Delete(id, username string) error {
 	role, err := s.RoleRepo.GetByID(id)
 	if err != nil {
 		return err
@@ -152,10 +161,16 @@ func (s *RoleService) Delete(id string) error {
 		return errors.New("cannot delete system roles")
 	}
 
-	return s.RoleRepo.Delete(id)
+	if err := s.RoleRepo.Delete(id); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntityRole, id, role, nil)
+	s.invalidatePermissionCache()
+
+	return nil
 }
 
-func (s *RoleService) AssignPermissions(roleId string, req dto.AssignPermissions, currentUserRole string) error {
+func (s *RoleService) AssignPermissions(roleId, username string, req dto.AssignPermissions, currentUserRole string) error {
 	// Verify role exists
 	role, err := s.RoleRepo.GetByID(roleId)
 	if err != nil {
@@ -177,7 +192,28 @@ func (s *RoleService) AssignPermissions(roleId string, req dto.AssignPermissions
 		}
 	}
 
-	return s.RoleRepo.AssignPermissions(roleId, req.PermissionIds)
+	currentPermissionIds, err := s.RoleRepo.GetRolePermissions(roleId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.RoleRepo.AssignPermissions(roleId, req.PermissionIds); err != nil {
+		return err
+	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionAssignPermissions, domainauditlog.EntityRole, roleId,
+		map[string]interface{}{"permission_ids": currentPermissionIds},
+		map[string]interface{}{"permission_ids": req.PermissionIds},
+	)
+	s.invalidatePermissionCache()
+
+	return nil
+}
+
+// invalidatePermissionCache drops cached user permissions, every user holding the role may be affected
+func (s *RoleService) invalidatePermissionCache() {
+	if invalidator, ok := s.PermissionRepo.(interfacepermission.PermissionCacheInvalidator); ok {
+		invalidator.InvalidateAll()
+	}
 }
 
 func (s *RoleService) GetRolePermissions(roleId string) ([]string, error) {
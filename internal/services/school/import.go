@@ -0,0 +1,290 @@
+package serviceschool
+
+import (
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	domainauditlog "safety-riding/internal/domain/auditlog"
+	"safety-riding/internal/domain/school"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/spreadsheet"
+	"safety-riding/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	importStatusCreated  = "created"
+	importStatusUpdated  = "updated"
+	importStatusRejected = "rejected"
+)
+
+// importSchoolRow is a parsed spreadsheet row waiting to be validated and stored
+type importSchoolRow struct {
+	Row     int
+	Data    dto.AddSchool
+	Reasons []string
+}
+
+// ImportSchools imports schools from a CSV or XLSX file, matching existing schools by NPSN.
+// When dryRun is true nothing is written and the report shows what would happen.
+// Rows located outside the user's region, or matching a school outside it, are rejected.
+func (s *SchoolService) ImportSchools(username string, scope filter.RegionScope, fileHeader *multipart.FileHeader, dryRun bool) (dto.ImportSchoolResponse, error) {
+	format, err := spreadsheet.DetectFormat(fileHeader.Filename)
+	if err != nil {
+		return dto.ImportSchoolResponse{}, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return dto.ImportSchoolResponse{}, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	records, err := spreadsheet.ReadRows(file, format)
+	if err != nil {
+		return dto.ImportSchoolResponse{}, fmt.Errorf("failed to read file %s: %w", fileHeader.Filename, err)
+	}
+
+	rows, err := parseImportSchoolRows(records)
+	if err != nil {
+		return dto.ImportSchoolResponse{}, err
+	}
+
+	maxRows := utils.GetEnv("MAX_IMPORT_ROWS", 5000).(int)
+	if len(rows) > maxRows {
+		return dto.ImportSchoolResponse{}, fmt.Errorf("maximum %d rows are allowed per import (got %d)", maxRows, len(rows))
+	}
+
+	npsns := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Data.NPSN != "" {
+			npsns = append(npsns, row.Data.NPSN)
+		}
+	}
+
+	existing, err := s.SchoolRepo.GetByNPSNs(npsns)
+	if err != nil {
+		return dto.ImportSchoolResponse{}, err
+	}
+
+	existingByNPSN := make(map[string]domainschool.School, len(existing))
+	for _, sc := range existing {
+		existingByNPSN[sc.NPSN] = sc
+	}
+
+	result := dto.ImportSchoolResponse{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Rows:      make([]dto.ImportSchoolRowResult, 0, len(rows)),
+	}
+
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		report := dto.ImportSchoolRowResult{
+			Row:     row.Row,
+			NPSN:    row.Data.NPSN,
+			Name:    row.Data.Name,
+			Reasons: row.Reasons,
+		}
+
+		if firstRow, ok := seen[row.Data.NPSN]; ok && row.Data.NPSN != "" {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("duplicate npsn, already used in row %d", firstRow))
+		} else if row.Data.NPSN != "" {
+			seen[row.Data.NPSN] = row.Row
+		}
+
+		current, exists := existingByNPSN[row.Data.NPSN]
+		if !scope.Allows(row.Data.ProvinceId, row.Data.CityId) || (exists && !scope.Allows(current.ProvinceId, current.CityId)) {
+			report.Reasons = append(report.Reasons, messages.ErrOutOfRegion)
+		}
+
+		if len(report.Reasons) > 0 {
+			report.Status = importStatusRejected
+			result.Rejected++
+			result.Rows = append(result.Rows, report)
+			continue
+		}
+
+		if exists {
+			report.Status = importStatusUpdated
+			report.SchoolId = current.ID
+			if !dryRun {
+				before := current
+				applySchoolImport(&current, row.Data)
+				current.UpdatedAt = time.Now()
+				current.UpdatedBy = username
+				if err := s.SchoolRepo.Update(current); err != nil {
+					report.Status = importStatusRejected
+					report.Reasons = []string{err.Error()}
+				} else {
+					s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntitySchool, current.ID, before, current)
+				}
+			}
+		} else {
+			report.Status = importStatusCreated
+			if !dryRun {
+				data, err := s.AddSchool(username, scope, row.Data)
+				if err != nil {
+					report.Status = importStatusRejected
+					report.Reasons = []string{err.Error()}
+				}
+				report.SchoolId = data.ID
+			}
+		}
+
+		switch report.Status {
+		case importStatusCreated:
+			result.Created++
+		case importStatusUpdated:
+			result.Updated++
+		default:
+			result.Rejected++
+		}
+		result.Rows = append(result.Rows, report)
+	}
+
+	return result, nil
+}
+
+// parseImportSchoolRows converts spreadsheet records into validated import rows.
+// The first record must be the header row using the dto.AddSchool json field names.
+func parseImportSchoolRows(records [][]string) ([]importSchoolRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	headers := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		headers[i] = spreadsheet.NormalizeHeader(h)
+	}
+
+	hasNPSN := false
+	for _, h := range headers {
+		if h == "npsn" {
+			hasNPSN = true
+			break
+		}
+	}
+	if !hasNPSN {
+		return nil, fmt.Errorf("missing required column: npsn")
+	}
+
+	rows := make([]importSchoolRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if spreadsheet.IsEmptyRow(record) {
+			continue
+		}
+
+		// Row numbers follow the spreadsheet, the header is row 1
+		rows = append(rows, parseImportSchoolRow(i+2, spreadsheet.RowMap(headers, record)))
+	}
+
+	return rows, nil
+}
+
+func parseImportSchoolRow(rowNumber int, data map[string]string) importSchoolRow {
+	row := importSchoolRow{Row: rowNumber}
+	row.Data = dto.AddSchool{
+		Name:         data["name"],
+		NPSN:         data["npsn"],
+		Address:      data["address"],
+		Phone:        data["phone"],
+		Email:        data["email"],
+		DistrictId:   data["district_id"],
+		DistrictName: data["district_name"],
+		CityId:       data["city_id"],
+		CityName:     data["city_name"],
+		ProvinceId:   data["province_id"],
+		ProvinceName: data["province_name"],
+		PostalCode:   data["postal_code"],
+	}
+
+	row.Data.Latitude = parseImportFloat(data, "latitude", &row.Reasons)
+	row.Data.Longitude = parseImportFloat(data, "longitude", &row.Reasons)
+	row.Data.StudentCount = parseImportInt(data, "student_count", &row.Reasons)
+	row.Data.TeacherCount = parseImportInt(data, "teacher_count", &row.Reasons)
+	row.Data.MajorCount = parseImportInt(data, "major_count", &row.Reasons)
+
+	if err := binding.Validator.ValidateStruct(row.Data); err != nil {
+		for _, msg := range utils.ValidateError(err, reflect.TypeOf(row.Data), "json") {
+			row.Reasons = append(row.Reasons, fmt.Sprintf("%s: %s", msg.Field, msg.Message))
+		}
+	}
+
+	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
+		"student_count": row.Data.StudentCount,
+		"teacher_count": row.Data.TeacherCount,
+		"major_count":   row.Data.MajorCount,
+	}); err != nil {
+		row.Reasons = append(row.Reasons, err.Error())
+	}
+
+	return row
+}
+
+func parseImportInt(data map[string]string, key string, reasons *[]string) int {
+	value := data[key]
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		*reasons = append(*reasons, fmt.Sprintf("%s: must be a whole number", key))
+		return 0
+	}
+	return n
+}
+
+func parseImportFloat(data map[string]string, key string, reasons *[]string) float64 {
+	value := strings.ReplaceAll(data[key], ",", ".")
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		*reasons = append(*reasons, fmt.Sprintf("%s: must be a number", key))
+		return 0
+	}
+	return f
+}
+
+// applySchoolImport overwrites the school profile with the imported row.
+// Visit statistics are left untouched since they are not part of the import.
+func applySchoolImport(school *domainschool.School, req dto.AddSchool) {
+	school.Name = strings.ToUpper(req.Name)
+	school.Address = req.Address
+	school.Phone = utils.NormalizePhoneTo62(req.Phone)
+	school.Email = req.Email
+	school.DistrictId = req.DistrictId
+	school.DistrictName = req.DistrictName
+	school.CityId = req.CityId
+	school.CityName = req.CityName
+	school.ProvinceId = req.ProvinceId
+	school.ProvinceName = req.ProvinceName
+	if req.PostalCode != "" {
+		school.PostalCode = req.PostalCode
+	}
+	if req.Latitude != 0 {
+		school.Latitude = req.Latitude
+	}
+	if req.Longitude != 0 {
+		school.Longitude = req.Longitude
+	}
+	if req.StudentCount != 0 {
+		school.StudentCount = req.StudentCount
+	}
+	if req.TeacherCount != 0 {
+		school.TeacherCount = req.TeacherCount
+	}
+	if req.MajorCount != 0 {
+		school.MajorCount = req.MajorCount
+	}
+}
@@ -0,0 +1,55 @@
+package serviceschool
+
+import "testing"
+
+func TestParseImportSchoolRows(t *testing.T) {
+	header := []string{"Name", "NPSN", "Address", "Phone", "Email", "District ID", "District Name", "City ID", "City Name", "Province ID", "Province Name", "Student Count"}
+	valid := []string{"SMA Negeri 1", "50100001", "Jl. Udayana No. 1", "081234567", "sman1@example.com", "5171010", "Denpasar Selatan", "5171", "Denpasar", "51", "Bali", "900"}
+
+	tests := []struct {
+		name        string
+		records     [][]string
+		wantErr     bool
+		wantRows    int
+		wantReasons []int
+	}{
+		{name: "empty file", records: nil, wantErr: true},
+		{name: "missing npsn column", records: [][]string{{"name", "address"}}, wantErr: true},
+		{name: "valid row", records: [][]string{header, valid}, wantRows: 1, wantReasons: []int{0}},
+		{name: "blank rows are skipped", records: [][]string{header, {"", " "}, valid}, wantRows: 1, wantReasons: []int{0}},
+		{
+			name: "invalid email and count are rejected",
+			records: [][]string{header, {
+				"SMA Negeri 2", "50100002", "Jl. Udayana No. 2", "081234567", "not-an-email", "5171010", "Denpasar Selatan", "5171", "Denpasar", "51", "Bali", "abc",
+			}},
+			wantRows:    1,
+			wantReasons: []int{2},
+		},
+		{
+			name:        "short row misses required fields",
+			records:     [][]string{header, {"SMA Negeri 3", "50100003"}},
+			wantRows:    1,
+			wantReasons: []int{9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportSchoolRows(tt.records)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImportSchoolRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rows) != tt.wantRows {
+				t.Fatalf("parseImportSchoolRows() returned %d rows, want %d", len(rows), tt.wantRows)
+			}
+			for i, row := range rows {
+				if len(row.Reasons) != tt.wantReasons[i] {
+					t.Fatalf("row %d has %d reasons (%v), want %d", row.Row, len(row.Reasons), row.Reasons, tt.wantReasons[i])
+				}
+			}
+		})
+	}
+}
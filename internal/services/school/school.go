@@ -1,8 +1,10 @@
 package serviceschool
 
 import (
+	domainauditlog "safety-riding/internal/domain/auditlog"
 	"safety-riding/internal/domain/school"
 	"safety-riding/internal/dto"
+	interfaceauditlog "safety-riding/internal/interfaces/auditlog"
 	interfaceschool "safety-riding/internal/interfaces/school"
 	"safety-riding/pkg/filter"
 	"safety-riding/utils"
@@ -11,22 +13,27 @@ import (
 )
 
 type SchoolService struct {
-	SchoolRepo interfaceschool.RepoSchoolInterface
+	SchoolRepo    interfaceschool.RepoSchoolInterface
+	AuditRecorder interfaceauditlog.AuditRecorder
 }
 
-func NewSchoolService(schoolRepo interfaceschool.RepoSchoolInterface) *SchoolService {
+func NewSchoolService(schoolRepo interfaceschool.RepoSchoolInterface, auditRecorder interfaceauditlog.AuditRecorder) *SchoolService {
 	return &SchoolService{
-		SchoolRepo: schoolRepo,
+		SchoolRepo:    schoolRepo,
+		AuditRecorder: auditRecorder,
 	}
 }
 
-func (s *SchoolService) AddSchool(username string, req dto.AddSchool) (domainschool.School, error) {
+func (s *SchoolService) AddSchool(username string, scope filter.RegionScope, req dto.AddSchool) (domainschool.School, error) {
+	if !scope.Allows(req.ProvinceId, req.CityId) {
+		return domainschool.School{}, filter.ErrOutOfRegion
+	}
+
 	phone := utils.NormalizePhoneTo62(req.Phone)
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"student_count": req.StudentCount,
 		"teacher_count": req.TeacherCount,
 		"major_count":   req.MajorCount,
-		"visit_count":   req.VisitCount,
 	}); err != nil {
 		return domainschool.School{}, err
 	}
@@ -49,9 +56,6 @@ func (s *SchoolService) AddSchool(username string, req dto.AddSchool) (domainsch
 		Longitude:    req.Longitude,
 		StudentCount: req.StudentCount,
 		TeacherCount: req.TeacherCount,
-		VisitCount:   req.VisitCount,
-		IsEducated:   req.IsEducated,
-		LastVisitAt:  req.LastVisitAt,
 		CreatedAt:    time.Now(),
 		CreatedBy:    username,
 	}
@@ -59,29 +63,30 @@ func (s *SchoolService) AddSchool(username string, req dto.AddSchool) (domainsch
 	if err := s.SchoolRepo.Create(data); err != nil {
 		return domainschool.School{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionCreate, domainauditlog.EntitySchool, data.ID, nil, data)
 
 	return data, nil
 }
 
-func (s *SchoolService) GetSchoolById(id string) (domainschool.School, error) {
-	return s.SchoolRepo.GetByID(id)
+func (s *SchoolService) GetSchoolById(id string, scope filter.RegionScope) (domainschool.School, error) {
+	return s.SchoolRepo.GetByID(id, scope)
 }
 
-func (s *SchoolService) UpdateSchool(id, username string, req dto.UpdateSchool) (domainschool.School, error) {
+func (s *SchoolService) UpdateSchool(id, username string, scope filter.RegionScope, req dto.UpdateSchool) (domainschool.School, error) {
 	if err := utils.ValidateNonNegativeBatch(map[string]interface{}{
 		"student_count": req.StudentCount,
 		"teacher_count": req.TeacherCount,
 		"major_count":   req.MajorCount,
-		"visit_count":   req.VisitCount,
 	}); err != nil {
 		return domainschool.School{}, err
 	}
 
 	// Get existing school
-	school, err := s.SchoolRepo.GetByID(id)
+	school, err := s.SchoolRepo.GetByID(id, scope)
 	if err != nil {
 		return domainschool.School{}, err
 	}
+	before := school
 
 	// Update fields if provided
 	if req.Name != "" {
@@ -133,12 +138,9 @@ func (s *SchoolService) UpdateSchool(id, username string, req dto.UpdateSchool)
 	if req.TeacherCount != 0 {
 		school.TeacherCount = req.TeacherCount
 	}
-	if req.VisitCount != 0 {
-		school.VisitCount = req.VisitCount
-	}
-	school.IsEducated = req.IsEducated
-	if req.LastVisitAt != nil {
-		school.LastVisitAt = req.LastVisitAt
+
+	if !scope.Allows(school.ProvinceId, school.CityId) {
+		return domainschool.School{}, filter.ErrOutOfRegion
 	}
 
 	school.UpdatedAt = time.Now()
@@ -147,6 +149,7 @@ func (s *SchoolService) UpdateSchool(id, username string, req dto.UpdateSchool)
 	if err := s.SchoolRepo.Update(school); err != nil {
 		return domainschool.School{}, err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionUpdate, domainauditlog.EntitySchool, id, before, school)
 
 	return school, nil
 }
@@ -155,9 +158,9 @@ func (s *SchoolService) FetchSchool(params filter.BaseParams) ([]domainschool.Sc
 	return s.SchoolRepo.Fetch(params)
 }
 
-func (s *SchoolService) DeleteSchool(id, username string) error {
+func (s *SchoolService) DeleteSchool(id, username string, scope filter.RegionScope) error {
 	// Check if school exists
-	school, err := s.SchoolRepo.GetByID(id)
+	school, err := s.SchoolRepo.GetByID(id, scope)
 	if err != nil {
 		return err
 	}
@@ -169,6 +172,7 @@ func (s *SchoolService) DeleteSchool(id, username string) error {
 	if err := s.SchoolRepo.Delete(id); err != nil {
 		return err
 	}
+	s.AuditRecorder.Record(username, domainauditlog.ActionDelete, domainauditlog.EntitySchool, id, school, nil)
 
 	return nil
 }
@@ -342,10 +346,10 @@ func (s *SchoolService) GetEducationPriority(params filter.BaseParams) (dto.Educ
 
 var _ interfaceschool.ServiceSchoolInterface = (*SchoolService)(nil)
 
-func (s *SchoolService) GetSummary() (*dto.SchoolSummary, error) {
-	return s.SchoolRepo.GetSummary()
+func (s *SchoolService) GetSummary(scope filter.RegionScope) (*dto.SchoolSummary, error) {
+	return s.SchoolRepo.GetSummary(scope)
 }
 
-func (s *SchoolService) GetForMap() ([]dto.SchoolMapItem, error) {
-	return s.SchoolRepo.GetForMap()
+func (s *SchoolService) GetForMap(scope filter.RegionScope) ([]dto.SchoolMapItem, error) {
+	return s.SchoolRepo.GetForMap(scope)
 }
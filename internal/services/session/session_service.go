@@ -2,6 +2,7 @@ package servicesession
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	domainsession "safety-riding/internal/domain/session"
 	domainuser "safety-riding/internal/domain/user"
@@ -23,13 +24,19 @@ func NewSessionService(sessionRepo interfacesession.RepoSessionInterface) *Servi
 	}
 }
 
-// CreateSession creates a new login session
-func (s *ServiceSession) CreateSession(ctx context.Context, user *domainuser.Users, token string, ginCtx *gin.Context) (*domainsession.Session, error) {
+// CreateSession creates a new login session and returns it with the plain refresh token.
+// Only the hash of the refresh token is stored in the session.
+func (s *ServiceSession) CreateSession(ctx context.Context, user *domainuser.Users, token string, ginCtx *gin.Context) (*domainsession.Session, string, error) {
 	sessionID := uuid.New().String()
 
-	// Get JWT expiration time
-	jwtExpHours := utils.GetEnv("JWT_EXP", 24).(int)
-	expiresAt := time.Now().Add(time.Hour * time.Duration(jwtExpHours))
+	// The session lives as long as its refresh token
+	refreshExpHours := utils.GetEnv("JWT_REFRESH_EXP_HOURS", 168).(int)
+	expiresAt := time.Now().Add(time.Hour * time.Duration(refreshExpHours))
+
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
 
 	// Extract device info from request
 	deviceInfo := extractDeviceInfo(ginCtx)
@@ -37,26 +44,70 @@ func (s *ServiceSession) CreateSession(ctx context.Context, user *domainuser.Use
 	userAgent := ginCtx.GetHeader("User-Agent")
 
 	session := &domainsession.Session{
-		SessionID:    sessionID,
-		UserID:       user.Id,
-		Email:        user.Email,
-		Role:         user.Role,
-		Token:        token,
-		DeviceInfo:   deviceInfo,
-		IP:           ip,
-		UserAgent:    userAgent,
-		LoginAt:      time.Now(),
-		LastActivity: time.Now(),
-		ExpiresAt:    expiresAt,
+		SessionID:        sessionID,
+		UserID:           user.Id,
+		Email:            user.Email,
+		Role:             user.Role,
+		Token:            token,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		DeviceInfo:       deviceInfo,
+		IP:               ip,
+		UserAgent:        userAgent,
+		LoginAt:          time.Now(),
+		LastActivity:     time.Now(),
+		ExpiresAt:        expiresAt,
 	}
 
 	if err := s.SessionRepo.Create(ctx, session); err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+		return nil, "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, refreshToken, nil
+}
+
+// GetSessionByRefreshToken returns the session a refresh token was issued for, including already rotated tokens
+func (s *ServiceSession) GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*domainsession.Session, error) {
+	session, err := s.SessionRepo.GetByRefreshToken(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, domainsession.ErrRefreshTokenInvalid
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		s.SessionRepo.Delete(ctx, session.SessionID)
+		return nil, domainsession.ErrRefreshTokenInvalid
 	}
 
 	return session, nil
 }
 
+// RotateRefreshToken exchanges a refresh token for a new one and binds the new access token to the session.
+// Presenting a refresh token that was already rotated revokes the whole session, the revoked session is returned
+// with domainsession.ErrRefreshTokenReused so the caller can invalidate its access token.
+func (s *ServiceSession) RotateRefreshToken(ctx context.Context, refreshToken, newToken string) (*domainsession.Session, string, error) {
+	session, err := s.GetSessionByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	newRefreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	rotated, err := s.SessionRepo.RotateTokens(ctx, session.SessionID, utils.HashToken(refreshToken), newToken, utils.HashToken(newRefreshToken))
+	if errors.Is(err, domainsession.ErrRefreshTokenReused) {
+		if errDelete := s.SessionRepo.Delete(ctx, session.SessionID); errDelete != nil {
+			return nil, "", fmt.Errorf("failed to revoke session: %w", errDelete)
+		}
+		return rotated, "", err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return rotated, newRefreshToken, nil
+}
+
 // ValidateSession validates if a session is still active and valid
 func (s *ServiceSession) ValidateSession(ctx context.Context, token string) (*domainsession.Session, error) {
 	session, err := s.SessionRepo.GetByToken(ctx, token)
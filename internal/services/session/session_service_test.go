@@ -0,0 +1,141 @@
+package servicesession
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainsession "safety-riding/internal/domain/session"
+	domainuser "safety-riding/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stubSessionRepo struct {
+	sessions map[string]domainsession.Session
+	refresh  map[string]string
+}
+
+func (s *stubSessionRepo) Create(ctx context.Context, session *domainsession.Session) error {
+	s.sessions[session.SessionID] = *session
+	s.refresh[session.RefreshTokenHash] = session.SessionID
+	return nil
+}
+func (s *stubSessionRepo) GetBySessionID(ctx context.Context, sessionID string) (*domainsession.Session, error) {
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	return &session, nil
+}
+func (s *stubSessionRepo) GetByUserID(ctx context.Context, userID string) ([]*domainsession.Session, error) {
+	return nil, nil
+}
+func (s *stubSessionRepo) GetByToken(ctx context.Context, token string) (*domainsession.Session, error) {
+	return nil, errors.New("session not found for token")
+}
+func (s *stubSessionRepo) GetByRefreshToken(ctx context.Context, refreshTokenHash string) (*domainsession.Session, error) {
+	sessionID, ok := s.refresh[refreshTokenHash]
+	if !ok {
+		return nil, errors.New("session not found for refresh token")
+	}
+	return s.GetBySessionID(ctx, sessionID)
+}
+func (s *stubSessionRepo) RotateTokens(ctx context.Context, sessionID, oldRefreshTokenHash, token, refreshTokenHash string) (*domainsession.Session, error) {
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	if session.RefreshTokenHash != oldRefreshTokenHash {
+		return &session, domainsession.ErrRefreshTokenReused
+	}
+	session.Token = token
+	session.RefreshTokenHash = refreshTokenHash
+	s.sessions[sessionID] = session
+	s.refresh[refreshTokenHash] = sessionID
+	return &session, nil
+}
+func (s *stubSessionRepo) UpdateActivity(ctx context.Context, sessionID string) error { return nil }
+func (s *stubSessionRepo) Delete(ctx context.Context, sessionID string) error {
+	delete(s.sessions, sessionID)
+	return nil
+}
+func (s *stubSessionRepo) DeleteByUserID(ctx context.Context, userID string) error { return nil }
+func (s *stubSessionRepo) DeleteExpired(ctx context.Context) error                 { return nil }
+func (s *stubSessionRepo) SetExpiration(ctx context.Context, sessionID string, expiration time.Duration) error {
+	return nil
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newSession := func(t *testing.T) (*ServiceSession, *stubSessionRepo, *domainsession.Session, string) {
+		t.Helper()
+		repo := &stubSessionRepo{sessions: map[string]domainsession.Session{}, refresh: map[string]string{}}
+		svc := NewSessionService(repo)
+
+		ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginCtx.Request = httptest.NewRequest("POST", "/api/user/login", nil)
+
+		session, refreshToken, err := svc.CreateSession(context.Background(), &domainuser.Users{Id: "user-1"}, "access-1", ginCtx)
+		if err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		return svc, repo, session, refreshToken
+	}
+
+	t.Run("refresh token is stored hashed", func(t *testing.T) {
+		_, _, session, refreshToken := newSession(t)
+		if refreshToken == "" || session.RefreshTokenHash == refreshToken {
+			t.Fatalf("refresh token is stored in plain text")
+		}
+	})
+
+	t.Run("rotation issues a new refresh token", func(t *testing.T) {
+		svc, _, _, refreshToken := newSession(t)
+
+		session, rotated, err := svc.RotateRefreshToken(context.Background(), refreshToken, "access-2")
+		if err != nil {
+			t.Fatalf("RotateRefreshToken() error = %v", err)
+		}
+		if rotated == "" || rotated == refreshToken {
+			t.Fatalf("RotateRefreshToken() returned %q, want a new refresh token", rotated)
+		}
+		if session.Token != "access-2" {
+			t.Fatalf("session token = %q, want access-2", session.Token)
+		}
+
+		if _, _, err := svc.RotateRefreshToken(context.Background(), rotated, "access-3"); err != nil {
+			t.Fatalf("RotateRefreshToken() with rotated token error = %v", err)
+		}
+	})
+
+	t.Run("reuse revokes the session", func(t *testing.T) {
+		svc, repo, created, refreshToken := newSession(t)
+		_, rotated, _ := svc.RotateRefreshToken(context.Background(), refreshToken, "access-2")
+
+		revoked, _, err := svc.RotateRefreshToken(context.Background(), refreshToken, "access-3")
+		if !errors.Is(err, domainsession.ErrRefreshTokenReused) {
+			t.Fatalf("RotateRefreshToken() error = %v, want %v", err, domainsession.ErrRefreshTokenReused)
+		}
+		if revoked == nil || revoked.Token != "access-2" {
+			t.Fatalf("revoked session = %+v, want the session holding access-2", revoked)
+		}
+		if _, ok := repo.sessions[created.SessionID]; ok {
+			t.Fatalf("session was not revoked")
+		}
+
+		if _, _, err := svc.RotateRefreshToken(context.Background(), rotated, "access-4"); !errors.Is(err, domainsession.ErrRefreshTokenInvalid) {
+			t.Fatalf("RotateRefreshToken() after revocation error = %v, want %v", err, domainsession.ErrRefreshTokenInvalid)
+		}
+	})
+
+	t.Run("unknown refresh token is rejected", func(t *testing.T) {
+		svc, _, _, _ := newSession(t)
+		if _, _, err := svc.RotateRefreshToken(context.Background(), "unknown", "access-2"); !errors.Is(err, domainsession.ErrRefreshTokenInvalid) {
+			t.Fatalf("RotateRefreshToken() error = %v, want %v", err, domainsession.ErrRefreshTokenInvalid)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package servicestoragegc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"safety-riding/internal/dto"
+	interfacestoragegc "safety-riding/internal/interfaces/storagegc"
+	serviceattachment "safety-riding/internal/services/attachment"
+	servicecertificate "safety-riding/internal/services/certificate"
+	"safety-riding/pkg/imaging"
+	"safety-riding/pkg/storage"
+)
+
+// fileFolder is a storage folder with the table and columns referencing its objects, the staging folder of the
+// direct uploads has no table so every object in it older than the grace period is an orphan
+type fileFolder struct {
+	Name    string
+	Table   string
+	Columns []string
+}
+
+var photoColumns = []string{"photo_url", "medium_url", "thumbnail_url"}
+
+var fileFolders = []fileFolder{
+	{Name: "event-photos", Table: "event_photos", Columns: photoColumns},
+	{Name: "accident-photos", Table: "accident_photos", Columns: photoColumns},
+	{Name: serviceattachment.Folder, Table: "attachments", Columns: []string{"object_name"}},
+	{Name: servicecertificate.Folder, Table: "certificates", Columns: []string{"file_url"}},
+	{Name: servicecertificate.BatchFolder, Table: "certificate_batches", Columns: []string{"file_url"}},
+	{Name: imaging.StagingFolder},
+}
+
+// Folders returns the names of the folders the garbage collector knows
+func Folders() []string {
+	names := make([]string, len(fileFolders))
+	for i, folder := range fileFolders {
+		names[i] = folder.Name
+	}
+	return names
+}
+
+type StorageGCService struct {
+	StorageGCRepo   interfacestoragegc.RepoStorageGCInterface
+	StorageProvider storage.StorageProvider
+}
+
+func NewStorageGCService(storageGCRepo interfacestoragegc.RepoStorageGCInterface, storageProvider storage.StorageProvider) *StorageGCService {
+	return &StorageGCService{
+		StorageGCRepo:   storageGCRepo,
+		StorageProvider: storageProvider,
+	}
+}
+
+// Reconcile compares the objects of each folder with the rows referencing them. It reports the objects no row
+// references and the live rows whose object is missing, and deletes the orphans when opts.Delete is set
+func (s *StorageGCService) Reconcile(ctx context.Context, opts dto.StorageGCOptions) (dto.StorageGCReport, error) {
+	folders, err := selectFolders(opts.Folders)
+	if err != nil {
+		return dto.StorageGCReport{}, err
+	}
+
+	now := time.Now()
+	report := dto.StorageGCReport{
+		StartedAt: now,
+		Delete:    opts.Delete,
+		Cutoff:    now.Add(-opts.GracePeriod),
+	}
+
+	for _, folder := range folders {
+		var refs []dto.StoredFileRef
+		if folder.Table != "" {
+			// the rows are read before the objects, so a file saved in between is younger than the cutoff
+			if refs, err = s.StorageGCRepo.FetchFileRefs(folder.Table, folder.Columns, report.Cutoff); err != nil {
+				return report, fmt.Errorf("failed to read %s: %w", folder.Table, err)
+			}
+		}
+
+		objects, err := s.StorageProvider.ListFiles(ctx, folder.Name+"/")
+		if err != nil {
+			return report, fmt.Errorf("failed to list %s: %w", folder.Name, err)
+		}
+
+		folderReport := reconcileFolder(folder, objects, refs, report.Cutoff)
+		if opts.Delete && folderReport.Skipped == "" {
+			s.deleteOrphans(ctx, folderReport.Orphans)
+		}
+		report.Folders = append(report.Folders, folderReport)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (s *StorageGCService) deleteOrphans(ctx context.Context, orphans []dto.OrphanFile) {
+	for i := range orphans {
+		if err := s.StorageProvider.DeleteFile(ctx, s.StorageProvider.GetFileURL(orphans[i].ObjectName)); err != nil {
+			orphans[i].Error = err.Error()
+			continue
+		}
+		orphans[i].Deleted = true
+	}
+}
+
+func selectFolders(names []string) ([]fileFolder, error) {
+	if len(names) == 0 {
+		return fileFolders, nil
+	}
+
+	var folders []fileFolder
+	for _, name := range names {
+		found := false
+		for _, folder := range fileFolders {
+			if folder.Name == name {
+				folders = append(folders, folder)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown folder '%s', expected one of %s", name, strings.Join(Folders(), ", "))
+		}
+	}
+	return folders, nil
+}
+
+// reconcileFolder matches the listed objects of a folder with the rows referencing them. The orphans are not
+// deleted when none of the objects is referenced while live rows are, that points to a storage configuration
+// that differs from the one the rows were saved with rather than to lost rows
+func reconcileFolder(folder fileFolder, objects []storage.FileInfo, refs []dto.StoredFileRef, cutoff time.Time) dto.StorageGCFolderReport {
+	report := dto.StorageGCFolderReport{
+		Folder:  folder.Name,
+		Objects: len(objects),
+		Orphans: []dto.OrphanFile{},
+		Missing: []dto.MissingFile{},
+	}
+
+	referenced := map[string]bool{}
+	for _, ref := range refs {
+		if key := objectKey(ref.Url, folder.Name); key != "" {
+			referenced[key] = true
+		}
+	}
+
+	stored := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		stored[object.Name] = true
+		if referenced[object.Name] {
+			report.Referenced++
+			continue
+		}
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+		report.Orphans = append(report.Orphans, dto.OrphanFile{ObjectName: object.Name, Size: object.Size, LastModified: object.LastModified})
+		report.OrphanBytes += object.Size
+	}
+
+	liveRefs := 0
+	for _, ref := range refs {
+		key := objectKey(ref.Url, folder.Name)
+		if !ref.Live || key == "" {
+			continue
+		}
+		liveRefs++
+		if !stored[key] {
+			report.Missing = append(report.Missing, dto.MissingFile{Table: folder.Table, RowId: ref.RowId, Url: ref.Url})
+		}
+	}
+
+	if len(report.Orphans) > 0 && report.Referenced == 0 && liveRefs > 0 {
+		report.Skipped = "no stored object matches the rows, check the storage configuration"
+	}
+	return report
+}
+
+// objectKey returns the object name a stored file URL points to, from the folder on. The scheme, host and
+// bucket are left out so the rows still match after the storage base URL changed, URLs outside the folder
+// such as photos linked from other sites return an empty key
+func objectKey(fileURL, folder string) string {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return ""
+	}
+
+	p := "/" + strings.TrimLeft(u.Path, "/")
+	idx := strings.Index(p, "/"+folder+"/")
+	if idx < 0 {
+		return ""
+	}
+	return p[idx+1:]
+}
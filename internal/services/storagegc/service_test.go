@@ -0,0 +1,95 @@
+package servicestoragegc
+
+import (
+	"testing"
+	"time"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/storage"
+)
+
+func TestObjectKey(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "minio", url: "http://localhost:9000/safety-riding/event-photos/a.jpg", want: "event-photos/a.jpg"},
+		{name: "r2 public url", url: "https://files.example.com/event-photos/a_medium.jpg", want: "event-photos/a_medium.jpg"},
+		{name: "signed local url", url: "/files/event-photos/a.jpg?sig=abc", want: "event-photos/a.jpg"},
+		{name: "escaped name", url: "https://files.example.com/event-photos/a%20b.jpg", want: "event-photos/a b.jpg"},
+		{name: "other folder", url: "https://files.example.com/accident-photos/a.jpg", want: ""},
+		{name: "external photo", url: "https://example.com/photo.jpg", want: ""},
+		{name: "folder name as a file", url: "https://files.example.com/event-photos", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectKey(tt.url, "event-photos"); got != tt.want {
+				t.Fatalf("objectKey(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileFolder(t *testing.T) {
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	old, recent := cutoff.Add(-time.Hour), cutoff.Add(time.Hour)
+	folder := fileFolder{Name: "event-photos", Table: "event_photos"}
+	base := "https://files.example.com/"
+
+	objects := []storage.FileInfo{
+		{Name: "event-photos/live.jpg", Size: 10, LastModified: old},
+		{Name: "event-photos/deleted.jpg", Size: 20, LastModified: old},
+		{Name: "event-photos/orphan.jpg", Size: 30, LastModified: old},
+		{Name: "event-photos/new.jpg", Size: 40, LastModified: recent},
+	}
+	refs := []dto.StoredFileRef{
+		{RowId: "1", Url: base + "event-photos/live.jpg", Live: true},
+		{RowId: "2", Url: base + "event-photos/deleted.jpg"},
+		{RowId: "3", Url: base + "event-photos/gone.jpg", Live: true},
+		{RowId: "4", Url: "https://example.com/linked.jpg", Live: true},
+	}
+
+	report := reconcileFolder(folder, objects, refs, cutoff)
+	if report.Objects != 4 || report.Referenced != 2 {
+		t.Fatalf("objects = %d, referenced = %d, want 4 and 2", report.Objects, report.Referenced)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].ObjectName != "event-photos/orphan.jpg" || report.OrphanBytes != 30 {
+		t.Fatalf("orphans = %+v (%d bytes), want only event-photos/orphan.jpg", report.Orphans, report.OrphanBytes)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].RowId != "3" {
+		t.Fatalf("missing = %+v, want row 3", report.Missing)
+	}
+	if report.Skipped != "" {
+		t.Fatalf("skipped = %q, want the orphans to be deleted", report.Skipped)
+	}
+}
+
+func TestReconcileFolderSkipsMismatchedStorage(t *testing.T) {
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	folder := fileFolder{Name: "event-photos", Table: "event_photos"}
+
+	objects := []storage.FileInfo{{Name: "event-photos/a.jpg", LastModified: cutoff.Add(-time.Hour)}}
+	refs := []dto.StoredFileRef{{RowId: "1", Url: "https://files.example.com/event-photos/b.jpg", Live: true}}
+
+	report := reconcileFolder(folder, objects, refs, cutoff)
+	if len(report.Orphans) != 1 || report.Skipped == "" {
+		t.Fatalf("orphans = %+v, skipped = %q, want the orphan reported but not deleted", report.Orphans, report.Skipped)
+	}
+}
+
+func TestReconcileStagingFolder(t *testing.T) {
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	folder := fileFolder{Name: "uploads"}
+
+	objects := []storage.FileInfo{
+		{Name: "uploads/event-photos/1/a.jpg", LastModified: cutoff.Add(-time.Hour)},
+		{Name: "uploads/event-photos/1/b.jpg", LastModified: cutoff.Add(time.Hour)},
+	}
+
+	report := reconcileFolder(folder, objects, nil, cutoff)
+	if len(report.Orphans) != 1 || report.Orphans[0].ObjectName != "uploads/event-photos/1/a.jpg" || report.Skipped != "" {
+		t.Fatalf("orphans = %+v, skipped = %q, want the expired upload", report.Orphans, report.Skipped)
+	}
+}
@@ -0,0 +1,77 @@
+package servicestoragemigration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// journal records the objects copied and verified so far, one "<sha256>  <object name>" line each like
+// the output of sha256sum, so an interrupted migration resumes where it stopped
+type journal struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]string
+}
+
+// openJournal reads the state file, it is only opened for writing when readOnly is false
+func openJournal(path string, readOnly bool) (*journal, error) {
+	j := &journal{done: map[string]string{}}
+	if path == "" {
+		return j, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			sum, name, ok := strings.Cut(scanner.Text(), "  ")
+			if ok && name != "" {
+				j.done[name] = sum
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+	}
+
+	if !readOnly {
+		if j.file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to open state file: %w", err)
+		}
+	}
+	return j, nil
+}
+
+func (j *journal) Done(name string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, ok := j.done[name]
+	return ok
+}
+
+func (j *journal) Record(name, sum string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file != nil {
+		if _, err := fmt.Fprintf(j.file, "%s  %s\n", sum, name); err != nil {
+			return fmt.Errorf("failed to write state file: %w", err)
+		}
+	}
+	j.done[name] = sum
+	return nil
+}
+
+func (j *journal) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
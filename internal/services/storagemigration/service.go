@@ -0,0 +1,262 @@
+package servicestoragemigration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"safety-riding/internal/dto"
+	interfacestoragemigration "safety-riding/internal/interfaces/storagemigration"
+	"safety-riding/pkg/storage"
+)
+
+const (
+	defaultBatchSize = 500
+	defaultWorkers   = 4
+)
+
+// fileTable is a table keeping absolute URLs of stored objects
+type fileTable struct {
+	Table   string
+	Columns []string
+}
+
+var fileTables = []fileTable{
+	{Table: "event_photos", Columns: []string{"photo_url", "medium_url", "thumbnail_url"}},
+	{Table: "accident_photos", Columns: []string{"photo_url", "medium_url", "thumbnail_url"}},
+	{Table: "certificate_templates", Columns: []string{"background_url"}},
+	{Table: "certificates", Columns: []string{"file_url"}},
+	{Table: "certificate_batches", Columns: []string{"file_url"}},
+}
+
+type StorageMigrationService struct {
+	StorageMigrationRepo interfacestoragemigration.RepoStorageMigrationInterface
+	Source               storage.StorageProvider
+	Target               storage.StorageProvider
+}
+
+func NewStorageMigrationService(storageMigrationRepo interfacestoragemigration.RepoStorageMigrationInterface, source, target storage.StorageProvider) *StorageMigrationService {
+	return &StorageMigrationService{
+		StorageMigrationRepo: storageMigrationRepo,
+		Source:               source,
+		Target:               target,
+	}
+}
+
+// Migrate copies the objects of the source below opts.Prefix to the target under the same names, each copy is
+// read back and compared by SHA-256 before it is recorded in the state file. With opts.Rewrite the stored URLs
+// pointing to copied objects are then moved to the target, rows with an object still to copy are left as they are.
+// A dry run copies and writes nothing and reports what a real run would do
+func (s *StorageMigrationService) Migrate(ctx context.Context, opts dto.StorageMigrationOptions) (dto.StorageMigrationReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+
+	report := dto.StorageMigrationReport{
+		StartedAt: time.Now(),
+		DryRun:    opts.DryRun,
+		Failed:    []dto.MigrationFailure{},
+		Rewrites:  []dto.MigrationRewrite{},
+	}
+
+	state, err := openJournal(opts.StateFile, opts.DryRun)
+	if err != nil {
+		return report, err
+	}
+	defer state.Close()
+
+	objects, err := s.Source.ListFiles(ctx, opts.Prefix)
+	if err != nil {
+		return report, fmt.Errorf("failed to list the source objects: %w", err)
+	}
+	report.Objects = len(objects)
+
+	listed := make(map[string]bool, len(objects))
+	var pending []storage.FileInfo
+	for _, object := range objects {
+		listed[object.Name] = true
+		if state.Done(object.Name) {
+			report.Skipped++
+			continue
+		}
+		pending = append(pending, object)
+		report.Bytes += object.Size
+	}
+
+	copied := state.Done
+	if opts.DryRun {
+		copied = func(name string) bool { return listed[name] || state.Done(name) }
+	} else {
+		s.copyObjects(ctx, pending, opts.Workers, state, &report)
+	}
+
+	if opts.Rewrite {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		for _, table := range fileTables {
+			rewrite, err := s.rewriteTable(table, copied, opts)
+			report.Rewrites = append(report.Rewrites, rewrite)
+			if err != nil {
+				return report, fmt.Errorf("failed to rewrite %s: %w", table.Table, err)
+			}
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (s *StorageMigrationService) copyObjects(ctx context.Context, objects []storage.FileInfo, workers int, state *journal, report *dto.StorageMigrationReport) {
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	queue := make(chan storage.FileInfo)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range queue {
+				sum, err := s.copyObject(ctx, object)
+				if err == nil {
+					err = state.Record(object.Name, sum)
+				}
+
+				mu.Lock()
+				if err != nil {
+					report.Failed = append(report.Failed, dto.MigrationFailure{ObjectName: object.Name, Error: err.Error()})
+				} else {
+					report.Copied++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, object := range objects {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- object
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].ObjectName < report.Failed[j].ObjectName })
+}
+
+// copyObject copies an object to the target and returns its SHA-256 once the copy reads back the same
+func (s *StorageMigrationService) copyObject(ctx context.Context, object storage.FileInfo) (string, error) {
+	if object.ContentType == "" {
+		info, err := s.Source.StatFile(ctx, object.Name)
+		if err != nil {
+			return "", err
+		}
+		object.ContentType = info.ContentType
+	}
+
+	reader, err := s.Source.DownloadFile(ctx, object.Name)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if err := s.Target.PutFile(ctx, object.Name, io.TeeReader(reader, hash), object.Size, object.ContentType); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	readBack, err := s.Target.DownloadFile(ctx, object.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the copy back: %w", err)
+	}
+	defer readBack.Close()
+
+	check := sha256.New()
+	size, err := io.Copy(check, readBack)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the copy back: %w", err)
+	}
+	if size != object.Size || hex.EncodeToString(check.Sum(nil)) != sum {
+		return "", fmt.Errorf("checksum mismatch, the copy has %d of %d bytes", size, object.Size)
+	}
+	return sum, nil
+}
+
+func (s *StorageMigrationService) rewriteTable(table fileTable, copied func(string) bool, opts dto.StorageMigrationOptions) (dto.MigrationRewrite, error) {
+	result := dto.MigrationRewrite{Table: table.Table}
+	sourcePrefix := s.Source.GetFileURL("")
+
+	afterId := ""
+	for {
+		rows, err := s.StorageMigrationRepo.FetchFileURLs(table.Table, table.Columns, afterId, opts.BatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		afterId = rows[len(rows)-1].Id
+
+		var changed []dto.StoredFileURLs
+		for _, row := range rows {
+			result.Rows++
+			urls, pending := rewriteURLs(row.Urls, sourcePrefix, s.Target.GetFileURL, copied)
+			if pending {
+				result.Pending++
+			} else if len(urls) > 0 {
+				result.Rewritten++
+				changed = append(changed, dto.StoredFileURLs{Id: row.Id, Urls: urls})
+			}
+		}
+
+		if !opts.DryRun && len(changed) > 0 {
+			if err := s.StorageMigrationRepo.UpdateFileURLs(table.Table, changed); err != nil {
+				return result, err
+			}
+		}
+		if len(rows) < opts.BatchSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// rewriteURLs returns the columns of a row whose URL moves to the target. A row is pending, and left whole,
+// while one of its objects is not copied, URLs outside the source such as linked photos are kept
+func rewriteURLs(urls map[string]string, sourcePrefix string, targetURL func(string) string, copied func(string) bool) (map[string]string, bool) {
+	changed := map[string]string{}
+	for column, fileURL := range urls {
+		name := sourceObjectName(fileURL, sourcePrefix)
+		if name == "" {
+			continue
+		}
+		if !copied(name) {
+			return nil, true
+		}
+		changed[column] = targetURL(name)
+	}
+	return changed, false
+}
+
+// sourceObjectName returns the object a URL of the source points to, the way the certificate service
+// trims GetFileURL("") from its URLs, or an empty name for any other URL
+func sourceObjectName(fileURL, sourcePrefix string) string {
+	if sourcePrefix == "" || !strings.HasPrefix(fileURL, sourcePrefix) {
+		return ""
+	}
+	name, _, _ := strings.Cut(strings.TrimPrefix(fileURL, sourcePrefix), "?")
+	return name
+}
@@ -0,0 +1,165 @@
+package servicestoragemigration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/storage"
+)
+
+type fakeRepo struct {
+	rows map[string][]dto.StoredFileURLs
+}
+
+func (f *fakeRepo) FetchFileURLs(table string, columns []string, afterId string, limit int) ([]dto.StoredFileURLs, error) {
+	var ret []dto.StoredFileURLs
+	for _, row := range f.rows[table] {
+		if row.Id > afterId && len(ret) < limit {
+			ret = append(ret, row)
+		}
+	}
+	return ret, nil
+}
+
+func (f *fakeRepo) UpdateFileURLs(table string, rows []dto.StoredFileURLs) error {
+	for _, changed := range rows {
+		for _, row := range f.rows[table] {
+			if row.Id != changed.Id {
+				continue
+			}
+			for column, url := range changed.Urls {
+				row.Urls[column] = url
+			}
+		}
+	}
+	return nil
+}
+
+func TestSourceObjectName(t *testing.T) {
+	prefix := "http://localhost:9000/safety-riding/"
+	tests := map[string]string{
+		prefix + "event-photos/a.jpg":                 "event-photos/a.jpg",
+		prefix + "event-photos/a.jpg?sig=abc":         "event-photos/a.jpg",
+		"https://pub-1.r2.dev/event-photos/a.jpg":     "",
+		"https://example.com/linked.jpg":              "",
+		"http://localhost:9000/other-bucket/file.jpg": "",
+	}
+	for url, want := range tests {
+		if got := sourceObjectName(url, prefix); got != want {
+			t.Errorf("sourceObjectName(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestRewriteURLs(t *testing.T) {
+	prefix := "http://minio/bucket/"
+	target := func(name string) string { return "https://r2/" + name }
+	copied := func(name string) bool { return name != "event-photos/new.jpg" }
+
+	urls, pending := rewriteURLs(map[string]string{
+		"photo_url":     prefix + "event-photos/a.jpg",
+		"thumbnail_url": "https://example.com/linked.jpg",
+	}, prefix, target, copied)
+	if pending || len(urls) != 1 || urls["photo_url"] != "https://r2/event-photos/a.jpg" {
+		t.Fatalf("rewriteURLs() = %v, %v, want only photo_url moved", urls, pending)
+	}
+
+	urls, pending = rewriteURLs(map[string]string{
+		"photo_url":     prefix + "event-photos/a.jpg",
+		"thumbnail_url": prefix + "event-photos/new.jpg",
+	}, prefix, target, copied)
+	if !pending || len(urls) != 0 {
+		t.Fatalf("rewriteURLs() = %v, %v, want the row left whole while an object is not copied", urls, pending)
+	}
+}
+
+func newLocal(t *testing.T, baseURL string) storage.StorageProvider {
+	provider, err := storage.NewLocalAdapter(storage.Config{LocalPath: t.TempDir(), BaseURL: baseURL, SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalAdapter() error = %v", err)
+	}
+	return provider
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	source := newLocal(t, "http://old.example.com/files")
+	target := newLocal(t, "http://new.example.com/files")
+
+	var photoURLs []string
+	for _, data := range []string{"first", "second"} {
+		url, err := source.UploadFileFromBytes(ctx, []byte(data), "photo.jpg", "event-photos", "image/jpeg")
+		if err != nil {
+			t.Fatalf("UploadFileFromBytes() error = %v", err)
+		}
+		photoURLs = append(photoURLs, url)
+	}
+	sort.Strings(photoURLs)
+
+	repo := &fakeRepo{rows: map[string][]dto.StoredFileURLs{
+		"event_photos": {
+			{Id: "1", Urls: map[string]string{"photo_url": photoURLs[0], "medium_url": photoURLs[0], "thumbnail_url": photoURLs[0]}},
+			{Id: "2", Urls: map[string]string{"photo_url": photoURLs[1], "medium_url": "", "thumbnail_url": ""}},
+			{Id: "3", Urls: map[string]string{"photo_url": "https://example.com/linked.jpg", "medium_url": "", "thumbnail_url": ""}},
+		},
+	}}
+	service := NewStorageMigrationService(repo, source, target)
+	stateFile := filepath.Join(t.TempDir(), "migration.state")
+	opts := dto.StorageMigrationOptions{StateFile: stateFile, Rewrite: true, BatchSize: 2}
+
+	dryRun := opts
+	dryRun.DryRun = true
+	report, err := service.Migrate(ctx, dryRun)
+	if err != nil {
+		t.Fatalf("Migrate() dry run error = %v", err)
+	}
+	if report.Copied != 0 || report.Rewrites[0].Rewritten != 2 || !strings.HasPrefix(repo.rows["event_photos"][0].Urls["photo_url"], "http://old.example.com/") {
+		t.Fatalf("Migrate() dry run = %+v, want nothing copied or rewritten", report)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote the state file, error = %v", err)
+	}
+
+	report, err = service.Migrate(ctx, opts)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if report.Objects != 2 || report.Copied != 2 || len(report.Failed) != 0 || report.Bytes != int64(len("first")+len("second")) {
+		t.Fatalf("Migrate() = %+v, want 2 objects copied", report)
+	}
+	if rewrite := report.Rewrites[0]; rewrite.Rows != 3 || rewrite.Rewritten != 2 || rewrite.Pending != 0 {
+		t.Fatalf("Migrate() event_photos = %+v, want 2 of 3 rows rewritten", rewrite)
+	}
+
+	for _, row := range repo.rows["event_photos"][:2] {
+		url := row.Urls["photo_url"]
+		if !strings.HasPrefix(url, "http://new.example.com/files/event-photos/") {
+			t.Fatalf("photo_url = %q, want a target URL", url)
+		}
+		reader, err := target.DownloadFile(ctx, url)
+		if err != nil {
+			t.Fatalf("DownloadFile(%q) error = %v", url, err)
+		}
+		reader.Close()
+	}
+	if row := repo.rows["event_photos"][0]; row.Urls["thumbnail_url"] != row.Urls["photo_url"] {
+		t.Fatalf("thumbnail_url = %q, want the rewritten photo_url", row.Urls["thumbnail_url"])
+	}
+	if url := repo.rows["event_photos"][2].Urls["photo_url"]; url != "https://example.com/linked.jpg" {
+		t.Fatalf("linked photo_url = %q, want it kept", url)
+	}
+
+	// A second run resumes from the state file and has nothing left to do
+	report, err = service.Migrate(ctx, opts)
+	if err != nil {
+		t.Fatalf("Migrate() again error = %v", err)
+	}
+	if report.Copied != 0 || report.Skipped != 2 || report.Rewrites[0].Rewritten != 0 {
+		t.Fatalf("Migrate() again = %+v, want every object skipped", report)
+	}
+}
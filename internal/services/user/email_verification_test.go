@@ -0,0 +1,77 @@
+package serviceuser
+
+import (
+	"testing"
+
+	domainauth "safety-riding/internal/domain/auth"
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/messages"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestEmailVerification(t *testing.T) {
+	newService := func() (*ServiceUser, *stubMailer) {
+		hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("Passw0rd!"), bcrypt.MinCost)
+		users := &stubUserRepo{users: map[string]domainuser.Users{
+			"user-1": {Id: "user-1", Name: "Rider", Email: "rider@example.com", Password: string(hashedPwd)},
+		}}
+		tokens := &stubUserTokenRepo{tokens: map[string]domainauth.UserToken{}}
+		mail := &stubMailer{}
+		return NewUserService(users, nil, nil, nil, tokens, nil, mail, &stubSessionService{}), mail
+	}
+
+	t.Run("unverified user cannot login when verification is required", func(t *testing.T) {
+		svc, _ := newService()
+		_, err := svc.LoginUser(dto.Login{Email: "rider@example.com", Password: "Passw0rd!"}, "log-1", dto.LoginPolicy{RequireVerifiedEmail: true})
+		if err == nil || err.Error() != messages.ErrEmailNotVerified {
+			t.Fatalf("LoginUser() error = %v, want %s", err, messages.ErrEmailNotVerified)
+		}
+	})
+
+	t.Run("token verifies the email once", func(t *testing.T) {
+		svc, mail := newService()
+		if err := svc.ResendVerificationEmail(dto.ResendVerificationRequest{Email: "rider@example.com"}); err != nil {
+			t.Fatalf("ResendVerificationEmail() error = %v", err)
+		}
+		if mail.to != "rider@example.com" {
+			t.Fatalf("verification email sent to %q", mail.to)
+		}
+
+		token := mailedToken(t, mail.body)
+		if err := svc.VerifyEmail(dto.VerifyEmailRequest{Token: token}); err != nil {
+			t.Fatalf("VerifyEmail() error = %v", err)
+		}
+		user, _ := svc.UserRepo.GetByID("user-1")
+		if user.EmailVerifiedAt == nil {
+			t.Fatalf("email_verified_at was not set")
+		}
+
+		if err := svc.VerifyEmail(dto.VerifyEmailRequest{Token: token}); err == nil {
+			t.Fatalf("VerifyEmail() accepted a used token")
+		}
+	})
+
+	t.Run("resend is skipped for verified users", func(t *testing.T) {
+		svc, mail := newService()
+		_ = svc.ResendVerificationEmail(dto.ResendVerificationRequest{Email: "rider@example.com"})
+		_ = svc.VerifyEmail(dto.VerifyEmailRequest{Token: mailedToken(t, mail.body)})
+
+		mail.to = ""
+		if err := svc.ResendVerificationEmail(dto.ResendVerificationRequest{Email: "rider@example.com"}); err != nil {
+			t.Fatalf("ResendVerificationEmail() error = %v", err)
+		}
+		if mail.to != "" {
+			t.Fatalf("verification email sent to an already verified user")
+		}
+	})
+
+	t.Run("reset token cannot verify an email", func(t *testing.T) {
+		svc, mail := newService()
+		_ = svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"})
+		if err := svc.VerifyEmail(dto.VerifyEmailRequest{Token: mailedToken(t, mail.body)}); err == nil {
+			t.Fatalf("VerifyEmail() accepted a password reset token")
+		}
+	})
+}
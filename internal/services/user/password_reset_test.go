@@ -0,0 +1,268 @@
+package serviceuser
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	domainsession "safety-riding/internal/domain/session"
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type stubUserRepo struct {
+	users map[string]domainuser.Users
+}
+
+func (s *stubUserRepo) Store(m domainuser.Users) error {
+	s.users[m.Id] = m
+	return nil
+}
+func (s *stubUserRepo) GetByEmail(email string) (domainuser.Users, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return domainuser.Users{}, gorm.ErrRecordNotFound
+}
+func (s *stubUserRepo) GetByPhone(phone string) (domainuser.Users, error) {
+	return domainuser.Users{}, gorm.ErrRecordNotFound
+}
+func (s *stubUserRepo) GetByID(id string) (domainuser.Users, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return domainuser.Users{}, gorm.ErrRecordNotFound
+}
+func (s *stubUserRepo) GetAll(params filter.BaseParams) ([]domainuser.Users, int64, error) {
+	return nil, 0, nil
+}
+func (s *stubUserRepo) Update(m domainuser.Users) error {
+	s.users[m.Id] = m
+	return nil
+}
+func (s *stubUserRepo) Delete(id string) error {
+	delete(s.users, id)
+	return nil
+}
+
+type stubUserTokenRepo struct {
+	tokens map[string]domainauth.UserToken
+}
+
+func (s *stubUserTokenRepo) Store(m domainauth.UserToken) error {
+	s.tokens[m.ID] = m
+	return nil
+}
+func (s *stubUserTokenRepo) GetByHash(purpose, tokenHash string) (domainauth.UserToken, error) {
+	for _, t := range s.tokens {
+		if t.Purpose == purpose && t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return domainauth.UserToken{}, gorm.ErrRecordNotFound
+}
+func (s *stubUserTokenRepo) MarkUsed(id string) (bool, error) {
+	t, ok := s.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	s.tokens[id] = t
+	return true, nil
+}
+func (s *stubUserTokenRepo) RevokeByUser(userId, purpose string) error {
+	for id, t := range s.tokens {
+		if t.UserId == userId && t.Purpose == purpose && t.UsedAt == nil {
+			now := time.Now()
+			t.UsedAt = &now
+			s.tokens[id] = t
+		}
+	}
+	return nil
+}
+
+type stubMailer struct {
+	to   string
+	body string
+	err  error
+}
+
+func (s *stubMailer) Send(to, subject, body string) error {
+	s.to, s.body = to, body
+	return s.err
+}
+
+type stubBlacklistRepo struct {
+	revocations map[string]domainauth.TokenRevocation
+}
+
+func (s *stubBlacklistRepo) Store(m domainauth.Blacklist) error { return nil }
+func (s *stubBlacklistRepo) GetByToken(token string) (domainauth.Blacklist, error) {
+	return domainauth.Blacklist{}, gorm.ErrRecordNotFound
+}
+func (s *stubBlacklistRepo) RevokeUserTokens(userId string, revokedAt time.Time) error {
+	s.revocations[userId] = domainauth.TokenRevocation{UserId: userId, RevokedAt: revokedAt}
+	return nil
+}
+func (s *stubBlacklistRepo) GetRevocation(userId string) (domainauth.TokenRevocation, error) {
+	if m, ok := s.revocations[userId]; ok {
+		return m, nil
+	}
+	return domainauth.TokenRevocation{}, gorm.ErrRecordNotFound
+}
+
+type stubSessionService struct {
+	destroyed []string
+}
+
+func (s *stubSessionService) CreateSession(ctx context.Context, user *domainuser.Users, token string, ginCtx *gin.Context) (*domainsession.Session, string, error) {
+	return nil, "", nil
+}
+func (s *stubSessionService) GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*domainsession.Session, error) {
+	return nil, domainsession.ErrRefreshTokenInvalid
+}
+func (s *stubSessionService) RotateRefreshToken(ctx context.Context, refreshToken, newToken string) (*domainsession.Session, string, error) {
+	return nil, "", domainsession.ErrRefreshTokenInvalid
+}
+func (s *stubSessionService) ValidateSession(ctx context.Context, token string) (*domainsession.Session, error) {
+	return nil, nil
+}
+func (s *stubSessionService) GetUserSessions(ctx context.Context, userID string, currentSessionID string) ([]*domainsession.SessionInfo, error) {
+	return nil, nil
+}
+func (s *stubSessionService) DestroySession(ctx context.Context, sessionID string) error { return nil }
+func (s *stubSessionService) DestroySessionByToken(ctx context.Context, token string) error {
+	return nil
+}
+func (s *stubSessionService) DestroyAllUserSessions(ctx context.Context, userID string) error {
+	s.destroyed = append(s.destroyed, userID)
+	return nil
+}
+func (s *stubSessionService) DestroyOtherSessions(ctx context.Context, userID string, currentSessionID string) error {
+	return nil
+}
+func (s *stubSessionService) GetSessionByToken(ctx context.Context, token string) (*domainsession.Session, error) {
+	return nil, nil
+}
+func (s *stubSessionService) GetSessionBySessionID(ctx context.Context, sessionID string) (*domainsession.Session, error) {
+	return nil, nil
+}
+
+// mailedToken extracts the reset token from the link in the email body
+func mailedToken(t *testing.T, body string) string {
+	t.Helper()
+	idx := strings.Index(body, "?token=")
+	if idx < 0 {
+		t.Fatalf("email body has no reset link: %q", body)
+	}
+	raw := strings.Fields(body[idx+len("?token="):])[0]
+	token, err := url.QueryUnescape(raw)
+	if err != nil {
+		t.Fatalf("invalid token in reset link: %v", err)
+	}
+	return token
+}
+
+func TestPasswordReset(t *testing.T) {
+	newService := func() (*ServiceUser, *stubUserTokenRepo, *stubMailer, *stubSessionService) {
+		users := &stubUserRepo{users: map[string]domainuser.Users{
+			"user-1": {Id: "user-1", Name: "Rider", Email: "rider@example.com", Password: "old"},
+		}}
+		tokens := &stubUserTokenRepo{tokens: map[string]domainauth.UserToken{}}
+		mail := &stubMailer{}
+		sessions := &stubSessionService{}
+		blacklist := &stubBlacklistRepo{revocations: map[string]domainauth.TokenRevocation{}}
+		return NewUserService(users, blacklist, nil, nil, tokens, nil, mail, sessions), tokens, mail, sessions
+	}
+	const newPassword = "N3w-Passw0rd!"
+
+	t.Run("unknown email is silently ignored", func(t *testing.T) {
+		svc, tokens, mail, _ := newService()
+		if err := svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "nobody@example.com"}); err != nil {
+			t.Fatalf("ForgotPassword() error = %v", err)
+		}
+		if len(tokens.tokens) != 0 || mail.to != "" {
+			t.Fatalf("ForgotPassword() stored %d tokens and mailed %q, want nothing", len(tokens.tokens), mail.to)
+		}
+	})
+
+	t.Run("token is hashed, single use and revokes sessions", func(t *testing.T) {
+		svc, tokens, mail, sessions := newService()
+		if err := svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"}); err != nil {
+			t.Fatalf("ForgotPassword() error = %v", err)
+		}
+		if mail.to != "rider@example.com" {
+			t.Fatalf("reset email sent to %q", mail.to)
+		}
+
+		token := mailedToken(t, mail.body)
+		for _, stored := range tokens.tokens {
+			if stored.TokenHash == token {
+				t.Fatalf("token is stored in plain text")
+			}
+		}
+
+		if err := svc.ResetPassword(dto.ResetPasswordRequest{Token: token, NewPassword: newPassword}); err != nil {
+			t.Fatalf("ResetPassword() error = %v", err)
+		}
+		user, _ := svc.UserRepo.GetByID("user-1")
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(newPassword)) != nil {
+			t.Fatalf("password was not updated")
+		}
+		if len(sessions.destroyed) != 1 || sessions.destroyed[0] != "user-1" {
+			t.Fatalf("sessions destroyed for %v, want [user-1]", sessions.destroyed)
+		}
+		if _, err := svc.BlacklistRepo.GetRevocation("user-1"); err != nil {
+			t.Fatalf("access tokens of user-1 were not revoked: %v", err)
+		}
+
+		if err := svc.ResetPassword(dto.ResetPasswordRequest{Token: token, NewPassword: newPassword}); err == nil {
+			t.Fatalf("ResetPassword() accepted a used token")
+		}
+	})
+
+	t.Run("mailer failure is not reported to the caller", func(t *testing.T) {
+		svc, _, mail, _ := newService()
+		mail.err = errors.New("535 authentication failed")
+		if err := svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"}); err != nil {
+			t.Fatalf("ForgotPassword() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("new request invalidates the previous token", func(t *testing.T) {
+		svc, _, mail, _ := newService()
+		_ = svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"})
+		first := mailedToken(t, mail.body)
+		_ = svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"})
+
+		if err := svc.ResetPassword(dto.ResetPasswordRequest{Token: first, NewPassword: newPassword}); err == nil {
+			t.Fatalf("ResetPassword() accepted a replaced token")
+		}
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		svc, tokens, mail, _ := newService()
+		_ = svc.ForgotPassword(dto.ForgotPasswordRequest{Email: "rider@example.com"})
+		for id, stored := range tokens.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Minute)
+			tokens.tokens[id] = stored
+		}
+
+		err := svc.ResetPassword(dto.ResetPasswordRequest{Token: mailedToken(t, mail.body), NewPassword: newPassword})
+		if err == nil || err.Error() != "invalid or expired token" {
+			t.Fatalf("ResetPassword() error = %v, want invalid or expired token", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package serviceuser
+
+import (
+	"errors"
+	"time"
+
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+)
+
+func (s *ServiceUser) GetUserRegions(id string) ([]domainuser.UserRegion, error) {
+	if _, err := s.UserRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.UserRegionRepo.GetByUser(id)
+}
+
+// AssignUserRegions replaces the regions the user is limited to, duplicates and cities of an assigned province are dropped.
+// The caller can only hand out regions inside their own scope and cannot change their own regions
+func (s *ServiceUser) AssignUserRegions(id, callerId, username string, scope filter.RegionScope, req dto.AssignUserRegions) ([]domainuser.UserRegion, error) {
+	user, err := s.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == utils.RoleSuperAdmin {
+		return nil, errors.New("superadmin users cannot be limited to a region")
+	}
+	if err := checkRegionAssignment(id, callerId, scope, req.Regions); err != nil {
+		return nil, err
+	}
+
+	wholeProvinces := make(map[string]bool)
+	for _, item := range req.Regions {
+		if item.CityId == "" {
+			wholeProvinces[item.ProvinceId] = true
+		}
+	}
+
+	now := time.Now()
+	seen := make(map[domainuser.UserRegion]bool)
+	regions := make([]domainuser.UserRegion, 0, len(req.Regions))
+	for _, item := range req.Regions {
+		if item.CityId != "" && wholeProvinces[item.ProvinceId] {
+			continue
+		}
+
+		key := domainuser.UserRegion{ProvinceId: item.ProvinceId, CityId: item.CityId}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		regions = append(regions, domainuser.UserRegion{
+			Id:         utils.CreateUUID(),
+			UserId:     id,
+			ProvinceId: item.ProvinceId,
+			CityId:     item.CityId,
+			CreatedAt:  now,
+			CreatedBy:  username,
+		})
+	}
+
+	if err := s.UserRegionRepo.ReplaceByUser(id, regions); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+// checkRegionAssignment keeps a region-restricted caller from widening access, an empty list lifts every
+// restriction so only an unrestricted caller may send it
+func checkRegionAssignment(id, callerId string, scope filter.RegionScope, regions []dto.UserRegionItem) error {
+	if id == callerId {
+		return errors.New("you cannot change your own regions")
+	}
+	if len(regions) == 0 && scope.IsRestricted() {
+		return filter.ErrOutOfRegion
+	}
+	for _, region := range regions {
+		if !scope.Allows(region.ProvinceId, region.CityId) {
+			return filter.ErrOutOfRegion
+		}
+	}
+	return nil
+}
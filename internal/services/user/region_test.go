@@ -0,0 +1,43 @@
+package serviceuser
+
+import (
+	"errors"
+	"testing"
+
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/filter"
+)
+
+func TestCheckRegionAssignment(t *testing.T) {
+	restricted := filter.RegionScope{Regions: []filter.Region{{ProvinceId: "32"}, {ProvinceId: "33", CityId: "01"}}}
+
+	tests := []struct {
+		name       string
+		callerId   string
+		scope      filter.RegionScope
+		regions    []dto.UserRegionItem
+		wantErr    bool
+		outOfScope bool
+	}{
+		{name: "unrestricted caller", callerId: "admin", regions: []dto.UserRegionItem{{ProvinceId: "11"}}},
+		{name: "unrestricted caller lifts the restriction", callerId: "admin"},
+		{name: "inside the scope", callerId: "admin", scope: restricted, regions: []dto.UserRegionItem{{ProvinceId: "32", CityId: "05"}, {ProvinceId: "33", CityId: "01"}}},
+		{name: "own regions", callerId: "target", regions: []dto.UserRegionItem{{ProvinceId: "32"}}, wantErr: true},
+		{name: "own restriction lifted", callerId: "target", scope: restricted, wantErr: true},
+		{name: "restricted caller lifts the restriction", callerId: "admin", scope: restricted, wantErr: true, outOfScope: true},
+		{name: "other province", callerId: "admin", scope: restricted, regions: []dto.UserRegionItem{{ProvinceId: "11"}}, wantErr: true, outOfScope: true},
+		{name: "whole province of an assigned city", callerId: "admin", scope: restricted, regions: []dto.UserRegionItem{{ProvinceId: "33"}}, wantErr: true, outOfScope: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRegionAssignment("target", tt.callerId, tt.scope, tt.regions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRegionAssignment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.outOfScope && !errors.Is(err, filter.ErrOutOfRegion) {
+				t.Fatalf("checkRegionAssignment() error = %v, want ErrOutOfRegion", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package serviceuser
+
+import (
+	"errors"
+	"fmt"
+	domainauth "safety-riding/internal/domain/auth"
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/security"
+	"safety-riding/utils"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	recoveryCodeCount = 10
+	// Recovery codes stay valid until they are used or regenerated
+	recoveryCodeLifetime = 10 * 365 * 24 * time.Hour
+)
+
+var (
+	errInvalidTwoFactorCode   = errors.New(messages.ErrInvalidTwoFactor)
+	errTwoFactorAlreadyActive = errors.New("two-factor authentication is already enabled")
+	errTwoFactorNotActive     = errors.New("two-factor authentication is not enabled")
+	errTwoFactorNotSetUp      = errors.New("two-factor authentication has not been set up")
+)
+
+// LoginTwoFactor completes a login that was paused for a second factor.
+// The user is returned once the challenge is valid, also when the code is wrong, so the caller can throttle per account.
+func (s *ServiceUser) LoginTwoFactor(req dto.TwoFactorLoginRequest, logId string) (domainuser.Users, dto.LoginResult, error) {
+	challenge, err := s.findUserToken(domainauth.TokenPurposeTwoFactorLogin, req.ChallengeToken)
+	if err != nil {
+		return domainuser.Users{}, dto.LoginResult{}, err
+	}
+
+	data, err := s.UserRepo.GetByID(challenge.UserId)
+	if err != nil {
+		return domainuser.Users{}, dto.LoginResult{}, errors.New("user not found")
+	}
+	if data.TOTPSecret == "" {
+		return data, dto.LoginResult{}, errTwoFactorNotSetUp
+	}
+
+	// Recovery codes only exist once enrollment is finished
+	enrolling := data.TOTPEnabledAt == nil
+	ok, err := s.verifySecondFactor(&data, req.Code, !enrolling)
+	if err != nil {
+		return data, dto.LoginResult{}, err
+	}
+	if !ok {
+		return data, dto.LoginResult{}, errInvalidTwoFactorCode
+	}
+
+	if _, err = s.consumeUserToken(domainauth.TokenPurposeTwoFactorLogin, req.ChallengeToken); err != nil {
+		return data, dto.LoginResult{}, err
+	}
+
+	var result dto.LoginResult
+	if enrolling {
+		now := time.Now()
+		data.TOTPEnabledAt = &now
+		if result.RecoveryCodes, err = s.issueRecoveryCodes(data.Id); err != nil {
+			return data, dto.LoginResult{}, err
+		}
+	}
+
+	if err = s.UserRepo.Update(data); err != nil {
+		return data, dto.LoginResult{}, err
+	}
+
+	if result.Token, err = s.generateAccessToken(&data, logId); err != nil {
+		return data, dto.LoginResult{}, err
+	}
+
+	return data, result, nil
+}
+
+// SetupTwoFactorWithChallenge starts enrollment for a user whose role requires 2FA but who has not enrolled yet
+func (s *ServiceUser) SetupTwoFactorWithChallenge(req dto.TwoFactorChallengeRequest) (dto.TwoFactorSetup, error) {
+	challenge, err := s.findUserToken(domainauth.TokenPurposeTwoFactorLogin, req.ChallengeToken)
+	if err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+
+	data, err := s.UserRepo.GetByID(challenge.UserId)
+	if err != nil {
+		return dto.TwoFactorSetup{}, errors.New("user not found")
+	}
+
+	return s.startTwoFactorSetup(data)
+}
+
+func (s *ServiceUser) SetupTwoFactor(id string) (dto.TwoFactorSetup, error) {
+	data, err := s.UserRepo.GetByID(id)
+	if err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+
+	return s.startTwoFactorSetup(data)
+}
+
+func (s *ServiceUser) EnableTwoFactor(id string, req dto.TwoFactorCodeRequest) ([]string, error) {
+	data, err := s.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if data.TOTPEnabledAt != nil {
+		return nil, errTwoFactorAlreadyActive
+	}
+	if data.TOTPSecret == "" {
+		return nil, errTwoFactorNotSetUp
+	}
+
+	ok, err := s.verifySecondFactor(&data, req.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	data.TOTPEnabledAt = &now
+	if err = s.UserRepo.Update(data); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(data.Id)
+}
+
+func (s *ServiceUser) DisableTwoFactor(id string, req dto.TwoFactorDisableRequest) error {
+	data, err := s.UserRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if data.TOTPEnabledAt == nil {
+		return errTwoFactorNotActive
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(req.Password)); err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	ok, err := s.verifySecondFactor(&data, req.Code, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidTwoFactorCode
+	}
+
+	data.TOTPSecret = ""
+	data.TOTPEnabledAt = nil
+	data.TOTPLastStep = 0
+	if err = s.UserRepo.Update(data); err != nil {
+		return err
+	}
+
+	return s.UserTokenRepo.RevokeByUser(data.Id, domainauth.TokenPurposeRecoveryCode)
+}
+
+func (s *ServiceUser) RegenerateRecoveryCodes(id string, req dto.TwoFactorCodeRequest) ([]string, error) {
+	data, err := s.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if data.TOTPEnabledAt == nil {
+		return nil, errTwoFactorNotActive
+	}
+
+	ok, err := s.verifySecondFactor(&data, req.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	if err = s.UserRepo.Update(data); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(data.Id)
+}
+
+// startTwoFactorSetup stores a new pending secret, 2FA only becomes active once a code from it is verified
+func (s *ServiceUser) startTwoFactorSetup(data domainuser.Users) (dto.TwoFactorSetup, error) {
+	if data.TOTPEnabledAt != nil {
+		return dto.TwoFactorSetup{}, errTwoFactorAlreadyActive
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+
+	data.TOTPSecret = secret
+	data.TOTPLastStep = 0
+	if err = s.UserRepo.Update(data); err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+
+	issuer := utils.GetEnv("TOTP_ISSUER", "Safety Riding").(string)
+	return dto.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(issuer, data.Email, secret),
+	}, nil
+}
+
+// verifySecondFactor accepts a TOTP code, or a recovery code when allowRecovery is set.
+// An accepted TOTP step is recorded on data so the same code cannot be replayed, the caller must persist data.
+func (s *ServiceUser) verifySecondFactor(data *domainuser.Users, code string, allowRecovery bool) (bool, error) {
+	if step, ok := security.ValidateTOTP(data.TOTPSecret, code, time.Now()); ok {
+		if step <= data.TOTPLastStep {
+			return false, nil
+		}
+		data.TOTPLastStep = step
+		return true, nil
+	}
+
+	if !allowRecovery {
+		return false, nil
+	}
+
+	token, err := s.findUserToken(domainauth.TokenPurposeRecoveryCode, normalizeRecoveryCode(code))
+	if err != nil || token.UserId != data.Id {
+		return false, nil
+	}
+
+	used, err := s.UserTokenRepo.MarkUsed(token.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return used, nil
+}
+
+// issueRecoveryCodes replaces the recovery codes of a user and returns the new plain codes
+func (s *ServiceUser) issueRecoveryCodes(userId string) ([]string, error) {
+	if err := s.UserTokenRepo.RevokeByUser(userId, domainauth.TokenPurposeRecoveryCode); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.GenerateSecureToken(5)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = s.UserTokenRepo.Store(domainauth.UserToken{
+			ID:        utils.CreateUUID(),
+			UserId:    userId,
+			Purpose:   domainauth.TokenPurposeRecoveryCode,
+			TokenHash: utils.HashToken(raw),
+			ExpiresAt: time.Now().Add(recoveryCodeLifetime),
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, fmt.Sprintf("%s-%s", raw[:5], raw[5:]))
+	}
+
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
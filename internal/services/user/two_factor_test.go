@@ -0,0 +1,122 @@
+package serviceuser
+
+import (
+	"testing"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	domainuser "safety-riding/internal/domain/user"
+	"safety-riding/internal/dto"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/security"
+	"safety-riding/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTwoFactorLogin(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
+
+	const password = "Passw0rd!"
+	login := dto.Login{Email: "admin@example.com", Password: password}
+	requireAdmin := dto.LoginPolicy{TwoFactorRequiredRoles: []string{utils.RoleAdmin}}
+
+	newService := func() *ServiceUser {
+		hashedPwd, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		users := &stubUserRepo{users: map[string]domainuser.Users{
+			"user-1": {Id: "user-1", Name: "Admin", Email: "admin@example.com", Password: string(hashedPwd), Role: utils.RoleAdmin},
+		}}
+		tokens := &stubUserTokenRepo{tokens: map[string]domainauth.UserToken{}}
+		return NewUserService(users, nil, nil, nil, tokens, nil, &stubMailer{}, nil)
+	}
+
+	// enroll walks through the forced enrollment and returns the secret and recovery codes
+	enroll := func(t *testing.T, svc *ServiceUser) (string, []string) {
+		t.Helper()
+		result, err := svc.LoginUser(login, "log-1", requireAdmin)
+		if err != nil {
+			t.Fatalf("LoginUser() error = %v", err)
+		}
+		if !result.TwoFactorSetupRequired || result.Token != "" {
+			t.Fatalf("LoginUser() = %+v, want a setup challenge without token", result)
+		}
+
+		setup, err := svc.SetupTwoFactorWithChallenge(dto.TwoFactorChallengeRequest{ChallengeToken: result.ChallengeToken})
+		if err != nil {
+			t.Fatalf("SetupTwoFactorWithChallenge() error = %v", err)
+		}
+
+		code, _ := security.TOTPCode(setup.Secret, time.Now())
+		_, done, err := svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: code}, "log-1")
+		if err != nil {
+			t.Fatalf("LoginTwoFactor() error = %v", err)
+		}
+		if done.Token == "" || len(done.RecoveryCodes) != recoveryCodeCount {
+			t.Fatalf("LoginTwoFactor() = %+v, want a token and %d recovery codes", done, recoveryCodeCount)
+		}
+		return setup.Secret, done.RecoveryCodes
+	}
+
+	t.Run("roles without enforcement log in directly", func(t *testing.T) {
+		svc := newService()
+		result, err := svc.LoginUser(login, "log-1", dto.LoginPolicy{})
+		if err != nil || result.Token == "" || result.TwoFactorRequired {
+			t.Fatalf("LoginUser() = %+v, %v, want a token", result, err)
+		}
+	})
+
+	t.Run("required role enrolls during login", func(t *testing.T) {
+		svc := newService()
+		enroll(t, svc)
+
+		user, _ := svc.UserRepo.GetByID("user-1")
+		if user.TOTPEnabledAt == nil {
+			t.Fatalf("two-factor was not enabled")
+		}
+	})
+
+	t.Run("enrolled user always needs a second factor and codes cannot be replayed", func(t *testing.T) {
+		svc := newService()
+		secret, _ := enroll(t, svc)
+
+		result, err := svc.LoginUser(login, "log-1", dto.LoginPolicy{})
+		if err != nil || !result.TwoFactorRequired || result.TwoFactorSetupRequired {
+			t.Fatalf("LoginUser() = %+v, %v, want a two-factor challenge", result, err)
+		}
+
+		code, _ := security.TOTPCode(secret, time.Now())
+		user, _, err := svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: code}, "log-1")
+		if err == nil || err.Error() != messages.ErrInvalidTwoFactor {
+			t.Fatalf("LoginTwoFactor() with replayed code error = %v, want %s", err, messages.ErrInvalidTwoFactor)
+		}
+		if user.Id != "user-1" {
+			t.Fatalf("LoginTwoFactor() did not return the user for a wrong code")
+		}
+	})
+
+	t.Run("recovery code works once", func(t *testing.T) {
+		svc := newService()
+		_, codes := enroll(t, svc)
+
+		result, _ := svc.LoginUser(login, "log-1", dto.LoginPolicy{})
+		if _, done, err := svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: codes[0]}, "log-1"); err != nil || done.Token == "" {
+			t.Fatalf("LoginTwoFactor() with recovery code = %+v, %v", done, err)
+		}
+
+		result, _ = svc.LoginUser(login, "log-1", dto.LoginPolicy{})
+		if _, _, err := svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: codes[0]}, "log-1"); err == nil {
+			t.Fatalf("LoginTwoFactor() accepted a used recovery code")
+		}
+	})
+
+	t.Run("challenge token is single use", func(t *testing.T) {
+		svc := newService()
+		_, codes := enroll(t, svc)
+
+		result, _ := svc.LoginUser(login, "log-1", dto.LoginPolicy{})
+		_, _, _ = svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: codes[0]}, "log-1")
+		if _, _, err := svc.LoginTwoFactor(dto.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: codes[1]}, "log-1"); err == nil {
+			t.Fatalf("LoginTwoFactor() accepted a used challenge token")
+		}
+	})
+}
@@ -1,17 +1,26 @@
 package serviceuser
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"regexp"
 	domainauth "safety-riding/internal/domain/auth"
+	domainsession "safety-riding/internal/domain/session"
 	domainuser "safety-riding/internal/domain/user"
 	"safety-riding/internal/dto"
 	interfaceauth "safety-riding/internal/interfaces/auth"
 	interfacepermission "safety-riding/internal/interfaces/permission"
 	interfacerole "safety-riding/internal/interfaces/role"
+	interfacesession "safety-riding/internal/interfaces/session"
 	interfaceuser "safety-riding/internal/interfaces/user"
 	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/mailer"
+	"safety-riding/pkg/messages"
 	"safety-riding/utils"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,14 +32,22 @@ type ServiceUser struct {
 	BlacklistRepo  interfaceauth.RepoAuthInterface
 	RoleRepo       interfacerole.RepoRoleInterface
 	PermissionRepo interfacepermission.RepoPermissionInterface
+	UserTokenRepo  interfaceauth.RepoUserTokenInterface
+	UserRegionRepo interfaceuser.RepoUserRegionInterface
+	Mailer         mailer.Mailer
+	SessionService interfacesession.ServiceSessionInterface // nil when Redis is not available
 }
 
-func NewUserService(userRepo interfaceuser.RepoUserInterface, blacklistRepo interfaceauth.RepoAuthInterface, roleRepo interfacerole.RepoRoleInterface, permissionRepo interfacepermission.RepoPermissionInterface) *ServiceUser {
+func NewUserService(userRepo interfaceuser.RepoUserInterface, blacklistRepo interfaceauth.RepoAuthInterface, roleRepo interfacerole.RepoRoleInterface, permissionRepo interfacepermission.RepoPermissionInterface, userTokenRepo interfaceauth.RepoUserTokenInterface, userRegionRepo interfaceuser.RepoUserRegionInterface, mailSender mailer.Mailer, sessionService interfacesession.ServiceSessionInterface) *ServiceUser {
 	return &ServiceUser{
 		UserRepo:       userRepo,
 		BlacklistRepo:  blacklistRepo,
 		RoleRepo:       roleRepo,
 		PermissionRepo: permissionRepo,
+		UserTokenRepo:  userTokenRepo,
+		UserRegionRepo: userRegionRepo,
+		Mailer:         mailSender,
+		SessionService: sessionService,
 	}
 }
 
@@ -123,6 +140,11 @@ func (s *ServiceUser) RegisterUser(req dto.UserRegister) (domainuser.Users, erro
 		return domainuser.Users{}, err
 	}
 
+	// The account exists even when the email fails, the user can ask for a new link
+	if err = s.sendVerificationEmail(data); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[ServiceUser][RegisterUser]; sendVerificationEmail user %s; Error: %+v", data.Id, err))
+	}
+
 	return data, nil
 }
 
@@ -165,15 +187,18 @@ func (s *ServiceUser) AdminCreateUser(req dto.AdminCreateUser, creatorRole strin
 		return domainuser.Users{}, errors.New("invalid role: " + roleName)
 	}
 
+	// Accounts created by an admin are trusted, they do not need to verify their email
+	now := time.Now()
 	data = domainuser.Users{
-		Id:        utils.CreateUUID(),
-		Name:      req.Name,
-		Phone:     phone,
-		Email:     email,
-		Password:  string(hashedPwd),
-		Role:      roleName,
-		RoleId:    &roleEntity.Id,
-		CreatedAt: time.Now(),
+		Id:              utils.CreateUUID(),
+		Name:            req.Name,
+		Phone:           phone,
+		Email:           email,
+		Password:        string(hashedPwd),
+		Role:            roleName,
+		RoleId:          &roleEntity.Id,
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
 	}
 
 	if err = s.UserRepo.Store(data); err != nil {
@@ -183,22 +208,91 @@ func (s *ServiceUser) AdminCreateUser(req dto.AdminCreateUser, creatorRole strin
 	return data, nil
 }
 
-func (s *ServiceUser) LoginUser(req dto.Login, logId string) (string, error) {
+func (s *ServiceUser) LoginUser(req dto.Login, logId string, policy dto.LoginPolicy) (dto.LoginResult, error) {
 	data, err := s.UserRepo.GetByEmail(req.Email)
 	if err != nil {
-		return "", err
+		return dto.LoginResult{}, err
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(req.Password)); err != nil {
-		return "", err
+		return dto.LoginResult{}, err
+	}
+
+	if policy.RequireVerifiedEmail && data.EmailVerifiedAt == nil {
+		return dto.LoginResult{}, errors.New(messages.ErrEmailNotVerified)
+	}
+
+	// The password is correct but a second factor is still needed before a token is issued
+	setupRequired := data.TOTPEnabledAt == nil && slices.Contains(policy.TwoFactorRequiredRoles, data.Role)
+	if data.TOTPEnabledAt != nil || setupRequired {
+		ttlMinutes := utils.GetEnv("TWO_FACTOR_CHALLENGE_TTL_MINUTES", 5).(int)
+		challenge, err := s.issueUserToken(data.Id, domainauth.TokenPurposeTwoFactorLogin, time.Duration(ttlMinutes)*time.Minute)
+		if err != nil {
+			return dto.LoginResult{}, err
+		}
+
+		return dto.LoginResult{
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: setupRequired,
+			ChallengeToken:         challenge,
+		}, nil
+	}
+
+	token, err := s.generateAccessToken(&data, logId)
+	if err != nil {
+		return dto.LoginResult{}, err
+	}
+
+	return dto.LoginResult{Token: token}, nil
+}
+
+// generateAccessToken issues a short-lived access token when sessions can hand out refresh tokens,
+// without Redis there is nothing to refresh with so the long-lived JWT_EXP token is kept
+func (s *ServiceUser) generateAccessToken(data *domainuser.Users, logId string) (string, error) {
+	if s.SessionService == nil {
+		return utils.GenerateJwt(data, logId)
+	}
+
+	ttlMinutes := utils.GetEnv("JWT_ACCESS_EXP_MINUTES", 15).(int)
+	return utils.GenerateJwtWithTTL(data, logId, time.Duration(ttlMinutes)*time.Minute)
+}
+
+func (s *ServiceUser) RefreshToken(req dto.RefreshTokenRequest, logId string) (string, string, error) {
+	if s.SessionService == nil {
+		return "", "", errors.New("refresh tokens are not available")
+	}
+
+	ctx := context.Background()
+	session, err := s.SessionService.GetSessionByRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Load the user again so role changes are picked up by the new access token
+	data, err := s.UserRepo.GetByID(session.UserID)
+	if err != nil {
+		return "", "", domainsession.ErrRefreshTokenInvalid
 	}
 
-	token, err := utils.GenerateJwt(&data, logId)
+	token, err := s.generateAccessToken(&data, logId)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	return token, nil
+	rotated, refreshToken, err := s.SessionService.RotateRefreshToken(ctx, req.RefreshToken, token)
+	if errors.Is(err, domainsession.ErrRefreshTokenReused) {
+		// The session is gone, also block the access token that is still in circulation
+		logger.WriteLog(logger.LogLevelWarn, fmt.Sprintf("[ServiceUser][RefreshToken]; refresh token reuse detected, session %s of user %s revoked", rotated.SessionID, rotated.UserID))
+		if errBlacklist := s.LogoutUser(rotated.Token); errBlacklist != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[ServiceUser][RefreshToken]; LogoutUser; Error: %+v", errBlacklist))
+		}
+		return "", "", err
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
 }
 
 func (s *ServiceUser) LogoutUser(token string) error {
@@ -327,7 +421,8 @@ func (s *ServiceUser) Update(id, role string, req dto.UserUpdate) (domainuser.Us
 		data.Email = req.Email
 	}
 
-	if strings.TrimSpace(req.Role) != "" {
+	roleChanged := strings.TrimSpace(req.Role) != ""
+	if roleChanged {
 		newRoleName := strings.ToLower(req.Role)
 
 		if newRoleName == utils.RoleSuperAdmin && role != utils.RoleSuperAdmin {
@@ -347,6 +442,9 @@ func (s *ServiceUser) Update(id, role string, req dto.UserUpdate) (domainuser.Us
 	if err = s.UserRepo.Update(data); err != nil {
 		return domainuser.Users{}, err
 	}
+	if roleChanged {
+		s.invalidatePermissionCache(id)
+	}
 
 	return data, nil
 }
@@ -384,24 +482,33 @@ func (s *ServiceUser) ChangePassword(id string, req dto.ChangePassword) (domainu
 	return data, nil
 }
 
-func (s *ServiceUser) ForgotPassword(req dto.ForgotPasswordRequest) (string, error) {
+func (s *ServiceUser) ForgotPassword(req dto.ForgotPasswordRequest) error {
 	data, err := s.UserRepo.GetByEmail(req.Email)
 	if err != nil {
 		// Return nil error to prevent email enumeration
-		return "", nil
+		return nil
 	}
 
-	// Generate a short-lived token (e.g., 15 minutes)
-	// For simplicity, we'll use the existing JWT generator but with a specific "reset_password" purpose
-	// In a real app, this should be a random string stored in Redis or DB with expiry
-	token, err := utils.GenerateJwt(&data, "reset_password")
+	ttlMinutes := utils.GetEnv("PASSWORD_RESET_TOKEN_TTL_MINUTES", 30).(int)
+	token, err := s.issueUserToken(data.Id, domainauth.TokenPurposePasswordReset, time.Duration(ttlMinutes)*time.Minute)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	resetURL := utils.GetEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password").(string)
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"We received a request to reset the password of your Safety Riding account.\n"+
+		"Open the link below to choose a new password. The link expires in %d minutes and can only be used once.\n\n"+
+		"%s?token=%s\n\n"+
+		"If you did not request a password reset, you can ignore this email.",
+		data.Name, ttlMinutes, resetURL, url.QueryEscape(token))
+
+	// A failed send must look like any other request, otherwise the response reveals which emails are registered
+	if err = s.Mailer.Send(data.Email, "Reset your Safety Riding password", body); err != nil {
+		logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[ServiceUser][ForgotPassword]; Mailer.Send user %s; Error: %+v", data.Id, err))
 	}
 
-	// In a real application, send this token via email
-	// For now, we return it so it can be logged/displayed for testing
-	return token, nil
+	return nil
 }
 
 func (s *ServiceUser) ResetPassword(req dto.ResetPasswordRequest) error {
@@ -410,16 +517,13 @@ func (s *ServiceUser) ResetPassword(req dto.ResetPasswordRequest) error {
 		return err
 	}
 
-	// Validate token and extract user ID
-	// Note: This relies on the token being a valid JWT signed by this server
-	claims, err := utils.JwtClaim(req.Token)
+	// Consume the token before changing the password so it cannot be redeemed twice
+	token, err := s.consumeUserToken(domainauth.TokenPurposePasswordReset, req.Token)
 	if err != nil {
-		return errors.New("invalid or expired token")
+		return err
 	}
 
-	userId := claims["user_id"].(string)
-
-	data, err := s.UserRepo.GetByID(userId)
+	data, err := s.UserRepo.GetByID(token.UserId)
 	if err != nil {
 		return errors.New("user not found")
 	}
@@ -431,20 +535,91 @@ func (s *ServiceUser) ResetPassword(req dto.ResetPasswordRequest) error {
 
 	data.Password = string(hashedPwd)
 
+	// Sign the user out everywhere, the old password may have been compromised. The access tokens already
+	// issued are revoked before the password changes, so a failed revocation never leaves them usable
+	if s.BlacklistRepo != nil {
+		if err = s.BlacklistRepo.RevokeUserTokens(data.Id, time.Now().Truncate(time.Second)); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[ServiceUser][ResetPassword]; RevokeUserTokens user %s; Error: %+v", data.Id, err))
+			return err
+		}
+	}
+
 	if err = s.UserRepo.Update(data); err != nil {
 		return err
 	}
 
-	// Ideally, we should also invalidate the token here (e.g., add to blacklist)
-	// But since we're using stateless JWTs for this mock, we'll skip it for now
-	// or we can reuse the Logout logic
-	_ = s.LogoutUser(req.Token)
+	if s.SessionService != nil {
+		if err = s.SessionService.DestroyAllUserSessions(context.Background(), data.Id); err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("[ServiceUser][ResetPassword]; DestroyAllUserSessions user %s; Error: %+v", data.Id, err))
+		}
+	}
 
 	return nil
 }
 
+func (s *ServiceUser) sendVerificationEmail(data domainuser.Users) error {
+	ttlHours := utils.GetEnv("EMAIL_VERIFICATION_TOKEN_TTL_HOURS", 24).(int)
+	token, err := s.issueUserToken(data.Id, domainauth.TokenPurposeEmailVerification, time.Duration(ttlHours)*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	verifyURL := utils.GetEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email").(string)
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"Thank you for registering a Safety Riding account.\n"+
+		"Open the link below to verify your email address. The link expires in %d hours.\n\n"+
+		"%s?token=%s\n\n"+
+		"If you did not create an account, you can ignore this email.",
+		data.Name, ttlHours, verifyURL, url.QueryEscape(token))
+
+	return s.Mailer.Send(data.Email, "Verify your Safety Riding email address", body)
+}
+
+func (s *ServiceUser) ResendVerificationEmail(req dto.ResendVerificationRequest) error {
+	data, err := s.UserRepo.GetByEmail(req.Email)
+	if err != nil || data.EmailVerifiedAt != nil {
+		// Return nil error to prevent email enumeration
+		return nil
+	}
+
+	return s.sendVerificationEmail(data)
+}
+
+func (s *ServiceUser) VerifyEmail(req dto.VerifyEmailRequest) error {
+	token, err := s.consumeUserToken(domainauth.TokenPurposeEmailVerification, req.Token)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.UserRepo.GetByID(token.UserId)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if data.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	data.EmailVerifiedAt = &now
+
+	return s.UserRepo.Update(data)
+}
+
 func (s *ServiceUser) Delete(id string) error {
-	return s.UserRepo.Delete(id)
+	if err := s.UserRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidatePermissionCache(id)
+
+	return nil
+}
+
+// invalidatePermissionCache drops the cached permissions of a user when the permission repo caches them
+func (s *ServiceUser) invalidatePermissionCache(userId string) {
+	if invalidator, ok := s.PermissionRepo.(interfacepermission.PermissionCacheInvalidator); ok {
+		invalidator.InvalidateUser(userId)
+	}
 }
 
 var _ interfaceuser.ServiceUserInterface = (*ServiceUser)(nil)
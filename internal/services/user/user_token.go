@@ -0,0 +1,67 @@
+package serviceuser
+
+import (
+	"errors"
+	domainauth "safety-riding/internal/domain/auth"
+	"safety-riding/utils"
+	"time"
+)
+
+var errInvalidUserToken = errors.New("invalid or expired token")
+
+// issueUserToken creates a new single-use token for the user and returns its plain value.
+// Older unused tokens with the same purpose are revoked so only the latest link works.
+func (s *ServiceUser) issueUserToken(userId, purpose string, ttl time.Duration) (string, error) {
+	if err := s.UserTokenRepo.RevokeByUser(userId, purpose); err != nil {
+		return "", err
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.UserTokenRepo.Store(domainauth.UserToken{
+		ID:        utils.CreateUUID(),
+		UserId:    userId,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// findUserToken returns the stored token for a plain token when it is unused and not expired, without consuming it
+func (s *ServiceUser) findUserToken(purpose, token string) (domainauth.UserToken, error) {
+	data, err := s.UserTokenRepo.GetByHash(purpose, utils.HashToken(token))
+	if err != nil {
+		return domainauth.UserToken{}, errInvalidUserToken
+	}
+	if data.UsedAt != nil || time.Now().After(data.ExpiresAt) {
+		return domainauth.UserToken{}, errInvalidUserToken
+	}
+
+	return data, nil
+}
+
+// consumeUserToken validates a plain token and marks it as used, it can only succeed once per token
+func (s *ServiceUser) consumeUserToken(purpose, token string) (domainauth.UserToken, error) {
+	data, err := s.findUserToken(purpose, token)
+	if err != nil {
+		return domainauth.UserToken{}, err
+	}
+
+	used, err := s.UserTokenRepo.MarkUsed(data.ID)
+	if err != nil {
+		return domainauth.UserToken{}, err
+	}
+	if !used {
+		return domainauth.UserToken{}, errInvalidUserToken
+	}
+
+	return data, nil
+}
@@ -114,15 +114,20 @@ func main() {
 	routes.DistrictRoutes()
 	routes.AccidentRoutes()
 	routes.EventRoutes()
+	routes.AssessmentRoutes()
+	routes.CertificateRoutes()
+	routes.InstructorRoutes()
 	routes.BudgetRoutes()
 	routes.MarketShareRoutes()
 	routes.ApprovalRecordRoutes()
 	routes.AppConfigRoutes()
 	routes.RoleRoutes()
 	routes.PermissionRoutes()
+	routes.AuditLogRoutes()
 	routes.MenuRoutes()
 	routes.PoldaRoutes()
 	routes.DashboardRoutes()
+	routes.FileRoutes()
 
 	// Register session routes if Redis is available
 	if redisClient != nil {
@@ -131,6 +136,9 @@ func main() {
 
 	logger.WriteLog(logger.LogLevelInfo, "✓ All routes registered successfully")
 
+	// Reconcile the photo and attachment folders of the storage in the background when STORAGE_GC_INTERVAL_HOURS is set
+	routes.StorageGCJob()
+
 	err = routes.App.Run(fmt.Sprintf(":%s", port))
 	FailOnError(err, "Failed run service")
 }
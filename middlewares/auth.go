@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"safety-riding/internal/interfaces/auth"
 	"safety-riding/internal/interfaces/permission"
+	interfaceuser "safety-riding/internal/interfaces/user"
+	"safety-riding/pkg/filter"
 	"safety-riding/pkg/logger"
 	"safety-riding/pkg/messages"
 	"safety-riding/pkg/response"
@@ -21,13 +23,15 @@ import (
 type Middleware struct {
 	BlacklistRepo  interfaceauth.RepoAuthInterface
 	PermissionRepo interfacepermission.RepoPermissionInterface
+	UserRegionRepo interfaceuser.RepoUserRegionInterface // nil disables region scoping
 }
 
 // NewMiddleware creates a new middleware with its dependencies
-func NewMiddleware(blacklistRepo interfaceauth.RepoAuthInterface, permissionRepo interfacepermission.RepoPermissionInterface) *Middleware {
+func NewMiddleware(blacklistRepo interfaceauth.RepoAuthInterface, permissionRepo interfacepermission.RepoPermissionInterface, userRegionRepo interfaceuser.RepoUserRegionInterface) *Middleware {
 	return &Middleware{
 		BlacklistRepo:  blacklistRepo,
 		PermissionRepo: permissionRepo,
+		UserRegionRepo: userRegionRepo,
 	}
 }
 
@@ -72,7 +76,34 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Tokens issued before the last password reset are no longer accepted
+		revoked, err := m.revokedByReset(dataJWT)
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; blacklistRepo.GetRevocation; Error: %+v", logPrefix, err))
+			res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+		if revoked {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; Invalid Token: %s; Error: token was issued before the password reset;", logPrefix, tokenString))
+			res := response.Response(http.StatusUnauthorized, messages.MsgFail, logId, nil)
+			res.Error = "Please login and try again"
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		}
+
+		scope, err := m.regionScope(dataJWT)
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; UserRegionRepo.GetByUser; Error: %+v", logPrefix, err))
+			res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+
 		ctx.Set(utils.CtxKeyAuthData, dataJWT)
+		ctx.Set(filter.CtxKeyRegionScope, scope)
 		ctx.Set("token", tokenString)
 		ctx.Set("userId", utils.InterfaceString(dataJWT["user_id"]))
 
@@ -80,6 +111,39 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// revokedByReset reports whether the token was issued before the user's tokens were revoked,
+// iat only has second precision so the revocation time is compared in whole seconds
+func (m *Middleware) revokedByReset(dataJWT map[string]interface{}) (bool, error) {
+	revocation, err := m.BlacklistRepo.GetRevocation(utils.InterfaceString(dataJWT["user_id"]))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	issuedAt, _ := dataJWT["iat"].(float64)
+	return int64(issuedAt) < revocation.RevokedAt.Unix(), nil
+}
+
+// regionScope loads the regions the user is limited to, superadmin and users without assignments see everything
+func (m *Middleware) regionScope(dataJWT map[string]interface{}) (filter.RegionScope, error) {
+	if m.UserRegionRepo == nil || utils.InterfaceString(dataJWT["role"]) == utils.RoleSuperAdmin {
+		return filter.RegionScope{}, nil
+	}
+
+	regions, err := m.UserRegionRepo.GetByUser(utils.InterfaceString(dataJWT["user_id"]))
+	if err != nil {
+		return filter.RegionScope{}, err
+	}
+
+	scope := filter.RegionScope{Regions: make([]filter.Region, 0, len(regions))}
+	for _, region := range regions {
+		scope.Regions = append(scope.Regions, filter.Region{ProvinceId: region.ProvinceId, CityId: region.CityId})
+	}
+	return scope, nil
+}
+
 func (m *Middleware) RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var (
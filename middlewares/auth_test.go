@@ -0,0 +1,215 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	domainpermission "safety-riding/internal/domain/permission"
+	domainuser "safety-riding/internal/domain/user"
+	repositoryauth "safety-riding/internal/repositories/auth"
+	repositorypermission "safety-riding/internal/repositories/permission"
+	"safety-riding/pkg/cache"
+	"safety-riding/pkg/filter"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// countingBlacklistRepo stands in for the database and counts the queries it receives
+type countingBlacklistRepo struct {
+	queries     int
+	tokens      map[string]domainauth.Blacklist
+	revocations map[string]domainauth.TokenRevocation
+}
+
+func (s *countingBlacklistRepo) Store(m domainauth.Blacklist) error {
+	s.tokens[m.Token] = m
+	return nil
+}
+func (s *countingBlacklistRepo) GetByToken(token string) (domainauth.Blacklist, error) {
+	s.queries++
+	if m, ok := s.tokens[token]; ok {
+		return m, nil
+	}
+	return domainauth.Blacklist{}, gorm.ErrRecordNotFound
+}
+func (s *countingBlacklistRepo) RevokeUserTokens(userId string, revokedAt time.Time) error {
+	s.revocations[userId] = domainauth.TokenRevocation{UserId: userId, RevokedAt: revokedAt}
+	return nil
+}
+func (s *countingBlacklistRepo) GetRevocation(userId string) (domainauth.TokenRevocation, error) {
+	s.queries++
+	if m, ok := s.revocations[userId]; ok {
+		return m, nil
+	}
+	return domainauth.TokenRevocation{}, gorm.ErrRecordNotFound
+}
+
+type countingPermissionRepo struct {
+	queries     int
+	permissions []domainpermission.Permission
+}
+
+func (s *countingPermissionRepo) Store(m domainpermission.Permission) error { return nil }
+func (s *countingPermissionRepo) GetByID(id string) (domainpermission.Permission, error) {
+	return domainpermission.Permission{}, nil
+}
+func (s *countingPermissionRepo) GetByName(name string) (domainpermission.Permission, error) {
+	return domainpermission.Permission{}, nil
+}
+func (s *countingPermissionRepo) GetAll(params filter.BaseParams) ([]domainpermission.Permission, int64, error) {
+	return nil, 0, nil
+}
+func (s *countingPermissionRepo) Update(m domainpermission.Permission) error { return nil }
+func (s *countingPermissionRepo) Delete(id string) error                     { return nil }
+func (s *countingPermissionRepo) GetByResource(resource string) ([]domainpermission.Permission, error) {
+	return nil, nil
+}
+func (s *countingPermissionRepo) GetUserPermissions(userId string) ([]domainpermission.Permission, error) {
+	s.queries++
+	return s.permissions, nil
+}
+
+type authFixture struct {
+	engine      *gin.Engine
+	mdw         *Middleware
+	token       string
+	blacklist   *countingBlacklistRepo
+	permissions *countingPermissionRepo
+}
+
+func (f *authFixture) queries() int {
+	return f.blacklist.queries + f.permissions.queries
+}
+
+func (f *authFixture) do() int {
+	req := httptest.NewRequest(http.MethodGet, "/schools", nil)
+	req.Header.Set("Authorization", "Bearer "+f.token)
+	w := httptest.NewRecorder()
+	f.engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+func newAuthFixture(tb testing.TB, cached bool) *authFixture {
+	tb.Helper()
+	gin.SetMode(gin.ReleaseMode)
+	tb.Setenv("JWT_KEY", "test-key")
+
+	token, err := utils.GenerateJwt(&domainuser.Users{Id: "user-1", Name: "Staff", Role: "staff"}, "log-1")
+	if err != nil {
+		tb.Fatalf("GenerateJwt() error = %v", err)
+	}
+
+	f := &authFixture{
+		token:       token,
+		blacklist:   &countingBlacklistRepo{tokens: map[string]domainauth.Blacklist{}, revocations: map[string]domainauth.TokenRevocation{}},
+		permissions: &countingPermissionRepo{permissions: []domainpermission.Permission{{Resource: "schools", Action: "view"}}},
+	}
+
+	var mdw *Middleware
+	if cached {
+		c := cache.NewMemoryCache()
+		mdw = NewMiddleware(
+			repositoryauth.NewCachedBlacklistRepo(f.blacklist, c, time.Minute),
+			repositorypermission.NewCachedPermissionRepo(f.permissions, c, time.Minute),
+			nil,
+		)
+	} else {
+		mdw = NewMiddleware(f.blacklist, f.permissions, nil)
+	}
+
+	f.mdw = mdw
+	f.engine = gin.New()
+	f.engine.GET("/schools", mdw.AuthMiddleware(), mdw.PermissionMiddleware("schools", "view"), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	return f
+}
+
+func TestCachedMiddleware(t *testing.T) {
+	t.Run("repeated requests are served from the cache", func(t *testing.T) {
+		f := newAuthFixture(t, true)
+		for i := 0; i < 5; i++ {
+			if code := f.do(); code != http.StatusOK {
+				t.Fatalf("request %d status = %d, want %d", i, code, http.StatusOK)
+			}
+		}
+		if f.queries() != 3 {
+			t.Fatalf("database queries = %d, want 3", f.queries())
+		}
+	})
+
+	t.Run("password reset rejects earlier tokens", func(t *testing.T) {
+		f := newAuthFixture(t, true)
+		if code := f.do(); code != http.StatusOK {
+			t.Fatalf("status before reset = %d, want %d", code, http.StatusOK)
+		}
+
+		repo := f.mdw.BlacklistRepo
+		if err := repo.RevokeUserTokens("user-1", time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("RevokeUserTokens() error = %v", err)
+		}
+		if code := f.do(); code != http.StatusUnauthorized {
+			t.Fatalf("status after reset = %d, want %d", code, http.StatusUnauthorized)
+		}
+
+		_ = repo.RevokeUserTokens("user-1", time.Now().Add(-time.Minute))
+		if code := f.do(); code != http.StatusOK {
+			t.Fatalf("status for a token issued after the reset = %d, want %d", code, http.StatusOK)
+		}
+	})
+
+	t.Run("logout is visible immediately", func(t *testing.T) {
+		db := &countingBlacklistRepo{tokens: map[string]domainauth.Blacklist{}, revocations: map[string]domainauth.TokenRevocation{}}
+		repo := repositoryauth.NewCachedBlacklistRepo(db, cache.NewMemoryCache(), time.Minute)
+
+		if _, err := repo.GetByToken("token-1"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByToken() error = %v, want record not found", err)
+		}
+		_ = repo.Store(domainauth.Blacklist{ID: "bl-1", Token: "token-1"})
+		if _, err := repo.GetByToken("token-1"); err != nil {
+			t.Fatalf("GetByToken() after logout error = %v, want blacklisted", err)
+		}
+	})
+
+	t.Run("invalidation reloads permissions", func(t *testing.T) {
+		db := &countingPermissionRepo{permissions: []domainpermission.Permission{{Resource: "schools", Action: "view"}}}
+		repo := repositorypermission.NewCachedPermissionRepo(db, cache.NewMemoryCache(), time.Minute)
+
+		_, _ = repo.GetUserPermissions("user-1")
+		db.permissions = nil
+		if perms, _ := repo.GetUserPermissions("user-1"); len(perms) != 1 {
+			t.Fatalf("GetUserPermissions() = %d permissions, want the cached 1", len(perms))
+		}
+
+		repo.InvalidateUser("user-1")
+		if perms, _ := repo.GetUserPermissions("user-1"); len(perms) != 0 {
+			t.Fatalf("GetUserPermissions() after InvalidateUser = %d permissions, want 0", len(perms))
+		}
+	})
+}
+
+func benchmarkAuthorizedRequest(b *testing.B, cached bool) {
+	f := newAuthFixture(b, cached)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.do()
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(f.queries())/float64(b.N), "db_queries/op")
+}
+
+func BenchmarkAuthorizedRequestWithoutCache(b *testing.B) {
+	benchmarkAuthorizedRequest(b, false)
+}
+
+func BenchmarkAuthorizedRequestWithCache(b *testing.B) {
+	benchmarkAuthorizedRequest(b, true)
+}
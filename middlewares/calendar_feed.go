@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	domainauth "safety-riding/internal/domain/auth"
+	interfaceauth "safety-riding/internal/interfaces/auth"
+	interfaceuser "safety-riding/internal/interfaces/user"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/logger"
+	"safety-riding/pkg/messages"
+	"safety-riding/pkg/response"
+	"safety-riding/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedMiddleware authenticates calendar clients with the feed token in the URL instead of a JWT.
+// The token owner is loaded as auth data so PermissionMiddleware and region scoping apply as for a logged in user.
+func (m *Middleware) CalendarFeedMiddleware(userTokenRepo interfaceauth.RepoUserTokenInterface, userRepo interfaceuser.RepoUserInterface) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		logId := utils.GenerateLogId(ctx)
+		logPrefix := fmt.Sprintf("[%s][CalendarFeedMiddleware]", logId)
+
+		unauthorized := func(reason string) {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; %s", logPrefix, reason))
+			res := response.Response(http.StatusUnauthorized, messages.MsgFail, logId, nil)
+			res.Error = "invalid or expired calendar feed"
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		}
+
+		token := strings.TrimSuffix(ctx.Param("token"), ".ics")
+		feedToken, err := userTokenRepo.GetByHash(domainauth.TokenPurposeCalendarFeed, utils.HashToken(token))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; UserTokenRepo.GetByHash; Error: %+v", logPrefix, err))
+			res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+		if err != nil {
+			unauthorized("unknown feed token")
+			return
+		}
+		if feedToken.UsedAt != nil || time.Now().After(feedToken.ExpiresAt) {
+			unauthorized("revoked or expired feed token")
+			return
+		}
+
+		user, err := userRepo.GetByID(feedToken.UserId)
+		if err != nil {
+			unauthorized(fmt.Sprintf("userRepo.GetByID; Error: %+v", err))
+			return
+		}
+
+		dataJWT := map[string]interface{}{
+			"user_id":  user.Id,
+			"username": user.Name,
+			"role":     user.Role,
+		}
+		scope, err := m.regionScope(dataJWT)
+		if err != nil {
+			logger.WriteLog(logger.LogLevelError, fmt.Sprintf("%s; UserRegionRepo.GetByUser; Error: %+v", logPrefix, err))
+			res := response.Response(http.StatusInternalServerError, messages.MsgFail, logId, nil)
+			res.Error = err.Error()
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+
+		ctx.Set(utils.CtxKeyAuthData, dataJWT)
+		ctx.Set(filter.CtxKeyRegionScope, scope)
+		ctx.Set("userId", user.Id)
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,11 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id
+    FROM permissions
+    WHERE name = 'view_audit_logs'
+);
+
+DELETE FROM permissions
+WHERE name = 'view_audit_logs';
+
+DROP TABLE IF EXISTS audit_logs;
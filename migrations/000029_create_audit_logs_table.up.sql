@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    before_data JSONB,
+    after_data JSONB,
+    diff JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity
+    ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor
+    ON audit_logs (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at
+    ON audit_logs (created_at DESC);
+
+INSERT INTO permissions (id, name, display_name, resource, action, created_at, updated_at)
+SELECT gen_random_uuid(), 'view_audit_logs', 'View Audit Logs', 'audit_logs', 'view', NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'view_audit_logs');
+
+INSERT INTO role_permissions (id, role_id, permission_id, created_at)
+SELECT
+    gen_random_uuid(),
+    r.id,
+    p.id,
+    NOW()
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'superadmin')
+AND p.name = 'view_audit_logs'
+AND NOT EXISTS (
+    SELECT 1 FROM role_permissions rp
+    WHERE rp.role_id = r.id AND rp.permission_id = p.id
+);
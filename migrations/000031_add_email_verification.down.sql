@@ -0,0 +1,4 @@
+DELETE FROM app_configs
+WHERE config_key = 'auth.email_verification_required';
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
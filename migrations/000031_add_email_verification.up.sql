@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP NULL;
+
+-- Accounts created before verification existed are trusted as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+INSERT INTO app_configs (id, config_key, display_name, category, value, description, is_active)
+VALUES (
+    gen_random_uuid(),
+    'auth.email_verification_required',
+    'Email Verification Required',
+    'auth',
+    'false',
+    'Require users to verify their email address before they can log in. Self-registered users always receive a verification email.',
+    TRUE
+)
+ON CONFLICT (config_key) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM app_configs
+WHERE config_key IN ('auth.two_factor_required.superadmin', 'auth.two_factor_required.admin');
+
+DELETE FROM user_tokens
+WHERE purpose IN ('two_factor_login', 'two_factor_recovery');
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,25 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64) NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+INSERT INTO app_configs (id, config_key, display_name, category, value, description, is_active)
+VALUES
+    (
+        gen_random_uuid(),
+        'auth.two_factor_required.superadmin',
+        'Two-Factor Required for Superadmin',
+        'auth',
+        'false',
+        'Require superadmin accounts to use TOTP two-factor authentication. Accounts without 2FA are asked to enroll during their next login.',
+        TRUE
+    ),
+    (
+        gen_random_uuid(),
+        'auth.two_factor_required.admin',
+        'Two-Factor Required for Admin',
+        'auth',
+        'false',
+        'Require admin accounts to use TOTP two-factor authentication. Accounts without 2FA are asked to enroll during their next login.',
+        TRUE
+    )
+ON CONFLICT (config_key) DO NOTHING;
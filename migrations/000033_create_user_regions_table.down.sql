@@ -0,0 +1,11 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id
+    FROM permissions
+    WHERE name = 'assign_regions_users'
+);
+
+DELETE FROM permissions
+WHERE name = 'assign_regions_users';
+
+DROP TABLE IF EXISTS user_regions;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS user_regions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    province_id VARCHAR(20) NOT NULL,
+    city_id VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255),
+    CONSTRAINT idx_unique_user_region UNIQUE (user_id, province_id, city_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_regions_user_id
+    ON user_regions (user_id);
+
+INSERT INTO permissions (id, name, display_name, resource, action, created_at, updated_at)
+SELECT gen_random_uuid(), 'assign_regions_users', 'Assign User Regions', 'users', 'assign_regions', NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'assign_regions_users');
+
+INSERT INTO role_permissions (id, role_id, permission_id, created_at)
+SELECT
+    gen_random_uuid(),
+    r.id,
+    p.id,
+    NOW()
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'superadmin')
+AND p.name = 'assign_regions_users'
+AND NOT EXISTS (
+    SELECT 1 FROM role_permissions rp
+    WHERE rp.role_id = r.id AND rp.permission_id = p.id
+);
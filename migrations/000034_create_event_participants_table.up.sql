@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS event_participants (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id       UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name           VARCHAR(150) NOT NULL,
+    gender         VARCHAR(10) NOT NULL DEFAULT '',
+    age            INTEGER NOT NULL DEFAULT 0,
+    grade          VARCHAR(50) NOT NULL DEFAULT '',
+    organization   VARCHAR(200) NOT NULL DEFAULT '',
+    phone          VARCHAR(20) NOT NULL DEFAULT '',
+    license_status VARCHAR(20) NOT NULL DEFAULT '',
+    identity_key   VARCHAR(400) NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by     TEXT,
+    deleted_at     TIMESTAMP,
+    deleted_by     TEXT
+);
+
+COMMENT ON COLUMN event_participants.identity_key IS 'Normalized name with phone, or with organization when there is no phone, used to find the same person across events';
+COMMENT ON COLUMN event_participants.license_status IS 'Driving license status (none/in_process/licensed)';
+
+CREATE INDEX IF NOT EXISTS idx_event_participants_event_id ON event_participants (event_id);
+CREATE INDEX IF NOT EXISTS idx_event_participants_identity_key ON event_participants (identity_key) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_event_participant ON event_participants (event_id, identity_key) WHERE deleted_at IS NULL;
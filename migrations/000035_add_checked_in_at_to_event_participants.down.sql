@@ -0,0 +1,2 @@
+ALTER TABLE event_participants
+    DROP COLUMN IF EXISTS checked_in_at;
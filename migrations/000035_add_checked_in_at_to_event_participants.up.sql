@@ -0,0 +1,4 @@
+ALTER TABLE event_participants
+    ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMP;
+
+COMMENT ON COLUMN event_participants.checked_in_at IS 'When the participant checked in with the event QR code, NULL until then';
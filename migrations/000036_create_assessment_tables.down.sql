@@ -0,0 +1,11 @@
+-- Remove role permissions for question banks
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE resource = 'question_banks');
+
+-- Remove question bank permissions
+DELETE FROM permissions WHERE resource = 'question_banks';
+
+DROP TABLE IF EXISTS assessment_submissions;
+DROP TABLE IF EXISTS event_assessments;
+DROP TABLE IF EXISTS assessment_questions;
+DROP TABLE IF EXISTS question_banks;
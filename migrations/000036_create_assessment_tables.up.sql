@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS question_banks (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by  TEXT,
+    updated_at  TIMESTAMP,
+    updated_by  TEXT,
+    deleted_at  TIMESTAMP,
+    deleted_by  TEXT
+);
+
+CREATE TABLE IF NOT EXISTS assessment_questions (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bank_id        UUID NOT NULL REFERENCES question_banks(id) ON DELETE CASCADE,
+    question       TEXT NOT NULL,
+    options        JSONB NOT NULL,
+    correct_option INTEGER NOT NULL,
+    sort_order     INTEGER NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at     TIMESTAMP
+);
+
+COMMENT ON COLUMN assessment_questions.options IS 'Answer choices as a JSON array of strings';
+COMMENT ON COLUMN assessment_questions.correct_option IS 'Zero based index of the correct choice in options';
+
+CREATE INDEX IF NOT EXISTS idx_assessment_questions_bank_id ON assessment_questions (bank_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS event_assessments (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id   UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    bank_id    UUID NOT NULL REFERENCES question_banks(id),
+    type       VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by TEXT,
+    deleted_at TIMESTAMP,
+    deleted_by TEXT
+);
+
+COMMENT ON COLUMN event_assessments.type IS 'pre (before the session) or post (after the session)';
+
+CREATE INDEX IF NOT EXISTS idx_event_assessments_bank_id ON event_assessments (bank_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_event_assessment_type ON event_assessments (event_id, type) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS assessment_submissions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id   UUID NOT NULL REFERENCES event_assessments(id) ON DELETE CASCADE,
+    event_id        UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    participant_id  UUID NOT NULL REFERENCES event_participants(id) ON DELETE CASCADE,
+    answers         JSONB NOT NULL,
+    correct_count   INTEGER NOT NULL DEFAULT 0,
+    total_questions INTEGER NOT NULL DEFAULT 0,
+    score           NUMERIC(5,2) NOT NULL DEFAULT 0,
+    submitted_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    submitted_by    TEXT
+);
+
+COMMENT ON COLUMN assessment_submissions.answers IS 'Chosen option index per question id';
+COMMENT ON COLUMN assessment_submissions.score IS 'Percentage of correct answers (0-100)';
+
+CREATE INDEX IF NOT EXISTS idx_assessment_submissions_event_id ON assessment_submissions (event_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_assessment_submission ON assessment_submissions (assessment_id, participant_id);
+
+-- Insert question bank permissions
+INSERT INTO permissions (id, name, display_name, resource, action)
+VALUES
+  (gen_random_uuid(), 'view_question_banks', 'View Question Banks', 'question_banks', 'view'),
+  (gen_random_uuid(), 'create_question_banks', 'Create Question Banks', 'question_banks', 'create'),
+  (gen_random_uuid(), 'update_question_banks', 'Update Question Banks', 'question_banks', 'update'),
+  (gen_random_uuid(), 'delete_question_banks', 'Delete Question Banks', 'question_banks', 'delete')
+ON CONFLICT (name) DO NOTHING;
+
+-- Assign permissions to admin and superadmin roles
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'question_banks'
+WHERE r.name IN ('admin', 'superadmin')
+ON CONFLICT DO NOTHING;
+
+-- Assign view/create/update permissions to staff role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'question_banks'
+WHERE r.name = 'staff'
+  AND p.action IN ('view', 'create', 'update')
+ON CONFLICT DO NOTHING;
+
+-- Assign view permission to viewer role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'question_banks'
+WHERE r.name = 'viewer'
+  AND p.action = 'view'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Remove role permissions for certificate templates
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE resource = 'certificate_templates');
+
+-- Remove certificate template permissions
+DELETE FROM permissions WHERE resource = 'certificate_templates';
+
+DROP TABLE IF EXISTS certificates;
+DROP TABLE IF EXISTS certificate_templates;
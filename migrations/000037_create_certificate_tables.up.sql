@@ -0,0 +1,74 @@
+CREATE TABLE IF NOT EXISTS certificate_templates (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name           VARCHAR(200) NOT NULL,
+    page_size      VARCHAR(10) NOT NULL DEFAULT 'A4',
+    orientation    VARCHAR(10) NOT NULL DEFAULT 'landscape',
+    background_url TEXT NOT NULL DEFAULT '',
+    fields         JSONB NOT NULL DEFAULT '[]',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by     TEXT,
+    updated_at     TIMESTAMP,
+    updated_by     TEXT,
+    deleted_at     TIMESTAMP,
+    deleted_by     TEXT
+);
+
+COMMENT ON COLUMN certificate_templates.fields IS 'Text fields printed on the background, with {{placeholder}} markers and positions in millimetres';
+
+CREATE TABLE IF NOT EXISTS certificates (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id          UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    participant_id    UUID NOT NULL REFERENCES event_participants(id) ON DELETE CASCADE,
+    template_id       UUID NOT NULL REFERENCES certificate_templates(id),
+    verification_code VARCHAR(20) NOT NULL,
+    participant_name  VARCHAR(150) NOT NULL,
+    organization      VARCHAR(200) NOT NULL DEFAULT '',
+    event_title       VARCHAR(200) NOT NULL,
+    event_date        VARCHAR(20) NOT NULL,
+    file_url          TEXT NOT NULL DEFAULT '',
+    issued_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    issued_by         TEXT,
+    deleted_at        TIMESTAMP,
+    deleted_by        TEXT
+);
+
+COMMENT ON COLUMN certificates.participant_name IS 'Name as printed on the certificate, kept when the roster changes';
+COMMENT ON COLUMN certificates.file_url IS 'Individual PDF, empty when the certificate was only generated in a merged PDF';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_certificate_verification_code ON certificates (verification_code);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_certificate_participant ON certificates (event_id, participant_id) WHERE deleted_at IS NULL;
+
+-- Insert certificate template permissions
+INSERT INTO permissions (id, name, display_name, resource, action)
+VALUES
+  (gen_random_uuid(), 'view_certificate_templates', 'View Certificate Templates', 'certificate_templates', 'view'),
+  (gen_random_uuid(), 'create_certificate_templates', 'Create Certificate Templates', 'certificate_templates', 'create'),
+  (gen_random_uuid(), 'update_certificate_templates', 'Update Certificate Templates', 'certificate_templates', 'update'),
+  (gen_random_uuid(), 'delete_certificate_templates', 'Delete Certificate Templates', 'certificate_templates', 'delete')
+ON CONFLICT (name) DO NOTHING;
+
+-- Assign permissions to admin and superadmin roles
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'certificate_templates'
+WHERE r.name IN ('admin', 'superadmin')
+ON CONFLICT DO NOTHING;
+
+-- Assign view/create/update permissions to staff role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'certificate_templates'
+WHERE r.name = 'staff'
+  AND p.action IN ('view', 'create', 'update')
+ON CONFLICT DO NOTHING;
+
+-- Assign view permission to viewer role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'certificate_templates'
+WHERE r.name = 'viewer'
+  AND p.action = 'view'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+-- Remove role permissions for instructors
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE resource = 'instructors');
+
+-- Remove role menus for instructors
+DELETE FROM role_menus
+WHERE menu_item_id IN (SELECT id FROM menu_items WHERE name = 'instructors');
+
+-- Remove instructor permissions
+DELETE FROM permissions WHERE resource = 'instructors';
+
+-- Remove instructors menu item
+DELETE FROM menu_items WHERE name = 'instructors';
+
+DROP TABLE IF EXISTS event_instructors;
+DROP TABLE IF EXISTS instructors;
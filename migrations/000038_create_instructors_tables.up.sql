@@ -0,0 +1,115 @@
+CREATE TABLE IF NOT EXISTS instructors (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             VARCHAR(100) NOT NULL,
+    phone            VARCHAR(20) NOT NULL DEFAULT '',
+    email            VARCHAR(100) NOT NULL DEFAULT '',
+    home_province_id VARCHAR(20),
+    home_city_id     VARCHAR(20),
+    certifications   JSONB NOT NULL DEFAULT '[]',
+    is_active        BOOLEAN NOT NULL DEFAULT TRUE,
+    notes            TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by       TEXT,
+    updated_at       TIMESTAMP,
+    updated_by       TEXT,
+    deleted_at       TIMESTAMP,
+    deleted_by       TEXT
+);
+
+COMMENT ON COLUMN instructors.certifications IS 'Safety riding certifications as a JSON array of {name, issuer, number, issued_at, expires_at}';
+
+CREATE INDEX IF NOT EXISTS idx_instructors_name_ci ON instructors ((LOWER(name))) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_instructors_home_region ON instructors (home_province_id, home_city_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS event_instructors (
+    event_id      UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    instructor_id UUID NOT NULL REFERENCES instructors(id),
+    role          VARCHAR(20) NOT NULL DEFAULT 'lead',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by    TEXT,
+    PRIMARY KEY (event_id, instructor_id)
+);
+
+COMMENT ON COLUMN event_instructors.role IS 'lead (the first instructor of the event) or assistant';
+
+CREATE INDEX IF NOT EXISTS idx_event_instructors_instructor_id ON event_instructors (instructor_id);
+
+-- Build the directory from the free-text instructors of existing events,
+-- the home region is the region of the instructor's latest event
+INSERT INTO instructors (id, name, phone, home_province_id, home_city_id, created_at, created_by)
+SELECT gen_random_uuid(),
+       MIN(TRIM(e.instructor_name)),
+       COALESCE(e.instructor_phone, ''),
+       (ARRAY_AGG(e.province_id ORDER BY e.event_date DESC))[1],
+       (ARRAY_AGG(e.city_id ORDER BY e.event_date DESC))[1],
+       NOW(),
+       'system'
+FROM events e
+WHERE e.deleted_at IS NULL
+  AND TRIM(COALESCE(e.instructor_name, '')) <> ''
+GROUP BY LOWER(TRIM(e.instructor_name)), COALESCE(e.instructor_phone, '');
+
+INSERT INTO event_instructors (event_id, instructor_id, role, created_at, created_by)
+SELECT e.id, i.id, 'lead', NOW(), 'system'
+FROM events e
+JOIN instructors i
+  ON LOWER(i.name) = LOWER(TRIM(e.instructor_name))
+ AND i.phone = COALESCE(e.instructor_phone, '')
+WHERE e.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+-- Insert Instructors menu item
+INSERT INTO menu_items (id, name, display_name, path, icon, parent_id, order_index, is_active)
+VALUES (
+  gen_random_uuid(),
+  'instructors',
+  'Instructors',
+  '/instructors',
+  'bi-person-badge',
+  NULL,
+  5,
+  TRUE
+) ON CONFLICT (name) DO NOTHING;
+
+-- Insert instructor permissions
+INSERT INTO permissions (id, name, display_name, resource, action)
+VALUES
+  (gen_random_uuid(), 'view_instructors', 'View Instructors', 'instructors', 'view'),
+  (gen_random_uuid(), 'create_instructors', 'Create Instructors', 'instructors', 'create'),
+  (gen_random_uuid(), 'update_instructors', 'Update Instructors', 'instructors', 'update'),
+  (gen_random_uuid(), 'delete_instructors', 'Delete Instructors', 'instructors', 'delete')
+ON CONFLICT (name) DO NOTHING;
+
+-- Assign menu to admin, staff, and superadmin roles
+INSERT INTO role_menus (id, role_id, menu_item_id)
+SELECT gen_random_uuid(), r.id, m.id
+FROM roles r
+JOIN menu_items m ON m.name = 'instructors'
+WHERE r.name IN ('admin', 'staff', 'superadmin')
+ON CONFLICT DO NOTHING;
+
+-- Assign permissions to admin and superadmin roles
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'instructors'
+WHERE r.name IN ('admin', 'superadmin')
+ON CONFLICT DO NOTHING;
+
+-- Assign view/create/update permissions to staff role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'instructors'
+WHERE r.name = 'staff'
+  AND p.action IN ('view', 'create', 'update')
+ON CONFLICT DO NOTHING;
+
+-- Assign view permission to viewer role
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT gen_random_uuid(), r.id, p.id
+FROM roles r
+JOIN permissions p ON p.resource = 'instructors'
+WHERE r.name = 'viewer'
+  AND p.action = 'view'
+ON CONFLICT DO NOTHING;
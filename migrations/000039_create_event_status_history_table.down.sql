@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_event_status_history_event_id;
+DROP TABLE IF EXISTS event_status_history;
@@ -0,0 +1,26 @@
+-- Earlier versions accepted any status text, bring existing events onto the planned -> ongoing -> completed flow
+UPDATE events SET status = LOWER(TRIM(status)) WHERE status IS NOT NULL;
+UPDATE events SET status = 'planned' WHERE status IS NULL OR status IN ('', 'pending', 'confirmed');
+UPDATE events SET status = 'ongoing' WHERE status IN ('on going', 'on progress');
+UPDATE events SET status = 'cancelled' WHERE status = 'canceled';
+
+CREATE TABLE IF NOT EXISTS event_status_history (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id    UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL DEFAULT '',
+    to_status   VARCHAR(50) NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    changed_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    changed_by  TEXT
+);
+
+COMMENT ON TABLE event_status_history IS 'Status transitions of events, the first entry of an event has an empty from_status';
+COMMENT ON COLUMN event_status_history.reason IS 'Required when an event is cancelled';
+
+CREATE INDEX IF NOT EXISTS idx_event_status_history_event_id ON event_status_history (event_id, changed_at);
+
+-- Existing events start their history with the status they have now
+INSERT INTO event_status_history (event_id, from_status, to_status, changed_at, changed_by)
+SELECT id, '', status, created_at, created_by
+FROM events
+WHERE deleted_at IS NULL;
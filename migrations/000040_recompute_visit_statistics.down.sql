@@ -0,0 +1,11 @@
+-- The recomputed visit statistics are kept, they are correct for the previous version too.
+
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id
+    FROM permissions
+    WHERE name = 'recompute_education_stats'
+);
+
+DELETE FROM permissions
+WHERE name = 'recompute_education_stats';
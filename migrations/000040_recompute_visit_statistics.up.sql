@@ -0,0 +1,55 @@
+-- Visit statistics of schools and publics are derived from their completed events,
+-- rebuild them once since deleted, cancelled and moved events were never subtracted.
+
+UPDATE schools AS venue SET
+    visit_count = COALESCE(v.visits, 0),
+    is_educated = COALESCE(v.visits, 0) > 0,
+    last_visit_at = v.last_visit_at
+FROM schools AS t
+LEFT JOIN (
+    SELECT e.school_id AS venue_id, COUNT(*) AS visits, MAX(CASE
+        WHEN e.event_date !~ '^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$' THEN NULL
+        WHEN e.end_time ~ '^([01]?[0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$' THEN (e.event_date || ' ' || e.end_time)::timestamp
+        ELSE e.event_date::timestamp
+    END) AS last_visit_at
+    FROM events e
+    WHERE e.deleted_at IS NULL AND e.status = 'completed' AND e.school_id IS NOT NULL
+    GROUP BY e.school_id
+) v ON v.venue_id = t.id
+WHERE venue.id = t.id;
+
+UPDATE publics AS venue SET
+    visit_count = COALESCE(v.visits, 0),
+    is_educated = COALESCE(v.visits, 0) > 0,
+    last_visit_at = v.last_visit_at
+FROM publics AS t
+LEFT JOIN (
+    SELECT e.public_id AS venue_id, COUNT(*) AS visits, MAX(CASE
+        WHEN e.event_date !~ '^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$' THEN NULL
+        WHEN e.end_time ~ '^([01]?[0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$' THEN (e.event_date || ' ' || e.end_time)::timestamp
+        ELSE e.event_date::timestamp
+    END) AS last_visit_at
+    FROM events e
+    WHERE e.deleted_at IS NULL AND e.status = 'completed' AND e.public_id IS NOT NULL
+    GROUP BY e.public_id
+) v ON v.venue_id = t.id
+WHERE venue.id = t.id;
+
+INSERT INTO permissions (id, name, display_name, resource, action, created_at, updated_at)
+SELECT gen_random_uuid(), 'recompute_education_stats', 'Recompute Visit Statistics', 'education_stats', 'recompute', NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'recompute_education_stats');
+
+INSERT INTO role_permissions (id, role_id, permission_id, created_at)
+SELECT
+    gen_random_uuid(),
+    r.id,
+    p.id,
+    NOW()
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'superadmin')
+AND p.name = 'recompute_education_stats'
+AND NOT EXISTS (
+    SELECT 1 FROM role_permissions rp
+    WHERE rp.role_id = r.id AND rp.permission_id = p.id
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ux_events_request_number;
+
+ALTER TABLE events DROP COLUMN IF EXISTS request_number;
@@ -0,0 +1,8 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS request_number INTEGER;
+
+COMMENT ON COLUMN events.request_number IS 'Request number of the approved training request the event was created from';
+
+-- A training request becomes at most one event, it can be converted again once that event is deleted
+CREATE UNIQUE INDEX IF NOT EXISTS ux_events_request_number
+    ON events (request_number)
+    WHERE request_number IS NOT NULL AND deleted_at IS NULL;
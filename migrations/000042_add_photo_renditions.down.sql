@@ -0,0 +1,11 @@
+ALTER TABLE event_photos
+    DROP COLUMN IF EXISTS medium_url,
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude;
+
+ALTER TABLE accident_photos
+    DROP COLUMN IF EXISTS medium_url,
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude;
@@ -0,0 +1,18 @@
+ALTER TABLE event_photos
+    ADD COLUMN IF NOT EXISTS medium_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+ALTER TABLE accident_photos
+    ADD COLUMN IF NOT EXISTS medium_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+COMMENT ON COLUMN event_photos.latitude IS 'GPS position from the EXIF data of the upload, only kept when PHOTO_KEEP_LOCATION is enabled';
+COMMENT ON COLUMN accident_photos.latitude IS 'GPS position from the EXIF data of the upload, only kept when PHOTO_KEEP_LOCATION is enabled';
+
+-- Photos uploaded before have no renditions, they are served as they are
+UPDATE event_photos SET medium_url = photo_url, thumbnail_url = photo_url WHERE medium_url = '';
+UPDATE accident_photos SET medium_url = photo_url, thumbnail_url = photo_url WHERE medium_url = '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_accident_photos_cover;
+DROP INDEX IF EXISTS idx_event_photos_cover;
+
+ALTER TABLE accident_photos DROP COLUMN IF EXISTS is_cover;
+ALTER TABLE event_photos DROP COLUMN IF EXISTS is_cover;
@@ -0,0 +1,23 @@
+ALTER TABLE event_photos ADD COLUMN IF NOT EXISTS is_cover BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE accident_photos ADD COLUMN IF NOT EXISTS is_cover BOOLEAN NOT NULL DEFAULT false;
+
+-- An event or accident has at most one cover photo
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_photos_cover ON event_photos (event_id) WHERE is_cover AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accident_photos_cover ON accident_photos (accident_id) WHERE is_cover AND deleted_at IS NULL;
+
+-- photo_order becomes a dense 1..n sequence per event and accident
+UPDATE event_photos p SET photo_order = o.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY photo_order, created_at, id) AS position
+    FROM event_photos
+    WHERE deleted_at IS NULL
+) o
+WHERE p.id = o.id AND p.photo_order <> o.position;
+
+UPDATE accident_photos p SET photo_order = o.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY accident_id ORDER BY photo_order, created_at, id) AS position
+    FROM accident_photos
+    WHERE deleted_at IS NULL
+) o
+WHERE p.id = o.id AND p.photo_order <> o.position;
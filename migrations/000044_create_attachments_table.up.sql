@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type  VARCHAR(20) NOT NULL,
+    entity_id    UUID NOT NULL,
+    file_name    VARCHAR(255) NOT NULL,
+    file_url     TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size         BIGINT NOT NULL DEFAULT 0,
+    description  TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by   TEXT,
+    deleted_at   TIMESTAMP,
+    deleted_by   TEXT
+);
+
+COMMENT ON COLUMN attachments.entity_type IS 'Parent resource of the document (event/accident/budget)';
+COMMENT ON COLUMN attachments.file_name IS 'Name of the uploaded file, the stored object gets a generated name';
+
+CREATE INDEX IF NOT EXISTS idx_attachments_entity ON attachments (entity_type, entity_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- The rows keep the object name, the app that reads file_url needs STORAGE_BASE_URL in front of it
+COMMENT ON COLUMN attachments.object_name IS NULL;
+ALTER TABLE attachments RENAME COLUMN object_name TO file_url;
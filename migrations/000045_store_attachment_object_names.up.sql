@@ -0,0 +1,8 @@
+-- Attachments keep the name of their object instead of a public URL, downloads go through presigned URLs
+ALTER TABLE attachments RENAME COLUMN file_url TO object_name;
+
+UPDATE attachments
+SET object_name = substring(split_part(object_name, '?', 1) FROM '(attachments/(event|accident|budget)/.*)$')
+WHERE object_name ~ 'attachments/(event|accident|budget)/';
+
+COMMENT ON COLUMN attachments.object_name IS 'Name of the object in the storage bucket, below attachments/<entity_type>/<entity_id>';
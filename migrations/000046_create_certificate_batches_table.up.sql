@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS certificate_batches (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id          UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    template_id       UUID NOT NULL REFERENCES certificate_templates(id),
+    file_url          TEXT NOT NULL,
+    certificate_count INT NOT NULL DEFAULT 0,
+    generated_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    generated_by      TEXT,
+    deleted_at        TIMESTAMP,
+    deleted_by        TEXT
+);
+
+COMMENT ON TABLE certificate_batches IS 'Merged PDF of the latest merged certificate generation of an event';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_certificate_batch_event ON certificate_batches (event_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS token_revocations (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE token_revocations IS 'Access tokens of the user issued before revoked_at are rejected, set when the password is reset';
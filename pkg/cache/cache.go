@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache is a small key/value store for data that can always be rebuilt from the database
+type Cache interface {
+	// Get returns the cached value, found is false on a miss
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix removes every key that starts with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// New returns a Redis backed cache, or an in-memory cache for this process when Redis is not available
+func New(client *redis.Client) Cache {
+	if client != nil {
+		return NewRedisCache(client)
+	}
+	return NewMemoryCache()
+}
@@ -0,0 +1,206 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	OrientationLandscape = "landscape"
+	OrientationPortrait  = "portrait"
+
+	PageA4     = "A4"
+	PageLetter = "Letter"
+
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+)
+
+// Placeholders that can be used in the text of a template field
+const (
+	PlaceholderParticipantName  = "participant_name"
+	PlaceholderOrganization     = "organization"
+	PlaceholderGrade            = "grade"
+	PlaceholderEventTitle       = "event_title"
+	PlaceholderEventDate        = "event_date"
+	PlaceholderEventLocation    = "event_location"
+	PlaceholderInstructorName   = "instructor_name"
+	PlaceholderVerificationCode = "verification_code"
+	PlaceholderVerifyURL        = "verify_url"
+	PlaceholderIssuedDate       = "issued_date"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// Placeholders lists the names accepted between {{ and }} in a field text
+func Placeholders() []string {
+	return []string{
+		PlaceholderParticipantName, PlaceholderOrganization, PlaceholderGrade,
+		PlaceholderEventTitle, PlaceholderEventDate, PlaceholderEventLocation, PlaceholderInstructorName,
+		PlaceholderVerificationCode, PlaceholderVerifyURL, PlaceholderIssuedDate,
+	}
+}
+
+// Field is a line of text printed on the certificate. Positions and sizes are in millimetres from the top left corner.
+type Field struct {
+	Text       string  `json:"text" binding:"required"`
+	X          float64 `json:"x" binding:"min=0"`
+	Y          float64 `json:"y" binding:"min=0"`
+	Width      float64 `json:"width" binding:"min=0"`
+	FontFamily string  `json:"font_family" binding:"omitempty,oneof=helvetica times courier"`
+	FontStyle  string  `json:"font_style" binding:"omitempty,oneof=B I BI"`
+	FontSize   float64 `json:"font_size" binding:"omitempty,min=4,max=120"`
+	Align      string  `json:"align" binding:"omitempty,oneof=left center right"`
+	Color      string  `json:"color" binding:"omitempty,hexcolor"`
+}
+
+// Template describes the page layout, Background is an optional JPEG or PNG covering the whole page
+type Template struct {
+	PageSize    string
+	Orientation string
+	Background  []byte
+	Fields      []Field
+}
+
+// Fill replaces the {{placeholder}} markers of text, unknown placeholders become empty
+func Fill(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return values[placeholderPattern.FindStringSubmatch(match)[1]]
+	})
+}
+
+// ValidateFields rejects fields using a placeholder that is not in Placeholders
+func ValidateFields(fields []Field) error {
+	known := make(map[string]bool)
+	for _, p := range Placeholders() {
+		known[p] = true
+	}
+
+	for i, field := range fields {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(field.Text, -1) {
+			if !known[match[1]] {
+				return fmt.Errorf("field %d uses unknown placeholder {{%s}}", i+1, match[1])
+			}
+		}
+		if field.Color != "" {
+			if _, _, _, err := parseColor(field.Color); err != nil {
+				return fmt.Errorf("field %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Render draws one page per entry of pages, each entry holding the placeholder values of a certificate
+func Render(tpl Template, pages []map[string]string) ([]byte, error) {
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("nothing to render")
+	}
+
+	orientation := "L"
+	if tpl.Orientation == OrientationPortrait {
+		orientation = "P"
+	}
+	pageSize := tpl.PageSize
+	if pageSize == "" {
+		pageSize = PageA4
+	}
+
+	pdf := fpdf.New(orientation, "mm", pageSize, "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	// Core fonts are cp1252 encoded, this keeps accented names readable
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	background := ""
+	if len(tpl.Background) > 0 {
+		imageType, err := imageType(tpl.Background)
+		if err != nil {
+			return nil, err
+		}
+		background = "background"
+		pdf.RegisterImageOptionsReader(background, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(tpl.Background))
+	}
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	for _, values := range pages {
+		pdf.AddPage()
+		if background != "" {
+			pdf.ImageOptions(background, 0, 0, pageWidth, pageHeight, false, fpdf.ImageOptions{}, 0, "")
+		}
+
+		for _, field := range tpl.Fields {
+			drawField(pdf, translate, field, Fill(field.Text, values), pageWidth)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawField(pdf *fpdf.Fpdf, translate func(string) string, field Field, text string, pageWidth float64) {
+	family := field.FontFamily
+	if family == "" {
+		family = "helvetica"
+	}
+	size := field.FontSize
+	if size == 0 {
+		size = 14
+	}
+	r, g, b, _ := parseColor(field.Color)
+
+	pdf.SetFont(family, field.FontStyle, size)
+	pdf.SetTextColor(r, g, b)
+
+	// A field without width spans the page from x, so centered text is centered on the page
+	width := field.Width
+	if width == 0 {
+		width = pageWidth - field.X
+	}
+	align := map[string]string{AlignLeft: "L", AlignCenter: "C", AlignRight: "R"}[field.Align]
+	if align == "" {
+		align = "L"
+	}
+
+	_, lineHeight := pdf.GetFontSize()
+	pdf.SetXY(field.X, field.Y)
+	pdf.MultiCell(width, lineHeight*1.3, translate(text), "", align, false)
+}
+
+// parseColor reads a #RRGGBB color, an empty color is black
+func parseColor(color string) (r, g, b int, err error) {
+	if color == "" {
+		return 0, 0, 0, nil
+	}
+
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, use the #RRGGBB format", color)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, use the #RRGGBB format", color)
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), nil
+}
+
+func imageType(data []byte) (string, error) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return "JPG", nil
+	case "image/png":
+		return "PNG", nil
+	default:
+		return "", fmt.Errorf("certificate background must be a JPEG or PNG image")
+	}
+}
@@ -0,0 +1,90 @@
+package certificate
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"testing"
+)
+
+func TestFill(t *testing.T) {
+	values := map[string]string{PlaceholderParticipantName: "Budi Santoso", PlaceholderEventTitle: "Safety Riding"}
+
+	got := Fill("This certifies that {{participant_name}} joined {{ event_title }}{{grade}}", values)
+	want := "This certifies that Budi Santoso joined Safety Riding"
+	if got != want {
+		t.Fatalf("Fill() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	if err := ValidateFields([]Field{{Text: "{{participant_name}} - {{verification_code}}", Color: "#1a2B3c"}}); err != nil {
+		t.Fatalf("ValidateFields() error = %v", err)
+	}
+	if err := ValidateFields([]Field{{Text: "{{student_name}}"}}); err == nil {
+		t.Fatalf("ValidateFields() accepted an unknown placeholder")
+	}
+	if err := ValidateFields([]Field{{Text: "x", Color: "#fff"}}); err == nil {
+		t.Fatalf("ValidateFields() accepted a short color")
+	}
+}
+
+func TestRender(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	img.Set(1, 1, color.RGBA{R: 200, A: 255})
+	var background bytes.Buffer
+	if err := png.Encode(&background, img); err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := Template{
+		Orientation: OrientationLandscape,
+		Background:  background.Bytes(),
+		Fields: []Field{
+			{Text: "{{participant_name}}", Y: 90, FontSize: 28, FontStyle: "B", Align: AlignCenter, Color: "#003366"},
+			{Text: "Code {{verification_code}}", X: 20, Y: 190, FontSize: 9},
+		},
+	}
+
+	data, err := Render(tpl, []map[string]string{
+		{PlaceholderParticipantName: "Budi Santoso", PlaceholderVerificationCode: "ABCD-EFGH-JKLM"},
+		{PlaceholderParticipantName: "Siti Rahayu", PlaceholderVerificationCode: "NPQR-STUV-WXYZ"},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		t.Fatalf("Render() did not return a PDF")
+	}
+	if pages := len(regexp.MustCompile(`/Type /Page\b`).FindAll(data, -1)); pages != 2 {
+		t.Fatalf("Render() returned %d pages, want 2", pages)
+	}
+
+	if _, err := Render(Template{Background: []byte("not an image")}, []map[string]string{{}}); err == nil {
+		t.Fatalf("Render() accepted a background that is not an image")
+	}
+}
+
+func TestVerificationCode(t *testing.T) {
+	code, err := NewVerificationCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`).MatchString(code) {
+		t.Fatalf("NewVerificationCode() = %q", code)
+	}
+
+	tests := map[string]string{
+		"abcd efgh jkmn": "ABCD-EFGH-JKMN",
+		"ABCDEFGHJKMN":   "ABCD-EFGH-JKMN",
+		"ABCD-EFGH-JKM":  "",
+		"ABCD-EFGH-JKM0": "",
+	}
+	for input, want := range tests {
+		if got := NormalizeVerificationCode(input); got != want {
+			t.Fatalf("NormalizeVerificationCode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// Letters and digits that cannot be confused when read from paper (no 0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const (
+	codeLength    = 12
+	codeGroupSize = 4
+)
+
+// NewVerificationCode returns a random code printed on a certificate, formatted as XXXX-XXXX-XXXX
+func NewVerificationCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	// The alphabet has 32 letters so every byte maps to a letter without bias
+	code := make([]byte, codeLength)
+	for i, b := range buf {
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return formatCode(string(code)), nil
+}
+
+// NormalizeVerificationCode accepts a code typed in lower case, with spaces or without dashes,
+// and returns it in the stored format. It returns an empty string when the input cannot be a code.
+func NormalizeVerificationCode(input string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(input) {
+		switch {
+		case c == '-' || c == ' ':
+			continue
+		case !strings.ContainsRune(codeAlphabet, c):
+			return ""
+		}
+		b.WriteRune(c)
+	}
+
+	if b.Len() != codeLength {
+		return ""
+	}
+	return formatCode(b.String())
+}
+
+func formatCode(code string) string {
+	groups := make([]string, 0, codeLength/codeGroupSize)
+	for i := 0; i < len(code); i += codeGroupSize {
+		groups = append(groups, code[i:i+codeGroupSize])
+	}
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,204 @@
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"safety-riding/pkg/filter"
+	"safety-riding/pkg/spreadsheet"
+	"safety-riding/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FetchFunc returns a single page of records, usually a service Fetch method.
+// It pages with params.Offset, so the repository is expected to select the page with filter.Paginate.
+type FetchFunc[T any] func(params filter.BaseParams) ([]T, int64, error)
+
+// column describes an exportable struct field
+type column struct {
+	name   string // json name, used as the header
+	dbName string // gorm column name, used in the select
+	index  int
+}
+
+// Format returns the requested export format from the "format" query, defaulting to csv.
+func Format(ctx *gin.Context) (string, error) {
+	format := strings.ToLower(ctx.DefaultQuery("format", spreadsheet.FormatCSV))
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return "", fmt.Errorf("invalid format %q, allowed values are csv and xlsx", format)
+	}
+	return format, nil
+}
+
+// Stream writes every record matching params as a file download.
+// The result set is walked page by page with the same search, filters and order used by fetch,
+// and params.Columns restricts the exported columns to the requested ones.
+// Errors returned before anything is written leave the response untouched so the caller can reply with JSON.
+func Stream[T any](ctx *gin.Context, name, format string, params filter.BaseParams, fetch FetchFunc[T]) (int, error) {
+	columns, err := resolveColumns[T](params.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	// Always select the primary key so preloaded relations keep working
+	params.Columns = []string{"id"}
+	for _, c := range columns {
+		if c.dbName != "id" {
+			params.Columns = append(params.Columns, c.dbName)
+		}
+	}
+
+	params.Limit = utils.GetEnv("EXPORT_BATCH_SIZE", 1000).(int)
+	params.Offset = 0
+
+	records, total, err := fetch(params)
+	if err != nil {
+		return 0, err
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102_150405"), format)
+	ctx.Header("Content-Type", spreadsheet.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	writer, err := spreadsheet.NewWriter(ctx.Writer, format, name)
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	if err := writer.WriteRow(header); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for {
+		for _, record := range records {
+			if err := writer.WriteRow(recordValues(record, columns)); err != nil {
+				return written, err
+			}
+			written++
+		}
+
+		params.Offset += params.Limit
+		if len(records) < params.Limit || int64(params.Offset) >= total {
+			break
+		}
+
+		if records, _, err = fetch(params); err != nil {
+			return written, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return written, err
+	}
+	ctx.Writer.Flush()
+
+	return written, nil
+}
+
+// resolveColumns returns the exportable columns of T, restricted to requested when given.
+// Requested columns may be repeated query values or a single comma separated value.
+func resolveColumns[T any](requested []string) ([]column, error) {
+	available := exportableColumns(reflect.TypeOf((*T)(nil)).Elem())
+
+	var names []string
+	for _, r := range requested {
+		for _, name := range strings.Split(r, ",") {
+			if name = strings.TrimSpace(name); name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	columns := make([]column, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(available, func(c column) bool { return c.name == name })
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid column: %s", name)
+		}
+		columns = append(columns, available[idx])
+	}
+	return columns, nil
+}
+
+// exportableColumns lists the flat fields of t that are stored in its own table.
+// Relations (structs and slices) and fields hidden from json are skipped.
+func exportableColumns(t reflect.Type) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Slice || (ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{})) {
+			continue
+		}
+
+		dbName := name
+		for _, part := range strings.Split(field.Tag.Get("gorm"), ";") {
+			if strings.HasPrefix(part, "column:") {
+				dbName = strings.TrimPrefix(part, "column:")
+			}
+		}
+
+		columns = append(columns, column{name: name, dbName: dbName, index: i})
+	}
+	return columns
+}
+
+func recordValues(record interface{}, columns []column) []string {
+	v := reflect.ValueOf(record)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = formatValue(v.Field(c.index))
+	}
+	return row
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch val := v.Interface().(type) {
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format("2006-01-02 15:04:05")
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
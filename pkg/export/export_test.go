@@ -0,0 +1,75 @@
+package export
+
+import (
+	"testing"
+	"time"
+)
+
+type exportRecord struct {
+	ID        string     `json:"id" gorm:"column:id;primaryKey"`
+	Name      string     `json:"name" gorm:"column:name"`
+	Count     int        `json:"count" gorm:"column:total_count"`
+	Ratio     float64    `json:"ratio" gorm:"column:ratio"`
+	Active    bool       `json:"active" gorm:"column:active"`
+	VisitedAt *time.Time `json:"visited_at" gorm:"column:visited_at"`
+	Children  []string   `json:"children,omitempty"`
+	Parent    *struct{}  `json:"parent,omitempty"`
+	DeletedBy string     `json:"-"`
+}
+
+func TestResolveColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "defaults to every flat column", want: []string{"id", "name", "count", "ratio", "active", "visited_at"}},
+		{name: "repeated values keep requested order", requested: []string{"name", "id"}, want: []string{"name", "id"}},
+		{name: "comma separated values", requested: []string{"name, count,name"}, want: []string{"name", "count"}},
+		{name: "relation is not exportable", requested: []string{"children"}, wantErr: true},
+		{name: "unknown column", requested: []string{"password"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveColumns[exportRecord](tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("resolveColumns() returned %d columns, want %d", len(got), len(tt.want))
+			}
+			for i, c := range got {
+				if c.name != tt.want[i] {
+					t.Fatalf("column %d = %q, want %q", i, c.name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordValues(t *testing.T) {
+	visitedAt := time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+	record := exportRecord{ID: "1", Name: "SMA 1", Count: 12, Ratio: 0.5, Active: true, VisitedAt: &visitedAt}
+
+	columns, err := resolveColumns[exportRecord](nil)
+	if err != nil {
+		t.Fatalf("resolveColumns() error = %v", err)
+	}
+	if columns[2].dbName != "total_count" {
+		t.Fatalf("count column dbName = %q, want %q", columns[2].dbName, "total_count")
+	}
+
+	want := []string{"1", "SMA 1", "12", "0.5", "true", "2025-03-01 08:30:00"}
+	got := recordValues(record, columns)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("value %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := recordValues(exportRecord{}, columns)[5]; got != "" {
+		t.Fatalf("nil time = %q, want empty", got)
+	}
+}
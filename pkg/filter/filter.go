@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type BaseParams struct {
@@ -17,6 +18,16 @@ type BaseParams struct {
 	Limit          int                    `json:"limit" form:"limit"`
 	Offset         int                    `json:"offset" form:"offset"`
 	Columns        []string               `json:"columns" form:"columns"`
+	Scope          RegionScope            `json:"-" form:"-"` // set from the authenticated user, never from the request
+}
+
+// Paginate returns a GORM scope selecting the page of params after the query's own order.
+// id is appended as the last sort key because the sort column is rarely unique, and without
+// a total order consecutive pages, such as the batches of an export, can repeat or skip rows.
+func Paginate(params BaseParams) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC").Offset(params.Offset).Limit(params.Limit)
+	}
 }
 
 func GetBaseParams(ctx *gin.Context, defOrderBy, defOrderDirection string, defLimit int) (req BaseParams, err error) {
@@ -0,0 +1,98 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+
+	"safety-riding/pkg/messages"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CtxKeyRegionScope holds the RegionScope of the authenticated user
+const CtxKeyRegionScope = "region_scope"
+
+// ErrOutOfRegion is returned when a write targets a province or city outside the user's scope
+var ErrOutOfRegion = errors.New(messages.ErrOutOfRegion)
+
+// Region is a whole province when CityId is empty, otherwise a single city of that province.
+// City codes are only unique within their province, so both ids are always compared.
+type Region struct {
+	ProvinceId string `json:"province_id"`
+	CityId     string `json:"city_id"`
+}
+
+// RegionScope limits data to the regions assigned to a user, the zero value does not restrict anything
+type RegionScope struct {
+	Regions []Region `json:"regions"`
+}
+
+func (s RegionScope) IsRestricted() bool {
+	return len(s.Regions) > 0
+}
+
+// Allows reports whether a record located in the given province and city is inside the scope
+func (s RegionScope) Allows(provinceId, cityId string) bool {
+	if !s.IsRestricted() {
+		return true
+	}
+
+	for _, region := range s.Regions {
+		if region.ProvinceId != provinceId {
+			continue
+		}
+		if region.CityId == "" || region.CityId == cityId {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition builds the SQL condition matching the scope for the given columns, it is empty when the scope does not restrict
+func (s RegionScope) Condition(provinceColumn, cityColumn string) (string, []interface{}) {
+	if !s.IsRestricted() {
+		return "", nil
+	}
+
+	var (
+		provinces []string
+		clauses   []string
+		args      []interface{}
+	)
+	for _, region := range s.Regions {
+		if region.CityId == "" {
+			provinces = append(provinces, region.ProvinceId)
+			continue
+		}
+		clauses = append(clauses, "("+provinceColumn+" = ? AND "+cityColumn+" = ?)")
+		args = append(args, region.ProvinceId, region.CityId)
+	}
+	if len(provinces) > 0 {
+		clauses = append([]string{provinceColumn + " IN ?"}, clauses...)
+		args = append([]interface{}{provinces}, args...)
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// Apply returns a GORM scope that filters a query by the region scope
+func (s RegionScope) Apply(provinceColumn, cityColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition, args := s.Condition(provinceColumn, cityColumn)
+		if condition == "" {
+			return db
+		}
+		return db.Where(condition, args...)
+	}
+}
+
+// GetRegionScope returns the scope set by the auth middleware for the current request
+func GetRegionScope(ctx *gin.Context) RegionScope {
+	if scope, ok := ctx.Get(CtxKeyRegionScope); ok {
+		if regionScope, ok := scope.(RegionScope); ok {
+			return regionScope
+		}
+	}
+	return RegionScope{}
+}
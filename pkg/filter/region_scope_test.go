@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegionScope(t *testing.T) {
+	scope := RegionScope{Regions: []Region{
+		{ProvinceId: "32"},
+		{ProvinceId: "33", CityId: "01"},
+	}}
+
+	cases := []struct {
+		provinceId, cityId string
+		want               bool
+	}{
+		{"32", "", true},
+		{"32", "05", true},
+		{"33", "01", true},
+		{"33", "02", false},
+		{"34", "01", false},
+		{"", "01", false},
+	}
+	for _, c := range cases {
+		if got := scope.Allows(c.provinceId, c.cityId); got != c.want {
+			t.Fatalf("Allows(%q, %q) = %v, want %v", c.provinceId, c.cityId, got, c.want)
+		}
+	}
+
+	condition, args := scope.Condition("province_id", "city_id")
+	if want := "(province_id IN ? OR (province_id = ? AND city_id = ?))"; condition != want {
+		t.Fatalf("Condition() = %q, want %q", condition, want)
+	}
+	if want := []interface{}{[]string{"32"}, "33", "01"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("Condition() args = %v, want %v", args, want)
+	}
+
+	var unrestricted RegionScope
+	if !unrestricted.Allows("99", "99") {
+		t.Fatalf("zero scope must not restrict")
+	}
+	if condition, args := unrestricted.Condition("province_id", "city_id"); condition != "" || args != nil {
+		t.Fatalf("zero scope Condition() = %q, %v, want empty", condition, args)
+	}
+}
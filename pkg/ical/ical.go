@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// Event statuses defined by RFC 5545
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	utcLayout  = "20060102T150405Z"
+	dateLayout = "20060102"
+	lineLimit  = 75 // octets per content line before it must be folded
+)
+
+// Event is a single VEVENT. Start and End are converted to UTC, or written as dates when AllDay is set.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR published as a subscription feed
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Write serializes the calendar with CRLF line endings and folded long lines
+func (c Calendar) Write(w io.Writer) error {
+	var b strings.Builder
+
+	line(&b, "BEGIN:VCALENDAR")
+	line(&b, "VERSION:2.0")
+	line(&b, "PRODID:"+escape(c.ProdID))
+	line(&b, "CALSCALE:GREGORIAN")
+	line(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		line(&b, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		line(&b, "BEGIN:VEVENT")
+		line(&b, "UID:"+escape(e.UID))
+		line(&b, "DTSTAMP:"+e.LastModified.UTC().Format(utcLayout))
+		line(&b, "LAST-MODIFIED:"+e.LastModified.UTC().Format(utcLayout))
+		if e.AllDay {
+			line(&b, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+			line(&b, "DTEND;VALUE=DATE:"+e.End.Format(dateLayout))
+		} else {
+			line(&b, "DTSTART:"+e.Start.UTC().Format(utcLayout))
+			line(&b, "DTEND:"+e.End.UTC().Format(utcLayout))
+		}
+		line(&b, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			line(&b, "DESCRIPTION:"+escape(e.Description))
+		}
+		if e.Location != "" {
+			line(&b, "LOCATION:"+escape(e.Location))
+		}
+		if e.Status != "" {
+			line(&b, "STATUS:"+e.Status)
+		}
+		line(&b, "END:VEVENT")
+	}
+
+	line(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escape quotes the characters that have a meaning in TEXT values
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// line writes a content line, folding it every 75 octets without splitting a UTF-8 character
+func line(b *strings.Builder, s string) {
+	limit := lineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = lineLimit - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarWrite(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	modified := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	cal := Calendar{
+		ProdID: "-//Safety Riding//Events//EN",
+		Name:   "Events",
+		Events: []Event{
+			{
+				UID:          "event-1@safety-riding",
+				Summary:      "Riding clinic; SMA 1, Bandung",
+				Description:  "Bring helmets\nand jackets",
+				Location:     "Jl. Merdeka",
+				Status:       StatusCancelled,
+				Start:        time.Date(2026, 3, 10, 8, 0, 0, 0, jakarta),
+				End:          time.Date(2026, 3, 10, 10, 0, 0, 0, jakarta),
+				LastModified: modified,
+			},
+			{
+				UID:          "event-2@safety-riding",
+				Summary:      strings.Repeat("é", 60),
+				Status:       StatusConfirmed,
+				Start:        time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+				End:          time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+				AllDay:       true,
+				LastModified: modified,
+			},
+		},
+	}
+
+	var b strings.Builder
+	if err := cal.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20260310T010000Z\r\n",
+		"DTEND:20260310T030000Z\r\n",
+		"SUMMARY:Riding clinic\\; SMA 1\\, Bandung\r\n",
+		"DESCRIPTION:Bring helmets\\nand jackets\r\n",
+		"STATUS:CANCELLED\r\n",
+		"DTSTART;VALUE=DATE:20260311\r\n",
+		"LAST-MODIFIED:20260301T093000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Write() output is missing %q:\n%s", want, out)
+		}
+	}
+
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(l) > lineLimit {
+			t.Fatalf("line of %d octets is not folded: %q", len(l), l)
+		}
+	}
+	if !strings.Contains(out, "\r\n é") {
+		t.Fatalf("long summary was not folded on a character boundary:\n%s", out)
+	}
+}
@@ -0,0 +1,101 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"safety-riding/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// StagingFolder holds the direct uploads until they are confirmed, a bucket lifecycle rule or the storage
+// garbage collector removes what is never confirmed
+const StagingFolder = "uploads"
+
+// ContentTypes are the image types a direct upload may declare, with the extension of their object
+var ContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var (
+	// ErrUploadNotFound is returned when a direct upload was not made or belongs to another record
+	ErrUploadNotFound = errors.New("upload not found, upload the file to its upload_url first")
+	// ErrUploadTooLarge is returned when a direct upload is larger than allowed
+	ErrUploadTooLarge = errors.New("uploaded file is too large")
+)
+
+// Slot is a reserved object a client uploads to with PUT
+type Slot struct {
+	ObjectName  string
+	URL         string
+	ContentType string
+	ExpiresAt   time.Time
+}
+
+// PresignUpload reserves an object below the staging folder for a direct upload. The client must send
+// the same Content-Type header, it is checked again when the upload is imported.
+func PresignUpload(ctx context.Context, provider storage.StorageProvider, folder, contentType string, expiry time.Duration) (Slot, error) {
+	ext, ok := ContentTypes[contentType]
+	if !ok {
+		return Slot{}, ErrNotImage
+	}
+
+	objectName := path.Join(StagingFolder, folder, uuid.NewString()+ext)
+	url, err := provider.PresignedPutURL(ctx, objectName, expiry)
+	if err != nil {
+		return Slot{}, err
+	}
+	return Slot{ObjectName: objectName, URL: url, ContentType: contentType, ExpiresAt: time.Now().Add(expiry)}, nil
+}
+
+// ImportUpload checks a direct upload reserved below folder, stores it like UploadFile into target and
+// removes the uploaded object, so the stored photo never keeps the EXIF data of the original
+func ImportUpload(ctx context.Context, provider storage.StorageProvider, objectName, folder, target string, maxSize int64, opts Options) (Photo, error) {
+	prefix := path.Join(StagingFolder, folder) + "/"
+	if path.Clean(objectName) != objectName || !strings.HasPrefix(objectName, prefix) {
+		return Photo{}, ErrUploadNotFound
+	}
+
+	info, err := provider.StatFile(ctx, objectName)
+	if errors.Is(err, storage.ErrFileNotFound) {
+		return Photo{}, ErrUploadNotFound
+	} else if err != nil {
+		return Photo{}, err
+	}
+	if _, ok := ContentTypes[strings.ToLower(strings.TrimSpace(strings.Split(info.ContentType, ";")[0]))]; !ok {
+		_ = provider.DeleteFile(ctx, provider.GetFileURL(objectName))
+		return Photo{}, fmt.Errorf("%s: %w", path.Base(objectName), ErrNotImage)
+	}
+	if info.Size > maxSize {
+		_ = provider.DeleteFile(ctx, provider.GetFileURL(objectName))
+		return Photo{}, fmt.Errorf("%s exceeds %d bytes: %w", path.Base(objectName), maxSize, ErrUploadTooLarge)
+	}
+
+	object, err := provider.DownloadFile(ctx, objectName)
+	if err != nil {
+		return Photo{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(object, maxSize+1))
+	object.Close()
+	if err != nil {
+		return Photo{}, fmt.Errorf("failed to read upload %s: %w", path.Base(objectName), err)
+	}
+
+	photo, err := Upload(ctx, provider, data, path.Base(objectName), target, opts)
+	if err != nil {
+		if errors.Is(err, ErrNotImage) {
+			_ = provider.DeleteFile(ctx, provider.GetFileURL(objectName))
+		}
+		return Photo{}, err
+	}
+	_ = provider.DeleteFile(ctx, provider.GetFileURL(objectName))
+	return photo, nil
+}
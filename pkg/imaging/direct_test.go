@@ -0,0 +1,69 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"safety-riding/pkg/storage"
+)
+
+func TestImportUpload(t *testing.T) {
+	ctx := context.Background()
+	provider, err := storage.NewLocalAdapter(storage.Config{LocalPath: t.TempDir(), BaseURL: "http://localhost/files", SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalAdapter() error = %v", err)
+	}
+
+	// The local adapter cannot presign, the uploads are stored as a client would upload them
+	if _, err := PresignUpload(ctx, provider, "event-photos/1", "image/jpeg", time.Minute); !errors.Is(err, storage.ErrNotSupported) {
+		t.Fatalf("PresignUpload() error = %v, want ErrNotSupported", err)
+	}
+	if _, err := PresignUpload(ctx, provider, "event-photos/1", "image/gif", time.Minute); !errors.Is(err, ErrNotImage) {
+		t.Fatalf("PresignUpload() gif error = %v, want ErrNotImage", err)
+	}
+
+	stage := func(data []byte, folder string) string {
+		fileURL, err := provider.UploadFileFromBytes(ctx, data, "upload.jpg", StagingFolder+"/"+folder, "image/jpeg")
+		if err != nil {
+			t.Fatalf("UploadFileFromBytes() error = %v", err)
+		}
+		u, _ := url.Parse(fileURL)
+		return strings.TrimPrefix(u.Path, "/files/")
+	}
+
+	objectName := stage(withExif(testJPEG(t, 400, 300)), "event-photos/1")
+	photo, err := ImportUpload(ctx, provider, objectName, "event-photos/1", "event-photos", 1<<20, DefaultOptions)
+	if err != nil {
+		t.Fatalf("ImportUpload() error = %v", err)
+	}
+	if len(photo.URLs()) != 3 || photo.Location == nil {
+		t.Fatalf("ImportUpload() = %+v, want three renditions and the EXIF location", photo)
+	}
+	if _, err := provider.StatFile(ctx, objectName); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Fatalf("StatFile() of the imported upload error = %v, want ErrFileNotFound", err)
+	}
+
+	tests := []struct {
+		name       string
+		objectName string
+		maxSize    int64
+		want       error
+	}{
+		{name: "already imported", objectName: objectName, maxSize: 1 << 20, want: ErrUploadNotFound},
+		{name: "other record", objectName: stage(testJPEG(t, 10, 10), "event-photos/2"), maxSize: 1 << 20, want: ErrUploadNotFound},
+		{name: "outside the staging folder", objectName: StagingFolder + "/event-photos/1/../2/a.jpg", maxSize: 1 << 20, want: ErrUploadNotFound},
+		{name: "too large", objectName: stage(testJPEG(t, 10, 10), "event-photos/1"), maxSize: 10, want: ErrUploadTooLarge},
+		{name: "not an image", objectName: stage([]byte("<html></html>"), "event-photos/1"), maxSize: 1 << 20, want: ErrNotImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImportUpload(ctx, provider, tt.objectName, "event-photos/1", "event-photos", tt.maxSize, DefaultOptions); !errors.Is(err, tt.want) {
+				t.Fatalf("ImportUpload() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
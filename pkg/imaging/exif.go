@@ -0,0 +1,160 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// exifMeta is the part of the EXIF data that matters once the metadata is stripped
+type exifMeta struct {
+	Orientation int
+	Location    *Location
+}
+
+const (
+	tagOrientation = 0x0112
+	tagGPSInfo     = 0x8825
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+)
+
+// readExif reads the orientation and GPS position from the APP1 segment of a JPEG, broken data is ignored
+func readExif(data []byte) exifMeta {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return exifMeta{}
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return exifMeta{}
+		}
+		marker := data[pos+1]
+		// Start of scan, the metadata segments are all before it
+		if marker == 0xDA || marker == 0xD9 {
+			return exifMeta{}
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return exifMeta{}
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return parseTiff(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return exifMeta{}
+}
+
+// tiff reads the IFD entries of the TIFF structure that holds the EXIF tags
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	typ    uint16
+	count  uint32
+	offset int // offset of the value, inside the entry when it fits in 4 bytes
+}
+
+func parseTiff(data []byte) exifMeta {
+	if len(data) < 8 {
+		return exifMeta{}
+	}
+	t := tiff{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return exifMeta{}
+	}
+
+	var meta exifMeta
+	ifd0 := t.entries(int(t.order.Uint32(data[4:])))
+	if e, ok := ifd0[tagOrientation]; ok && e.typ == 3 {
+		meta.Orientation = int(t.order.Uint16(data[e.offset:]))
+	}
+	if e, ok := ifd0[tagGPSInfo]; ok && e.typ == 4 {
+		meta.Location = t.location(t.entries(int(t.order.Uint32(data[e.offset:]))))
+	}
+	return meta
+}
+
+func (t tiff) entries(offset int) map[uint16]ifdEntry {
+	entries := map[uint16]ifdEntry{}
+	if offset <= 0 || offset+2 > len(t.data) {
+		return entries
+	}
+
+	count := int(t.order.Uint16(t.data[offset:]))
+	for i := 0; i < count; i++ {
+		start := offset + 2 + i*12
+		if start+12 > len(t.data) {
+			break
+		}
+		e := ifdEntry{
+			typ:    t.order.Uint16(t.data[start+2:]),
+			count:  t.order.Uint32(t.data[start+4:]),
+			offset: start + 8,
+		}
+		if size := typeSize(e.typ) * int(e.count); size > 4 {
+			e.offset = int(t.order.Uint32(t.data[start+8:]))
+			if size < 0 || e.offset+size > len(t.data) {
+				continue
+			}
+		}
+		entries[t.order.Uint16(t.data[start:])] = e
+	}
+	return entries
+}
+
+// location converts the GPS degrees, minutes and seconds to decimal degrees
+func (t tiff) location(gps map[uint16]ifdEntry) *Location {
+	lat, okLat := t.coordinate(gps[tagGPSLatitude], gps[tagGPSLatitudeRef], 'S')
+	lon, okLon := t.coordinate(gps[tagGPSLongitude], gps[tagGPSLongitudeRef], 'W')
+	if !okLat || !okLon || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil
+	}
+	return &Location{Latitude: lat, Longitude: lon}
+}
+
+func (t tiff) coordinate(value, ref ifdEntry, negative byte) (float64, bool) {
+	if value.typ != 5 || value.count != 3 {
+		return 0, false
+	}
+
+	var degrees float64
+	for i, scale := range []float64{1, 60, 3600} {
+		at := value.offset + i*8
+		numerator, denominator := t.order.Uint32(t.data[at:]), t.order.Uint32(t.data[at+4:])
+		if denominator == 0 {
+			return 0, false
+		}
+		degrees += float64(numerator) / float64(denominator) / scale
+	}
+	if ref.typ == 2 && ref.count > 0 && t.data[ref.offset] == negative {
+		degrees = -degrees
+	}
+	return degrees, true
+}
+
+// typeSize is the size in bytes of one value of a TIFF field type
+func typeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 6, 7:
+		return 1
+	case 3, 8:
+		return 2
+	case 4, 9, 11:
+		return 4
+	case 5, 10, 12:
+		return 8
+	}
+	return 0
+}
@@ -0,0 +1,194 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Rendition names, the original is re-encoded so it no longer carries the metadata of the upload
+const (
+	RenditionOriginal  = "original"
+	RenditionMedium    = "medium"
+	RenditionThumbnail = "thumbnail"
+)
+
+// maxPixels rejects images that would take too much memory to decode, a 12 MP phone photo is far below it
+const maxPixels = 50_000_000
+
+const jpegQuality = 85
+
+// ErrNotImage is returned when the content of an upload is not a supported image, whatever its name or content type says
+var ErrNotImage = errors.New("file is not a JPEG, PNG or WebP image")
+
+// Options are the longest edges of the renditions in pixels, smaller images are never enlarged
+type Options struct {
+	OriginalSize  int
+	MediumSize    int
+	ThumbnailSize int
+}
+
+// DefaultOptions keeps the original at most 2048 pixels wide for the gallery, 1024 for the map and 320 for lists
+var DefaultOptions = Options{OriginalSize: 2048, MediumSize: 1024, ThumbnailSize: 320}
+
+type Rendition struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Location is the GPS position recorded by the camera
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type Result struct {
+	ContentType string
+	Ext         string
+	Original    Rendition
+	Medium      Rendition
+	Thumbnail   Rendition
+	// Location is read from the EXIF data of a JPEG before it is stripped, nil when the photo has none
+	Location *Location
+}
+
+// Process checks the content of an uploaded image and re-encodes it into its renditions without its metadata.
+// The EXIF orientation is applied to the pixels since the stripped files can no longer carry it.
+// PNG stays PNG to keep transparency, JPEG and WebP become JPEG.
+func Process(data []byte, opts Options) (Result, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp":
+	default:
+		return Result{}, ErrNotImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Result{}, ErrNotImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return Result{}, fmt.Errorf("image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Result{}, ErrNotImage
+	}
+
+	result := Result{ContentType: "image/jpeg", Ext: ".jpg"}
+	if contentType == "image/png" {
+		result.ContentType, result.Ext = "image/png", ".png"
+	}
+	if contentType == "image/jpeg" {
+		meta := readExif(data)
+		img = orient(img, meta.Orientation)
+		result.Location = meta.Location
+	}
+
+	renditions := []struct {
+		target *Rendition
+		size   int
+	}{
+		{&result.Original, opts.OriginalSize},
+		{&result.Medium, opts.MediumSize},
+		{&result.Thumbnail, opts.ThumbnailSize},
+	}
+	for _, r := range renditions {
+		resized := resize(img, r.size, result.ContentType == "image/jpeg")
+		if *r.target, err = encode(resized, result.ContentType); err != nil {
+			return Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// fit scales width and height down so the longest edge is at most size
+func fit(width, height, size int) (int, int) {
+	if size <= 0 || (width <= size && height <= size) {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// resize draws the image into a new one of at most size pixels on its longest edge,
+// transparent pixels become white when the result is going to be a JPEG
+func resize(img image.Image, size int, opaque bool) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), size)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	op := draw.Src
+	if opaque {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, op, nil)
+	return dst
+}
+
+func encode(img *image.RGBA, contentType string) (Rendition, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return Rendition{}, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	return Rendition{Data: buf.Bytes(), Width: bounds.Dx(), Height: bounds.Dy()}, nil
+}
+
+// orient turns the pixels the way the EXIF orientation (1-8) says the image should be shown
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+)
+
+// testJPEG encodes an image that is red on the left half and blue on the right half
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExif inserts an APP1 segment with orientation 6 (rotated 90 degrees) and a GPS position of 6.5 S, 106.82 E
+func withExif(data []byte) []byte {
+	le := binary.LittleEndian
+	tiff := make([]byte, 140)
+	copy(tiff, "II")
+	le.PutUint16(tiff[2:], 42)
+	le.PutUint32(tiff[4:], 8)
+
+	entry := func(at int, tag, typ uint16, count, value uint32) {
+		le.PutUint16(tiff[at:], tag)
+		le.PutUint16(tiff[at+2:], typ)
+		le.PutUint32(tiff[at+4:], count)
+		le.PutUint32(tiff[at+8:], value)
+	}
+	le.PutUint16(tiff[8:], 2)
+	entry(10, tagOrientation, 3, 1, 6)
+	entry(22, tagGPSInfo, 4, 1, 38)
+
+	le.PutUint16(tiff[38:], 4)
+	entry(40, tagGPSLatitudeRef, 2, 2, 'S')
+	entry(52, tagGPSLatitude, 5, 3, 92)
+	entry(64, tagGPSLongitudeRef, 2, 2, 'E')
+	entry(76, tagGPSLongitude, 5, 3, 116)
+	for i, v := range []uint32{6, 30, 0, 106, 49, 12} {
+		le.PutUint32(tiff[92+i*8:], v)
+		le.PutUint32(tiff[96+i*8:], 1)
+	}
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestProcessJPEG(t *testing.T) {
+	result, err := Process(withExif(testJPEG(t, 400, 200)), Options{OriginalSize: 300, MediumSize: 100, ThumbnailSize: 40})
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if result.ContentType != "image/jpeg" || result.Ext != ".jpg" {
+		t.Fatalf("Process() content type = %s %s, want image/jpeg .jpg", result.ContentType, result.Ext)
+	}
+	sizes := [3][2]int{
+		{result.Original.Width, result.Original.Height},
+		{result.Medium.Width, result.Medium.Height},
+		{result.Thumbnail.Width, result.Thumbnail.Height},
+	}
+	if sizes != [3][2]int{{150, 300}, {50, 100}, {20, 40}} {
+		t.Fatalf("Process() rendition sizes = %v, want rotated 150x300, 50x100 and 20x40", sizes)
+	}
+
+	if result.Location == nil || math.Abs(result.Location.Latitude+6.5) > 1e-9 || math.Abs(result.Location.Longitude-(106+49.0/60+12.0/3600)) > 1e-9 {
+		t.Fatalf("Process() location = %+v, want -6.5, 106.82", result.Location)
+	}
+	if bytes.Contains(result.Original.Data, []byte("Exif")) {
+		t.Fatal("Process() kept the EXIF data in the original rendition")
+	}
+
+	// Rotated a quarter turn clockwise the red left half ends up on top
+	img, err := jpeg.Decode(bytes.NewReader(result.Original.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, b, _ := img.At(75, 50).RGBA(); r < b {
+		t.Fatalf("top of the rotated image is not red")
+	}
+	if r, _, b, _ := img.At(75, 250).RGBA(); b < r {
+		t.Fatalf("bottom of the rotated image is not blue")
+	}
+}
+
+func TestProcessRejectsNonImages(t *testing.T) {
+	inputs := map[string][]byte{
+		"text":            []byte("<?php echo 'hello'; ?>"),
+		"truncated jpeg":  testJPEG(t, 40, 40)[:30],
+		"empty":           {},
+		"renamed payload": append([]byte{0xFF, 0xD8, 0xFF}, []byte("not really a jpeg")...),
+	}
+	for name, data := range inputs {
+		if _, err := Process(data, DefaultOptions); !errors.Is(err, ErrNotImage) {
+			t.Errorf("Process(%s) error = %v, want ErrNotImage", name, err)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height, size int
+		want                [2]int
+	}{
+		{4000, 3000, 2048, [2]int{2048, 1536}},
+		{3000, 4000, 1024, [2]int{768, 1024}},
+		{200, 100, 320, [2]int{200, 100}},
+		{5000, 1, 320, [2]int{320, 1}},
+	}
+	for _, tt := range tests {
+		if w, h := fit(tt.width, tt.height, tt.size); [2]int{w, h} != tt.want {
+			t.Errorf("fit(%d, %d, %d) = %dx%d, want %v", tt.width, tt.height, tt.size, w, h, tt.want)
+		}
+	}
+}
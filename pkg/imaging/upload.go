@@ -0,0 +1,85 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"slices"
+	"strings"
+
+	"safety-riding/pkg/storage"
+)
+
+// Photo is an uploaded image with the URLs of its renditions
+type Photo struct {
+	URL          string
+	MediumURL    string
+	ThumbnailURL string
+	Location     *Location
+}
+
+// URLs lists the distinct stored files of the photo, to delete them together
+func (p Photo) URLs() []string {
+	var urls []string
+	for _, url := range []string{p.URL, p.MediumURL, p.ThumbnailURL} {
+		if url != "" && !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// UploadFile processes an uploaded image and stores its renditions in folder, the medium and thumbnail
+// renditions go to the medium and thumbnail sub folders. Nothing is left in storage when it fails.
+func UploadFile(ctx context.Context, provider storage.StorageProvider, fileHeader *multipart.FileHeader, folder string, opts Options) (Photo, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return Photo{}, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return Photo{}, fmt.Errorf("failed to read file %s: %w", fileHeader.Filename, err)
+	}
+
+	return Upload(ctx, provider, data, fileHeader.Filename, folder, opts)
+}
+
+// Upload processes the bytes of an image and stores its renditions like UploadFile
+func Upload(ctx context.Context, provider storage.StorageProvider, data []byte, filename, folder string, opts Options) (Photo, error) {
+	result, err := Process(data, opts)
+	if err != nil {
+		return Photo{}, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	name := strings.TrimSuffix(path.Base(filename), path.Ext(filename)) + result.Ext
+	photo := Photo{Location: result.Location}
+	uploads := []struct {
+		target    *string
+		rendition Rendition
+		folder    string
+	}{
+		{&photo.URL, result.Original, folder},
+		{&photo.MediumURL, result.Medium, path.Join(folder, RenditionMedium)},
+		{&photo.ThumbnailURL, result.Thumbnail, path.Join(folder, RenditionThumbnail)},
+	}
+	for _, u := range uploads {
+		if *u.target, err = provider.UploadFileFromBytes(ctx, u.rendition.Data, name, u.folder, result.ContentType); err != nil {
+			Delete(ctx, provider, photo)
+			return Photo{}, fmt.Errorf("failed to upload file %s to storage: %w", filename, err)
+		}
+	}
+	return photo, nil
+}
+
+// Delete removes the stored renditions of the photos, failures are ignored like a missing file
+func Delete(ctx context.Context, provider storage.StorageProvider, photos ...Photo) {
+	for _, photo := range photos {
+		for _, url := range photo.URLs() {
+			_ = provider.DeleteFile(ctx, url)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"safety-riding/pkg/logger"
+	"sync"
+	"time"
+)
+
+// LogMailer writes emails to the application log and optionally to a file instead of sending them.
+// It is meant for local development where no SMTP server is available.
+type LogMailer struct {
+	file string
+	mu   sync.Mutex
+}
+
+func NewLogMailer(config Config) *LogMailer {
+	return &LogMailer{file: config.LogFile}
+}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	logger.WriteLog(logger.LogLevelInfo, fmt.Sprintf("[LogMailer]; To: %s; Subject: %s; Body: %s", to, subject, body))
+
+	if m.file == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail log file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), to, subject, body)
+	return err
+}
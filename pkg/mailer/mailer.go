@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mailer sends plain text emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// Config holds the configuration for mailers
+type Config struct {
+	Driver   string // "smtp" or "log"
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	LogFile  string // For log, optional file the emails are appended to
+}
+
+// NewMailer creates a mailer based on the configured driver
+func NewMailer(config Config) (Mailer, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Driver)) {
+	case "smtp":
+		return NewSMTPMailer(config)
+	case "log", "":
+		return NewLogMailer(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s (supported: smtp, log)", config.Driver)
+	}
+}
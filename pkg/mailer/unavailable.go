@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnavailable is returned by Send when the configured mailer could not be initialized
+var ErrUnavailable = errors.New("mailer is unavailable")
+
+// unavailableMailer stands in for a mailer that failed to start, so the app still runs without sending emails
+type unavailableMailer struct {
+	cause error
+}
+
+// NewUnavailableMailer returns a mailer failing every Send with ErrUnavailable and the reason it did not start
+func NewUnavailableMailer(cause error) Mailer {
+	return &unavailableMailer{cause: cause}
+}
+
+func (u *unavailableMailer) Send(to, subject, body string) error {
+	return fmt.Errorf("%w: %v", ErrUnavailable, u.cause)
+}
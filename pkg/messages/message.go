@@ -20,5 +20,14 @@ const (
 )
 
 const (
-	ErrHashPassword = "crypto/bcrypt: hashedPassword is not the hash of the given password"
+	ErrHashPassword       = "crypto/bcrypt: hashedPassword is not the hash of the given password"
+	ErrEmailNotVerified   = "email address has not been verified"
+	ErrInvalidTwoFactor   = "invalid two-factor authentication code"
+	ErrOutOfRegion        = "data outside your assigned region cannot be managed"
+	ErrInvalidCheckIn     = "check-in link is invalid"
+	ErrCheckInNotOpen     = "check-in opens on the event day"
+	ErrCheckInClosed      = "check-in for this event has closed"
+	ErrNotOnRoster        = "you are not on the roster of this event, please check your name and phone number or contact the event staff"
+	ErrEventNotCompleted  = "certificates can only be issued for completed events"
+	ErrInvalidCertificate = "no certificate was issued with this verification code"
 )
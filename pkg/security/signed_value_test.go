@@ -0,0 +1,30 @@
+package security
+
+import "testing"
+
+func TestSignedValue(t *testing.T) {
+	const value = "4f5c2a1e-event"
+	token := SignValue("secret", "event_checkin", value)
+
+	got, err := VerifySignedValue("secret", "event_checkin", token)
+	if err != nil || got != value {
+		t.Fatalf("VerifySignedValue() = %q, %v, want %q", got, err, value)
+	}
+
+	cases := []struct {
+		name   string
+		secret string
+		token  string
+	}{
+		{"other secret", "secret", SignValue("other", "event_checkin", value)},
+		{"other purpose", "secret", SignValue("secret", "calendar_feed", value)},
+		{"tampered value", "secret", "5" + token[1:]},
+		{"no signature", "secret", value},
+		{"empty secret", "", SignValue("", "event_checkin", value)},
+	}
+	for _, c := range cases {
+		if _, err := VerifySignedValue(c.secret, "event_checkin", c.token); err == nil {
+			t.Fatalf("VerifySignedValue(%s) accepted %q", c.name, c.token)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package security
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// RFC 6238 appendix B test secret, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, c := range cases {
+		step, ok := ValidateTOTP(secret, c.code, time.Unix(c.unix, 0))
+		if !ok {
+			t.Fatalf("ValidateTOTP(%d, %s) = false, want true", c.unix, c.code)
+		}
+		if step != c.unix/totpPeriod {
+			t.Fatalf("ValidateTOTP(%d) step = %d, want %d", c.unix, step, c.unix/totpPeriod)
+		}
+	}
+
+	if _, ok := ValidateTOTP(secret, "287082", time.Unix(59+5*totpPeriod, 0)); ok {
+		t.Fatalf("ValidateTOTP() accepted a code outside the allowed skew")
+	}
+	if _, ok := ValidateTOTP(secret, "28708", time.Unix(59, 0)); ok {
+		t.Fatalf("ValidateTOTP() accepted a short code")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+
+	uri := TOTPProvisioningURI("Safety Riding", "admin@example.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Safety%20Riding:admin@example.com?") {
+		t.Fatalf("unexpected provisioning URI %q", uri)
+	}
+	if !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("provisioning URI %q does not contain the secret", uri)
+	}
+}
@@ -0,0 +1,83 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// DetectFormat returns the spreadsheet format based on the file extension.
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported file type %q, only .csv and .xlsx are allowed", filepath.Ext(filename))
+	}
+}
+
+// ReadRows reads every row of a CSV file or the first sheet of an XLSX file.
+func ReadRows(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xlsx: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("xlsx file has no sheet")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// NormalizeHeader converts a header cell like "District Name" into "district_name".
+func NormalizeHeader(header string) string {
+	header = strings.TrimPrefix(header, "\ufeff")
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.Join(strings.Fields(header), "_")
+}
+
+// RowMap maps every header of the first row to the cell value of a data row.
+func RowMap(headers, row []string) map[string]string {
+	data := make(map[string]string, len(headers))
+	for i, h := range headers {
+		if i < len(row) {
+			data[h] = strings.TrimSpace(row[i])
+		} else {
+			data[h] = ""
+		}
+	}
+	return data
+}
+
+// IsEmptyRow reports whether every cell of the row is blank.
+func IsEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
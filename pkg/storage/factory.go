@@ -14,7 +14,9 @@ func NewStorageProvider(config Config) (StorageProvider, error) {
 		return NewMinIOAdapter(config)
 	case "r2", "cloudflare", "cloudflare-r2":
 		return NewR2Adapter(config)
+	case "local":
+		return NewLocalAdapter(config)
 	default:
-		return nil, fmt.Errorf("unsupported storage provider: %s (supported: minio, r2)", config.Provider)
+		return nil, fmt.Errorf("unsupported storage provider: %s (supported: minio, r2, local)", config.Provider)
 	}
 }
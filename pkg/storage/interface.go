@@ -2,10 +2,26 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
 	"mime/multipart"
+	"time"
 )
 
+// ErrFileNotFound is returned by StatFile when no object has the name
+var ErrFileNotFound = errors.New("file not found in storage")
+
+// ErrNotSupported is returned by the operations a provider cannot offer
+var ErrNotSupported = errors.New("operation is not supported by the storage provider")
+
+// FileInfo describes a stored object
+type FileInfo struct {
+	Name         string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
 // StorageProvider defines the interface for object storage operations
 type StorageProvider interface {
 	// UploadFile uploads a file from multipart form and returns the public URL
@@ -14,6 +30,9 @@ type StorageProvider interface {
 	// UploadFileFromBytes uploads file from byte array and returns the public URL
 	UploadFileFromBytes(ctx context.Context, data []byte, filename string, folder string, contentType string) (string, error)
 
+	// PutFile stores size bytes of src under objectName as is, replacing an existing object
+	PutFile(ctx context.Context, objectName string, src io.Reader, size int64, contentType string) error
+
 	// DeleteFile deletes a file using its URL
 	DeleteFile(ctx context.Context, fileURL string) error
 
@@ -22,11 +41,23 @@ type StorageProvider interface {
 
 	// DownloadFile downloads a file and returns a ReadCloser
 	DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+
+	// PresignedPutURL returns a URL a client can upload an object to with PUT, without going through the app, until it expires
+	PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
+
+	// PresignedGetURL returns a temporary download URL for an object
+	PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
+
+	// StatFile returns the size and content type of an object, ErrFileNotFound when it does not exist
+	StatFile(ctx context.Context, objectName string) (FileInfo, error)
+
+	// ListFiles returns every object whose name starts with prefix, including the sub folders
+	ListFiles(ctx context.Context, prefix string) ([]FileInfo, error)
 }
 
 // Config holds the configuration for storage providers
 type Config struct {
-	Provider        string // "minio" or "r2"
+	Provider        string // "minio", "r2" or "local"
 	Endpoint        string
 	AccessKeyID     string
 	SecretAccessKey string
@@ -35,4 +66,6 @@ type Config struct {
 	BaseURL         string // Public URL to access files
 	Region          string // For R2
 	AccountID       string // For R2
+	LocalPath       string // For local, directory of the stored files
+	SigningKey      string // For local, signs the file URLs served by the app
 }
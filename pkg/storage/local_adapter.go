@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"safety-riding/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// fileURLPurpose separates the signatures of file URLs from the other signed values of the app
+const fileURLPurpose = "storage_file"
+
+// ErrInvalidFileURL is returned when a file URL is not signed by the local adapter or points outside its directory
+var ErrInvalidFileURL = errors.New("invalid file URL")
+
+// LocalAdapter implements StorageProvider on the local filesystem, for development and air-gapped deployments.
+// The app serves the files itself, a URL is only accepted with the signature the adapter added to it, so the
+// directory cannot be listed or guessed. The signature does not expire because the URLs are kept in the database.
+type LocalAdapter struct {
+	root       string
+	baseURL    string
+	signingKey string
+}
+
+// NewLocalAdapter creates a local storage adapter writing below config.LocalPath
+func NewLocalAdapter(config Config) (StorageProvider, error) {
+	if config.LocalPath == "" {
+		return nil, fmt.Errorf("local storage needs a directory")
+	}
+	if config.SigningKey == "" {
+		return nil, fmt.Errorf("local storage needs a signing key")
+	}
+
+	root, err := filepath.Abs(config.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalAdapter{
+		root:       root,
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		signingKey: config.SigningKey,
+	}, nil
+}
+
+// UploadFile stores a file from multipart form and returns its signed URL
+func (l *LocalAdapter) UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, folder string) (string, error) {
+	return l.write(ctx, file, fileHeader.Filename, folder)
+}
+
+// UploadFileFromBytes stores file from byte array and returns its signed URL
+func (l *LocalAdapter) UploadFileFromBytes(ctx context.Context, data []byte, filename string, folder string, contentType string) (string, error) {
+	return l.write(ctx, strings.NewReader(string(data)), filename, folder)
+}
+
+// PutFile stores a file under the given name, the size and content type are not kept
+func (l *LocalAdapter) PutFile(ctx context.Context, objectName string, src io.Reader, size int64, contentType string) error {
+	return l.put(ctx, src, objectName)
+}
+
+// DeleteFile deletes a file using its URL, a file that is already gone is not an error
+func (l *LocalAdapter) DeleteFile(ctx context.Context, fileURL string) error {
+	filePath, err := l.filePath(l.objectName(fileURL))
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetFileURL returns the signed URL for a file, or the unsigned base URL for an empty name
+func (l *LocalAdapter) GetFileURL(objectName string) string {
+	if objectName == "" {
+		return l.baseURL + "/"
+	}
+	return fmt.Sprintf("%s/%s?sig=%s", l.baseURL, objectName, l.signature(objectName))
+}
+
+// DownloadFile opens a stored file, objectName may also be a URL returned by the adapter
+func (l *LocalAdapter) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	filePath, err := l.filePath(l.objectName(objectName))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return file, nil
+}
+
+// PresignedPutURL is not supported, the app serves the local files so uploads go through it as well
+func (l *LocalAdapter) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+// PresignedGetURL returns the signed URL of the file, local URLs do not expire
+func (l *LocalAdapter) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	if _, err := l.filePath(objectName); err != nil {
+		return "", err
+	}
+	return l.GetFileURL(objectName), nil
+}
+
+// StatFile returns the size of a stored file and its content type sniffed from its first bytes
+func (l *LocalAdapter) StatFile(ctx context.Context, objectName string) (FileInfo, error) {
+	filePath, err := l.filePath(l.objectName(objectName))
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return FileInfo{}, ErrFileNotFound
+	} else if err != nil {
+		return FileInfo{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		return FileInfo{}, ErrFileNotFound
+	}
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	return FileInfo{Name: l.objectName(objectName), Size: info.Size(), ContentType: http.DetectContentType(head[:n]), LastModified: info.ModTime()}, nil
+}
+
+// ListFiles returns the stored files whose name starts with prefix, the files still being written are left out
+func (l *LocalAdapter) ListFiles(ctx context.Context, prefix string) ([]FileInfo, error) {
+	var files []FileInfo
+	err := filepath.WalkDir(l.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Name: name, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return files, nil
+}
+
+// URLPath is the path of the base URL, where the app serves the stored files
+func (l *LocalAdapter) URLPath() string {
+	if u, err := url.Parse(l.baseURL); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return "/files"
+}
+
+// Open returns a stored file for serving when sig is the signature of its URL
+func (l *LocalAdapter) Open(objectName, sig string) (*os.File, os.FileInfo, error) {
+	objectName = strings.TrimPrefix(objectName, "/")
+	if _, err := security.VerifySignedValue(l.signingKey, fileURLPurpose, objectName+"."+sig); err != nil {
+		return nil, nil, ErrInvalidFileURL
+	}
+
+	filePath, err := l.filePath(objectName)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, nil, os.ErrNotExist
+	}
+	return file, info, nil
+}
+
+func (l *LocalAdapter) write(ctx context.Context, src io.Reader, filename, folder string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Generate unique filename
+	ext := filepath.Ext(filename)
+	objectName := fmt.Sprintf("%s_%s%s", time.Now().Format("20060102_150405"), uuid.New().String()[:8], ext)
+	if folder != "" {
+		objectName = fmt.Sprintf("%s/%s", strings.Trim(folder, "/"), objectName)
+	}
+
+	if err := l.put(ctx, src, objectName); err != nil {
+		return "", err
+	}
+	return l.GetFileURL(objectName), nil
+}
+
+func (l *LocalAdapter) put(ctx context.Context, src io.Reader, objectName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	filePath, err := l.filePath(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	// Write next to the target and rename, so a file is never served half written
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+// objectName strips the base URL and the signature from a file URL
+func (l *LocalAdapter) objectName(fileURL string) string {
+	name := strings.TrimPrefix(fileURL, l.baseURL)
+	if idx := strings.Index(name, "?"); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.TrimPrefix(name, "/")
+}
+
+// filePath maps an object name to its file, names leaving the storage directory are rejected
+func (l *LocalAdapter) filePath(objectName string) (string, error) {
+	clean := strings.TrimPrefix(path.Clean("/"+objectName), "/")
+	if objectName == "" || clean != objectName {
+		return "", ErrInvalidFileURL
+	}
+	return filepath.Join(l.root, filepath.FromSlash(clean)), nil
+}
+
+func (l *LocalAdapter) signature(objectName string) string {
+	return strings.TrimPrefix(security.SignValue(l.signingKey, fileURLPurpose, objectName), objectName+".")
+}
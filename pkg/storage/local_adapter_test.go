@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLocalAdapter(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewLocalAdapter(Config{LocalPath: t.TempDir(), BaseURL: "http://localhost:8080/files/", SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalAdapter() error = %v", err)
+	}
+	local := provider.(*LocalAdapter)
+
+	fileURL, err := local.UploadFileFromBytes(ctx, []byte("photo"), "photo.jpg", "event-photos/medium", "image/jpeg")
+	if err != nil {
+		t.Fatalf("UploadFileFromBytes() error = %v", err)
+	}
+	u, err := url.Parse(fileURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/files/event-photos/medium/") || !strings.HasSuffix(u.Path, ".jpg") {
+		t.Fatalf("UploadFileFromBytes() = %q, want a URL below /files/event-photos/medium", fileURL)
+	}
+	if local.URLPath() != "/files" {
+		t.Fatalf("URLPath() = %q, want /files", local.URLPath())
+	}
+
+	objectName := strings.TrimPrefix(u.Path, "/files/")
+	file, _, err := local.Open(objectName, u.Query().Get("sig"))
+	if err != nil {
+		t.Fatalf("Open() with the signature error = %v", err)
+	}
+	file.Close()
+
+	for name, tt := range map[string][2]string{
+		"wrong signature":  {objectName, "invalid"},
+		"other file":       {"event-photos/other.jpg", u.Query().Get("sig")},
+		"parent directory": {"../" + objectName, u.Query().Get("sig")},
+	} {
+		if _, _, err := local.Open(tt[0], tt[1]); !errors.Is(err, ErrInvalidFileURL) {
+			t.Errorf("Open() %s error = %v, want ErrInvalidFileURL", name, err)
+		}
+	}
+
+	// The certificate service trims GetFileURL("") from a URL and downloads the rest
+	reader, err := local.DownloadFile(ctx, strings.TrimPrefix(fileURL, local.GetFileURL("")))
+	if err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "photo" {
+		t.Fatalf("DownloadFile() = %q, want photo", data)
+	}
+
+	if _, err := local.UploadFileFromBytes(ctx, []byte("other"), "other.jpg", "accident-photos", "image/jpeg"); err != nil {
+		t.Fatalf("UploadFileFromBytes() error = %v", err)
+	}
+	files, err := local.ListFiles(ctx, "event-photos/")
+	if err != nil || len(files) != 1 || files[0].Name != objectName || files[0].Size != 5 {
+		t.Fatalf("ListFiles() = %+v, %v, want only %s", files, err, objectName)
+	}
+
+	if err := local.DeleteFile(ctx, fileURL); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if _, _, err := local.Open(objectName, u.Query().Get("sig")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Open() after DeleteFile() error = %v, want not exist", err)
+	}
+	if err := local.DeleteFile(ctx, fileURL); err != nil {
+		t.Fatalf("DeleteFile() twice error = %v", err)
+	}
+}
+
+func TestUnavailableProvider(t *testing.T) {
+	provider := NewUnavailableProvider(errors.New("connection refused"))
+	if _, err := provider.UploadFileFromBytes(context.Background(), nil, "a.jpg", "", ""); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("UploadFileFromBytes() error = %v, want ErrUnavailable", err)
+	}
+}
@@ -187,3 +187,28 @@ func (m *MinIOAdapter) extractObjectName(fileURL string) string {
 	// Join everything after bucket name
 	return strings.Join(parts[bucketIndex+1:], "/")
 }
+
+// PutFile uploads an object under the given name
+func (m *MinIOAdapter) PutFile(ctx context.Context, objectName string, src io.Reader, size int64, contentType string) error {
+	return putObject(ctx, m.client, m.bucketName, objectName, src, size, contentType)
+}
+
+// PresignedPutURL returns a URL to upload an object directly to the bucket
+func (m *MinIOAdapter) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return presignedPutURL(ctx, m.client, m.bucketName, objectName, expiry)
+}
+
+// PresignedGetURL returns a temporary download URL for an object of the bucket
+func (m *MinIOAdapter) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return presignedGetURL(ctx, m.client, m.bucketName, objectName, expiry)
+}
+
+// StatFile returns the size and content type of an object of the bucket
+func (m *MinIOAdapter) StatFile(ctx context.Context, objectName string) (FileInfo, error) {
+	return statObject(ctx, m.client, m.bucketName, objectName)
+}
+
+// ListFiles returns the objects of the bucket below prefix
+func (m *MinIOAdapter) ListFiles(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return listObjects(ctx, m.client, m.bucketName, prefix)
+}
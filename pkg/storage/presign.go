@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// The MinIO and R2 adapters share the S3 client, so they share the put, presigning, stat and list calls
+
+func putObject(ctx context.Context, client *minio.Client, bucketName, objectName string, src io.Reader, size int64, contentType string) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if _, err := client.PutObject(ctx, bucketName, objectName, src, size, minio.PutObjectOptions{ContentType: contentType}); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+func presignedPutURL(ctx context.Context, client *minio.Client, bucketName, objectName string, expiry time.Duration) (string, error) {
+	u, err := client.PresignedPutObject(ctx, bucketName, objectName, expiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload: %w", err)
+	}
+	return u.String(), nil
+}
+
+func presignedGetURL(ctx context.Context, client *minio.Client, bucketName, objectName string, expiry time.Duration) (string, error) {
+	u, err := client.PresignedGetObject(ctx, bucketName, objectName, expiry, url.Values{})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download: %w", err)
+	}
+	return u.String(), nil
+}
+
+func statObject(ctx context.Context, client *minio.Client, bucketName, objectName string) (FileInfo, error) {
+	info, err := client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if resp := minio.ToErrorResponse(err); resp.Code == "NoSuchKey" || resp.StatusCode == 404 {
+			return FileInfo{}, ErrFileNotFound
+		}
+		return FileInfo{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return FileInfo{Name: info.Key, Size: info.Size, ContentType: info.ContentType, LastModified: info.LastModified}, nil
+}
+
+func listObjects(ctx context.Context, client *minio.Client, bucketName, prefix string) ([]FileInfo, error) {
+	var files []FileInfo
+	for object := range client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", object.Err)
+		}
+		files = append(files, FileInfo{Name: object.Key, Size: object.Size, ContentType: object.ContentType, LastModified: object.LastModified})
+	}
+	return files, nil
+}
@@ -183,3 +183,28 @@ func (r *R2Adapter) extractObjectName(fileURL string) string {
 
 	return objectName
 }
+
+// PutFile uploads an object under the given name
+func (r *R2Adapter) PutFile(ctx context.Context, objectName string, src io.Reader, size int64, contentType string) error {
+	return putObject(ctx, r.client, r.bucketName, objectName, src, size, contentType)
+}
+
+// PresignedPutURL returns a URL to upload an object directly to the bucket
+func (r *R2Adapter) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return presignedPutURL(ctx, r.client, r.bucketName, objectName, expiry)
+}
+
+// PresignedGetURL returns a temporary download URL for an object of the bucket
+func (r *R2Adapter) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return presignedGetURL(ctx, r.client, r.bucketName, objectName, expiry)
+}
+
+// StatFile returns the size and content type of an object of the bucket
+func (r *R2Adapter) StatFile(ctx context.Context, objectName string) (FileInfo, error) {
+	return statObject(ctx, r.client, r.bucketName, objectName)
+}
+
+// ListFiles returns the objects of the bucket below prefix
+func (r *R2Adapter) ListFiles(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return listObjects(ctx, r.client, r.bucketName, prefix)
+}
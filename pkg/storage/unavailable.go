@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"time"
+)
+
+// ErrUnavailable is returned by every operation of the storage when the provider could not be initialized
+var ErrUnavailable = errors.New("file storage is unavailable")
+
+// unavailableProvider stands in for a provider that failed to start, so the app still runs without file uploads
+type unavailableProvider struct {
+	cause error
+}
+
+// NewUnavailableProvider returns a provider failing every operation with ErrUnavailable and the reason it did not start
+func NewUnavailableProvider(cause error) StorageProvider {
+	return &unavailableProvider{cause: cause}
+}
+
+func (u *unavailableProvider) err() error {
+	return fmt.Errorf("%w: %v", ErrUnavailable, u.cause)
+}
+
+func (u *unavailableProvider) UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, folder string) (string, error) {
+	return "", u.err()
+}
+
+func (u *unavailableProvider) UploadFileFromBytes(ctx context.Context, data []byte, filename string, folder string, contentType string) (string, error) {
+	return "", u.err()
+}
+
+func (u *unavailableProvider) PutFile(ctx context.Context, objectName string, src io.Reader, size int64, contentType string) error {
+	return u.err()
+}
+
+func (u *unavailableProvider) DeleteFile(ctx context.Context, fileURL string) error {
+	return u.err()
+}
+
+func (u *unavailableProvider) GetFileURL(objectName string) string {
+	return ""
+}
+
+func (u *unavailableProvider) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	return nil, u.err()
+}
+
+func (u *unavailableProvider) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return "", u.err()
+}
+
+func (u *unavailableProvider) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return "", u.err()
+}
+
+func (u *unavailableProvider) StatFile(ctx context.Context, objectName string) (FileInfo, error) {
+	return FileInfo{}, u.err()
+}
+
+func (u *unavailableProvider) ListFiles(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return nil, u.err()
+}
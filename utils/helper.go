@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	domainevent "safety-riding/internal/domain/event"
 	"safety-riding/internal/dto"
+	"sort"
 	"strings"
 	"time"
 )
@@ -68,6 +69,47 @@ func ValidatePhotoLimit(currentCount, incomingCount, maxAccidentPhotos int) erro
 	return nil
 }
 
+// AppendPhotoOrders places new photos after the current ones, a requested photo_order only sorts the new photos
+// among themselves and photos without one keep their upload order at the end, so photo_order stays 1..n
+func AppendPhotoOrders(currentCount int, requested []int) []int {
+	positions := make([]int, len(requested))
+	for i := range positions {
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(a, b int) bool {
+		ra, rb := requested[positions[a]], requested[positions[b]]
+		if ra <= 0 || rb <= 0 {
+			return ra > 0 && rb <= 0
+		}
+		return ra < rb
+	})
+
+	orders := make([]int, len(requested))
+	for rank, i := range positions {
+		orders[i] = currentCount + rank + 1
+	}
+	return orders
+}
+
+// ValidatePhotoOrder checks that a reorder request lists every current photo exactly once
+func ValidatePhotoOrder(currentIds, requestedIds []string) error {
+	if len(requestedIds) != len(currentIds) {
+		return fmt.Errorf("photo_ids must list all %d photos, got %d", len(currentIds), len(requestedIds))
+	}
+
+	current := make(map[string]bool, len(currentIds))
+	for _, id := range currentIds {
+		current[id] = true
+	}
+	for _, id := range requestedIds {
+		if !current[id] {
+			return fmt.Errorf("photo %s is not a photo of this record or is listed twice", id)
+		}
+		delete(current, id)
+	}
+	return nil
+}
+
 func ValidatePhotoFileSize(fileHeader *multipart.FileHeader) error {
 	if fileHeader == nil {
 		return fmt.Errorf("invalid file header")
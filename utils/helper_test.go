@@ -0,0 +1,52 @@
+package utils
+
+import "testing"
+
+func TestAppendPhotoOrders(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int
+		requested []int
+		want      []int
+	}{
+		{name: "upload order", current: 0, requested: []int{0, 0, 0}, want: []int{1, 2, 3}},
+		{name: "after current photos", current: 2, requested: []int{0, 0}, want: []int{3, 4}},
+		{name: "requested order", current: 1, requested: []int{3, 1, 2}, want: []int{4, 2, 3}},
+		{name: "unordered go last", current: 0, requested: []int{0, 5, 0, 2}, want: []int{3, 2, 4, 1}},
+		{name: "ties keep upload order", current: 0, requested: []int{1, 1}, want: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AppendPhotoOrders(tt.current, tt.requested)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("AppendPhotoOrders(%d, %v) = %v, want %v", tt.current, tt.requested, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePhotoOrder(t *testing.T) {
+	current := []string{"a", "b", "c"}
+	tests := []struct {
+		name      string
+		requested []string
+		wantErr   bool
+	}{
+		{name: "all photos", requested: []string{"c", "a", "b"}},
+		{name: "missing photo", requested: []string{"c", "a"}, wantErr: true},
+		{name: "listed twice", requested: []string{"a", "a", "b"}, wantErr: true},
+		{name: "other photo", requested: []string{"a", "b", "d"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePhotoOrder(current, tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePhotoOrder(%v) error = %v, wantErr %v", tt.requested, err, tt.wantErr)
+			}
+		})
+	}
+}
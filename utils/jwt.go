@@ -20,13 +20,18 @@ type AppClaims struct {
 }
 
 func GenerateJwt(user *domainuser.Users, logId string) (string, error) {
+	return GenerateJwtWithTTL(user, logId, time.Hour*time.Duration(GetEnv("JWT_EXP", 24).(int)))
+}
+
+// GenerateJwtWithTTL issues an access token that expires after ttl
+func GenerateJwtWithTTL(user *domainuser.Users, logId string, ttl time.Duration) (string, error) {
 	claims := AppClaims{
 		UserId:   user.Id,
 		Username: user.Name,
 		Role:     user.Role,
 		RegisteredClaims: &jwt.RegisteredClaims{
 			ID:        logId,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(GetEnv("JWT_EXP", 24).(int)))),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}